@@ -1,12 +1,22 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
-	_ "github.com/joho/godotenv/autoload"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/raihaninkam/tickitz/internals/configs"
+	"github.com/raihaninkam/tickitz/internals/repositories"
 	"github.com/raihaninkam/tickitz/internals/routers"
-	"github.com/raihaninkam/tickitz/pkg"
+	"github.com/redis/go-redis/v9"
 )
 
 // @title 					Backend Golang Tickitz App
@@ -18,31 +28,176 @@ import (
 // @name Authorization
 // @description Masukkan format: Bearer <token>
 func main() {
+	command, args := "serve", []string{}
+	if len(os.Args) > 1 {
+		command, args = os.Args[1], os.Args[2:]
+	}
+
+	switch command {
+	case "serve":
+		serve()
+	case "migrate":
+		exitOnError(runMigrate(args))
+	case "seed":
+		exitOnError(runSeed(args))
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func serve() {
+	// Load Config (env / .env / CONFIG_FILE), semua nilai yang salah dilaporkan sekaligus
+	cfg, err := configs.Load()
+	if err != nil {
+		slog.Error("invalid config", "error", err)
+		os.Exit(1)
+	}
+
+	// Init Logger (LOG_LEVEL, LOG_FORMAT)
+	logger := configs.InitLogger(cfg.Log)
 
 	// Init Database
-	db, err := configs.InitDB()
+	db, err := configs.InitDB(cfg.DB)
 	if err != nil {
-		log.Println("Failed to connect to database\nCause: ", err.Error())
+		logger.Error("failed to connect to database", "error", err)
+		return
 	}
 	defer db.Close()
 
 	if err := configs.PingDB(db); err != nil {
-		log.Println("Ping to DB failed\nCause: ", err.Error())
+		logger.Error("ping to db failed", "error", err)
+		return
+	}
+	logger.Info("db connected")
+
+	// schema harus sudah di migrasi terbaru yang dibawa binary
+	if err := checkSchema(context.Background(), db); err != nil {
+		logger.Error("refusing to serve, database schema is not up to date; run `tickitz migrate up`", "error", err)
 		return
 	}
-	log.Println("db connected")
 
 	// Init Redis
-	rdb, err := configs.InitRedis()
+	// Redis hanya cache: jika belum tersedia server tetap jalan dan cache memakai fallback lokal
+	rdb, err := configs.InitRedis(cfg.Redis)
+	if err != nil {
+		logger.Warn("ping to redis failed, running in degraded mode", "error", err)
+	}
+
+	// Init Payment Gateway
+	gateway, err := configs.InitPaymentGateway(cfg.Payment)
 	if err != nil {
-		log.Println("Ping to Redis failed\nCause: ", err.Error())
+		logger.Error("failed to init payment gateway", "error", err)
 		return
 	}
 
-	hc := pkg.NewHashConfig()
-	hc.UseRecommended()
+	// Init Ticket Signer (QR tiket)
+	signer, err := configs.InitTicketSigner(cfg.Ticket)
+	if err != nil {
+		logger.Error("failed to init ticket signer", "error", err)
+		return
+	}
+
+	// Init Mailer (verifikasi email & reset password)
+	mailer, err := configs.InitMailer(cfg.Mail)
+	if err != nil {
+		logger.Error("failed to init mailer", "error", err)
+		return
+	}
 
-	router := routers.InitRouter(db, rdb, hc)
+	// SIGINT / SIGTERM memulai graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	router.Run(":9001")
+	// background worker berhenti setelah request selesai di-drain, lihat shutdown
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	var workers sync.WaitGroup
+
+	// background worker: lepas kursi yang masa tahannya habis
+	seatHoldRepo := repositories.NewSeatHoldRepository(db, rdb, cfg.SeatHoldDuration)
+	workers.Go(func() { seatHoldRepo.RunSweeper(workerCtx, time.Minute) })
+
+	// background worker: batalkan order yang tidak dibayar sampai batas waktu pembayaran
+	paymentRepo := repositories.NewPaymentRepository(db, rdb, cfg.Loyalty)
+	workers.Go(func() { paymentRepo.RunExpirySweeper(workerCtx, time.Minute, cfg.Payment.Expiry) })
+
+	// background worker: kirim ulang refund yang gagal di payment gateway
+	refundRepo := repositories.NewRefundRepository(db, rdb, cfg.OrderCancelCutoff)
+	workers.Go(func() { refundRepo.RunRefundRetrier(workerCtx, gateway, 5*time.Minute) })
+
+	// background worker: catat poin loyalty yang kadaluarsa
+	pointsRepo := repositories.NewPointsRepository(db, cfg.Loyalty)
+	workers.Go(func() { pointsRepo.RunExpirySweeper(workerCtx, time.Hour) })
+
+	// background worker: hitung ulang rating review & penjualan tiket (movie_stats)
+	reviewRepo := repositories.NewReviewRepository(db, rdb)
+	workers.Go(func() { reviewRepo.RunStatsRefresher(workerCtx, 5*time.Minute) })
+
+	// blacklist token & session dicabut: Redis dengan fallback Postgres
+	revocationStore := repositories.NewTokenRevocationStore(db, rdb, cfg.TokenRevocationStore, cfg.JWT.AccessTTL)
+	workers.Go(func() { repositories.RunRevocationMaintenance(workerCtx, revocationStore, 10*time.Minute) })
+
+	router := routers.InitRouter(cfg, db, rdb, gateway, signer, mailer, revocationStore, logger)
+
+	srv := &http.Server{
+		Addr:              cfg.Server.Addr,
+		Handler:           router,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("http server listening", "addr", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		logger.Info("shutdown signal received, draining in-flight requests", "timeout", cfg.Server.ShutdownTimeout.String())
+	case err := <-serverErr:
+		logger.Error("http server failed", "error", err)
+	}
+	// sinyal kedua langsung menghentikan proses tanpa menunggu drain
+	stop()
+
+	shutdown(logger, cfg.Server.ShutdownTimeout, srv, stopWorkers, &workers, db, rdb)
+}
+
+// shutdown berurutan dalam satu deadline: berhenti menerima koneksi dan menunggu request yang sedang
+// berjalan (misal transaksi CreateOrder), menghentikan background worker, lalu menutup pool Postgres
+// dan client Redis
+func shutdown(logger *slog.Logger, timeout time.Duration, srv *http.Server, stopWorkers context.CancelFunc, workers *sync.WaitGroup, db *pgxpool.Pool, rdb *redis.Client) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Error("http server did not drain before deadline, closing remaining connections", "error", err)
+		srv.Close()
+	}
+
+	stopWorkers()
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		logger.Warn("background workers did not stop before deadline")
+	}
+
+	db.Close()
+	if err := rdb.Close(); err != nil {
+		logger.Warn("failed to close redis client", "error", err)
+	}
+	logger.Info("server stopped")
 }
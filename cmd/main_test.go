@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// startServer menjalankan http.Server di port acak. Pool dan client Redis tidak pernah tersambung,
+// cukup untuk memastikan shutdown menutupnya
+func startServer(t *testing.T, handler http.Handler) (*http.Server, string, *pgxpool.Pool, *redis.Client) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{Handler: handler}
+	go srv.Serve(ln)
+
+	db, err := pgxpool.New(context.Background(), "postgres://tickitz@127.0.0.1:1/tickitz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1"})
+	return srv, "http://" + ln.Addr().String(), db, rdb
+}
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestShutdownDrainsInFlightRequests(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	srv, url, db, rdb := startServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte("order created"))
+	}))
+
+	response := make(chan int, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			response <- 0
+			return
+		}
+		resp.Body.Close()
+		response <- resp.StatusCode
+	}()
+	<-started
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	workerStopped := false
+	workers.Go(func() {
+		<-workerCtx.Done()
+		workerStopped = true
+	})
+
+	done := make(chan struct{})
+	go func() {
+		shutdown(discardLogger, 5*time.Second, srv, stopWorkers, &workers, db, rdb)
+		close(done)
+	}()
+
+	// request yang sedang berjalan ditunggu, koneksi baru ditolak
+	select {
+	case <-done:
+		t.Fatal("shutdown returned before in-flight request finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if _, err := http.Get(url); err == nil {
+		t.Error("new request accepted during shutdown")
+	}
+
+	close(release)
+	if code := <-response; code != http.StatusOK {
+		t.Errorf("in-flight request status %d, want 200", code)
+	}
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("shutdown did not return after request finished")
+	}
+	if !workerStopped {
+		t.Error("background worker not stopped")
+	}
+	if err := rdb.Ping(context.Background()).Err(); err != redis.ErrClosed {
+		t.Errorf("redis client not closed: %v", err)
+	}
+}
+
+func TestShutdownDeadline(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	srv, url, db, rdb := startServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	go http.Get(url)
+	time.Sleep(20 * time.Millisecond)
+
+	// worker yang tidak berhenti dan request yang macet tidak menahan shutdown melewati deadline
+	var workers sync.WaitGroup
+	workers.Go(func() { <-release })
+
+	start := time.Now()
+	shutdown(discardLogger, 100*time.Millisecond, srv, func() {}, &workers, db, rdb)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("shutdown took %v with a 100ms deadline", elapsed)
+	}
+}
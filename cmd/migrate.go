@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	dbfs "github.com/raihaninkam/tickitz/db"
+	"github.com/raihaninkam/tickitz/internals/configs"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+const usage = `usage:
+  tickitz [serve]                 jalankan HTTP server (default)
+  tickitz migrate up [N]          terapkan semua / N migrasi berikutnya
+  tickitz migrate down [N]        batalkan N migrasi terakhir (default 1)
+  tickitz migrate status          versi schema dan daftar migrasi
+  tickitz migrate force V         tandai database di versi V tanpa menjalankan SQL
+  tickitz seed [--profile demo]   isi data awal dari db/seeds/<profile>
+`
+
+// newMigrator migrator dari file db/migrations yang di-embed ke binary
+func newMigrator(db *pgxpool.Pool) (*pkg.Migrator, error) {
+	migrations, err := fs.Sub(dbfs.Migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return pkg.NewMigrator(db, migrations)
+}
+
+// checkSchema dipanggil sebelum serve: server menolak jalan jika migrasi belum diterapkan
+// atau migrasi terakhir gagal di tengah jalan (dirty)
+func checkSchema(ctx context.Context, db *pgxpool.Pool) error {
+	migrator, err := newMigrator(db)
+	if err != nil {
+		return err
+	}
+	return migrator.CheckCurrent(ctx)
+}
+
+// connectDB koneksi Postgres untuk subcommand migrate / seed, hanya butuh env DB*
+func connectDB() (*pgxpool.Pool, error) {
+	cfg, err := configs.LoadDB()
+	if err != nil {
+		return nil, err
+	}
+	db, err := configs.InitDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := configs.PingDB(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing migrate command (up, down, status, force)")
+	}
+	command, rest := args[0], args[1:]
+
+	n := 0
+	if command != "status" && len(rest) > 0 {
+		value, err := strconv.Atoi(rest[0])
+		if err != nil || value < 0 {
+			return fmt.Errorf("invalid number %q", rest[0])
+		}
+		n = value
+	} else if command == "force" {
+		return errors.New("migrate force needs a version")
+	}
+
+	db, err := connectDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := newMigrator(db)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx, n)
+		for _, m := range applied {
+			fmt.Printf("applied  %06d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("no change, database is up to date")
+		}
+	case "down":
+		reverted, err := migrator.Down(ctx, n)
+		for _, m := range reverted {
+			fmt.Printf("reverted %06d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			fmt.Println("no change, nothing to revert")
+		}
+	case "status":
+		statuses, version, dirty, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("version: %d (latest %d)", version, migrator.Latest())
+		if dirty {
+			fmt.Print(" dirty")
+		}
+		fmt.Println()
+		for _, s := range statuses {
+			mark := " "
+			if s.Applied {
+				mark = "x"
+			}
+			fmt.Printf("[%s] %06d_%s\n", mark, s.Version, s.Name)
+		}
+	case "force":
+		if err := migrator.Force(ctx, uint64(n)); err != nil {
+			return err
+		}
+		fmt.Printf("forced version %d\n", n)
+	default:
+		return fmt.Errorf("unknown migrate command %q", command)
+	}
+	return nil
+}
+
+func runSeed(args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	profile := flags.String("profile", "demo", "seed profile (folder di db/seeds)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	seeds, err := fs.Sub(dbfs.Seeds, "seeds")
+	if err != nil {
+		return err
+	}
+	profiles, err := pkg.SeedProfiles(seeds)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(profiles, *profile) {
+		return fmt.Errorf("unknown seed profile %q (available: %s)", *profile, strings.Join(profiles, ", "))
+	}
+
+	db, err := connectDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// seed ditulis untuk schema terbaru, jadi migrasi harus sudah diterapkan
+	ctx := context.Background()
+	if err := checkSchema(ctx, db); err != nil {
+		return fmt.Errorf("%w, run `tickitz migrate up` first", err)
+	}
+
+	files, err := pkg.Seed(ctx, db, seeds, *profile)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		fmt.Printf("seeded %s\n", file)
+	}
+	return nil
+}
+
+func exitOnError(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
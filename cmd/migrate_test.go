@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	dbfs "github.com/raihaninkam/tickitz/db"
+	"github.com/raihaninkam/tickitz/internals/testutil"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+func TestMain(m *testing.M) { testutil.Main(m) }
+
+func TestEmbeddedMigrationsAndSeeds(t *testing.T) {
+	migrator, err := newMigrator(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := fs.ReadDir(dbfs.Migrations, "migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if migrator.Latest() == 0 || len(entries) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+	// setiap migrasi harus bisa dibatalkan
+	for _, entry := range entries {
+		if up, ok := strings.CutSuffix(entry.Name(), ".up.sql"); ok {
+			if _, err := fs.Stat(dbfs.Migrations, "migrations/"+up+".down.sql"); err != nil {
+				t.Errorf("migration %s has no down file", up)
+			}
+		}
+	}
+
+	seeds, err := fs.Sub(dbfs.Seeds, "seeds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	profiles, err := pkg.SeedProfiles(seeds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(profiles, "demo") {
+		t.Errorf("seed profiles %v, want demo", profiles)
+	}
+}
+
+func TestMigrateRoundTrip(t *testing.T) {
+	env := testutil.Setup(t)
+	ctx := t.Context()
+	migrator, err := newMigrator(env.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest := migrator.Latest()
+	if err := checkSchema(ctx, env.DB); err != nil {
+		t.Fatalf("freshly migrated database: %v", err)
+	}
+
+	// semua migrasi bisa dibatalkan lalu diterapkan lagi
+	statuses, _, _, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reverted, err := migrator.Down(ctx, len(statuses))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reverted) != len(statuses) {
+		t.Errorf("reverted %d migrations, want %d", len(reverted), len(statuses))
+	}
+	if err := checkSchema(ctx, env.DB); !errors.Is(err, pkg.ErrSchemaBehind) {
+		t.Errorf("empty database: err %v, want %v", err, pkg.ErrSchemaBehind)
+	}
+	if applied, err := migrator.Up(ctx, 0); err != nil || len(applied) != len(statuses) {
+		t.Fatalf("up applied %d migrations (err %v), want %d", len(applied), err, len(statuses))
+	}
+
+	seeds, err := fs.Sub(dbfs.Seeds, "seeds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pkg.Seed(ctx, env.DB, seeds, "demo"); err != nil {
+		t.Fatalf("seed demo: %v", err)
+	}
+
+	// migrasi yang gagal dibatalkan seluruhnya, versi tetap di migrasi sebelumnya
+	broken, err := pkg.NewMigrator(env.DB, fstest.MapFS{
+		"000001_init.up.sql":   {Data: []byte("SELECT 1")},
+		"999999_broken.up.sql": {Data: []byte("CREATE TABLE half_done (id int); SELECT * FROM missing_table")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := broken.Up(ctx, 0); err == nil {
+		t.Fatal("broken migration applied")
+	}
+	if version, dirty, err := migrator.Version(ctx); err != nil || version != latest || dirty {
+		t.Errorf("after failed migration: version %d dirty %v (err %v), want %d clean", version, dirty, err, latest)
+	}
+	var exists bool
+	if err := env.DB.QueryRow(ctx, "SELECT to_regclass('public.half_done') IS NOT NULL").Scan(&exists); err != nil || exists {
+		t.Errorf("partial migration left table behind (exists %v, err %v)", exists, err)
+	}
+
+	// database dirty (migrasi lewat CLI migrate gagal): server dan migrasi berhenti sampai di-force
+	if _, err := env.DB.Exec(ctx, "UPDATE schema_migrations SET dirty = true"); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSchema(ctx, env.DB); !errors.Is(err, pkg.ErrDirtyDatabase) {
+		t.Errorf("dirty database: err %v, want %v", err, pkg.ErrDirtyDatabase)
+	}
+	if _, err := migrator.Down(ctx, 1); !errors.Is(err, pkg.ErrDirtyDatabase) {
+		t.Errorf("down on dirty database: err %v, want %v", err, pkg.ErrDirtyDatabase)
+	}
+	if err := migrator.Force(ctx, latest); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSchema(ctx, env.DB); err != nil {
+		t.Errorf("after force: %v", err)
+	}
+}
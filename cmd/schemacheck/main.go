@@ -0,0 +1,237 @@
+// schemacheck memastikan setiap query SQL di repository cocok dengan schema hasil migrasi.
+// Semua string literal SQL (SELECT / INSERT / UPDATE / DELETE / WITH) di-prepare ke Postgres,
+// jadi tabel, kolom, fungsi atau target ON CONFLICT yang tidak dibuat migrasi langsung ketahuan
+// tanpa menjalankan query-nya.
+//
+//	go run ./cmd/schemacheck [-migrate] [-dir internals/repositories]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	dbfs "github.com/raihaninkam/tickitz/db"
+	"github.com/raihaninkam/tickitz/internals/configs"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+// query SQL yang ditemukan di source beserta posisinya
+type query struct {
+	pos token.Position
+	sql string
+}
+
+var (
+	sqlStartRe = regexp.MustCompile(`(?is)^\s*(SELECT|INSERT|UPDATE|DELETE|WITH)\s`)
+	// template fmt.Sprintf (nama tabel / kondisi diisi saat runtime) tidak bisa di-prepare
+	formatVerbRe = regexp.MustCompile(`%(\[\d+\])?[sdv]`)
+)
+
+// fragmentCodes error yang berarti string hanya potongan query (disambung saat runtime),
+// bukan ketidakcocokan schema
+var fragmentCodes = []string{
+	"42601", // syntax_error
+	"42P18", // indeterminate_datatype
+	"42P08", // ambiguous_parameter
+}
+
+func main() {
+	dir := flag.String("dir", "internals/repositories", "folder source Go yang berisi query")
+	migrate := flag.Bool("migrate", false, "jalankan migrate up sebelum pengecekan")
+	verbose := flag.Bool("v", false, "tampilkan query yang dilewati")
+	flag.Parse()
+
+	if err := run(*dir, *migrate, *verbose); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir string, migrate, verbose bool) error {
+	queries, err := collectQueries(dir)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := configs.LoadDB()
+	if err != nil {
+		return err
+	}
+	db, err := configs.InitDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	migrations, err := fs.Sub(dbfs.Migrations, "migrations")
+	if err != nil {
+		return err
+	}
+	migrator, err := pkg.NewMigrator(db, migrations)
+	if err != nil {
+		return err
+	}
+	if migrate {
+		if _, err := migrator.Up(ctx, 0); err != nil {
+			return err
+		}
+	}
+	if err := migrator.CheckCurrent(ctx); err != nil {
+		return fmt.Errorf("%w, run with -migrate or `tickitz migrate up` first", err)
+	}
+
+	conn, err := db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	checked, skipped, failed, err := checkQueries(ctx, conn.Conn().PgConn(), queries, verbose)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d queries match the schema, %d skipped (fragments / templates), %d failed\n", checked, skipped, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d queries do not match the schema", failed)
+	}
+	return nil
+}
+
+// checkQueries prepare setiap query ke Postgres, query yang tidak cocok dengan schema dicetak
+func checkQueries(ctx context.Context, conn *pgconn.PgConn, queries []query, verbose bool) (checked, skipped, failed int, err error) {
+	for _, q := range queries {
+		if formatVerbRe.MatchString(q.sql) {
+			skipped++
+			if verbose {
+				fmt.Printf("skip %s: format template\n", q.pos)
+			}
+			continue
+		}
+
+		// prepare tanpa nama: hanya di-parse & di-plan oleh Postgres, tidak dieksekusi
+		_, err := conn.Prepare(ctx, "", q.sql, nil)
+		var pgErr *pgconn.PgError
+		switch {
+		case err == nil:
+			checked++
+		case errors.As(err, &pgErr) && slices.Contains(fragmentCodes, pgErr.Code):
+			skipped++
+			if verbose {
+				fmt.Printf("skip %s: fragment (%s)\n", q.pos, pgErr.Message)
+			}
+		case errors.As(err, &pgErr):
+			failed++
+			fmt.Printf("%s: %s (SQLSTATE %s)\n\t%s\n", q.pos, pgErr.Message, pgErr.Code, oneLine(q.sql))
+		default:
+			return checked, skipped, failed, err
+		}
+	}
+	return checked, skipped, failed, nil
+}
+
+// collectQueries membaca semua file .go (kecuali _test.go) di dir dan mengambil string konstan
+// yang terlihat seperti query, termasuk gabungan literal dengan "+" dan const di file yang sama
+func collectQueries(dir string) ([]query, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var queries []query
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		consts := fileConsts(f)
+		ast.Inspect(f, func(n ast.Node) bool {
+			expr, ok := n.(ast.Expr)
+			if _, isIdent := n.(*ast.Ident); !ok || isIdent {
+				return true
+			}
+			value, ok := constString(expr, consts)
+			if !ok {
+				return true
+			}
+			if sqlStartRe.MatchString(value) {
+				queries = append(queries, query{pos: fset.Position(expr.Pos()), sql: value})
+			}
+			// string konstan sudah diambil utuh, bagian-bagiannya tidak perlu diperiksa lagi
+			return false
+		})
+	}
+	return queries, nil
+}
+
+// fileConsts nilai const string di file (package level maupun di dalam fungsi)
+func fileConsts(f *ast.File) map[string]string {
+	consts := map[string]string{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		decl, ok := n.(*ast.GenDecl)
+		if !ok || decl.Tok != token.CONST {
+			return true
+		}
+		for _, spec := range decl.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if i < len(vs.Values) {
+					if value, ok := constString(vs.Values[i], consts); ok {
+						consts[name.Name] = value
+					}
+				}
+			}
+		}
+		return true
+	})
+	return consts
+}
+
+func constString(expr ast.Expr, consts map[string]string) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		value, err := strconv.Unquote(e.Value)
+		return value, err == nil
+	case *ast.Ident:
+		value, ok := consts[e.Name]
+		return value, ok
+	case *ast.ParenExpr:
+		return constString(e.X, consts)
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		left, ok := constString(e.X, consts)
+		if !ok {
+			return "", false
+		}
+		right, ok := constString(e.Y, consts)
+		return left + right, ok
+	}
+	return "", false
+}
+
+func oneLine(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/raihaninkam/tickitz/internals/testutil"
+)
+
+func TestMain(m *testing.M) { testutil.Main(m) }
+
+// writeSource menulis file Go ke dir untuk diparse collectQueries
+func writeSource(t *testing.T, dir, name, src string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCollectQueries(t *testing.T) {
+	dir := t.TempDir()
+	writeSource(t, dir, "movie.repository.go", `package repositories
+
+const movieColumns = "id, title"
+
+func (r *MovieRepository) GetMovie() {
+	const sql = "SELECT " + movieColumns + " FROM movies WHERE id = $1"
+	r.db.QueryRow(ctx, sql, id)
+	r.db.Exec(ctx, `+"`"+`
+		UPDATE movies SET title = $1
+		WHERE id = $2`+"`"+`, title, id)
+	r.db.Query(ctx, fmt.Sprintf("SELECT * FROM %s", table))
+	errors.New("movie not found")
+	r.db.Exec(ctx, "DELETE FROM " + table)
+}
+`)
+	writeSource(t, dir, "movie.repository_test.go", `package repositories
+
+const fixture = "INSERT INTO movies (title) VALUES ('test')"
+`)
+
+	queries, err := collectQueries(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"SELECT id, title FROM movies WHERE id = $1",
+		"UPDATE movies SET title = $1 WHERE id = $2",
+		"SELECT * FROM %s",
+		// potongan query ikut terambil, baru dilewati saat prepare (syntax_error)
+		"DELETE FROM",
+	}
+	if len(queries) != len(want) {
+		for _, q := range queries {
+			t.Logf("%s: %s", q.pos, oneLine(q.sql))
+		}
+		t.Fatalf("collected %d queries, want %d", len(queries), len(want))
+	}
+	for i, q := range queries {
+		if got := oneLine(q.sql); got != want[i] {
+			t.Errorf("query %d = %q, want %q", i, got, want[i])
+		}
+		if filepath.Base(q.pos.Filename) != "movie.repository.go" || q.pos.Line == 0 {
+			t.Errorf("query %d position %s", i, q.pos)
+		}
+	}
+	if !formatVerbRe.MatchString(queries[2].sql) || formatVerbRe.MatchString(queries[0].sql) {
+		t.Error("format template not told apart from plain query")
+	}
+}
+
+func TestCollectQueriesParseError(t *testing.T) {
+	dir := t.TempDir()
+	writeSource(t, dir, "broken.go", "package repositories\n\nfunc {")
+	if _, err := collectQueries(dir); err == nil {
+		t.Error("parse error swallowed")
+	}
+}
+
+// TestRepositoryQueriesMatchSchema menjalankan pengecekan yang sama dengan `go run ./cmd/schemacheck`
+// terhadap database test yang sudah dimigrasi
+func TestRepositoryQueriesMatchSchema(t *testing.T) {
+	env := testutil.Setup(t)
+	ctx := t.Context()
+
+	queries, err := collectQueries("../../internals/repositories")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queries) == 0 {
+		t.Fatal("no queries collected from repositories")
+	}
+	conn, err := env.DB.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Release()
+
+	checked, _, failed, err := checkQueries(ctx, conn.Conn().PgConn(), queries, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failed > 0 || checked == 0 {
+		t.Errorf("%d queries checked, %d do not match the schema", checked, failed)
+	}
+
+	// kolom yang tidak ada di schema harus terdeteksi, bukan dianggap fragment
+	broken := []query{{sql: "SELECT no_such_column FROM movies"}}
+	if _, _, failed, err := checkQueries(ctx, conn.Conn().PgConn(), broken, false); err != nil || failed != 1 {
+		t.Errorf("unknown column: failed %d, err %v", failed, err)
+	}
+	fragment := []query{{sql: "DELETE FROM "}}
+	if _, skipped, failed, err := checkQueries(ctx, conn.Conn().PgConn(), fragment, false); err != nil || skipped != 1 || failed != 0 {
+		t.Errorf("fragment: skipped %d, failed %d, err %v", skipped, failed, err)
+	}
+}
@@ -0,0 +1,15 @@
+// Package db menyimpan file SQL migrasi dan seed yang di-embed ke binary,
+// dipakai subcommand `tickitz migrate` dan `tickitz seed`
+package db
+
+import "embed"
+
+// Migrations file migrasi format golang-migrate: <versi>_<nama>.up.sql / .down.sql
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
+
+// Seeds data awal per profile: seeds/<profile>/*.sql, dijalankan urut nama file
+//
+//go:embed seeds
+var Seeds embed.FS
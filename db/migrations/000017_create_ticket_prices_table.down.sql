@@ -0,0 +1 @@
+DROP TABLE public.ticket_prices;
@@ -0,0 +1,22 @@
+-- public.ticket_prices definition
+
+-- Drop table
+
+-- DROP TABLE public.ticket_prices;
+
+CREATE TABLE public.ticket_prices (
+	id serial NOT NULL,
+	cinemas_id int4 NULL,
+	price int4 NOT NULL,
+	created_at timestamp DEFAULT now() NULL,
+	updated_at timestamp DEFAULT now() NULL,
+	CONSTRAINT "ticket_prices_pkey" PRIMARY KEY (id),
+	CONSTRAINT "ticket_prices_price_check" CHECK (price >= 0)
+);
+-- cinemas_id NULL adalah harga dasar default untuk semua cinema
+CREATE UNIQUE INDEX ticket_prices_cinemas_id_key ON public.ticket_prices USING btree ((COALESCE(cinemas_id, 0)));
+
+
+-- public.ticket_prices foreign keys
+
+ALTER TABLE public.ticket_prices ADD CONSTRAINT "ticket_prices_cinemas_id_fkey" FOREIGN KEY (cinemas_id) REFERENCES public.cinemas(id) ON DELETE CASCADE;
@@ -0,0 +1 @@
+DROP TABLE public.price_rules;
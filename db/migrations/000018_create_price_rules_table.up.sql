@@ -0,0 +1,27 @@
+-- public.price_rules definition
+
+-- Drop table
+
+-- DROP TABLE public.price_rules;
+
+CREATE TABLE public.price_rules (
+	id serial NOT NULL,
+	"name" varchar(100) NOT NULL,
+	cinemas_id int4 NULL,
+	seat_class varchar(20) NULL,
+	day_type varchar(10) NULL,
+	start_time time NULL,
+	end_time time NULL,
+	amount int4 NOT NULL,
+	is_active bool DEFAULT true NOT NULL,
+	created_at timestamp DEFAULT now() NULL,
+	updated_at timestamp DEFAULT now() NULL,
+	CONSTRAINT "price_rules_pkey" PRIMARY KEY (id),
+	CONSTRAINT "price_rules_day_type_check" CHECK (day_type IN ('weekday', 'weekend'))
+);
+CREATE INDEX idx_price_rules_cinemas_id ON public.price_rules USING btree (cinemas_id);
+
+
+-- public.price_rules foreign keys
+
+ALTER TABLE public.price_rules ADD CONSTRAINT "price_rules_cinemas_id_fkey" FOREIGN KEY (cinemas_id) REFERENCES public.cinemas(id) ON DELETE CASCADE;
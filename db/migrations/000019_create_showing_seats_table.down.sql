@@ -0,0 +1 @@
+DROP TABLE public.showing_seats;
@@ -0,0 +1,25 @@
+-- public.showing_seats definition
+
+-- Drop table
+
+-- DROP TABLE public.showing_seats;
+
+CREATE TABLE public.showing_seats (
+	id serial NOT NULL,
+	now_showing_id int4 NOT NULL,
+	seat_id int4 NOT NULL,
+	status varchar(20) DEFAULT 'available' NOT NULL,
+	user_id int4 NULL,
+	-- order yang membeli kursi ini (status = 'sold')
+	orders_id int4 NULL,
+	-- batas waktu kursi yang ditahan (status = 'held') sebelum checkout
+	held_until timestamp NULL,
+	created_at timestamp DEFAULT now() NULL,
+	updated_at timestamp DEFAULT now() NULL,
+	CONSTRAINT "showing_seats_pkey" PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_showing_seats_orders_id ON public.showing_seats USING btree (orders_id);
+CREATE INDEX idx_showing_seats_held_until ON public.showing_seats USING btree (held_until) WHERE status = 'held';
+
+ALTER TABLE public.showing_seats ADD CONSTRAINT "showing_seats_orders_id_fkey" FOREIGN KEY (orders_id) REFERENCES public.orders(id);
@@ -0,0 +1,26 @@
+-- public.payment_transactions definition
+
+-- Drop table
+
+-- DROP TABLE public.payment_transactions;
+
+CREATE TABLE public.payment_transactions (
+	id serial NOT NULL,
+	orders_id int4 NOT NULL,
+	provider varchar(30) NOT NULL,
+	reference varchar(100) NOT NULL,
+	amount int4 NOT NULL,
+	status varchar(20) DEFAULT 'pending' NOT NULL,
+	paid_at timestamp NULL,
+	created_at timestamp DEFAULT now() NULL,
+	updated_at timestamp DEFAULT now() NULL,
+	CONSTRAINT "payment_transactions_pkey" PRIMARY KEY (id),
+	CONSTRAINT "payment_transactions_reference_key" UNIQUE (reference),
+	CONSTRAINT "payment_transactions_status_check" CHECK (status IN ('pending', 'paid', 'failed', 'refunded'))
+);
+CREATE INDEX idx_payment_transactions_orders_id ON public.payment_transactions USING btree (orders_id);
+
+
+-- public.payment_transactions foreign keys
+
+ALTER TABLE public.payment_transactions ADD CONSTRAINT "payment_transactions_orders_id_fkey" FOREIGN KEY (orders_id) REFERENCES public.orders(id);
@@ -0,0 +1,3 @@
+-- status order: pending (belum bayar), paid, failed
+ALTER TABLE public.orders ADD COLUMN IF NOT EXISTS status varchar(20) DEFAULT 'pending' NOT NULL;
+UPDATE public.orders SET status = 'paid' WHERE "isPaid" = true;
@@ -0,0 +1,5 @@
+ALTER TABLE public.ticket DROP CONSTRAINT IF EXISTS "ticket_status_check";
+ALTER TABLE public.ticket DROP COLUMN IF EXISTS voided_at;
+ALTER TABLE public.ticket DROP COLUMN IF EXISTS status;
+ALTER TABLE public.orders DROP COLUMN IF EXISTS poin_awarded;
+ALTER TABLE public.orders DROP COLUMN IF EXISTS cancelled_at;
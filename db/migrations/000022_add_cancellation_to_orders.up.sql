@@ -0,0 +1,9 @@
+-- order yang dibatalkan: status = 'cancelled'
+ALTER TABLE public.orders ADD COLUMN IF NOT EXISTS cancelled_at timestamp NULL;
+-- poin yang sudah diberikan ke user untuk order ini, dikurangi kembali saat order dibatalkan
+ALTER TABLE public.orders ADD COLUMN IF NOT EXISTS poin_awarded float8 DEFAULT 0 NOT NULL;
+
+-- tiket dari order yang dibatalkan tidak berlaku lagi
+ALTER TABLE public.ticket ADD COLUMN IF NOT EXISTS status varchar(20) DEFAULT 'active' NOT NULL;
+ALTER TABLE public.ticket ADD COLUMN IF NOT EXISTS voided_at timestamp NULL;
+ALTER TABLE public.ticket ADD CONSTRAINT "ticket_status_check" CHECK (status IN ('active', 'void'));
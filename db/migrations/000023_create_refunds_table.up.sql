@@ -0,0 +1,31 @@
+-- public.refunds definition
+
+-- Drop table
+
+-- DROP TABLE public.refunds;
+
+CREATE TABLE public.refunds (
+	id serial NOT NULL,
+	orders_id int4 NOT NULL,
+	payment_transactions_id int4 NULL,
+	amount int4 NOT NULL,
+	reason text NULL,
+	status varchar(20) DEFAULT 'pending' NOT NULL,
+	-- jumlah percobaan ulang refund yang gagal di payment gateway, lihat RefundRepository.RetryFailedRefunds
+	attempts int4 DEFAULT 0 NOT NULL,
+	cancelled_by int4 NULL,
+	created_at timestamp DEFAULT now() NULL,
+	updated_at timestamp DEFAULT now() NULL,
+	CONSTRAINT "refunds_pkey" PRIMARY KEY (id),
+	CONSTRAINT "refunds_amount_check" CHECK (amount >= 0),
+	CONSTRAINT "refunds_status_check" CHECK (status IN ('pending', 'refunded', 'failed'))
+);
+CREATE INDEX idx_refunds_orders_id ON public.refunds USING btree (orders_id);
+CREATE INDEX idx_refunds_failed ON public.refunds USING btree (updated_at) WHERE status = 'failed';
+
+
+-- public.refunds foreign keys
+
+ALTER TABLE public.refunds ADD CONSTRAINT "refunds_orders_id_fkey" FOREIGN KEY (orders_id) REFERENCES public.orders(id);
+ALTER TABLE public.refunds ADD CONSTRAINT "refunds_payment_transactions_id_fkey" FOREIGN KEY (payment_transactions_id) REFERENCES public.payment_transactions(id);
+ALTER TABLE public.refunds ADD CONSTRAINT "refunds_cancelled_by_fkey" FOREIGN KEY (cancelled_by) REFERENCES public.users(id);
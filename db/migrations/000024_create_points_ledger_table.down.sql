@@ -0,0 +1,3 @@
+ALTER TABLE public.orders DROP COLUMN IF EXISTS discount;
+ALTER TABLE public.orders DROP COLUMN IF EXISTS poin_redeemed;
+DROP TABLE public.points_ledger;
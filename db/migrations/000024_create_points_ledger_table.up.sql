@@ -0,0 +1,40 @@
+-- public.points_ledger definition
+
+-- Drop table
+
+-- DROP TABLE public.points_ledger;
+
+CREATE TABLE public.points_ledger (
+	id serial NOT NULL,
+	users_id int4 NOT NULL,
+	orders_id int4 NULL,
+	entry_type varchar(10) NOT NULL,
+	points int4 NOT NULL,
+	description text NULL,
+	expires_at timestamp NULL,
+	created_by int4 NULL,
+	created_at timestamp DEFAULT now() NULL,
+	CONSTRAINT "points_ledger_pkey" PRIMARY KEY (id),
+	CONSTRAINT "points_ledger_entry_type_check" CHECK (entry_type IN ('earn', 'redeem', 'refund', 'expire', 'adjust'))
+);
+CREATE INDEX idx_points_ledger_users_id ON public.points_ledger USING btree (users_id, created_at);
+CREATE INDEX idx_points_ledger_orders_id ON public.points_ledger USING btree (orders_id);
+
+
+-- public.points_ledger foreign keys
+
+ALTER TABLE public.points_ledger ADD CONSTRAINT "points_ledger_users_id_fkey" FOREIGN KEY (users_id) REFERENCES public.users(id);
+ALTER TABLE public.points_ledger ADD CONSTRAINT "points_ledger_orders_id_fkey" FOREIGN KEY (orders_id) REFERENCES public.orders(id);
+ALTER TABLE public.points_ledger ADD CONSTRAINT "points_ledger_created_by_fkey" FOREIGN KEY (created_by) REFERENCES public.users(id);
+
+
+-- poin yang dipakai sebagai potongan harga pada order
+ALTER TABLE public.orders ADD COLUMN IF NOT EXISTS poin_redeemed int4 DEFAULT 0 NOT NULL;
+ALTER TABLE public.orders ADD COLUMN IF NOT EXISTS discount int4 DEFAULT 0 NOT NULL;
+
+-- saldo lama dipindahkan ke ledger sebagai saldo awal, sehingga users.poin = SUM(points_ledger.points)
+INSERT INTO public.points_ledger (users_id, entry_type, points, description)
+SELECT id, 'adjust', FLOOR(poin)::int4, 'Saldo awal'
+FROM public.users
+WHERE COALESCE(poin, 0) >= 1;
+UPDATE public.users SET poin = FLOOR(COALESCE(poin, 0));
@@ -0,0 +1,4 @@
+ALTER TABLE public.ticket DROP CONSTRAINT IF EXISTS "ticket_scanned_by_fkey";
+ALTER TABLE public.ticket DROP COLUMN IF EXISTS scanned_by;
+ALTER TABLE public.ticket DROP COLUMN IF EXISTS scanned_at;
+ALTER TABLE public.ticket ALTER COLUMN qr_code TYPE varchar(50) USING LEFT(qr_code, 50);
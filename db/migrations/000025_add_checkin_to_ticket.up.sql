@@ -0,0 +1,7 @@
+-- kode tiket bertanda tangan lebih panjang dari 50 karakter
+ALTER TABLE public.ticket ALTER COLUMN qr_code TYPE text;
+
+-- check-in di pintu studio, tiket hanya bisa di-scan sekali
+ALTER TABLE public.ticket ADD COLUMN IF NOT EXISTS scanned_at timestamp NULL;
+ALTER TABLE public.ticket ADD COLUMN IF NOT EXISTS scanned_by int4 NULL;
+ALTER TABLE public.ticket ADD CONSTRAINT "ticket_scanned_by_fkey" FOREIGN KEY (scanned_by) REFERENCES public.users(id);
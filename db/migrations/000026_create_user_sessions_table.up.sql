@@ -0,0 +1,25 @@
+-- public.user_sessions definition
+
+-- Drop table
+
+-- DROP TABLE public.user_sessions;
+
+-- satu baris per device/login, refresh token di dalamnya satu family
+CREATE TABLE public.user_sessions (
+	id uuid NOT NULL,
+	users_id int4 NOT NULL,
+	user_agent text NULL,
+	ip_address varchar(45) NULL,
+	created_at timestamp DEFAULT now() NULL,
+	last_used_at timestamp DEFAULT now() NULL,
+	expires_at timestamp NOT NULL,
+	revoked_at timestamp NULL,
+	revoke_reason varchar(30) NULL,
+	CONSTRAINT "user_sessions_pkey" PRIMARY KEY (id)
+);
+CREATE INDEX idx_user_sessions_users_id ON public.user_sessions USING btree (users_id);
+
+
+-- public.user_sessions foreign keys
+
+ALTER TABLE public.user_sessions ADD CONSTRAINT "user_sessions_users_id_fkey" FOREIGN KEY (users_id) REFERENCES public.users(id) ON DELETE CASCADE;
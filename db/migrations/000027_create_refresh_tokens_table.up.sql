@@ -0,0 +1,25 @@
+-- public.refresh_tokens definition
+
+-- Drop table
+
+-- DROP TABLE public.refresh_tokens;
+
+-- hanya hash sha256 token yang disimpan; token yang sudah dirotasi punya used_at
+CREATE TABLE public.refresh_tokens (
+	id serial NOT NULL,
+	session_id uuid NOT NULL,
+	token_hash varchar(64) NOT NULL,
+	parent_id int4 NULL,
+	expires_at timestamp NOT NULL,
+	used_at timestamp NULL,
+	created_at timestamp DEFAULT now() NULL,
+	CONSTRAINT "refresh_tokens_pkey" PRIMARY KEY (id),
+	CONSTRAINT "refresh_tokens_token_hash_key" UNIQUE (token_hash)
+);
+CREATE INDEX idx_refresh_tokens_session_id ON public.refresh_tokens USING btree (session_id);
+
+
+-- public.refresh_tokens foreign keys
+
+ALTER TABLE public.refresh_tokens ADD CONSTRAINT "refresh_tokens_session_id_fkey" FOREIGN KEY (session_id) REFERENCES public.user_sessions(id) ON DELETE CASCADE;
+ALTER TABLE public.refresh_tokens ADD CONSTRAINT "refresh_tokens_parent_id_fkey" FOREIGN KEY (parent_id) REFERENCES public.refresh_tokens(id);
@@ -0,0 +1,4 @@
+DELETE FROM public.blacklist_tokens WHERE "token" IS NULL;
+DROP INDEX IF EXISTS public.blacklist_tokens_jti_key;
+ALTER TABLE public.blacklist_tokens ALTER COLUMN "token" SET NOT NULL;
+ALTER TABLE public.blacklist_tokens DROP COLUMN IF EXISTS jti;
@@ -0,0 +1,7 @@
+-- token di-blacklist berdasarkan jti (atau sha256 token untuk token lama tanpa jti),
+-- data utama ada di Redis, tabel ini menjadi fallback yang tahan restart
+ALTER TABLE public.blacklist_tokens ADD COLUMN IF NOT EXISTS jti varchar(64) NULL;
+UPDATE public.blacklist_tokens SET jti = encode(sha256(convert_to("token", 'UTF8')), 'hex') WHERE jti IS NULL;
+ALTER TABLE public.blacklist_tokens ALTER COLUMN jti SET NOT NULL;
+ALTER TABLE public.blacklist_tokens ALTER COLUMN "token" DROP NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS blacklist_tokens_jti_key ON public.blacklist_tokens USING btree (jti);
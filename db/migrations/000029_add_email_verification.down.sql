@@ -0,0 +1,2 @@
+ALTER TABLE public.users DROP COLUMN IF EXISTS verified_at;
+ALTER TABLE public.users DROP COLUMN IF EXISTS is_verified;
@@ -0,0 +1,4 @@
+-- user baru harus verifikasi email sebelum bisa login; user lama dianggap sudah terverifikasi
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS is_verified bool DEFAULT false NOT NULL;
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS verified_at timestamp NULL;
+UPDATE public.users SET is_verified = true, verified_at = NOW() WHERE is_verified = false;
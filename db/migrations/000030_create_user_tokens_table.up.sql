@@ -0,0 +1,25 @@
+-- public.user_tokens definition
+
+-- Drop table
+
+-- DROP TABLE public.user_tokens;
+
+-- token sekali pakai untuk verifikasi email dan reset password, hanya hash sha256 yang disimpan
+CREATE TABLE public.user_tokens (
+	id serial NOT NULL,
+	users_id int4 NOT NULL,
+	purpose varchar(20) NOT NULL,
+	token_hash varchar(64) NOT NULL,
+	expires_at timestamp NOT NULL,
+	used_at timestamp NULL,
+	created_at timestamp DEFAULT now() NULL,
+	CONSTRAINT "user_tokens_pkey" PRIMARY KEY (id),
+	CONSTRAINT "user_tokens_token_hash_key" UNIQUE (token_hash),
+	CONSTRAINT "user_tokens_purpose_check" CHECK (purpose IN ('verify_email', 'reset_password'))
+);
+CREATE INDEX idx_user_tokens_users_id ON public.user_tokens USING btree (users_id, purpose);
+
+
+-- public.user_tokens foreign keys
+
+ALTER TABLE public.user_tokens ADD CONSTRAINT "user_tokens_users_id_fkey" FOREIGN KEY (users_id) REFERENCES public.users(id) ON DELETE CASCADE;
@@ -0,0 +1,8 @@
+-- cinemas_id, row dan seat_number tidak di-drop karena sudah dipakai sebelum migration ini
+ALTER TABLE public.cinemas DROP COLUMN IF EXISTS updated_at;
+DROP INDEX IF EXISTS public.idx_seats_cinemas_id;
+ALTER TABLE public.seats DROP CONSTRAINT IF EXISTS "seats_seat_class_check";
+ALTER TABLE public.seats DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE public.seats DROP COLUMN IF EXISTS aisle_after;
+ALTER TABLE public.seats DROP COLUMN IF EXISTS is_disabled;
+ALTER TABLE public.seats DROP COLUMN IF EXISTS seat_class;
@@ -0,0 +1,19 @@
+-- denah kursi per cinema diatur admin lewat /admin/cinemas/:id/layout
+-- (cinemas_id, row, seat_number sudah ada di sebagian database, karena itu IF NOT EXISTS)
+ALTER TABLE public.seats ADD COLUMN IF NOT EXISTS cinemas_id int4 NULL;
+ALTER TABLE public.seats ADD COLUMN IF NOT EXISTS "row" varchar(5) NULL;
+ALTER TABLE public.seats ADD COLUMN IF NOT EXISTS seat_number int4 NULL;
+ALTER TABLE public.seats ADD COLUMN IF NOT EXISTS seat_class varchar(20) DEFAULT 'regular' NOT NULL;
+-- kursi rusak/ditutup, tampil di denah tapi tidak bisa dipesan
+ALTER TABLE public.seats ADD COLUMN IF NOT EXISTS is_disabled bool DEFAULT false NOT NULL;
+-- ada lorong di sebelah kanan kursi ini
+ALTER TABLE public.seats ADD COLUMN IF NOT EXISTS aisle_after bool DEFAULT false NOT NULL;
+-- kursi yang dihapus dari denah tapi masih direferensikan showing_seats
+ALTER TABLE public.seats ADD COLUMN IF NOT EXISTS deleted_at timestamp NULL;
+ALTER TABLE public.seats ADD CONSTRAINT "seats_seat_class_check" CHECK (seat_class IN ('regular', 'love_nest', 'wheelchair'));
+CREATE INDEX IF NOT EXISTS idx_seats_cinemas_id ON public.seats USING btree (cinemas_id, "row", seat_number);
+
+-- sebelumnya love nest di-hardcode untuk F7-F10
+UPDATE public.seats SET seat_class = 'love_nest' WHERE "row" = 'F' AND seat_number BETWEEN 7 AND 10;
+
+ALTER TABLE public.cinemas ADD COLUMN IF NOT EXISTS updated_at timestamp DEFAULT now() NULL;
@@ -0,0 +1,8 @@
+-- cinemas_id tidak di-drop karena sudah dipakai sebelum migration ini
+DROP INDEX IF EXISTS public.idx_now_showing_cinemas_id_date;
+ALTER TABLE public.now_showing DROP CONSTRAINT IF EXISTS "now_showing_status_check";
+ALTER TABLE public.now_showing DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE public.now_showing DROP COLUMN IF EXISTS created_at;
+ALTER TABLE public.now_showing DROP COLUMN IF EXISTS cancel_reason;
+ALTER TABLE public.now_showing DROP COLUMN IF EXISTS cancelled_at;
+ALTER TABLE public.now_showing DROP COLUMN IF EXISTS status;
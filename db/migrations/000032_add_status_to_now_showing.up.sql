@@ -0,0 +1,12 @@
+-- jadwal tayang dikelola admin lewat /admin/showtimes
+-- (cinemas_id sudah ada di sebagian database, karena itu IF NOT EXISTS)
+ALTER TABLE public.now_showing ADD COLUMN IF NOT EXISTS cinemas_id int4 NULL;
+-- jadwal yang dibatalkan tidak dihapus karena masih direferensikan orders
+ALTER TABLE public.now_showing ADD COLUMN IF NOT EXISTS status varchar(20) DEFAULT 'scheduled' NOT NULL;
+ALTER TABLE public.now_showing ADD COLUMN IF NOT EXISTS cancelled_at timestamp NULL;
+ALTER TABLE public.now_showing ADD COLUMN IF NOT EXISTS cancel_reason text NULL;
+ALTER TABLE public.now_showing ADD COLUMN IF NOT EXISTS created_at timestamp DEFAULT now() NULL;
+ALTER TABLE public.now_showing ADD COLUMN IF NOT EXISTS updated_at timestamp DEFAULT now() NULL;
+ALTER TABLE public.now_showing ADD CONSTRAINT "now_showing_status_check" CHECK (status IN ('scheduled', 'cancelled'));
+-- cek bentrok jadwal per cinema (studio) per tanggal
+CREATE INDEX IF NOT EXISTS idx_now_showing_cinemas_id_date ON public.now_showing USING btree (cinemas_id, "date");
@@ -0,0 +1,4 @@
+-- movies_genre & movies_casts tidak di-drop karena sudah dipakai sebelum migration ini
+DROP INDEX IF EXISTS public.idx_movies_directors_id;
+DROP INDEX IF EXISTS public.idx_movies_casts_casts_id;
+DROP INDEX IF EXISTS public.idx_movies_genre_genres_id;
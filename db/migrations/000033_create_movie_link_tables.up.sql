@@ -0,0 +1,19 @@
+-- public.movies_genre & public.movies_casts definition
+-- (sudah dibuat manual di sebagian database, karena itu IF NOT EXISTS)
+
+CREATE TABLE IF NOT EXISTS public.movies_genre (
+	movies_id int4 NOT NULL,
+	genres_id int4 NOT NULL,
+	CONSTRAINT "movies_genre_pkey" PRIMARY KEY (movies_id, genres_id)
+);
+CREATE INDEX IF NOT EXISTS idx_movies_genre_genres_id ON public.movies_genre USING btree (genres_id);
+
+CREATE TABLE IF NOT EXISTS public.movies_casts (
+	movies_id int4 NOT NULL,
+	casts_id int4 NOT NULL,
+	CONSTRAINT "movies_casts_pkey" PRIMARY KEY (movies_id, casts_id)
+);
+CREATE INDEX IF NOT EXISTS idx_movies_casts_casts_id ON public.movies_casts USING btree (casts_id);
+
+-- daftar film per director (GET /directors/:id/movies)
+CREATE INDEX IF NOT EXISTS idx_movies_directors_id ON public.movies USING btree (directors_id);
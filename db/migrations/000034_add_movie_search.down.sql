@@ -0,0 +1,15 @@
+-- extension pg_trgm tidak di-drop karena bisa dipakai objek lain
+DROP INDEX IF EXISTS public.idx_casts_name_trgm;
+DROP INDEX IF EXISTS public.idx_directors_name_trgm;
+DROP INDEX IF EXISTS public.idx_movies_title_trgm;
+DROP INDEX IF EXISTS public.idx_movies_search_vector;
+DROP TRIGGER IF EXISTS casts_search_vector_update ON public.casts;
+DROP TRIGGER IF EXISTS directors_search_vector_update ON public.directors;
+DROP TRIGGER IF EXISTS movies_casts_search_vector_update ON public.movies_casts;
+DROP TRIGGER IF EXISTS movies_search_vector_update ON public.movies;
+DROP FUNCTION IF EXISTS public.casts_search_vector_trigger();
+DROP FUNCTION IF EXISTS public.directors_search_vector_trigger();
+DROP FUNCTION IF EXISTS public.movies_casts_search_vector_trigger();
+DROP FUNCTION IF EXISTS public.movies_search_vector_trigger();
+DROP FUNCTION IF EXISTS public.movie_search_document(int4, text, text, int4);
+ALTER TABLE public.movies DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,85 @@
+-- pencarian movie (GET /movies/search): full-text (tsvector) + fuzzy (pg_trgm)
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- dokumen pencarian: judul (A), director & cast (B), synopsis (C).
+-- config 'simple' karena judul dan synopsis bercampur bahasa Indonesia dan Inggris
+ALTER TABLE public.movies ADD COLUMN IF NOT EXISTS search_vector tsvector NULL;
+
+CREATE OR REPLACE FUNCTION public.movie_search_document(p_movie_id int4, p_title text, p_synopsis text, p_directors_id int4)
+RETURNS tsvector
+LANGUAGE sql STABLE AS $$
+	SELECT setweight(to_tsvector('simple', COALESCE(p_title, '')), 'A')
+		|| setweight(to_tsvector('simple', COALESCE((SELECT d."name" FROM public.directors d WHERE d.id = p_directors_id), '')), 'B')
+		|| setweight(to_tsvector('simple', COALESCE((
+			SELECT string_agg(c."name", ' ')
+			FROM public.movies_casts mc
+			JOIN public.casts c ON c.id = mc.casts_id
+			WHERE mc.movies_id = p_movie_id
+		), '')), 'B')
+		|| setweight(to_tsvector('simple', COALESCE(p_synopsis, '')), 'C')
+$$;
+
+-- movie dibuat / diubah
+CREATE OR REPLACE FUNCTION public.movies_search_vector_trigger()
+RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+	NEW.search_vector := public.movie_search_document(NEW.id, NEW.title, NEW.synopsis, NEW.directors_id);
+	RETURN NEW;
+END
+$$;
+CREATE TRIGGER movies_search_vector_update
+	BEFORE INSERT OR UPDATE OF title, synopsis, directors_id ON public.movies
+	FOR EACH ROW EXECUTE FUNCTION public.movies_search_vector_trigger();
+
+-- cast movie ditambah / dihapus
+CREATE OR REPLACE FUNCTION public.movies_casts_search_vector_trigger()
+RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+	UPDATE public.movies m
+	SET search_vector = public.movie_search_document(m.id, m.title, m.synopsis, m.directors_id)
+	WHERE m.id IN (OLD.movies_id, NEW.movies_id);
+	RETURN NULL;
+END
+$$;
+CREATE TRIGGER movies_casts_search_vector_update
+	AFTER INSERT OR UPDATE OR DELETE ON public.movies_casts
+	FOR EACH ROW EXECUTE FUNCTION public.movies_casts_search_vector_trigger();
+
+-- nama director / cast diubah
+CREATE OR REPLACE FUNCTION public.directors_search_vector_trigger()
+RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+	UPDATE public.movies m
+	SET search_vector = public.movie_search_document(m.id, m.title, m.synopsis, m.directors_id)
+	WHERE m.directors_id = NEW.id;
+	RETURN NULL;
+END
+$$;
+CREATE TRIGGER directors_search_vector_update
+	AFTER UPDATE OF "name" ON public.directors
+	FOR EACH ROW EXECUTE FUNCTION public.directors_search_vector_trigger();
+
+CREATE OR REPLACE FUNCTION public.casts_search_vector_trigger()
+RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+	UPDATE public.movies m
+	SET search_vector = public.movie_search_document(m.id, m.title, m.synopsis, m.directors_id)
+	WHERE m.id IN (SELECT mc.movies_id FROM public.movies_casts mc WHERE mc.casts_id = NEW.id);
+	RETURN NULL;
+END
+$$;
+CREATE TRIGGER casts_search_vector_update
+	AFTER UPDATE OF "name" ON public.casts
+	FOR EACH ROW EXECUTE FUNCTION public.casts_search_vector_trigger();
+
+UPDATE public.movies m SET search_vector = public.movie_search_document(m.id, m.title, m.synopsis, m.directors_id);
+
+CREATE INDEX IF NOT EXISTS idx_movies_search_vector ON public.movies USING gin (search_vector);
+-- fuzzy match judul & nama (salah ketik)
+CREATE INDEX IF NOT EXISTS idx_movies_title_trgm ON public.movies USING gin (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_directors_name_trgm ON public.directors USING gin ("name" gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_casts_name_trgm ON public.casts USING gin ("name" gin_trgm_ops);
@@ -0,0 +1,2 @@
+DROP MATERIALIZED VIEW IF EXISTS public.movie_stats;
+DROP TABLE public.movie_reviews;
@@ -0,0 +1,70 @@
+-- public.movie_reviews definition
+
+-- Drop table
+
+-- DROP TABLE public.movie_reviews;
+
+-- satu review per user per movie, hanya user dengan order paid untuk movie tersebut
+CREATE TABLE public.movie_reviews (
+	id serial NOT NULL,
+	movies_id int4 NOT NULL,
+	users_id int4 NOT NULL,
+	rating int2 NOT NULL,
+	body text NULL,
+	status varchar(20) DEFAULT 'published' NOT NULL,
+	moderated_by int4 NULL,
+	moderation_note text NULL,
+	moderated_at timestamp NULL,
+	created_at timestamp DEFAULT now() NULL,
+	updated_at timestamp DEFAULT now() NULL,
+	CONSTRAINT "movie_reviews_pkey" PRIMARY KEY (id),
+	CONSTRAINT "movie_reviews_movies_id_users_id_key" UNIQUE (movies_id, users_id),
+	CONSTRAINT "movie_reviews_rating_check" CHECK (rating BETWEEN 1 AND 5),
+	CONSTRAINT "movie_reviews_status_check" CHECK (status IN ('published', 'hidden'))
+);
+CREATE INDEX idx_movie_reviews_movies_id ON public.movie_reviews USING btree (movies_id, status, created_at);
+
+
+-- public.movie_reviews foreign keys
+
+ALTER TABLE public.movie_reviews ADD CONSTRAINT "movie_reviews_movies_id_fkey" FOREIGN KEY (movies_id) REFERENCES public.movies(id) ON DELETE CASCADE;
+ALTER TABLE public.movie_reviews ADD CONSTRAINT "movie_reviews_users_id_fkey" FOREIGN KEY (users_id) REFERENCES public.users(id) ON DELETE CASCADE;
+ALTER TABLE public.movie_reviews ADD CONSTRAINT "movie_reviews_moderated_by_fkey" FOREIGN KEY (moderated_by) REFERENCES public.users(id);
+
+
+-- public.movie_stats definition
+
+-- rating dari review (published) dan penjualan tiket per movie, di-refresh berkala oleh aplikasi.
+-- bayes_rating menarik rata-rata movie dengan sedikit review ke arah 3.0 (bobot 10 review)
+-- popularity_score = tiket terjual 30 hari terakhir x bayes_rating
+CREATE MATERIALIZED VIEW public.movie_stats AS
+WITH reviews AS (
+	SELECT movies_id, COUNT(*) AS review_count, AVG(rating)::float8 AS avg_rating
+	FROM public.movie_reviews
+	WHERE status = 'published'
+	GROUP BY movies_id
+),
+sales AS (
+	SELECT ns.movie_id AS movies_id,
+		COUNT(*) AS tickets_sold,
+		COUNT(*) FILTER (WHERE o.created_at >= NOW() - INTERVAL '30 days') AS tickets_sold_30d
+	FROM public.showing_seats ss
+	JOIN public.orders o ON o.id = ss.orders_id
+	JOIN public.now_showing ns ON ns.id = o.now_showing_id
+	WHERE o.status = 'paid'
+	GROUP BY ns.movie_id
+)
+SELECT m.id AS movies_id,
+	COALESCE(r.review_count, 0)::int4 AS review_count,
+	r.avg_rating,
+	((COALESCE(r.review_count, 0) * COALESCE(r.avg_rating, 0) + 10 * 3.0) / (COALESCE(r.review_count, 0) + 10))::float8 AS bayes_rating,
+	COALESCE(s.tickets_sold, 0)::int4 AS tickets_sold,
+	COALESCE(s.tickets_sold_30d, 0)::int4 AS tickets_sold_30d,
+	(COALESCE(s.tickets_sold_30d, 0) * (COALESCE(r.review_count, 0) * COALESCE(r.avg_rating, 0) + 10 * 3.0) / (COALESCE(r.review_count, 0) + 10))::float8 AS popularity_score,
+	NOW() AS refreshed_at
+FROM public.movies m
+LEFT JOIN reviews r ON r.movies_id = m.id
+LEFT JOIN sales s ON s.movies_id = m.id;
+
+-- unique index wajib untuk REFRESH MATERIALIZED VIEW CONCURRENTLY
+CREATE UNIQUE INDEX idx_movie_stats_movies_id ON public.movie_stats USING btree (movies_id);
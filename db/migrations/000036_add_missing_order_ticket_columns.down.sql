@@ -0,0 +1,8 @@
+ALTER TABLE public.movies DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE public.movies DROP COLUMN IF EXISTS created_at;
+ALTER TABLE public.orders_ticket DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE public.orders_ticket DROP COLUMN IF EXISTS created_at;
+ALTER TABLE public.ticket DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE public.ticket DROP COLUMN IF EXISTS created_at;
+ALTER TABLE public.orders DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE public.orders DROP COLUMN IF EXISTS cinemas_id;
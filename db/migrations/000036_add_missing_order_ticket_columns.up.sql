@@ -0,0 +1,28 @@
+-- kolom yang sudah dipakai repository tapi belum pernah dibuat migration
+-- (sebagian sudah ditambahkan manual di database lama, karena itu IF NOT EXISTS)
+
+-- cinema (studio) tempat order, dipakai CreateOrder untuk mencari kursi
+ALTER TABLE public.orders ADD COLUMN IF NOT EXISTS cinemas_id int4 NULL;
+ALTER TABLE public.orders ADD COLUMN IF NOT EXISTS updated_at timestamp DEFAULT now() NULL;
+-- order lama: cinema diambil dari jadwal, lalu dari tabel relasi orders_cinema
+UPDATE public.orders o SET cinemas_id = ns.cinemas_id
+FROM public.now_showing ns
+WHERE o.cinemas_id IS NULL AND ns.id = o.now_showing_id AND ns.cinemas_id IS NOT NULL;
+UPDATE public.orders o SET cinemas_id = oc.cinema_id
+FROM public.orders_cinema oc
+WHERE o.cinemas_id IS NULL AND oc.orders_id = o.id;
+UPDATE public.orders SET updated_at = COALESCE(cancelled_at, created_at, now()) WHERE updated_at IS NULL;
+
+ALTER TABLE public.ticket ADD COLUMN IF NOT EXISTS created_at timestamp DEFAULT now() NULL;
+ALTER TABLE public.ticket ADD COLUMN IF NOT EXISTS updated_at timestamp DEFAULT now() NULL;
+UPDATE public.ticket t SET created_at = o.created_at, updated_at = COALESCE(t.scanned_at, t.voided_at, o.created_at)
+FROM public.orders_ticket ot
+JOIN public.orders o ON o.id = ot.orders_id
+WHERE ot.ticket_id = t.id AND o.created_at IS NOT NULL;
+
+ALTER TABLE public.orders_ticket ADD COLUMN IF NOT EXISTS created_at timestamp DEFAULT now() NULL;
+ALTER TABLE public.orders_ticket ADD COLUMN IF NOT EXISTS updated_at timestamp DEFAULT now() NULL;
+
+-- UpdateMovie mencatat waktu perubahan
+ALTER TABLE public.movies ADD COLUMN IF NOT EXISTS created_at timestamp DEFAULT now() NULL;
+ALTER TABLE public.movies ADD COLUMN IF NOT EXISTS updated_at timestamp DEFAULT now() NULL;
@@ -0,0 +1,16 @@
+ALTER TABLE public.movies_casts DROP CONSTRAINT IF EXISTS "movies_casts_casts_id_fkey";
+ALTER TABLE public.movies_casts DROP CONSTRAINT IF EXISTS "movies_casts_movies_id_fkey";
+ALTER TABLE public.movies_genre DROP CONSTRAINT IF EXISTS "movies_genre_genres_id_fkey";
+ALTER TABLE public.movies_genre DROP CONSTRAINT IF EXISTS "movies_genre_movies_id_fkey";
+ALTER TABLE public.showing_seats DROP CONSTRAINT IF EXISTS "showing_seats_user_id_fkey";
+ALTER TABLE public.showing_seats DROP CONSTRAINT IF EXISTS "showing_seats_seat_id_fkey";
+ALTER TABLE public.showing_seats DROP CONSTRAINT IF EXISTS "showing_seats_now_showing_id_fkey";
+ALTER TABLE public.orders DROP CONSTRAINT IF EXISTS "orders_cinemas_id_fkey";
+ALTER TABLE public.seats DROP CONSTRAINT IF EXISTS "seats_cinemas_id_fkey";
+ALTER TABLE public.now_showing DROP CONSTRAINT IF EXISTS "now_showing_cinemas_id_fkey";
+
+CREATE INDEX IF NOT EXISTS idx_seats_cinemas_id ON public.seats USING btree (cinemas_id, "row", seat_number);
+ALTER TABLE public.seats DROP CONSTRAINT IF EXISTS "seats_cinemas_id_row_seat_number_key";
+ALTER TABLE public.showing_seats DROP CONSTRAINT IF EXISTS "showing_seats_status_check";
+ALTER TABLE public.showing_seats DROP CONSTRAINT IF EXISTS "showing_seats_now_showing_id_seat_id_key";
+ALTER TABLE public.users DROP CONSTRAINT IF EXISTS "users_email_key";
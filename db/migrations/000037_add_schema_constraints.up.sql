@@ -0,0 +1,69 @@
+-- unique constraint & foreign key yang selama ini hanya dijaga kode aplikasi.
+-- Data duplikat tidak dihapus otomatis: migration berhenti dan menyebutkan data yang harus dirapikan dulu
+DO $$
+DECLARE
+	duplicates text;
+BEGIN
+	SELECT string_agg(email, ', ') INTO duplicates
+	FROM (SELECT email FROM public.users GROUP BY email HAVING COUNT(*) > 1) d;
+	IF duplicates IS NOT NULL THEN
+		RAISE EXCEPTION 'duplicate users.email, merge these accounts before migrating: %', duplicates;
+	END IF;
+
+	SELECT string_agg(format('(now_showing_id=%s, seat_id=%s)', now_showing_id, seat_id), ', ') INTO duplicates
+	FROM (SELECT now_showing_id, seat_id FROM public.showing_seats GROUP BY now_showing_id, seat_id HAVING COUNT(*) > 1) d;
+	IF duplicates IS NOT NULL THEN
+		RAISE EXCEPTION 'duplicate showing_seats rows (seat booked twice), resolve them before migrating: %', duplicates;
+	END IF;
+
+	SELECT string_agg(format('(cinemas_id=%s, %s%s)', cinemas_id, "row", seat_number), ', ') INTO duplicates
+	FROM (SELECT cinemas_id, "row", seat_number FROM public.seats WHERE cinemas_id IS NOT NULL
+		  GROUP BY cinemas_id, "row", seat_number HAVING COUNT(*) > 1) d;
+	IF duplicates IS NOT NULL THEN
+		RAISE EXCEPTION 'duplicate seats in a cinema layout, resolve them before migrating: %', duplicates;
+	END IF;
+END
+$$;
+
+-- Register mengenali email ganda dari nama constraint users_email_key
+ALTER TABLE public.users DROP CONSTRAINT IF EXISTS "users_email_key";
+ALTER TABLE public.users ADD CONSTRAINT "users_email_key" UNIQUE (email);
+
+-- satu baris status (held / sold) per kursi per jadwal, mencegah kursi terjual dua kali
+ALTER TABLE public.showing_seats DROP CONSTRAINT IF EXISTS "showing_seats_now_showing_id_seat_id_key";
+ALTER TABLE public.showing_seats ADD CONSTRAINT "showing_seats_now_showing_id_seat_id_key" UNIQUE (now_showing_id, seat_id);
+ALTER TABLE public.showing_seats DROP CONSTRAINT IF EXISTS "showing_seats_status_check";
+ALTER TABLE public.showing_seats ADD CONSTRAINT "showing_seats_status_check" CHECK (status IN ('available', 'held', 'sold'));
+
+-- satu kursi per posisi di denah (kursi yang di-soft delete tetap memegang posisinya)
+ALTER TABLE public.seats DROP CONSTRAINT IF EXISTS "seats_cinemas_id_row_seat_number_key";
+ALTER TABLE public.seats ADD CONSTRAINT "seats_cinemas_id_row_seat_number_key" UNIQUE (cinemas_id, "row", seat_number);
+-- digantikan unique constraint di atas
+DROP INDEX IF EXISTS public.idx_seats_cinemas_id;
+
+ALTER TABLE public.now_showing DROP CONSTRAINT IF EXISTS "now_showing_cinemas_id_fkey";
+ALTER TABLE public.now_showing ADD CONSTRAINT "now_showing_cinemas_id_fkey" FOREIGN KEY (cinemas_id) REFERENCES public.cinemas(id);
+
+ALTER TABLE public.seats DROP CONSTRAINT IF EXISTS "seats_cinemas_id_fkey";
+ALTER TABLE public.seats ADD CONSTRAINT "seats_cinemas_id_fkey" FOREIGN KEY (cinemas_id) REFERENCES public.cinemas(id);
+
+ALTER TABLE public.orders DROP CONSTRAINT IF EXISTS "orders_cinemas_id_fkey";
+ALTER TABLE public.orders ADD CONSTRAINT "orders_cinemas_id_fkey" FOREIGN KEY (cinemas_id) REFERENCES public.cinemas(id);
+
+-- kursi yang hanya ditahan ikut terhapus bersama jadwalnya, kursi terjual tetap tertahan FK orders
+ALTER TABLE public.showing_seats DROP CONSTRAINT IF EXISTS "showing_seats_now_showing_id_fkey";
+ALTER TABLE public.showing_seats ADD CONSTRAINT "showing_seats_now_showing_id_fkey" FOREIGN KEY (now_showing_id) REFERENCES public.now_showing(id) ON DELETE CASCADE;
+ALTER TABLE public.showing_seats DROP CONSTRAINT IF EXISTS "showing_seats_seat_id_fkey";
+ALTER TABLE public.showing_seats ADD CONSTRAINT "showing_seats_seat_id_fkey" FOREIGN KEY (seat_id) REFERENCES public.seats(id);
+ALTER TABLE public.showing_seats DROP CONSTRAINT IF EXISTS "showing_seats_user_id_fkey";
+ALTER TABLE public.showing_seats ADD CONSTRAINT "showing_seats_user_id_fkey" FOREIGN KEY (user_id) REFERENCES public.users(id);
+
+ALTER TABLE public.movies_genre DROP CONSTRAINT IF EXISTS "movies_genre_movies_id_fkey";
+ALTER TABLE public.movies_genre ADD CONSTRAINT "movies_genre_movies_id_fkey" FOREIGN KEY (movies_id) REFERENCES public.movies(id) ON DELETE CASCADE;
+ALTER TABLE public.movies_genre DROP CONSTRAINT IF EXISTS "movies_genre_genres_id_fkey";
+ALTER TABLE public.movies_genre ADD CONSTRAINT "movies_genre_genres_id_fkey" FOREIGN KEY (genres_id) REFERENCES public.genres(id);
+
+ALTER TABLE public.movies_casts DROP CONSTRAINT IF EXISTS "movies_casts_movies_id_fkey";
+ALTER TABLE public.movies_casts ADD CONSTRAINT "movies_casts_movies_id_fkey" FOREIGN KEY (movies_id) REFERENCES public.movies(id) ON DELETE CASCADE;
+ALTER TABLE public.movies_casts DROP CONSTRAINT IF EXISTS "movies_casts_casts_id_fkey";
+ALTER TABLE public.movies_casts ADD CONSTRAINT "movies_casts_casts_id_fkey" FOREIGN KEY (casts_id) REFERENCES public.casts(id);
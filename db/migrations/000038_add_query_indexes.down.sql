@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS public.idx_now_showing_movie_id;
+DROP INDEX IF EXISTS public.idx_showing_seats_seat_id;
+DROP INDEX IF EXISTS public.idx_orders_ticket_ticket_id;
+DROP INDEX IF EXISTS public.idx_orders_ticket_orders_id;
+DROP INDEX IF EXISTS public.idx_orders_cinemas_id;
+DROP INDEX IF EXISTS public.idx_orders_now_showing_id;
+DROP INDEX IF EXISTS public.idx_orders_users_id;
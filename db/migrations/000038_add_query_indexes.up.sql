@@ -0,0 +1,16 @@
+-- index untuk query yang sering dipanggil repository
+
+-- riwayat order per user & kursi / penjualan per jadwal
+CREATE INDEX IF NOT EXISTS idx_orders_users_id ON public.orders USING btree (users_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_orders_now_showing_id ON public.orders USING btree (now_showing_id);
+CREATE INDEX IF NOT EXISTS idx_orders_cinemas_id ON public.orders USING btree (cinemas_id);
+
+-- tiket per order (GET /orders/:id/ticket, scan & void)
+CREATE INDEX IF NOT EXISTS idx_orders_ticket_orders_id ON public.orders_ticket USING btree (orders_id);
+CREATE INDEX IF NOT EXISTS idx_orders_ticket_ticket_id ON public.orders_ticket USING btree (ticket_id);
+
+-- cek kursi masih dipakai saat denah diubah
+CREATE INDEX IF NOT EXISTS idx_showing_seats_seat_id ON public.showing_seats USING btree (seat_id);
+
+-- jadwal per movie
+CREATE INDEX IF NOT EXISTS idx_now_showing_movie_id ON public.now_showing USING btree (movie_id, "date");
@@ -1,4 +0,0 @@
-INSERT INTO public."location" (id,"name") VALUES
-	 (1,'XXI'),
-	 (2,'CINEPLEX'),
-	 (3,'CGV');
@@ -1,4 +0,0 @@
-INSERT INTO public.casts (id,"name") VALUES
-	 (1,'Leonardo DiCaprio'),
-	 (2,'Sam Worthington'),
-	 (3,'Jeff Goldblum');
@@ -1,4 +0,0 @@
-INSERT INTO public.cinemas (id,cinema_name,created_at) VALUES
-	 (1,'XXI Plaza Indonesia','2025-08-28 09:55:33.930974'),
-	 (2,'CGV Pacific Place','2025-08-28 09:55:33.930974'),
-	 (3,'Cinepolis FX Sudirman','2025-08-28 09:55:33.930974');
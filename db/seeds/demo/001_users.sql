@@ -0,0 +1,12 @@
+-- akun demo (sudah terverifikasi):
+--   admin@tickitz.id / Admin#2025 (admin)
+--   user@tickitz.id  / User#2025  (user)
+INSERT INTO public.users (id, email, "password", poin, "role", is_verified, verified_at) VALUES
+	(1, 'admin@tickitz.id', '$argon2id$v=19$m=65536,t=2,p=1$95chj8RBYo63312khbXewA$gBRy7dcOV7w1x0nS9ydczdN39TMo2Wn8YVcffXcVIkE', 0, 'admin', true, now()),
+	(2, 'user@tickitz.id', '$argon2id$v=19$m=65536,t=2,p=1$qOOqPwsEI/3eBNMgYLh2vA$mTcRa2TIrmXn8XTm4xVX8uSK21pm0I8dfxDszc8gRWg', 0, 'user', true, now())
+ON CONFLICT DO NOTHING;
+
+INSERT INTO public.profile (id, first_name, last_name, phone_number, profile_picture) VALUES
+	(1, 'Admin', 'Tickitz', '081200000001', ''),
+	(2, 'Demo', 'User', '081200000002', '')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,45 @@
+INSERT INTO public."location" (id, "name") VALUES
+	(1, 'XXI'),
+	(2, 'CINEPLEX'),
+	(3, 'CGV')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO public.cinemas (id, cinema_name) VALUES
+	(1, 'XXI Plaza Indonesia'),
+	(2, 'CGV Pacific Place'),
+	(3, 'Cinepolis FX Sudirman')
+ON CONFLICT DO NOTHING;
+
+-- denah kursi A-F x 1-14 untuk cinema yang belum punya denah, lorong setelah kursi 7,
+-- F7-F10 love nest dan A1-A2 wheelchair
+INSERT INTO public.seats (cinemas_id, "row", seat_number, seat_class, aisle_after)
+SELECT c.id, r."row", n.seat_number,
+	CASE
+		WHEN r."row" = 'F' AND n.seat_number BETWEEN 7 AND 10 THEN 'love_nest'
+		WHEN r."row" = 'A' AND n.seat_number <= 2 THEN 'wheelchair'
+		ELSE 'regular'
+	END,
+	n.seat_number = 7
+FROM public.cinemas c
+CROSS JOIN unnest(ARRAY['A', 'B', 'C', 'D', 'E', 'F']) AS r("row")
+CROSS JOIN generate_series(1, 14) AS n(seat_number)
+WHERE c.id IN (1, 2, 3)
+	AND NOT EXISTS (SELECT 1 FROM public.seats s WHERE s.cinemas_id = c.id);
+
+INSERT INTO public.payment (id, "method") VALUES
+	(1, 'Credit Card'),
+	(2, 'OVO'),
+	(3, 'GoPay')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO public.ticket_prices (id, cinemas_id, price) VALUES
+	(1, NULL, 50000),
+	(2, 2, 55000)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO public.price_rules (id, "name", cinemas_id, seat_class, day_type, start_time, end_time, amount, is_active) VALUES
+	(1, 'Love Nest', NULL, 'love_nest', NULL, NULL, NULL, 15000, true),
+	(2, 'Weekend', NULL, NULL, 'weekend', NULL, NULL, 10000, true),
+	(3, 'Matinee', NULL, NULL, 'weekday', NULL, '12:00:00', -10000, true),
+	(4, 'Prime Time', NULL, NULL, NULL, '18:00:00', '22:00:00', 5000, true)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,44 @@
+INSERT INTO public.genres (id, "name") VALUES
+	(1, 'Action'),
+	(2, 'Sci-Fi'),
+	(3, 'Adventure'),
+	(4, 'Drama')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO public.directors (id, "name") VALUES
+	(1, 'Christopher Nolan'),
+	(2, 'James Cameron'),
+	(3, 'Steven Spielberg')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO public.casts (id, "name") VALUES
+	(1, 'Leonardo DiCaprio'),
+	(2, 'Sam Worthington'),
+	(3, 'Jeff Goldblum'),
+	(4, 'Christian Bale'),
+	(5, 'Matthew McConaughey')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO public.movies (id, title, synopsis, duration_minutes, release_date, poster_image, directors_id, rating, bg_path) VALUES
+	(1, 'Inception', 'A skilled thief enters dreams to steal secrets and plant ideas.', 148, '2010-07-16', 'inception_poster.jpg', 1, 8.8, 'inception_bg.jpg'),
+	(2, 'Avatar: The Way of Water', 'Jake Sully and Neytiri have formed a family and must protect it.', 192, '2022-12-16', 'avatar2_poster.jpg', 2, 7.6, NULL),
+	(3, 'Jurassic Park', 'Dinosaurs are brought back to life on a remote island park.', 127, '1993-06-11', 'jurassic.jpg', 3, 8.2, NULL),
+	(4, 'The Dark Knight', 'Batman faces the Joker, a criminal mastermind spreading chaos.', 152, '2008-07-18', 'dark_knight_poster.jpg', 1, 9.0, 'dark_knight_bg.jpg'),
+	(5, 'Interstellar', 'Explorers travel through a wormhole in space to save humanity.', 169, '2014-11-07', 'interstellar_poster.jpg', 1, 8.6, 'interstellar_bg.jpg')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO public.movies_genre (movies_id, genres_id) VALUES
+	(1, 1), (1, 2),
+	(2, 2), (2, 3),
+	(3, 3),
+	(4, 1), (4, 4),
+	(5, 2), (5, 4)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO public.movies_casts (movies_id, casts_id) VALUES
+	(1, 1),
+	(2, 2),
+	(3, 3),
+	(4, 4),
+	(5, 5)
+ON CONFLICT DO NOTHING;
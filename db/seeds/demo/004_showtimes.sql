@@ -0,0 +1,9 @@
+-- jadwal relatif terhadap tanggal seed supaya selalu ada jadwal yang bisa dipesan
+INSERT INTO public.now_showing (id, "date", "time", location_id, movie_id, cinemas_id) VALUES
+	(1, CURRENT_DATE + 1, '13:00', 1, 1, 1),
+	(2, CURRENT_DATE + 1, '19:00', 1, 4, 1),
+	(3, CURRENT_DATE + 1, '16:00', 3, 2, 2),
+	(4, CURRENT_DATE + 2, '19:30', 3, 5, 2),
+	(5, CURRENT_DATE + 2, '14:00', 2, 3, 3),
+	(6, CURRENT_DATE + 3, '20:00', 2, 1, 3)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,21 @@
+-- id di atas ditulis eksplisit, sequence dinaikkan supaya insert berikutnya dari aplikasi tidak bentrok
+DO $$
+DECLARE
+	t text;
+	seq text;
+	max_id bigint;
+BEGIN
+	FOREACH t IN ARRAY ARRAY['users', 'profile', 'location', 'cinemas', 'seats', 'payment', 'ticket_prices', 'price_rules',
+		'genres', 'directors', 'casts', 'movies', 'now_showing'] LOOP
+		SELECT substring(column_default FROM 'nextval\(''"?([^"'']+)"?''') INTO seq
+		FROM information_schema.columns
+		WHERE table_schema = 'public' AND table_name = t AND column_name = 'id';
+		CONTINUE WHEN seq IS NULL;
+
+		EXECUTE format('SELECT max(id) FROM public.%I', t) INTO max_id;
+		IF max_id IS NOT NULL THEN
+			PERFORM setval(seq, GREATEST(max_id, (SELECT last_value FROM pg_sequences WHERE schemaname = 'public' AND sequencename = seq)));
+		END IF;
+	END LOOP;
+END
+$$;
@@ -1,4 +0,0 @@
-INSERT INTO public.directors (id,"name") VALUES
-	 (1,'Christopher Nolan'),
-	 (2,'James Cameron'),
-	 (3,'Steven Spielberg');
@@ -1,4 +0,0 @@
-INSERT INTO public.genres (id,"name") VALUES
-	 (1,'Action'),
-	 (2,'Sci-Fi'),
-	 (3,'Adventure');
@@ -1,27 +0,0 @@
-INSERT INTO public.movies (id,title,synopsis,duration_minutes,release_date,poster_image,directors_id,rating,bg_path,is_deleted,deleted_at) VALUES
-	 (1,'Inception','Dream within a dream',148,'2010-07-16','inception.jpg',1,9.0,NULL,false,NULL),
-	 (3,'Jurassic Park','Dinosaurs revived',127,'1993-06-11','jurassic.jpg',3,8.0,NULL,false,NULL),
-	 (7,'Avengers: Secret Wars','The multiverse saga reaches its climax',148,'2026-05-01','secretwars.jpg',1,NULL,NULL,false,NULL),
-	 (2,'Avatar: The Way of Water','Jake Sully and Neytiri have formed a family....',192,'2022-12-16','avatar2_poster.jpg',1,9.4,NULL,false,NULL),
-	 (48,'Inception(1)','A skilled thief enters dreams to steal secrets and plant ideas.',148,'2010-07-16','inception_poster.jpg',1,8.8,'inception_bg.jpg',false,NULL),
-	 (49,'The Dark Knight','Batman faces the Joker, a criminal mastermind spreading chaos.',152,'2008-07-18','dark_knight_poster.jpg',1,9.0,'dark_knight_bg.jpg',false,NULL),
-	 (50,'Interstellar','Explorers travel through a wormhole in space to save humanity.',169,'2014-11-07','interstellar_poster.jpg',1,8.6,'interstellar_bg.jpg',false,NULL),
-	 (51,'The Matrix','A hacker discovers reality is a simulation controlled by machines.',136,'1999-03-31','matrix_poster.jpg',2,8.7,'matrix_bg.jpg',false,NULL),
-	 (52,'The Matrix Reloaded','Neo continues his fight against the machines as the prophecy unfolds.',138,'2003-05-15','matrix_reloaded_poster.jpg',2,7.2,'matrix_reloaded_bg.jpg',false,NULL),
-	 (53,'The Matrix Revolutions','The final battle between humans and machines reaches its climax.',129,'2003-11-05','matrix_revolutions_poster.jpg',2,6.8,'matrix_revolutions_bg.jpg',false,NULL);
-INSERT INTO public.movies (id,title,synopsis,duration_minutes,release_date,poster_image,directors_id,rating,bg_path,is_deleted,deleted_at) VALUES
-	 (54,'Avatar','A marine is torn between following orders and protecting Pandora.',162,'2009-12-18','avatar_poster.jpg',3,7.8,'avatar_bg.jpg',false,NULL),
-	 (55,'Avatar: The Way of Water','Jake Sully and Neytiri protect their family from new threats.',192,'2022-12-16','avatar2_poster.jpg',3,7.6,'avatar2_bg.jpg',false,NULL),
-	 (56,'Titanic','A romance blossoms aboard the doomed RMS Titanic.',195,'1997-12-19','titanic_poster.jpg',3,7.9,'titanic_bg.jpg',false,NULL),
-	 (57,'Gladiator','A betrayed general fights as a gladiator to avenge his family.',155,'2000-05-05','gladiator_poster.jpg',1,8.5,'gladiator_bg.jpg',false,NULL),
-	 (58,'The Godfather','The aging patriarch of a mafia family transfers control to his son.',175,'1972-03-24','godfather_poster.jpg',2,9.2,'godfather_bg.jpg',false,NULL),
-	 (59,'The Godfather Part II','The saga of the Corleone family continues with power and betrayal.',202,'1974-12-20','godfather2_poster.jpg',1,9.0,'godfather2_bg.jpg',false,NULL),
-	 (60,'The Godfather Part III','Michael Corleone seeks redemption while facing new enemies.',162,'1990-12-25','godfather3_poster.jpg',2,7.6,'godfather3_bg.jpg',false,NULL),
-	 (61,'Pulp Fiction','The lives of criminals intertwine in a series of violent stories.',154,'1994-10-14','pulpfiction_poster.jpg',2,8.9,'pulpfiction_bg.jpg',false,NULL),
-	 (62,'Kill Bill: Vol. 1','A bride seeks vengeance against assassins who betrayed her.',111,'2003-10-10','killbill1_poster.jpg',3,8.1,'killbill1_bg.jpg',false,NULL),
-	 (64,'Django Unchained','A freed slave teams up with a bounty hunter to rescue his wife.',165,'2012-12-25','django_poster.jpg',2,8.4,'django_bg.jpg',false,NULL);
-INSERT INTO public.movies (id,title,synopsis,duration_minutes,release_date,poster_image,directors_id,rating,bg_path,is_deleted,deleted_at) VALUES
-	 (65,'The Avengers','Earth’s mightiest heroes must unite to stop Loki and his army.',143,'2012-05-04','avengers_poster.jpg',1,8.0,'avengers_bg.jpg',false,NULL),
-	 (66,'Avengers: Infinity War','The Avengers face Thanos in a battle for the fate of the universe.',149,'2018-04-27','infinitywar_poster.jpg',3,8.4,'infinitywar_bg.jpg',false,NULL),
-	 (67,'Avengers: Endgame','The Avengers assemble for one final battle against Thanos.',181,'2019-04-26','endgame_poster.jpg',1,8.5,'endgame_bg.jpg',false,NULL),
-	 (63,'Kill Bill: Vol. 2','The Bride continues her quest to eliminate Bill.',137,'2004-04-16','killbill2_poster.jpg',1,8.0,'killbill2_bg.jpg',true,'2025-09-14 16:03:15.722254');
@@ -1,3 +0,0 @@
-INSERT INTO public.movies_cast (casts_id,movies_id) VALUES
-	 (2,2),
-	 (3,3);
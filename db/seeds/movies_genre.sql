@@ -1,3 +0,0 @@
-INSERT INTO public.movies_genre (genres_id,movies_id) VALUES
-	 (2,2),
-	 (3,3);
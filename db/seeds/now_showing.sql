@@ -1,5 +0,0 @@
-INSERT INTO public.now_showing (id,"date","time",location_id,movie_id) VALUES
-	 (1,'2025-08-28','19:00:00',1,1),
-	 (2,'2025-08-28','20:00:00',2,2),
-	 (3,'2025-08-29','18:30:00',3,3),
-	 (4,'2025-09-01','19:00:00',1,1);
@@ -1,11 +0,0 @@
-INSERT INTO public.orders (id,users_id,price,payment_id,"isPaid",created_at,now_showing_id,isorder) VALUES
-	 (1,1,50000,1,true,'2025-08-28 09:58:26.892072',1,true),
-	 (2,2,60000,2,true,'2025-08-28 09:58:26.892072',2,false),
-	 (3,3,55000,3,false,'2025-08-28 09:58:26.892072',3,true),
-	 (4,2,60000,2,false,'2025-09-01 03:57:54.092197',1,true),
-	 (5,8,50000,1,false,'2025-09-06 16:10:08.274945',2,true),
-	 (6,8,50000,1,false,'2025-09-08 00:06:19.547891',2,false),
-	 (7,8,50000,1,false,'2025-09-08 10:39:45.679391',2,true),
-	 (8,3,80000,1,false,'2025-09-13 09:51:46.533161',2,true),
-	 (9,3,100000,1,false,'2025-09-14 05:29:48.628051',1,false),
-	 (10,3,90000,1,false,'2025-09-14 05:45:59.085765',2,true);
@@ -1,11 +0,0 @@
-INSERT INTO public.orders_cinema (orders_id,cinema_id) VALUES
-	 (1,1),
-	 (2,2),
-	 (3,3),
-	 (4,2),
-	 (5,1),
-	 (6,1),
-	 (7,1),
-	 (8,1),
-	 (9,1),
-	 (10,2);
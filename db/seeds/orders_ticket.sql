@@ -1,11 +0,0 @@
-INSERT INTO public.orders_ticket (orders_id,ticket_id) VALUES
-	 (1,1),
-	 (2,2),
-	 (3,3),
-	 (4,1),
-	 (5,6),
-	 (6,7),
-	 (7,8),
-	 (8,9),
-	 (9,10),
-	 (10,11);
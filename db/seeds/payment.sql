@@ -1,4 +0,0 @@
-INSERT INTO public.payment (id,"method") VALUES
-	 (1,'Credit Card'),
-	 (2,'OVO'),
-	 (3,'GoPay');
@@ -1,4 +0,0 @@
-INSERT INTO public.profile (id,first_name,last_name,phone_number,profile_picture,created_at,updated_at) VALUES
-	 (1,'Andi','Saputra','081234567890','andi.png','2025-08-28 09:54:55.310492','2025-08-28 09:54:55.310492'),
-	 (2,'raihan','inkam','08111727072','raihan.png','2025-08-28 09:54:55.310492','2025-09-01 04:04:51.034576'),
-	 (3,'citra','raya cibubur','081234567','1757834673211895300_profile_3.jpg','2025-08-28 09:54:55.310492','2025-09-14 07:24:33.526152');
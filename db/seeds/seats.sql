@@ -1,18 +0,0 @@
-INSERT INTO public.seats (id,seats_map,orders_id) VALUES
-	 (4,'A1',1),
-	 (5,'B2',2),
-	 (6,'C3',3),
-	 (7,'seat_number',4),
-	 (8,'A1',5),
-	 (9,'A2',5),
-	 (10,'A3',5),
-	 (11,'A1',6),
-	 (12,'A2',6),
-	 (13,'A3',6);
-INSERT INTO public.seats (id,seats_map,orders_id) VALUES
-	 (14,'A1',7),
-	 (15,'A2',7),
-	 (16,'A3',7),
-	 (17,'A1',8),
-	 (18,'A4',9),
-	 (19,'A3',10);
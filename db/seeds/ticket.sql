@@ -1,11 +0,0 @@
-INSERT INTO public.ticket (id,qr_code) VALUES
-	 (1,'QR001'),
-	 (2,'QR002'),
-	 (3,'QR003'),
-	 (5,'generated_qr_code'),
-	 (6,'f9c83576b40806190f9bdf009e7602c5'),
-	 (7,'aa4c2a7209046981d7a7c5475cf6a1e2'),
-	 (8,'bf62ce4671c15023f22967802b17e9f8'),
-	 (9,'8bf9d3c099b88fb326229428f4382382'),
-	 (10,'d4e47c0b849cd300190ef1359436caee'),
-	 (11,'8f055b84e856b3ea4ca116fdd64f658b');
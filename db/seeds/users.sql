@@ -1,21 +0,0 @@
-INSERT INTO public.users (id,email,"password",poin,"role") VALUES
-	 (4,'andi@mail.com','$argon2id$v=19$m=65536,t=2,p=1$0OLl7DG7OMuiq7P6kPT8pQ$PLfVPVdNfIG5uoFv7KG5eo3bQQ9zDBE56TlGr/TV7rk',100.0,'admin'),
-	 (2,'budi@mail.com','$argon2id$v=19$m=65536,t=2,p=1$pw7f+hLUeXrkWQ6PLcVi4g$ffcsuZKg0ssUidxasXmkZ0yjUoaBvkm1FxHNqzJyOxU',50.0,'admin'),
-	 (7,'andi1@mail.com','$argon2id$v=19$m=65536,t=2,p=1$miay+YwOOdUyOCmf2KZmsQ$7Ri+GjiuTIKHxTj/JwdZNd1mBRKzdZnDGH/P7Ovs5NI',100.0,'admin'),
-	 (10,'andi2@mail.com','$argon2id$v=19$m=65536,t=2,p=1$jjo4x6Ww3o9ZTyL+laADiw$/lLQqKQ5ezIX8S20EEVscEY8CI8ZcUf6RHh0QqZlu2Y',100.0,'admin'),
-	 (1,'andi3@mail.com','$argon2id$v=19$m=65536,t=2,p=1$UbemFfo3Imd27mZ2XuDVhQ$00lxUXvdI/fkA+dgVVxl2VzOOwgZFbRsLMSUdo2rr3U',100.0,'admin'),
-	 (5,'budi1@mail.com','$argon2id$v=19$m=65536,t=2,p=1$bGqmZdMa6F1VBOVrgLI/Og$wl/sOdjnqH9BXZ5axxECshUhFUVOjWxvAauCbw9W2co',50.0,'admin'),
-	 (8,'budi2@mail.com','$argon2id$v=19$m=65536,t=2,p=1$+t3jyte/5DWz8gntWiMfQw$o8zSP83v8DyY5sWMjD8QihwFqBvRX1dhA2gVC2XCe3k',50.0,'admin'),
-	 (11,'budi3@mail.com','$argon2id$v=19$m=65536,t=2,p=1$+ZVjfeNbRGf2nqehQjwAyg$CL39UW7X0JnDf3wk3eZGrLMa+NAH0FfsCzMg5Kk+gNg',50.0,'admin'),
-	 (6,'citra1@mail.com','$argon2id$v=19$m=65536,t=2,p=1$7HKdJRXu5gpdXEAfDNHrmQ$Li+Bw6EboEBOKjKa5onri3xD0bL38OtUIZfvEkxLAFI',20.0,'user'),
-	 (9,'citra2@mail.com','$argon2id$v=19$m=65536,t=2,p=1$f+JdXyzJQn1R/+PxPwS3OQ$xkVrkBGzU/kVXiL/kOrPhicS5Opho0zLZomFEECuDhk',20.0,'user');
-INSERT INTO public.users (id,email,"password",poin,"role") VALUES
-	 (12,'citra3@mail.com','$argon2id$v=19$m=65536,t=2,p=1$6PPHkWzR6ryqQgw8NfwU0Q$2PLGdxtR0qRHxTVeCcngOcNAvDQqHU1mc4G0k1M+uB8',20.0,'user'),
-	 (13,'inkam@mail.com','$argon2id$v=19$m=65536,t=2,p=1$UFQgy6JrWBtgxMtaluFJmg$X0grh5jAHVL+bs5QxSEKmhbMmtoJoeu5CU6GKesF/G0',0.0,'user'),
-	 (14,'inkam@gmail.com','$argon2id$v=19$m=65536,t=2,p=1$sHW8hBQ3kEVRI/QzD5rq9A$OLmPATBb9c9cLCr4nXH9xOTZWj/aIU8dfa0AKVPk2vU',0.0,'user'),
-	 (15,'inkam1@gmail.com','$argon2id$v=19$m=65536,t=2,p=1$E7Dq8d0srsqb++z++BOxdw$O75N9pf/s/7qWt1+5DBm/QXbb2U3W62NxTTaKbLNAug',0.0,'user'),
-	 (16,'inkam1@gmail.com','$argon2id$v=19$m=65536,t=2,p=1$zUA2VWp+aY1ASg4jY766jA$ifL7JThQ033JbbfGUugNs55DY6COEux5upMn59PyMiU',0.0,'user'),
-	 (17,'inkam1@gmail.com','$argon2id$v=19$m=65536,t=2,p=1$DtpUQRZHIa/mm3iLFQwLKQ$6np2zjo04t3XfJ+0swwKQl90yFSl1KbpdObUZatGZCM',0.0,'user'),
-	 (18,'inkam2@gmail.com','$argon2id$v=19$m=65536,t=2,p=1$QI51MANcH+9SxYzSbQ5w3w$lIP9pkwwsMdnLGm47GFQZvgveouQLWsEL68Q0FnUX88',0.0,'user'),
-	 (19,'andi@gmail.com','$argon2id$v=19$m=65536,t=2,p=1$OlxRZUCz+9v96QEZlKxylQ$zxSGEjK5O1Xcd5uJk8YlkAyzVFsGrfd60ZHo5Z6FBL4',0.0,'user'),
-	 (3,'citra@mail.com','$argon2id$v=19$m=65536,t=2,p=1$5dqNGzUr58SINSTf4O7KUg$9UHKgMascnMQa/wTlvrU3wLuOyQmXASE7NA5tj74sxQ',20.0,'user');
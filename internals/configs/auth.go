@@ -0,0 +1,29 @@
+package configs
+
+import (
+	"time"
+
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+// loadJWT JWT_SECRET (wajib), JWT_ISSUER, JWT_ACCESS_TTL_MINUTES (default 30)
+func loadJWT(env *envReader) pkg.JWTConfig {
+	return pkg.JWTConfig{
+		Secret:    env.required("JWT_SECRET"),
+		Issuer:    env.str("JWT_ISSUER", ""),
+		AccessTTL: env.duration("JWT_ACCESS_TTL_MINUTES", 30, 1, time.Minute),
+	}
+}
+
+// loadLockout aturan lockout login:
+// LOGIN_MAX_ATTEMPTS (default 5), LOGIN_ATTEMPT_WINDOW_MINUTES (default 15),
+// LOGIN_LOCK_MINUTES (default 1), LOGIN_MAX_LOCK_MINUTES (default 60)
+func loadLockout(env *envReader) models.LockoutRules {
+	return models.LockoutRules{
+		MaxAttempts:   env.int("LOGIN_MAX_ATTEMPTS", 5, 1),
+		AttemptWindow: env.duration("LOGIN_ATTEMPT_WINDOW_MINUTES", 15, 1, time.Minute),
+		BaseLock:      env.duration("LOGIN_LOCK_MINUTES", 1, 1, time.Minute),
+		MaxLock:       env.duration("LOGIN_MAX_LOCK_MINUTES", 60, 1, time.Minute),
+	}
+}
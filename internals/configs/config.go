@@ -0,0 +1,256 @@
+package configs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/utils"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+// Config seluruh konfigurasi aplikasi, dibaca sekali saat start lewat Load lalu diteruskan ke router
+type Config struct {
+	Server ServerConfig
+	DB     DBConfig
+	Redis  RedisConfig
+	Log    LogConfig
+
+	JWT                  pkg.JWTConfig
+	RefreshTokenTTL      time.Duration
+	TokenRevocationStore string // "redis" (fallback ke Postgres) atau "postgres"
+	Lockout              models.LockoutRules
+	RateLimits           RateLimitConfig
+	AccountTokens        models.AccountTokenRules
+
+	CORS   CORSConfig
+	Upload utils.FileUploadConfig
+	Hash   pkg.HashConfig
+
+	SeatHoldDuration  time.Duration
+	OrderCancelCutoff time.Duration
+	Loyalty           models.LoyaltyRules
+
+	Payment PaymentConfig
+	Ticket  TicketConfig
+	Mail    MailConfig
+}
+
+// ServerConfig alamat dan timeout HTTP server:
+// SERVER_ADDR (default ":9001"), SERVER_READ_HEADER_TIMEOUT_SECONDS (5), SERVER_READ_TIMEOUT_SECONDS (15),
+// SERVER_WRITE_TIMEOUT_SECONDS (30), SERVER_IDLE_TIMEOUT_SECONDS (60) dan SERVER_SHUTDOWN_TIMEOUT_SECONDS (20),
+// batas waktu menunggu request yang sedang berjalan saat server dimatikan
+type ServerConfig struct {
+	Addr              string
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
+}
+
+// CORSConfig origin yang boleh mengakses API (CORS_ALLOWED_ORIGINS, dipisah koma)
+type CORSConfig struct {
+	AllowedOrigins []string
+}
+
+// RateLimitConfig aturan rate limit per grup route (RATE_LIMIT_<NAMA>="<limit>/<window>")
+type RateLimitConfig struct {
+	AuthIP     models.RateLimitRule
+	AuthEmail  models.RateLimitRule
+	OrdersUser models.RateLimitRule
+}
+
+var defaultCORSOrigins = []string{"http://127.0.0.1:5500", "http://localhost:5173", "http://localhost:80", "http://frontend:80", "http://frontend:3000"}
+
+// Load membaca konfigurasi dari env. File CONFIG_FILE (format .env) dibaca lebih dulu jika di-set,
+// jika tidak .env di working directory dipakai bila ada. Env yang sudah di-set tidak ditimpa file.
+// Semua nilai yang kosong / tidak valid dilaporkan sekaligus dalam satu error
+func Load() (*Config, error) {
+	if err := loadEnvFile(); err != nil {
+		return nil, err
+	}
+
+	env := &envReader{}
+	cfg := &Config{
+		Server: ServerConfig{
+			Addr:              env.str("SERVER_ADDR", ":9001"),
+			ReadHeaderTimeout: env.duration("SERVER_READ_HEADER_TIMEOUT_SECONDS", 5, 1, time.Second),
+			ReadTimeout:       env.duration("SERVER_READ_TIMEOUT_SECONDS", 15, 1, time.Second),
+			WriteTimeout:      env.duration("SERVER_WRITE_TIMEOUT_SECONDS", 30, 1, time.Second),
+			IdleTimeout:       env.duration("SERVER_IDLE_TIMEOUT_SECONDS", 60, 1, time.Second),
+			ShutdownTimeout:   env.duration("SERVER_SHUTDOWN_TIMEOUT_SECONDS", 20, 1, time.Second),
+		},
+		DB:    loadDB(env),
+		Redis: loadRedis(env),
+		Log:   loadLog(env),
+
+		JWT:                  loadJWT(env),
+		RefreshTokenTTL:      env.duration("REFRESH_TOKEN_DAYS", 30, 1, 24*time.Hour),
+		TokenRevocationStore: env.oneOf("TOKEN_REVOCATION_STORE", "redis", "redis", "postgres"),
+		Lockout:              loadLockout(env),
+		RateLimits: RateLimitConfig{
+			AuthIP:     env.rateLimit("AUTH_IP", 30, time.Minute),
+			AuthEmail:  env.rateLimit("AUTH_EMAIL", 10, 15*time.Minute),
+			OrdersUser: env.rateLimit("ORDERS_USER", 60, time.Minute),
+		},
+		AccountTokens: loadAccountTokens(env),
+
+		CORS: CORSConfig{AllowedOrigins: env.list("CORS_ALLOWED_ORIGINS", defaultCORSOrigins)},
+		Upload: utils.FileUploadConfig{
+			MaxSize:     int64(env.int("UPLOAD_MAX_MB", 5, 1)) * 1024 * 1024,
+			UploadDir:   "./uploads",
+			AllowedExts: normalizeExts(env.list("UPLOAD_ALLOWED_EXTS", []string{".jpg", ".jpeg", ".png", ".gif", ".webp"})),
+		},
+		Hash: loadHash(env),
+
+		SeatHoldDuration:  env.duration("SEAT_HOLD_MINUTES", 10, 1, time.Minute),
+		OrderCancelCutoff: env.duration("ORDER_CANCEL_CUTOFF_MINUTES", 60, 0, time.Minute),
+		Loyalty:           loadLoyalty(env),
+
+		Payment: loadPayment(env),
+		Ticket:  loadTicket(env),
+		Mail:    loadMail(env),
+	}
+
+	if err := env.err(); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg, nil
+}
+
+func loadEnvFile() error {
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := godotenv.Load(path); err != nil {
+			return fmt.Errorf("failed to read CONFIG_FILE %s: %w", path, err)
+		}
+		return nil
+	}
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read .env: %w", err)
+	}
+	return nil
+}
+
+// loadHash parameter argon2id untuk hash password, default sesuai HashConfig.UseRecommended:
+// ARGON2_MEMORY_KB (65536), ARGON2_TIME (2), ARGON2_THREADS (1), ARGON2_KEY_LEN (32), ARGON2_SALT_LEN (16)
+func loadHash(env *envReader) pkg.HashConfig {
+	var hc pkg.HashConfig
+	hc.UseRecommended()
+	hc.SetConfig(
+		uint32(env.int("ARGON2_MEMORY_KB", int(hc.Memory), 1024)),
+		uint32(env.int("ARGON2_TIME", int(hc.Time), 1)),
+		uint32(env.int("ARGON2_KEY_LEN", int(hc.KeyLen), 16)),
+		uint32(env.int("ARGON2_SALT_LEN", int(hc.SaltLen), 8)),
+		uint8(min(env.int("ARGON2_THREADS", int(hc.Thread), 1), 255)),
+	)
+	return hc
+}
+
+func normalizeExts(exts []string) []string {
+	normalized := make([]string, 0, len(exts))
+	for _, ext := range exts {
+		ext = strings.ToLower(ext)
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		normalized = append(normalized, ext)
+	}
+	return normalized
+}
+
+// envReader membaca env dan mengumpulkan semua kesalahan supaya bisa dilaporkan sekaligus
+type envReader struct {
+	errs []error
+}
+
+func (e *envReader) fail(format string, args ...any) {
+	e.errs = append(e.errs, fmt.Errorf(format, args...))
+}
+
+func (e *envReader) err() error {
+	return errors.Join(e.errs...)
+}
+
+func (e *envReader) str(key, fallback string) string {
+	if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func (e *envReader) required(key string) string {
+	value := e.str(key, "")
+	if value == "" {
+		e.fail("%s is required", key)
+	}
+	return value
+}
+
+func (e *envReader) int(key string, fallback, min int) int {
+	raw := e.str(key, "")
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < min {
+		e.fail("%s must be an integer >= %d, got %q", key, min, raw)
+		return fallback
+	}
+	return value
+}
+
+// duration angka di env dikali unit, contoh SEAT_HOLD_MINUTES=10 dengan unit time.Minute
+func (e *envReader) duration(key string, fallback, min int, unit time.Duration) time.Duration {
+	return time.Duration(e.int(key, fallback, min)) * unit
+}
+
+func (e *envReader) oneOf(key, fallback string, allowed ...string) string {
+	value := strings.ToLower(e.str(key, fallback))
+	for _, a := range allowed {
+		if value == a {
+			return value
+		}
+	}
+	e.fail("%s must be one of %s, got %q", key, strings.Join(allowed, "/"), value)
+	return fallback
+}
+
+// list nilai dipisah koma, item kosong dibuang
+func (e *envReader) list(key string, fallback []string) []string {
+	raw := e.str(key, "")
+	if raw == "" {
+		return fallback
+	}
+	var values []string
+	for item := range strings.SplitSeq(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+// rateLimit membaca RATE_LIMIT_<NAME> dengan format "<limit>/<window>", contoh "30/1m".
+// Limit 0 mematikan rate limit untuk aturan tersebut
+func (e *envReader) rateLimit(name string, limit int, window time.Duration) models.RateLimitRule {
+	key := "RATE_LIMIT_" + name
+	raw := e.str(key, "")
+	if raw == "" {
+		return models.RateLimitRule{Limit: limit, Window: window}
+	}
+
+	limitStr, windowStr, ok := strings.Cut(raw, "/")
+	l, limitErr := strconv.Atoi(limitStr)
+	w, windowErr := time.ParseDuration(windowStr)
+	if !ok || limitErr != nil || l < 0 || windowErr != nil || w <= 0 {
+		e.fail("%s must look like \"<limit>/<window>\" (e.g. 30/1m), got %q", key, raw)
+		return models.RateLimitRule{Limit: limit, Window: window}
+	}
+	return models.RateLimitRule{Limit: l, Window: w}
+}
@@ -0,0 +1,177 @@
+package configs
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raihaninkam/tickitz/internals/models"
+)
+
+// setEnv mengisi env untuk satu test, nilai kosong berarti env tidak di-set sama sekali.
+// Semua key didaftarkan lewat t.Setenv supaya nilai dari CONFIG_FILE juga dibersihkan setelah test
+func setEnv(t *testing.T, values map[string]string) {
+	t.Helper()
+	for key, value := range values {
+		t.Setenv(key, value)
+		if value == "" {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+// validEnv env minimal supaya Load berhasil, key opsional yang diperiksa test dikosongkan
+func validEnv() map[string]string {
+	seed := make([]byte, 32)
+	rand.Read(seed)
+	return map[string]string{
+		"CONFIG_FILE":            "",
+		"DBUSER":                 "tickitz",
+		"DBHOST":                 "localhost",
+		"DBNAME":                 "tickitz_test",
+		"DB_MAX_CONNS":           "",
+		"DB_MIN_CONNS":           "",
+		"JWT_SECRET":             "rahasia",
+		"TICKET_SIGNING_KEY":     base64.StdEncoding.EncodeToString(seed),
+		"SERVER_ADDR":            "",
+		"SEAT_HOLD_MINUTES":      "",
+		"RATE_LIMIT_AUTH_IP":     "",
+		"RATE_LIMIT_AUTH_EMAIL":  "",
+		"CORS_ALLOWED_ORIGINS":   "",
+		"UPLOAD_ALLOWED_EXTS":    "",
+		"LOG_FORMAT":             "",
+		"MAILER":                 "",
+		"TOKEN_REVOCATION_STORE": "",
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	setEnv(t, validEnv())
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Server.Addr != ":9001" || cfg.Server.ShutdownTimeout != 20*time.Second {
+		t.Errorf("server %+v", cfg.Server)
+	}
+	if cfg.DB.Port != "5432" || cfg.DB.MaxConns != 10 {
+		t.Errorf("db %+v", cfg.DB)
+	}
+	if cfg.SeatHoldDuration != 10*time.Minute {
+		t.Errorf("seat hold %v, want 10m", cfg.SeatHoldDuration)
+	}
+	if want := (models.RateLimitRule{Limit: 30, Window: time.Minute}); cfg.RateLimits.AuthIP != want {
+		t.Errorf("auth ip rate limit %+v, want %+v", cfg.RateLimits.AuthIP, want)
+	}
+	if cfg.TokenRevocationStore != "redis" || cfg.Log.Format != "json" || cfg.Mail.Provider != "local" {
+		t.Errorf("revocation %q, log format %q, mailer %q", cfg.TokenRevocationStore, cfg.Log.Format, cfg.Mail.Provider)
+	}
+	if !slices.Equal(cfg.CORS.AllowedOrigins, defaultCORSOrigins) {
+		t.Errorf("cors origins %v", cfg.CORS.AllowedOrigins)
+	}
+}
+
+func TestLoadParsesValues(t *testing.T) {
+	env := validEnv()
+	env["SERVER_ADDR"] = ":8080"
+	env["SEAT_HOLD_MINUTES"] = "5"
+	env["RATE_LIMIT_AUTH_IP"] = "0/1m"
+	env["RATE_LIMIT_AUTH_EMAIL"] = "3/30s"
+	env["CORS_ALLOWED_ORIGINS"] = " https://tickitz.id, ,https://admin.tickitz.id "
+	env["UPLOAD_ALLOWED_EXTS"] = "JPG,.png"
+	env["TOKEN_REVOCATION_STORE"] = "Postgres"
+	setEnv(t, env)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Addr != ":8080" || cfg.SeatHoldDuration != 5*time.Minute {
+		t.Errorf("addr %q, seat hold %v", cfg.Server.Addr, cfg.SeatHoldDuration)
+	}
+	// limit 0 mematikan rate limit
+	if cfg.RateLimits.AuthIP.Limit != 0 {
+		t.Errorf("auth ip limit %d, want 0", cfg.RateLimits.AuthIP.Limit)
+	}
+	if want := (models.RateLimitRule{Limit: 3, Window: 30 * time.Second}); cfg.RateLimits.AuthEmail != want {
+		t.Errorf("auth email rate limit %+v, want %+v", cfg.RateLimits.AuthEmail, want)
+	}
+	if want := []string{"https://tickitz.id", "https://admin.tickitz.id"}; !slices.Equal(cfg.CORS.AllowedOrigins, want) {
+		t.Errorf("cors origins %v, want %v", cfg.CORS.AllowedOrigins, want)
+	}
+	if want := []string{".jpg", ".png"}; !slices.Equal(cfg.Upload.AllowedExts, want) {
+		t.Errorf("upload exts %v, want %v", cfg.Upload.AllowedExts, want)
+	}
+	if cfg.TokenRevocationStore != "postgres" {
+		t.Errorf("revocation store %q, want postgres", cfg.TokenRevocationStore)
+	}
+}
+
+func TestLoadReportsAllErrors(t *testing.T) {
+	env := validEnv()
+	env["DBUSER"] = ""
+	env["JWT_SECRET"] = "  "
+	env["SEAT_HOLD_MINUTES"] = "sepuluh"
+	env["RATE_LIMIT_AUTH_IP"] = "30"
+	env["LOG_FORMAT"] = "xml"
+	env["DB_MAX_CONNS"] = "5"
+	env["DB_MIN_CONNS"] = "8"
+	env["MAILER"] = "smtp"
+	env["SMTP_HOST"] = ""
+	setEnv(t, env)
+
+	cfg, err := Load()
+	if err == nil {
+		t.Fatalf("invalid config accepted: %+v", cfg)
+	}
+	for _, want := range []string{
+		"DBUSER is required",
+		"JWT_SECRET is required",
+		`SEAT_HOLD_MINUTES must be an integer >= 1, got "sepuluh"`,
+		`RATE_LIMIT_AUTH_IP must look like "<limit>/<window>"`,
+		`LOG_FORMAT must be one of json/text, got "xml"`,
+		"DB_MIN_CONNS (8) must not exceed DB_MAX_CONNS (5)",
+		"SMTP_HOST is required",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	env := validEnv()
+	env["DBNAME"] = ""
+	env["SEAT_HOLD_MINUTES"] = "7"
+	setEnv(t, env)
+
+	path := filepath.Join(t.TempDir(), "tickitz.env")
+	content := "DBNAME=from_file\nSEAT_HOLD_MINUTES=15\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DB.Name != "from_file" {
+		t.Errorf("db name %q, want value from CONFIG_FILE", cfg.DB.Name)
+	}
+	// env yang sudah di-set tidak ditimpa file
+	if cfg.SeatHoldDuration != 7*time.Minute {
+		t.Errorf("seat hold %v, want env value 7m", cfg.SeatHoldDuration)
+	}
+
+	t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.env"))
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "failed to read CONFIG_FILE") {
+		t.Errorf("missing CONFIG_FILE: err %v", err)
+	}
+}
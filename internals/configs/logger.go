@@ -0,0 +1,29 @@
+package configs
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+// LogConfig level (LOG_LEVEL: debug/info/warn/error, default info) dan format (LOG_FORMAT: json/text, default json)
+type LogConfig struct {
+	Level  string
+	Format string
+}
+
+func loadLog(env *envReader) LogConfig {
+	return LogConfig{
+		Level:  env.oneOf("LOG_LEVEL", "info", "debug", "info", "warn", "warning", "error"),
+		Format: env.oneOf("LOG_FORMAT", "json", "json", "text"),
+	}
+}
+
+// InitLogger logger aplikasi. Logger ini juga dijadikan default sehingga slog.Info dkk. di worker
+// background dan log dari library (package log) ikut memakai format yang sama
+func InitLogger(cfg LogConfig) *slog.Logger {
+	logger := pkg.NewLogger(os.Stdout, pkg.ParseLogLevel(cfg.Level), cfg.Format)
+	slog.SetDefault(logger)
+	return logger
+}
@@ -0,0 +1,15 @@
+package configs
+
+import (
+	"github.com/raihaninkam/tickitz/internals/models"
+)
+
+// loadLoyalty aturan poin:
+// POIN_EARN_PER_AMOUNT (default 10000), POIN_REDEEM_VALUE (default 1000), POIN_EXPIRY_DAYS (default 365)
+func loadLoyalty(env *envReader) models.LoyaltyRules {
+	return models.LoyaltyRules{
+		EarnPerAmount: env.int("POIN_EARN_PER_AMOUNT", 10000, 1),
+		RedeemValue:   env.int("POIN_REDEEM_VALUE", 1000, 1),
+		ExpiryDays:    env.int("POIN_EXPIRY_DAYS", 365, 0),
+	}
+}
@@ -0,0 +1,58 @@
+package configs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+// MailConfig pengirim email. local menulis email ke OutboxDir (atau log jika kosong), smtp memakai SMTP_*
+type MailConfig struct {
+	Provider  string // MAILER: local (default) / smtp
+	From      string // MAIL_FROM
+	OutboxDir string // MAIL_OUTBOX_DIR
+	SMTPHost  string // SMTP_HOST, wajib untuk smtp
+	SMTPPort  string // SMTP_PORT, default 587
+	SMTPUser  string
+	SMTPPass  string
+}
+
+func loadMail(env *envReader) MailConfig {
+	cfg := MailConfig{
+		Provider:  env.oneOf("MAILER", "local", "local", "smtp"),
+		From:      env.str("MAIL_FROM", "Tickitz <no-reply@tickitz.local>"),
+		OutboxDir: env.str("MAIL_OUTBOX_DIR", ""),
+		SMTPPort:  env.str("SMTP_PORT", "587"),
+		SMTPUser:  env.str("SMTP_USER", ""),
+		SMTPPass:  env.str("SMTP_PASS", ""),
+	}
+	if cfg.Provider == "smtp" {
+		cfg.SMTPHost = env.required("SMTP_HOST")
+	}
+	return cfg
+}
+
+// InitMailer membuat pengirim email sesuai cfg.Provider
+func InitMailer(cfg MailConfig) (pkg.Mailer, error) {
+	switch cfg.Provider {
+	case "", "local":
+		return pkg.NewLocalMailer(cfg.OutboxDir, cfg.From), nil
+	case "smtp":
+		return pkg.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPass, cfg.From), nil
+	default:
+		return nil, fmt.Errorf("unsupported mailer: %s", cfg.Provider)
+	}
+}
+
+// loadAccountTokens masa berlaku token akun:
+// VERIFY_TOKEN_HOURS (default 24), RESET_TOKEN_MINUTES (default 30), FRONTEND_URL untuk link di email
+func loadAccountTokens(env *envReader) models.AccountTokenRules {
+	return models.AccountTokenRules{
+		VerifyTTL:   env.duration("VERIFY_TOKEN_HOURS", 24, 1, time.Hour),
+		ResetTTL:    env.duration("RESET_TOKEN_MINUTES", 30, 1, time.Minute),
+		FrontendURL: strings.TrimRight(env.str("FRONTEND_URL", ""), "/"),
+	}
+}
@@ -0,0 +1,35 @@
+package configs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+// PaymentConfig gateway pembayaran (PAYMENT_GATEWAY, default local), secret untuk verifikasi
+// signature POST /payments/webhook (PAYMENT_WEBHOOK_SECRET) dan batas waktu pembayaran
+// (PAYMENT_EXPIRY_MINUTES, default 30): charge kadaluarsa dan order yang belum dibayar dibatalkan
+type PaymentConfig struct {
+	Gateway       string
+	WebhookSecret string
+	Expiry        time.Duration
+}
+
+func loadPayment(env *envReader) PaymentConfig {
+	return PaymentConfig{
+		Gateway:       env.oneOf("PAYMENT_GATEWAY", "local", "local"),
+		WebhookSecret: env.str("PAYMENT_WEBHOOK_SECRET", ""),
+		Expiry:        env.duration("PAYMENT_EXPIRY_MINUTES", 30, 1, time.Minute),
+	}
+}
+
+// InitPaymentGateway membuat gateway pembayaran sesuai cfg.Gateway
+func InitPaymentGateway(cfg PaymentConfig) (pkg.PaymentGateway, error) {
+	switch cfg.Gateway {
+	case "", "local":
+		return pkg.NewLocalGateway(cfg.Expiry), nil
+	default:
+		return nil, fmt.Errorf("unsupported payment gateway: %s", cfg.Gateway)
+	}
+}
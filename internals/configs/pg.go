@@ -3,21 +3,86 @@ package configs
 import (
 	"context"
 	"fmt"
-	"os"
+	"net"
+	"net/url"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func InitDB() (*pgxpool.Pool, error) {
-	dbUser := os.Getenv("DBUSER")
-	dbPass := os.Getenv("DBPASS")
-	dbHost := os.Getenv("DBHOST")
-	dbPort := os.Getenv("DBPORT")
-	dbName := os.Getenv("DBNAME")
-	connString := fmt.Sprintf("postgres://%s:%s@%s:%s/%s", dbUser, dbPass, dbHost, dbPort, dbName)
-	return pgxpool.New(context.Background(), connString)
+// DBConfig koneksi Postgres (DBUSER, DBPASS, DBHOST, DBPORT, DBNAME) dan ukuran pool:
+// DB_MAX_CONNS (default 10), DB_MIN_CONNS (default 0),
+// DB_MAX_CONN_LIFETIME_MINUTES (default 60), DB_MAX_CONN_IDLE_MINUTES (default 30)
+type DBConfig struct {
+	User            string
+	Password        string
+	Host            string
+	Port            string
+	Name            string
+	MaxConns        int32
+	MinConns        int32
+	MaxConnLifetime time.Duration
+	MaxConnIdleTime time.Duration
 }
 
+func loadDB(env *envReader) DBConfig {
+	cfg := DBConfig{
+		User:            env.required("DBUSER"),
+		Password:        env.str("DBPASS", ""),
+		Host:            env.required("DBHOST"),
+		Port:            env.str("DBPORT", "5432"),
+		Name:            env.required("DBNAME"),
+		MaxConns:        int32(env.int("DB_MAX_CONNS", 10, 1)),
+		MinConns:        int32(env.int("DB_MIN_CONNS", 0, 0)),
+		MaxConnLifetime: env.duration("DB_MAX_CONN_LIFETIME_MINUTES", 60, 1, time.Minute),
+		MaxConnIdleTime: env.duration("DB_MAX_CONN_IDLE_MINUTES", 30, 1, time.Minute),
+	}
+	if cfg.MinConns > cfg.MaxConns {
+		env.fail("DB_MIN_CONNS (%d) must not exceed DB_MAX_CONNS (%d)", cfg.MinConns, cfg.MaxConns)
+	}
+	return cfg
+}
+
+// LoadDB hanya membaca konfigurasi Postgres, dipakai subcommand migrate / seed
+// supaya tidak perlu JWT_SECRET, TICKET_SIGNING_KEY dan konfigurasi server lainnya
+func LoadDB() (DBConfig, error) {
+	if err := loadEnvFile(); err != nil {
+		return DBConfig{}, err
+	}
+	env := &envReader{}
+	cfg := loadDB(env)
+	if err := env.err(); err != nil {
+		return DBConfig{}, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg, nil
+}
+
+// ConnString URL koneksi postgres, user dan password di-escape
+func (c DBConfig) ConnString() string {
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(c.User, c.Password),
+		Host:   net.JoinHostPort(c.Host, c.Port),
+		Path:   "/" + c.Name,
+	}
+	return u.String()
+}
+
+func InitDB(cfg DBConfig) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.ConnString())
+	if err != nil {
+		return nil, err
+	}
+	poolConfig.MaxConns = cfg.MaxConns
+	poolConfig.MinConns = cfg.MinConns
+	poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	return pgxpool.NewWithConfig(context.Background(), poolConfig)
+}
+
+// PingDB cek koneksi database, dibatasi 3 detik supaya readiness check tidak menggantung
 func PingDB(db *pgxpool.Pool) error {
-	return db.Ping(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	return db.Ping(ctx)
 }
@@ -2,31 +2,51 @@ package configs
 
 import (
 	"context"
-	"fmt"
-	"log"
-	"os"
+	"log/slog"
+	"net"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
-func InitRedis() (*redis.Client, error) {
-	rdbHost := os.Getenv("RDBHOST")
-	rdbPort := os.Getenv("RDBPORT")
-	rdbPass := os.Getenv("RDBPASS")
-	rdbUser := os.Getenv("RDBUSER")
+// RedisConfig koneksi Redis: RDBHOST (default localhost), RDBPORT (default 6379), RDBUSER, RDBPASS, RDBDB (default 0)
+type RedisConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	DB       int
+}
+
+func loadRedis(env *envReader) RedisConfig {
+	return RedisConfig{
+		Host:     env.str("RDBHOST", "localhost"),
+		Port:     env.str("RDBPORT", "6379"),
+		User:     env.str("RDBUSER", ""),
+		Password: env.str("RDBPASS", ""),
+		DB:       env.int("RDBDB", 0, 0),
+	}
+}
 
+// InitRedis membuat client Redis. Jika ping gagal client tetap dikembalikan beserta error-nya:
+// Redis hanya dipakai sebagai cache sehingga aplikasi bisa jalan (degraded) dan client
+// tersambung kembali sendiri begitu Redis hidup
+func InitRedis(cfg RedisConfig) (*redis.Client, error) {
 	client := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%s", rdbHost, rdbPort),
-		Password: rdbPass,
-		Username: rdbUser,
+		Addr:     net.JoinHostPort(cfg.Host, cfg.Port),
+		Password: cfg.Password,
+		Username: cfg.User,
+		DB:       cfg.DB,
+		// timeout pendek supaya request tidak tertahan lama saat Redis mati
+		DialTimeout:  2 * time.Second,
+		ReadTimeout:  time.Second,
+		WriteTimeout: time.Second,
+		MaxRetries:   1,
 	})
 
-	ctx := context.Background()
-	err := client.Ping(ctx).Err()
-	if err != nil {
-		log.Printf("Failed to connect to Redis: %v\n", err)
-		return nil, err
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return client, err
 	}
-	log.Printf("Redis Connected")
+	slog.Info("redis connected")
 	return client, nil
 }
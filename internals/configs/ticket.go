@@ -0,0 +1,55 @@
+package configs
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+// TicketConfig kunci tanda tangan QR tiket dan masa berlaku tiket setelah jam tayang
+type TicketConfig struct {
+	SigningKey     []byte
+	ValidAfterShow time.Duration
+}
+
+// revokedTicketKeys sha256 (hex) dari seed yang pernah ter-commit ke repository.
+// Siapa pun bisa memalsukan tiket dengan kunci ini, server menolak start sampai kuncinya diganti
+var revokedTicketKeys = map[string]bool{
+	"5a48e9a2f15f9689fa57eeae4f1c8c8697215360f654bb3b8969d9f55169bcf0": true,
+}
+
+// loadTicket TICKET_SIGNING_KEY (wajib, seed ed25519 32 byte, base64, buat dengan `openssl rand -base64 32`),
+// TICKET_VALID_AFTER_SHOW_MINUTES (default 180)
+func loadTicket(env *envReader) TicketConfig {
+	cfg := TicketConfig{ValidAfterShow: env.duration("TICKET_VALID_AFTER_SHOW_MINUTES", 180, 0, time.Minute)}
+
+	key := env.required("TICKET_SIGNING_KEY")
+	if key == "" {
+		return cfg
+	}
+	seed, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		env.fail("TICKET_SIGNING_KEY must be base64")
+		return cfg
+	}
+	if len(seed) != ed25519.SeedSize {
+		env.fail("TICKET_SIGNING_KEY must decode to %d bytes, got %d", ed25519.SeedSize, len(seed))
+		return cfg
+	}
+	fingerprint := sha256.Sum256(seed)
+	if revokedTicketKeys[hex.EncodeToString(fingerprint[:])] {
+		env.fail("TICKET_SIGNING_KEY has been revoked because it was published, generate a new one with `openssl rand -base64 32`")
+		return cfg
+	}
+	cfg.SigningKey = seed
+	return cfg
+}
+
+// InitTicketSigner membuat signer QR tiket
+func InitTicketSigner(cfg TicketConfig) (*pkg.TicketSigner, error) {
+	return pkg.NewTicketSigner(cfg.SigningKey, cfg.ValidAfterShow)
+}
@@ -0,0 +1,50 @@
+package configs
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestLoadTicketSigningKey(t *testing.T) {
+	fresh := make([]byte, 32)
+	rand.Read(fresh)
+
+	tests := []struct {
+		name    string
+		key     string
+		wantErr string
+	}{
+		{name: "missing", key: "", wantErr: "TICKET_SIGNING_KEY is required"},
+		{name: "blank", key: "   ", wantErr: "TICKET_SIGNING_KEY is required"},
+		{name: "not base64", key: "not-a-key!", wantErr: "must be base64"},
+		{name: "too short", key: base64.StdEncoding.EncodeToString(fresh[:16]), wantErr: "must decode to 32 bytes"},
+		{name: "leaked key", key: "JFUNQnw9oFwTMSRXOMkq+JL7oVGwur4B+VdllTPwQvM=", wantErr: "has been revoked"},
+		{name: "fresh key", key: base64.StdEncoding.EncodeToString(fresh)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TICKET_SIGNING_KEY", tt.key)
+			env := &envReader{}
+			cfg := loadTicket(env)
+
+			err := env.err()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if _, err := InitTicketSigner(cfg); err != nil {
+					t.Errorf("signer from valid key: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error %v, want %q", err, tt.wantErr)
+			}
+			if cfg.SigningKey != nil {
+				t.Error("rejected key still returned in config")
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/internals/utils"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+type AccountHandler struct {
+	acr    repositories.AccountStore
+	mailer pkg.Mailer
+	rules  models.AccountTokenRules
+	hc     *pkg.HashConfig
+}
+
+func NewAccountHandler(acr repositories.AccountStore, mailer pkg.Mailer, rules models.AccountTokenRules, hc *pkg.HashConfig) *AccountHandler {
+	return &AccountHandler{acr: acr, mailer: mailer, rules: rules, hc: hc}
+}
+
+// VerifyEmail godoc
+// @Summary     Verify Email
+// @Description Verifikasi email user memakai token sekali pakai yang dikirim lewat email saat register
+// @Tags        Auth
+// @Accept      json
+// @Produce     json
+// @Param       body body models.VerifyEmailRequest true "Token verifikasi"
+// @Success     200 {object} map[string]interface{} "Email berhasil diverifikasi"
+// @Failure     400 {object} map[string]interface{} "Bad Request - Token tidak valid, sudah dipakai atau expired"
+// @Failure     500 {object} map[string]interface{} "Internal Server Error"
+// @Router      /auth/verify [post]
+func (h *AccountHandler) VerifyEmail(ctx *gin.Context) {
+	var body models.VerifyEmailRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Token harus diisi",
+		})
+		return
+	}
+
+	if err := h.acr.VerifyEmail(ctx.Request.Context(), body.Token); err != nil {
+		if msg, ok := userTokenErrorMessage(err); ok {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   msg,
+			})
+			return
+		}
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "internal server error",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Email berhasil diverifikasi, silahkan login",
+	})
+}
+
+// ResendVerification godoc
+// @Summary     Resend Verification Email
+// @Description Kirim ulang email verifikasi. Respon selalu sama agar tidak membocorkan email yang terdaftar
+// @Tags        Auth
+// @Accept      json
+// @Produce     json
+// @Param       body body models.EmailRequest true "Email user"
+// @Success     200 {object} map[string]interface{} "Email verifikasi dikirim jika akun belum terverifikasi"
+// @Failure     400 {object} map[string]interface{} "Bad Request - Email tidak valid"
+// @Failure     500 {object} map[string]interface{} "Internal Server Error"
+// @Router      /auth/verify/resend [post]
+func (h *AccountHandler) ResendVerification(ctx *gin.Context) {
+	var body models.EmailRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Email harus diisi",
+		})
+		return
+	}
+
+	user, err := h.acr.GetUnverifiedUser(ctx.Request.Context(), body.Email)
+	if err == nil {
+		err = h.SendVerification(ctx.Request.Context(), user)
+	}
+	if err != nil && err.Error() != "user not found" && err.Error() != "user already verified" {
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "internal server error",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Jika akun belum terverifikasi, email verifikasi telah dikirim",
+	})
+}
+
+// ForgotPassword godoc
+// @Summary     Forgot Password
+// @Description Kirim token reset password ke email. Respon selalu sama agar tidak membocorkan email yang terdaftar
+// @Tags        Auth
+// @Accept      json
+// @Produce     json
+// @Param       body body models.EmailRequest true "Email user"
+// @Success     200 {object} map[string]interface{} "Email reset password dikirim jika email terdaftar"
+// @Failure     400 {object} map[string]interface{} "Bad Request - Email tidak valid"
+// @Failure     500 {object} map[string]interface{} "Internal Server Error"
+// @Router      /auth/password/forgot [post]
+func (h *AccountHandler) ForgotPassword(ctx *gin.Context) {
+	var body models.EmailRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Email harus diisi",
+		})
+		return
+	}
+
+	user, token, err := h.acr.CreatePasswordResetToken(ctx.Request.Context(), body.Email)
+	if err != nil {
+		if err.Error() != "user not found" {
+			utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "internal server error",
+			})
+			return
+		}
+	} else {
+		h.sendMail(pkg.Mail{
+			To:      user.Email,
+			Subject: "Reset password akun Tickitz",
+			Body: fmt.Sprintf("Halo,\n\nKami menerima permintaan reset password untuk akun Tickitz kamu.\n%s\n\nLink berlaku selama %s. Abaikan email ini jika kamu tidak meminta reset password.\n",
+				h.tokenLink("/reset-password", token), h.rules.ResetTTL),
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Jika email terdaftar, link reset password telah dikirim",
+	})
+}
+
+// ResetPassword godoc
+// @Summary     Reset Password
+// @Description Atur password baru memakai token reset password. Semua session user akan dicabut
+// @Tags        Auth
+// @Accept      json
+// @Produce     json
+// @Param       body body models.ResetPasswordRequest true "Token reset dan password baru"
+// @Success     200 {object} map[string]interface{} "Password berhasil direset"
+// @Failure     400 {object} map[string]interface{} "Bad Request - Token tidak valid atau password tidak memenuhi syarat"
+// @Failure     500 {object} map[string]interface{} "Internal Server Error"
+// @Router      /auth/password/reset [post]
+func (h *AccountHandler) ResetPassword(ctx *gin.Context) {
+	var body models.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Token dan password baru harus diisi",
+		})
+		return
+	}
+
+	if err := utils.ValidatePassword(body.NewPassword); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	hashedPassword, err := h.hc.GenHash(body.NewPassword)
+	if err != nil {
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "internal server error",
+		})
+		return
+	}
+
+	if err := h.acr.ResetPassword(ctx.Request.Context(), body.Token, hashedPassword); err != nil {
+		if msg, ok := userTokenErrorMessage(err); ok {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   msg,
+			})
+			return
+		}
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "internal server error",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Password berhasil direset, silahkan login kembali",
+	})
+}
+
+// SendVerification membuat token verifikasi baru dan mengirimkannya ke email user
+func (h *AccountHandler) SendVerification(rctx context.Context, user models.Users) error {
+	token, err := h.acr.CreateVerificationToken(rctx, user.Id)
+	if err != nil {
+		return err
+	}
+
+	h.sendMail(pkg.Mail{
+		To:      user.Email,
+		Subject: "Verifikasi email akun Tickitz",
+		Body: fmt.Sprintf("Halo,\n\nTerima kasih sudah mendaftar di Tickitz. Verifikasi email kamu melalui:\n%s\n\nLink berlaku selama %s.\n",
+			h.tokenLink("/verify-email", token), h.rules.VerifyTTL),
+	})
+	return nil
+}
+
+// sendMail mengirim email di background supaya respon tidak menunggu SMTP
+// (dan waktu respon tidak membocorkan apakah email terdaftar)
+func (h *AccountHandler) sendMail(mail pkg.Mail) {
+	sendMailAsync(h.mailer, mail)
+}
+
+func sendMailAsync(mailer pkg.Mailer, mail pkg.Mail) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := mailer.Send(ctx, mail); err != nil {
+			slog.Error("failed to send email", "error", err)
+		}
+	}()
+}
+
+// tokenLink link ke halaman frontend, atau token saja jika FRONTEND_URL tidak diisi
+func (h *AccountHandler) tokenLink(path, token string) string {
+	if h.rules.FrontendURL == "" {
+		return "Token: " + token
+	}
+	return h.rules.FrontendURL + path + "?token=" + url.QueryEscape(token)
+}
+
+func userTokenErrorMessage(err error) (string, bool) {
+	switch {
+	case strings.Contains(err.Error(), "invalid token"):
+		return "Token tidak valid", true
+	case strings.Contains(err.Error(), "token already used"):
+		return "Token sudah digunakan", true
+	case strings.Contains(err.Error(), "token expired"):
+		return "Token sudah expired, silahkan minta token baru", true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var mailTokenPattern = regexp.MustCompile(`\?token=(\S+)`)
+
+// receiveMail menunggu email yang dikirim handler di background dan mengambil token dari link-nya
+func (f *authFixture) receiveMail(t *testing.T, to string) string {
+	t.Helper()
+	select {
+	case mail := <-f.mails:
+		if mail.To != to {
+			t.Fatalf("mail sent to %s, want %s", mail.To, to)
+		}
+		match := mailTokenPattern.FindStringSubmatch(mail.Body)
+		if match == nil {
+			t.Fatalf("no token link in mail %q", mail.Body)
+		}
+		token, err := url.QueryUnescape(match[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	case <-time.After(time.Second):
+		t.Fatalf("no mail sent to %s", to)
+		return ""
+	}
+}
+
+func (f *authFixture) assertNoMail(t *testing.T) {
+	t.Helper()
+	select {
+	case mail := <-f.mails:
+		t.Errorf("unexpected mail to %s", mail.To)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func (f *authFixture) login(t *testing.T, email, password string) int {
+	t.Helper()
+	ctx, rec := newTestContext(t, http.MethodPost, map[string]string{"email": email, "password": password}, nil)
+	f.handler.Login(ctx)
+	return rec.Code
+}
+
+func TestVerifyEmail(t *testing.T) {
+	const email, password = "budi@example.com", "Rahasia#2025"
+	f := newAuthFixture()
+
+	ctx, rec := newTestContext(t, http.MethodPost, map[string]string{"email": email, "password": password}, nil)
+	f.handler.Register(ctx)
+	assertStatus(t, rec, http.StatusCreated)
+	token := f.receiveMail(t, email)
+
+	if code := f.login(t, email, password); code != http.StatusForbidden {
+		t.Errorf("login before verification: status %d, want %d", code, http.StatusForbidden)
+	}
+
+	// kirim ulang membuat token baru, respon sama untuk email yang tidak terdaftar
+	ctx, rec = newTestContext(t, http.MethodPost, map[string]string{"email": email}, nil)
+	f.account.ResendVerification(ctx)
+	assertStatus(t, rec, http.StatusOK)
+	resent := f.receiveMail(t, email)
+	if resent == token {
+		t.Error("resend reused the previous token")
+	}
+	ctx, rec = newTestContext(t, http.MethodPost, map[string]string{"email": "nobody@example.com"}, nil)
+	f.account.ResendVerification(ctx)
+	assertStatus(t, rec, http.StatusOK)
+	f.assertNoMail(t)
+
+	ctx, rec = newTestContext(t, http.MethodPost, map[string]string{"token": "bukan-token"}, nil)
+	f.account.VerifyEmail(ctx)
+	assertStatus(t, rec, http.StatusBadRequest)
+
+	ctx, rec = newTestContext(t, http.MethodPost, map[string]string{"token": resent}, nil)
+	f.account.VerifyEmail(ctx)
+	assertStatus(t, rec, http.StatusOK)
+
+	// token sekali pakai
+	ctx, rec = newTestContext(t, http.MethodPost, map[string]string{"token": resent}, nil)
+	f.account.VerifyEmail(ctx)
+	assertStatus(t, rec, http.StatusBadRequest)
+	if msg := decodeBody(t, rec)["error"]; msg != "Token sudah digunakan" {
+		t.Errorf("reused token error %q", msg)
+	}
+
+	if code := f.login(t, email, password); code != http.StatusOK {
+		t.Errorf("login after verification: status %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestForgotAndResetPassword(t *testing.T) {
+	const email, password, newPassword = "budi@example.com", "Rahasia#2025", "Baru#Sekali2025"
+	f := newAuthFixture()
+	f.users.addUser(t, f.hc, email, password, false)
+
+	// respon sama untuk email yang tidak terdaftar, tanpa email terkirim
+	ctx, rec := newTestContext(t, http.MethodPost, map[string]string{"email": "nobody@example.com"}, nil)
+	f.account.ForgotPassword(ctx)
+	assertStatus(t, rec, http.StatusOK)
+	f.assertNoMail(t)
+
+	ctx, rec = newTestContext(t, http.MethodPost, map[string]string{"email": email}, nil)
+	f.account.ForgotPassword(ctx)
+	assertStatus(t, rec, http.StatusOK)
+	token := f.receiveMail(t, email)
+
+	tests := []struct {
+		name string
+		body map[string]string
+		want int
+	}{
+		{name: "missing token", body: map[string]string{"new_password": newPassword}, want: http.StatusBadRequest},
+		{name: "weak password", body: map[string]string{"token": token, "new_password": "baru"}, want: http.StatusBadRequest},
+		{name: "invalid token", body: map[string]string{"token": "bukan-token", "new_password": newPassword}, want: http.StatusBadRequest},
+		{name: "reset", body: map[string]string{"token": token, "new_password": newPassword}, want: http.StatusOK},
+		{name: "token reused", body: map[string]string{"token": token, "new_password": "Lagi#Baru2025"}, want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		ctx, rec := newTestContext(t, http.MethodPost, tt.body, nil)
+		f.account.ResetPassword(ctx)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status %d, want %d, body %s", tt.name, rec.Code, tt.want, rec.Body.String())
+		}
+	}
+
+	user, err := f.users.GetEmailUserWithPasswordAndRole(t.Context(), email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := f.hc.CompareHashAndPassword(newPassword, user.Password); err != nil || !ok {
+		t.Errorf("new password not stored as hash (match %v, err %v)", ok, err)
+	}
+	// reset lewat email membuktikan kepemilikan email
+	if code := f.login(t, email, newPassword); code != http.StatusOK {
+		t.Errorf("login with new password: status %d, want %d", code, http.StatusOK)
+	}
+	if code := f.login(t, email, password); code != http.StatusBadRequest {
+		t.Errorf("login with old password: status %d, want %d", code, http.StatusBadRequest)
+	}
+}
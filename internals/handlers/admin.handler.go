@@ -1,9 +1,7 @@
 package handlers
 
 import (
-	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -16,16 +14,17 @@ import (
 )
 
 type MovieAdminHandler struct {
-	mar *repositories.MovieAdmin
+	mar    repositories.MovieAdminStore
+	upload utils.FileUploadConfig
 }
 
-func NewMovieAdminHandler(mar *repositories.MovieAdmin) *MovieAdminHandler {
-	return &MovieAdminHandler{mar: mar}
+func NewMovieAdminHandler(mar repositories.MovieAdminStore, upload utils.FileUploadConfig) *MovieAdminHandler {
+	return &MovieAdminHandler{mar: mar, upload: upload}
 }
 
 // AddMovie godoc
 // @Summary     Tambah Movie (Admin)
-// @Description Tambah data movie baru dengan upload gambar dan jadwal tayang
+// @Description Tambah data movie baru dengan upload gambar. Jadwal tayang dibuat lewat /admin/showtimes
 // @Tags        Admin-Movies
 // @Security    BearerAuth
 // @Accept      multipart/form-data
@@ -38,10 +37,6 @@ func NewMovieAdminHandler(mar *repositories.MovieAdmin) *MovieAdminHandler {
 // @Param       rating                   formData number   false "Rating film"
 // @Param       genres_id                formData string   true  "Array ID genre (comma-separated, e.g: 1,2,3)"
 // @Param       casts_id                 formData string   true  "Array ID cast (comma-separated, e.g: 1,2,3)"
-// @Param       showtime_dates[]         formData []string false "Array tanggal showtime (YYYY-MM-DD)" collectionFormat(multi)
-// @Param       showtime_times[]         formData []string false "Array waktu showtime (HH:MM)" collectionFormat(multi)
-// @Param       showtime_location_ids[]  formData []int    false "Array location ID untuk setiap showtime" collectionFormat(multi)
-// @Param       showtime_cinema_ids[]    formData []int    false "Array cinema ID untuk setiap showtime" collectionFormat(multi)
 // @Param       poster_image             formData file     true  "File gambar poster"
 // @Param       bg_path                  formData file     false "File gambar background"
 // @Success     201 {object} map[string]interface{} "{"success": true, "message": "Film berhasil ditambahkan", "data": {...}}"
@@ -63,7 +58,7 @@ func (h *MovieAdminHandler) AddMovie(ctx *gin.Context) {
 	if title == "" || synopsis == "" || durationStr == "" || releaseDateStr == "" || directorsIdStr == "" {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
-			"error":   "Semua field wajib diisi kecuali rating dan showtimes",
+			"error":   "Semua field wajib diisi kecuali rating",
 		})
 		return
 	}
@@ -140,71 +135,9 @@ func (h *MovieAdminHandler) AddMovie(ctx *gin.Context) {
 		}
 	}
 
-	// Parse showtimes dari form fields terpisah
-	var showtimes []models.Showtime
-
-	// Get arrays dari form
-	dates := ctx.PostFormArray("showtime_dates[]")
-	times := ctx.PostFormArray("showtime_times[]")
-	locationIdsStr := ctx.PostFormArray("showtime_location_ids[]")
-	cinemaIdsStr := ctx.PostFormArray("showtime_cinema_ids[]")
-
-	// Jika ada showtime data, validasi dan parse
-	if len(dates) > 0 || len(times) > 0 || len(locationIdsStr) > 0 || len(cinemaIdsStr) > 0 {
-		// Validasi jumlah array sama
-		if len(dates) != len(times) || len(dates) != len(locationIdsStr) || len(dates) != len(cinemaIdsStr) {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"success": false,
-				"error":   "Jumlah showtime date, time, location, dan cinema harus sama",
-			})
-			return
-		}
-
-		// Build showtimes array
-		for i := 0; i < len(dates); i++ {
-			locationId, err := strconv.Atoi(locationIdsStr[i])
-			if err != nil {
-				ctx.JSON(http.StatusBadRequest, gin.H{
-					"success": false,
-					"error":   fmt.Sprintf("Location ID showtime ke-%d harus berupa angka", i+1),
-				})
-				return
-			}
-
-			cinemaId, err := strconv.Atoi(cinemaIdsStr[i])
-			if err != nil {
-				ctx.JSON(http.StatusBadRequest, gin.H{
-					"success": false,
-					"error":   fmt.Sprintf("Cinema ID showtime ke-%d harus berupa angka", i+1),
-				})
-				return
-			}
-
-			// Validasi format date (YYYY-MM-DD)
-			if _, err := time.Parse("2006-01-02", dates[i]); err != nil {
-				ctx.JSON(http.StatusBadRequest, gin.H{
-					"success": false,
-					"error":   fmt.Sprintf("Format date showtime ke-%d harus YYYY-MM-DD", i+1),
-				})
-				return
-			}
-
-			// Validasi format time (HH:MM)
-			if _, err := time.Parse("15:04", times[i]); err != nil {
-				ctx.JSON(http.StatusBadRequest, gin.H{
-					"success": false,
-					"error":   fmt.Sprintf("Format time showtime ke-%d harus HH:MM", i+1),
-				})
-				return
-			}
-
-			showtimes = append(showtimes, models.Showtime{
-				Date:       dates[i],
-				Time:       times[i],
-				LocationId: locationId,
-				CinemasId:  cinemaId,
-			})
-		}
+	if hasShowtimeFields(ctx) {
+		rejectShowtimeFields(ctx)
+		return
 	}
 
 	// Parse release date
@@ -217,12 +150,7 @@ func (h *MovieAdminHandler) AddMovie(ctx *gin.Context) {
 		return
 	}
 
-	// File upload configuration
-	uploadConfig := utils.FileUploadConfig{
-		MaxSize:     5 * 1024 * 1024, // 5MB
-		UploadDir:   "./uploads",
-		AllowedExts: []string{".jpg", ".jpeg", ".png", ".gif", ".webp"},
-	}
+	uploadConfig := h.upload
 
 	// Upload poster image (required)
 	posterPath, err := utils.UploadImageFile(ctx, "poster_image", "posters", uploadConfig)
@@ -261,7 +189,6 @@ func (h *MovieAdminHandler) AddMovie(ctx *gin.Context) {
 		BgPath:          bgPath,
 		GenresId:        genresId,
 		CastsId:         castsId,
-		Showtimes:       showtimes,
 	})
 	if err != nil {
 		// Clean up uploaded files if database operation fails
@@ -270,7 +197,7 @@ func (h *MovieAdminHandler) AddMovie(ctx *gin.Context) {
 			utils.DeleteFile(*bgPath)
 		}
 
-		log.Println("AddMovie error:", err.Error())
+		utils.Logger(ctx.Request.Context()).Error("handler error", "op", "AddMovie", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   "internal server error",
@@ -307,7 +234,7 @@ func (h *MovieAdminHandler) GetAllMovies(ctx *gin.Context) {
 			})
 			return
 		}
-		log.Println("GetAllMovies error:", err.Error())
+		utils.Logger(ctx.Request.Context()).Error("handler error", "op", "GetAllMovies", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   "internal server error",
@@ -326,7 +253,7 @@ func (h *MovieAdminHandler) GetAllMovies(ctx *gin.Context) {
 
 // UpdateMovie godoc
 // @Summary     Update Movie Comprehensive (Admin)
-// @Description Update data film secara komprehensif berdasarkan ID dengan upload gambar opsional
+// @Description Update data film secara komprehensif berdasarkan ID dengan upload gambar opsional. Jadwal tayang diubah lewat /admin/showtimes
 // @Tags        Admin-Movies
 // @Security    BearerAuth
 // @Accept      multipart/form-data
@@ -446,25 +373,12 @@ func (h *MovieAdminHandler) UpdateMovie(ctx *gin.Context) {
 		req.CastsId = castsId
 	}
 
-	// Handle showtimes (jika ada)
-	if showtimesJSON := ctx.PostForm("showtimes"); showtimesJSON != "" {
-		var showtimes []models.Showtime
-		if err := json.Unmarshal([]byte(showtimesJSON), &showtimes); err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"success": false,
-				"error":   "Format showtimes tidak valid",
-			})
-			return
-		}
-		req.Showtimes = showtimes
+	if hasShowtimeFields(ctx) {
+		rejectShowtimeFields(ctx)
+		return
 	}
 
-	// File upload configuration
-	uploadConfig := utils.FileUploadConfig{
-		MaxSize:     5 * 1024 * 1024, // 5MB
-		UploadDir:   "./uploads",
-		AllowedExts: []string{".jpg", ".jpeg", ".png", ".gif", ".webp"},
-	}
+	uploadConfig := h.upload
 
 	var uploadedFiles []string
 
@@ -520,7 +434,7 @@ func (h *MovieAdminHandler) UpdateMovie(ctx *gin.Context) {
 				"error":   "Tidak ada field yang diupdate",
 			})
 		default:
-			log.Println("UpdateMovie error:", err.Error())
+			utils.Logger(ctx.Request.Context()).Error("handler error", "op", "UpdateMovie", "error", err)
 			ctx.JSON(http.StatusInternalServerError, gin.H{
 				"success": false,
 				"error":   "Internal server error",
@@ -616,7 +530,7 @@ func (h *MovieAdminHandler) DeleteMovie(ctx *gin.Context) {
 			})
 			return
 		}
-		log.Println("DeleteMovie error:", err.Error())
+		utils.Logger(ctx.Request.Context()).Error("handler error", "op", "DeleteMovie", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   "internal server error",
@@ -629,3 +543,22 @@ func (h *MovieAdminHandler) DeleteMovie(ctx *gin.Context) {
 		"message": "Film berhasil dihapus",
 	})
 }
+
+// hasShowtimeFields form lama yang masih mengirim jadwal tayang bersama data movie
+func hasShowtimeFields(ctx *gin.Context) bool {
+	for _, field := range []string{"showtimes", "showtime_dates[]", "showtime_times[]", "showtime_location_ids[]", "showtime_cinema_ids[]"} {
+		if _, ok := ctx.GetPostFormArray(field); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// rejectShowtimeFields jadwal tayang hanya dibuat lewat /admin/showtimes supaya selalu dicek bentrok jadwal
+// dan jadwal yang sudah punya order tidak ikut terhapus
+func rejectShowtimeFields(ctx *gin.Context) {
+	ctx.JSON(http.StatusBadRequest, gin.H{
+		"success": false,
+		"error":   "Jadwal tayang tidak bisa diatur lewat endpoint movie, gunakan /admin/showtimes",
+	})
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raihaninkam/tickitz/internals/utils"
+)
+
+// multipartContext context gin dengan body multipart/form-data, field boleh berulang
+func multipartContext(t *testing.T, method string, fields [][2]string, params ...gin.Param) (*gin.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	for _, field := range fields {
+		if err := form.WriteField(field[0], field[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	form.Close()
+
+	rec := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(rec)
+	ctx.Request = httptest.NewRequest(method, "/", &buf)
+	ctx.Request.Header.Set("Content-Type", form.FormDataContentType())
+	ctx.Params = params
+	return ctx, rec
+}
+
+// TestMovieEndpointsRejectShowtimes jadwal tayang lewat endpoint movie ditolak sebelum menyentuh
+// repository (store nil akan panic jika terpanggil)
+func TestMovieEndpointsRejectShowtimes(t *testing.T) {
+	handler := NewMovieAdminHandler(nil, utils.FileUploadConfig{})
+	movie := [][2]string{
+		{"title", "Tickitz"},
+		{"synopsis", "Film"},
+		{"duration_minutes", "120"},
+		{"release_date", "2025-10-01"},
+		{"directors_id", "1"},
+	}
+
+	ctx, rec := multipartContext(t, http.MethodPost, append(movie,
+		[2]string{"showtime_dates[]", "2025-10-30"},
+		[2]string{"showtime_times[]", "19:30"},
+		[2]string{"showtime_location_ids[]", "1"},
+		[2]string{"showtime_cinema_ids[]", "1"},
+	))
+	handler.AddMovie(ctx)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "/admin/showtimes") {
+		t.Errorf("add movie with showtimes: status %d, body %s", rec.Code, rec.Body.String())
+	}
+
+	ctx, rec = multipartContext(t, http.MethodPatch, [][2]string{
+		{"title", "Tickitz"},
+		{"showtimes", `[{"date":"2025-10-30","time":"19:30","location_id":1,"cinemas_id":1}]`},
+	}, gin.Param{Key: "movieId", Value: "1"})
+	handler.UpdateMovie(ctx)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "/admin/showtimes") {
+		t.Errorf("update movie with showtimes: status %d, body %s", rec.Code, rec.Body.String())
+	}
+}
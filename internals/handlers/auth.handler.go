@@ -1,13 +1,12 @@
 package handlers
 
 import (
-	"log"
 	"net/http"
-	"os"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/raihaninkam/tickitz/internals/middlewares"
 	"github.com/raihaninkam/tickitz/internals/models"
 	"github.com/raihaninkam/tickitz/internals/repositories"
 	"github.com/raihaninkam/tickitz/internals/utils"
@@ -15,16 +14,22 @@ import (
 )
 
 type AuthHandler struct {
-	ar *repositories.AuthRepository
+	ar      repositories.AuthStore
+	sr      repositories.SessionStore
+	store   repositories.TokenRevocationStore
+	account *AccountHandler
+	lr      repositories.LockoutStore
+	hc      *pkg.HashConfig
+	jwt     pkg.JWTConfig
 }
 
-func NewAuthHandler(ar *repositories.AuthRepository) *AuthHandler {
-	return &AuthHandler{ar: ar}
+func NewAuthHandler(ar repositories.AuthStore, sr repositories.SessionStore, store repositories.TokenRevocationStore, account *AccountHandler, lr repositories.LockoutStore, hc *pkg.HashConfig, jwt pkg.JWTConfig) *AuthHandler {
+	return &AuthHandler{ar: ar, sr: sr, store: store, account: account, lr: lr, hc: hc, jwt: jwt}
 }
 
 // Register godoc
 // @Summary     Register User
-// @Description Daftar User baru dengan email dan password. Password akan di-hash sebelum disimpan.
+// @Description Daftar User baru dengan email dan password. Password akan di-hash sebelum disimpan dan email verifikasi dikirim ke user.
 // @Tags        Auth
 // @Accept      json
 // @Produce     json
@@ -45,7 +50,7 @@ func (a *AuthHandler) Register(ctx *gin.Context) {
 			})
 			return
 		}
-		log.Println("Internal Server Error.\nCause: ", err.Error())
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   "internal server error",
@@ -74,7 +79,7 @@ func (a *AuthHandler) Register(ctx *gin.Context) {
 	// cek apakah email sudah terdaftar
 	exists, err := a.ar.CheckEmailExists(ctx.Request.Context(), body.Email)
 	if err != nil {
-		log.Println("Internal Server Error.\nCause: ", err.Error())
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   "internal server error",
@@ -90,11 +95,9 @@ func (a *AuthHandler) Register(ctx *gin.Context) {
 	}
 
 	// hash password sebelum disimpan
-	hc := pkg.NewHashConfig()
-	hc.UseRecommended() // menggunakan konfigurasi yang direkomendasikan
-	hashedPassword, err := hc.GenHash(body.Password)
+	hashedPassword, err := a.hc.GenHash(body.Password)
 	if err != nil {
-		log.Println("Internal Server Error.\nCause: ", err.Error())
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   "internal server error",
@@ -103,7 +106,7 @@ func (a *AuthHandler) Register(ctx *gin.Context) {
 	}
 
 	// simpan user ke database
-	err = a.ar.RegisterUserWithProfile(ctx.Request.Context(), body.Email, hashedPassword)
+	userId, err := a.ar.RegisterUserWithProfile(ctx.Request.Context(), body.Email, hashedPassword)
 	if err != nil {
 		if strings.Contains(err.Error(), "email already exists") {
 			ctx.JSON(http.StatusConflict, gin.H{
@@ -112,7 +115,7 @@ func (a *AuthHandler) Register(ctx *gin.Context) {
 			})
 			return
 		}
-		log.Println("Internal Server Error.\nCause: ", err.Error())
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   "internal server error",
@@ -120,22 +123,29 @@ func (a *AuthHandler) Register(ctx *gin.Context) {
 		return
 	}
 
+	// kirim email verifikasi, jika gagal user masih bisa minta kirim ulang
+	if err := a.account.SendVerification(ctx.Request.Context(), models.Users{Id: userId, Email: body.Email}); err != nil {
+		utils.Logger(ctx.Request.Context()).Warn("failed to send verification email", "error", err)
+	}
+
 	// response sukses tanpa data user
 	ctx.JSON(http.StatusCreated, gin.H{
 		"success": true,
-		"message": "User berhasil didaftarkan",
+		"message": "User berhasil didaftarkan, silahkan cek email untuk verifikasi akun",
 	})
 }
 
 // Login godoc
 // @Summary     Login User
-// @Description Login dengan email dan password. Jika sukses, akan mengembalikan JWT Token untuk autentikasi.
+// @Description Login dengan email dan password. Jika sukses, akan mengembalikan JWT Token (30 menit) dan refresh token untuk session/device ini.
 // @Tags        Auth
 // @Accept      json
 // @Produce     json
 // @Param       body body models.UserAuth true "Login Request"
 // @Success     200 {object} map[string]interface{} "Berhasil login, kembalikan token"
 // @Failure     400 {object} map[string]interface{} "Bad Request - Email/Password salah atau input tidak valid"
+// @Failure     403 {object} map[string]interface{} "Forbidden - Email belum diverifikasi"
+// @Failure     429 {object} map[string]interface{} "Too Many Requests - Akun terkunci sementara, lihat header Retry-After"
 // @Failure     500 {object} map[string]interface{} "Internal Server Error"
 // @Router      /auth/login [post]
 func (a *AuthHandler) Login(ctx *gin.Context) {
@@ -149,7 +159,7 @@ func (a *AuthHandler) Login(ctx *gin.Context) {
 			})
 			return
 		}
-		log.Println("Internal Server Error.\nCause: ", err.Error())
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   "internal server error",
@@ -174,17 +184,31 @@ func (a *AuthHandler) Login(ctx *gin.Context) {
 		return
 	}
 
+	// tolak login selama akun terkunci karena terlalu banyak percobaan gagal
+	lockedFor, err := a.lr.LockedFor(ctx.Request.Context(), body.Email)
+	if err != nil {
+		utils.Logger(ctx.Request.Context()).Error("login lockout check failed", "error", err)
+	}
+	if lockedFor > 0 {
+		middlewares.AbortTooManyRequests(ctx, lockedFor, "Terlalu banyak percobaan login gagal")
+		return
+	}
+
 	// ambil data user dari database
 	user, err := a.ar.GetEmailUserWithPasswordAndRole(ctx.Request.Context(), body.Email)
 	if err != nil {
 		if strings.Contains(err.Error(), "user not found") {
+			// email yang tidak terdaftar juga dihitung supaya respon tidak membedakan keduanya
+			if a.recordLoginFailure(ctx, body.Email) {
+				return
+			}
 			ctx.JSON(http.StatusBadRequest, gin.H{
 				"success": false,
 				"error":   "Email atau password salah",
 			})
 			return
 		}
-		log.Println("Internal Server Error.\nCause: ", err.Error())
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   "internal server error",
@@ -193,16 +217,15 @@ func (a *AuthHandler) Login(ctx *gin.Context) {
 	}
 
 	// bandingkan password menggunakan hash function Anda
-	hc := pkg.NewHashConfig()
-	isMatched, err := hc.CompareHashAndPassword(body.Password, user.Password)
+	isMatched, err := a.hc.CompareHashAndPassword(body.Password, user.Password)
 	if err != nil {
-		log.Println("Internal Server Error.\nCause: ", err.Error())
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
 		// Cek jika error terkait dengan hash format atau crypto
 		if strings.Contains(err.Error(), "hash") ||
 			strings.Contains(err.Error(), "crypto") ||
 			strings.Contains(err.Error(), "argon2id") ||
 			strings.Contains(err.Error(), "format") {
-			log.Println("Error during password hashing/comparison")
+			utils.Logger(ctx.Request.Context()).Error("password hash comparison failed")
 		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -213,6 +236,9 @@ func (a *AuthHandler) Login(ctx *gin.Context) {
 
 	// jika password tidak cocok
 	if !isMatched {
+		if a.recordLoginFailure(ctx, body.Email) {
+			return
+		}
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
 			"error":   "Email atau password salah",
@@ -220,11 +246,35 @@ func (a *AuthHandler) Login(ctx *gin.Context) {
 		return
 	}
 
-	// jika match, buat JWT token
-	claims := pkg.NewJWTClaims(user.Id, user.Role)
-	jwtToken, err := claims.GenToken()
+	if err := a.lr.Reset(ctx.Request.Context(), body.Email); err != nil {
+		utils.Logger(ctx.Request.Context()).Error("login lockout reset failed", "error", err)
+	}
+
+	// akun harus diverifikasi lewat email sebelum bisa login
+	if !user.IsVerified {
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Email belum diverifikasi, silahkan cek email kamu",
+		})
+		return
+	}
+
+	// jika match, buat session (refresh token) untuk device ini
+	session, err := a.sr.CreateSession(ctx.Request.Context(), user.Id, user.Role, ctx.Request.UserAgent(), ctx.ClientIP())
+	if err != nil {
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "internal server error",
+		})
+		return
+	}
+
+	// buat JWT token yang terikat ke session
+	claims := pkg.NewSessionJWTClaims(user.Id, user.Role, session.SessionId)
+	jwtToken, err := claims.GenToken(a.jwt)
 	if err != nil {
-		log.Println("Internal Server Error.\nCause: ", err.Error())
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   "internal server error",
@@ -233,11 +283,155 @@ func (a *AuthHandler) Login(ctx *gin.Context) {
 	}
 
 	// response sukses dengan token
+	ctx.JSON(http.StatusOK, gin.H{
+		"success":            true,
+		"message":            "Login berhasil",
+		"token":              jwtToken,
+		"role":               claims.Role,
+		"refresh_token":      session.RefreshToken,
+		"refresh_expires_at": session.ExpiresAt,
+		"session_id":         session.SessionId,
+	})
+}
+
+// RefreshToken godoc
+// @Summary     Refresh Token
+// @Description Tukar refresh token dengan access token baru. Refresh token dirotasi setiap dipakai; refresh token lama yang dipakai ulang akan mencabut seluruh session.
+// @Tags        Auth
+// @Accept      json
+// @Produce     json
+// @Param       body body models.RefreshTokenRequest true "Refresh Token"
+// @Success     200 {object} map[string]interface{} "Token baru"
+// @Failure     400 {object} map[string]interface{} "Bad Request - refresh_token kosong"
+// @Failure     401 {object} map[string]interface{} "Refresh token tidak valid, expired atau session dicabut"
+// @Failure     500 {object} map[string]interface{} "Internal Server Error"
+// @Router      /auth/refresh [post]
+func (a *AuthHandler) RefreshToken(ctx *gin.Context) {
+	var body models.RefreshTokenRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "refresh_token harus diisi",
+		})
+		return
+	}
+
+	session, err := a.sr.RotateRefreshToken(ctx.Request.Context(), body.RefreshToken, ctx.Request.UserAgent(), ctx.ClientIP())
+	if err != nil {
+		switch err.Error() {
+		case "invalid refresh token", "refresh token expired", "session revoked":
+			ctx.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "Silahkan login kembali",
+			})
+		case "refresh token reused":
+			ctx.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "Refresh token sudah pernah dipakai, semua token di device ini dicabut. Silahkan login kembali",
+			})
+		default:
+			utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "internal server error",
+			})
+		}
+		return
+	}
+
+	claims := pkg.NewSessionJWTClaims(session.UserId, session.Role, session.SessionId)
+	jwtToken, err := claims.GenToken(a.jwt)
+	if err != nil {
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "internal server error",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success":            true,
+		"message":            "Token berhasil diperbarui",
+		"token":              jwtToken,
+		"role":               claims.Role,
+		"refresh_token":      session.RefreshToken,
+		"refresh_expires_at": session.ExpiresAt,
+		"session_id":         session.SessionId,
+	})
+}
+
+// GetSessions godoc
+// @Summary      List Session
+// @Description  Daftar session (device) yang masih aktif untuk user yang login
+// @Tags         Auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   models.UserSession
+// @Failure      401  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /auth/sessions [get]
+func (a *AuthHandler) GetSessions(ctx *gin.Context) {
+	claims, exists := ctx.Get("claims")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+	user, ok := claims.(pkg.Claims)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+
+	sessions, err := a.sr.ListSessions(ctx.Request.Context(), user.UserId, user.SessionId)
+	if err != nil {
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"message": "Login berhasil",
-		"token":   jwtToken,
-		"role":    claims.Role,
+		"data":    sessions,
+	})
+}
+
+// RevokeSession godoc
+// @Summary      Cabut Session
+// @Description  Logout sebuah device: session dan seluruh refresh token-nya dicabut, access token dari session tersebut ikut ditolak
+// @Tags         Auth
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Session ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /auth/sessions/{id} [delete]
+func (a *AuthHandler) RevokeSession(ctx *gin.Context) {
+	claims, exists := ctx.Get("claims")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+	user, ok := claims.(pkg.Claims)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+
+	if err := a.sr.RevokeSession(ctx.Request.Context(), user.UserId, ctx.Param("id")); err != nil {
+		if err.Error() == "session not found" {
+			ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Session tidak ditemukan"})
+			return
+		}
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Session berhasil dicabut",
 	})
 }
 
@@ -275,7 +469,7 @@ func (a *AuthHandler) SecureLogout(ctx *gin.Context) {
 	tokenString := tokenParts[1]
 
 	claims := &pkg.Claims{}
-	err := claims.VerifyToken(tokenString)
+	err := claims.VerifyToken(tokenString, a.jwt)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
@@ -284,22 +478,72 @@ func (a *AuthHandler) SecureLogout(ctx *gin.Context) {
 		return
 	}
 
-	parsedToken, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(os.Getenv("JWT_SECRET")), nil
+	// token dicabut berdasarkan jti sampai masa berlakunya habis
+	expiryTime := claims.ExpiresAt.Time
+	err = a.store.RevokeToken(ctx.Request.Context(), claims.RevocationKey(tokenString), expiryTime)
+	if err != nil {
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "internal server error",
+		})
+		return
+	}
+
+	// session device ini ikut berakhir, refresh token tidak bisa dipakai lagi
+	if claims.SessionId != "" {
+		if err := a.sr.EndSession(ctx.Request.Context(), claims.SessionId); err != nil {
+			utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "internal server error",
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Logout berhasil. Token telah diblacklist.",
+		"user": gin.H{
+			"id":   claims.UserId,
+			"role": claims.Role,
+		},
 	})
+}
 
-	if err != nil || !parsedToken.Valid {
+// UnlockUser godoc
+// @Summary      Unlock User Login
+// @Description  Buka lockout login user yang terkunci karena terlalu banyak percobaan gagal (khusus admin)
+// @Tags         Auth
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "User ID"
+// @Success      200 {object} map[string]interface{} "Lockout berhasil dibuka"
+// @Failure      400 {object} map[string]interface{} "Bad Request - User ID tidak valid"
+// @Failure      404 {object} map[string]interface{} "Not Found - User tidak ditemukan"
+// @Failure      500 {object} map[string]interface{} "Internal Server Error"
+// @Router       /admin/users/{id}/unlock [post]
+func (a *AuthHandler) UnlockUser(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
-			"error":   "Token tidak valid",
+			"error":   "User ID harus berupa angka",
 		})
 		return
 	}
 
-	expiryTime := claims.ExpiresAt.Time
-	err = a.ar.AddToBlacklist(ctx.Request.Context(), tokenString, expiryTime)
+	email, err := a.lr.UnlockUser(ctx.Request.Context(), userID)
 	if err != nil {
-		log.Println("Internal Server Error.\nCause: ", err.Error())
+		if err.Error() == "user not found" {
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "User tidak ditemukan",
+			})
+			return
+		}
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   "internal server error",
@@ -309,10 +553,24 @@ func (a *AuthHandler) SecureLogout(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"message": "Logout berhasil. Token telah diblacklist.",
-		"user": gin.H{
-			"id":   claims.UserId,
-			"role": claims.Role,
+		"message": "Lockout login user berhasil dibuka",
+		"data": gin.H{
+			"id":    userID,
+			"email": email,
 		},
 	})
 }
+
+// recordLoginFailure mencatat login gagal, true jika akun baru saja dikunci dan respon 429 sudah dikirim
+func (a *AuthHandler) recordLoginFailure(ctx *gin.Context, email string) bool {
+	lockedFor, err := a.lr.RecordFailure(ctx.Request.Context(), email)
+	if err != nil {
+		utils.Logger(ctx.Request.Context()).Error("login lockout record failed", "error", err)
+		return false
+	}
+	if lockedFor > 0 {
+		middlewares.AbortTooManyRequests(ctx, lockedFor, "Terlalu banyak percobaan login gagal")
+		return true
+	}
+	return false
+}
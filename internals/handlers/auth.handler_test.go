@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+type authFixture struct {
+	handler  *AuthHandler
+	account  *AccountHandler
+	users    *fakeAuthStore
+	sessions *fakeSessionStore
+	lockout  *fakeLockoutStore
+	mails    fakeMailer
+	hc       *pkg.HashConfig
+}
+
+func newAuthFixture() *authFixture {
+	f := &authFixture{
+		users:    newFakeAuthStore(),
+		sessions: &fakeSessionStore{},
+		lockout:  newFakeLockoutStore(3),
+		mails:    make(fakeMailer, 1),
+		hc:       testHashConfig(),
+	}
+	rules := models.AccountTokenRules{VerifyTTL: time.Hour, ResetTTL: time.Hour, FrontendURL: "http://localhost:5173"}
+	f.account = NewAccountHandler(newFakeAccountStore(f.users), f.mails, rules, f.hc)
+	f.handler = NewAuthHandler(f.users, f.sessions, fakeRevocationStore{}, f.account, f.lockout, f.hc, testJWT)
+	return f
+}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     map[string]string
+		existing string
+		want     int
+	}{
+		{name: "missing password", body: map[string]string{"email": "budi@example.com"}, want: http.StatusBadRequest},
+		{name: "invalid email", body: map[string]string{"email": "budi", "password": "Rahasia#2025"}, want: http.StatusBadRequest},
+		{name: "weak password", body: map[string]string{"email": "budi@example.com", "password": "rahasia2025"}, want: http.StatusBadRequest},
+		{name: "email already registered", body: map[string]string{"email": "budi@example.com", "password": "Rahasia#2025"}, existing: "budi@example.com", want: http.StatusConflict},
+		{name: "created", body: map[string]string{"email": "budi@example.com", "password": "Rahasia#2025"}, want: http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newAuthFixture()
+			if tt.existing != "" {
+				f.users.addUser(t, f.hc, tt.existing, "Lama#2025", true)
+			}
+
+			ctx, rec := newTestContext(t, http.MethodPost, tt.body, nil)
+			f.handler.Register(ctx)
+			assertStatus(t, rec, tt.want)
+
+			if tt.want != http.StatusCreated {
+				return
+			}
+			user, err := f.users.GetEmailUserWithPasswordAndRole(t.Context(), tt.body["email"])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if user.Password == tt.body["password"] {
+				t.Error("password stored without hashing")
+			}
+			select {
+			case mail := <-f.mails:
+				if mail.To != tt.body["email"] {
+					t.Errorf("verification mail sent to %s, want %s", mail.To, tt.body["email"])
+				}
+			case <-time.After(time.Second):
+				t.Error("verification mail not sent")
+			}
+		})
+	}
+}
+
+func TestLogin(t *testing.T) {
+	const password = "Rahasia#2025"
+
+	t.Run("unknown email", func(t *testing.T) {
+		f := newAuthFixture()
+		ctx, rec := newTestContext(t, http.MethodPost, map[string]string{"email": "nobody@example.com", "password": password}, nil)
+		f.handler.Login(ctx)
+		assertStatus(t, rec, http.StatusBadRequest)
+	})
+
+	t.Run("wrong password locks account", func(t *testing.T) {
+		f := newAuthFixture()
+		f.users.addUser(t, f.hc, "budi@example.com", password, true)
+		body := map[string]string{"email": "budi@example.com", "password": "Salah#2025"}
+
+		for range f.lockout.maxAttempts - 1 {
+			ctx, rec := newTestContext(t, http.MethodPost, body, nil)
+			f.handler.Login(ctx)
+			assertStatus(t, rec, http.StatusBadRequest)
+		}
+		ctx, rec := newTestContext(t, http.MethodPost, body, nil)
+		f.handler.Login(ctx)
+		assertStatus(t, rec, http.StatusTooManyRequests)
+		if rec.Header().Get("Retry-After") == "" {
+			t.Error("missing Retry-After header")
+		}
+
+		// password benar tetap ditolak selama terkunci
+		ctx, rec = newTestContext(t, http.MethodPost, map[string]string{"email": "budi@example.com", "password": password}, nil)
+		f.handler.Login(ctx)
+		assertStatus(t, rec, http.StatusTooManyRequests)
+	})
+
+	t.Run("unverified email", func(t *testing.T) {
+		f := newAuthFixture()
+		f.users.addUser(t, f.hc, "budi@example.com", password, false)
+		ctx, rec := newTestContext(t, http.MethodPost, map[string]string{"email": "budi@example.com", "password": password}, nil)
+		f.handler.Login(ctx)
+		assertStatus(t, rec, http.StatusForbidden)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		f := newAuthFixture()
+		user := f.users.addUser(t, f.hc, "budi@example.com", password, true)
+		ctx, rec := newTestContext(t, http.MethodPost, map[string]string{"email": "budi@example.com", "password": password}, nil)
+		f.handler.Login(ctx)
+		assertStatus(t, rec, http.StatusOK)
+
+		body := decodeBody(t, rec)
+		token, _ := body["token"].(string)
+		var claims pkg.Claims
+		if err := claims.VerifyToken(token, testJWT); err != nil {
+			t.Fatalf("invalid access token: %v", err)
+		}
+		if claims.UserId != user.Id || claims.Role != "user" || claims.SessionId != body["session_id"] {
+			t.Errorf("claims %+v do not match user %d and session %v", claims, user.Id, body["session_id"])
+		}
+		if body["refresh_token"] == "" || body["refresh_token"] == nil {
+			t.Error("missing refresh_token")
+		}
+	})
+}
+
+func TestRefreshToken(t *testing.T) {
+	f := newAuthFixture()
+	user := f.users.addUser(t, f.hc, "budi@example.com", "Rahasia#2025", true)
+	session, err := f.sessions.CreateSession(t.Context(), user.Id, user.Role, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	refresh := func(token string) (*httptest.ResponseRecorder, map[string]any) {
+		ctx, rec := newTestContext(t, http.MethodPost, map[string]string{"refresh_token": token}, nil)
+		f.handler.RefreshToken(ctx)
+		if rec.Code != http.StatusOK {
+			return rec, nil
+		}
+		return rec, decodeBody(t, rec)
+	}
+
+	ctx, rec := newTestContext(t, http.MethodPost, map[string]string{}, nil)
+	f.handler.RefreshToken(ctx)
+	assertStatus(t, rec, http.StatusBadRequest)
+
+	rec, _ = refresh("refresh-unknown")
+	assertStatus(t, rec, http.StatusUnauthorized)
+
+	rec, body := refresh(session.RefreshToken)
+	assertStatus(t, rec, http.StatusOK)
+	rotated, _ := body["refresh_token"].(string)
+	if rotated == "" || rotated == session.RefreshToken {
+		t.Fatalf("refresh token not rotated: %q", rotated)
+	}
+	var claims pkg.Claims
+	if err := claims.VerifyToken(body["token"].(string), testJWT); err != nil {
+		t.Fatalf("invalid access token: %v", err)
+	}
+	if claims.UserId != user.Id || claims.SessionId != session.SessionId {
+		t.Errorf("claims %+v do not match user %d session %s", claims, user.Id, session.SessionId)
+	}
+
+	// token lama dipakai ulang: session dicabut, token hasil rotasi ikut tidak berlaku
+	rec, _ = refresh(session.RefreshToken)
+	assertStatus(t, rec, http.StatusUnauthorized)
+	if msg := decodeBody(t, rec)["error"].(string); !strings.Contains(msg, "sudah pernah dipakai") {
+		t.Errorf("reuse error %q", msg)
+	}
+	if !f.sessions.isRevoked(session.SessionId) {
+		t.Error("session not revoked after refresh token reuse")
+	}
+	rec, _ = refresh(rotated)
+	assertStatus(t, rec, http.StatusUnauthorized)
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raihaninkam/tickitz/internals/utils"
+)
+
+type CacheHandler struct{}
+
+func NewCacheHandler() *CacheHandler {
+	return &CacheHandler{}
+}
+
+// GetCacheStats godoc
+// @Summary      Statistik cache (Admin)
+// @Description  Counter hit, stale hit, negative hit, miss dan load cache Redis sejak server jalan
+// @Tags         Admin-Cache
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  utils.CacheStats
+// @Failure      401  {object}  map[string]interface{}
+// @Router       /admin/cache/stats [get]
+func (c *CacheHandler) GetCacheStats(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    utils.GetCacheStats(),
+	})
+}
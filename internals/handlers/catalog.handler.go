@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/internals/utils"
+)
+
+// CatalogHandler endpoint genre, cast dan director. label dipakai di pesan error, contoh "Genre"
+type CatalogHandler struct {
+	cr    repositories.CatalogStore
+	label string
+}
+
+func NewCatalogHandler(cr repositories.CatalogStore, label string) *CatalogHandler {
+	return &CatalogHandler{cr: cr, label: label}
+}
+
+// GetAll godoc
+// @Summary      List genre / cast / director
+// @Description  Ambil semua genre, cast atau director beserta jumlah movie-nya
+// @Tags         Catalog
+// @Produce      json
+// @Success      200  {array}   models.CatalogItem
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /genres [get]
+// @Router       /casts [get]
+// @Router       /directors [get]
+func (c *CatalogHandler) GetAll(ctx *gin.Context) {
+	items, err := c.cr.GetAll(ctx.Request.Context())
+	if err != nil {
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    items,
+	})
+}
+
+// GetMovies godoc
+// @Summary      Movie per genre / cast / director
+// @Description  Ambil daftar movie milik sebuah genre, cast atau director
+// @Tags         Catalog
+// @Produce      json
+// @Param        id   path      int  true  "Genre / Cast / Director ID"
+// @Success      200  {array}   models.CatalogMovie
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /genres/{id}/movies [get]
+// @Router       /casts/{id}/movies [get]
+// @Router       /directors/{id}/movies [get]
+func (c *CatalogHandler) GetMovies(ctx *gin.Context) {
+	id, ok := c.parseID(ctx)
+	if !ok {
+		return
+	}
+
+	movies, err := c.cr.GetMovies(ctx.Request.Context(), id)
+	if err != nil {
+		c.writeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    movies,
+		"total":   len(movies),
+	})
+}
+
+// Create godoc
+// @Summary      Tambah genre / cast / director (Admin)
+// @Tags         Admin-Catalog
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      models.CatalogRequest  true  "Nama"
+// @Success      201   {object}  models.CatalogItem
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      409   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /admin/genres [post]
+// @Router       /admin/casts [post]
+// @Router       /admin/directors [post]
+func (c *CatalogHandler) Create(ctx *gin.Context) {
+	name, ok := c.bindName(ctx)
+	if !ok {
+		return
+	}
+
+	item, err := c.cr.Create(ctx.Request.Context(), name)
+	if err != nil {
+		c.writeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": c.label + " berhasil ditambahkan",
+		"data":    item,
+	})
+}
+
+// Rename godoc
+// @Summary      Ubah nama genre / cast / director (Admin)
+// @Tags         Admin-Catalog
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                    true  "Genre / Cast / Director ID"
+// @Param        body  body      models.CatalogRequest  true  "Nama baru"
+// @Success      200   {object}  models.CatalogItem
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      409   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /admin/genres/{id} [put]
+// @Router       /admin/casts/{id} [put]
+// @Router       /admin/directors/{id} [put]
+func (c *CatalogHandler) Rename(ctx *gin.Context) {
+	id, ok := c.parseID(ctx)
+	if !ok {
+		return
+	}
+	name, ok := c.bindName(ctx)
+	if !ok {
+		return
+	}
+
+	item, err := c.cr.Rename(ctx.Request.Context(), id, name)
+	if err != nil {
+		c.writeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": c.label + " berhasil diupdate",
+		"data":    item,
+	})
+}
+
+// Delete godoc
+// @Summary      Hapus genre / cast / director (Admin)
+// @Description  Hanya bisa menghapus yang tidak dipakai movie manapun, gunakan merge untuk yang masih dipakai
+// @Tags         Admin-Catalog
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      int  true  "Genre / Cast / Director ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /admin/genres/{id} [delete]
+// @Router       /admin/casts/{id} [delete]
+// @Router       /admin/directors/{id} [delete]
+func (c *CatalogHandler) Delete(ctx *gin.Context) {
+	id, ok := c.parseID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.cr.Delete(ctx.Request.Context(), id); err != nil {
+		c.writeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": c.label + " berhasil dihapus",
+	})
+}
+
+// Merge godoc
+// @Summary      Gabungkan genre / cast / director (Admin)
+// @Description  Semua movie milik source_ids dipindah ke id di path, lalu source_ids dihapus
+// @Tags         Admin-Catalog
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                         true  "ID tujuan"
+// @Param        body  body      models.CatalogMergeRequest  true  "ID yang digabungkan"
+// @Success      200   {object}  models.CatalogMergeResponse
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /admin/genres/{id}/merge [post]
+// @Router       /admin/casts/{id}/merge [post]
+// @Router       /admin/directors/{id}/merge [post]
+func (c *CatalogHandler) Merge(ctx *gin.Context) {
+	id, ok := c.parseID(ctx)
+	if !ok {
+		return
+	}
+
+	var body models.CatalogMergeRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "source_ids wajib diisi (1-50 ID)"})
+		return
+	}
+
+	result, err := c.cr.Merge(ctx.Request.Context(), id, body.SourceIds)
+	if err != nil {
+		c.writeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": fmt.Sprintf("%d %s digabung ke %s, %d movie dipindah", len(result.Merged), strings.ToLower(c.label), result.Target.Name, result.MoviesMoved),
+		"data":    result,
+	})
+}
+
+func (c *CatalogHandler) parseID(ctx *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": c.label + " ID harus berupa angka"})
+		return 0, false
+	}
+	return id, true
+}
+
+func (c *CatalogHandler) bindName(ctx *gin.Context) (string, bool) {
+	var body models.CatalogRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.Name) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "name wajib diisi (maksimal 50 karakter)"})
+		return "", false
+	}
+	return strings.TrimSpace(body.Name), true
+}
+
+func (c *CatalogHandler) writeError(ctx *gin.Context, err error) {
+	switch err.Error() {
+	case "not found":
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": c.label + " tidak ditemukan"})
+	case "name already exists":
+		ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": "Nama " + strings.ToLower(c.label) + " sudah ada"})
+	case "in use":
+		ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": c.label + " masih dipakai movie, gunakan merge untuk menggabungkannya"})
+	case "cannot merge into itself":
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "source_ids tidak boleh berisi ID tujuan"})
+	default:
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+	}
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCatalogWriteError(t *testing.T) {
+	handler := NewCatalogHandler(nil, "Genre")
+	tests := []struct {
+		err     error
+		want    int
+		message string
+	}{
+		{err: errors.New("not found"), want: http.StatusNotFound, message: "Genre tidak ditemukan"},
+		{err: errors.New("name already exists"), want: http.StatusConflict, message: "Nama genre sudah ada"},
+		{err: errors.New("in use"), want: http.StatusConflict, message: "Genre masih dipakai movie, gunakan merge untuk menggabungkannya"},
+		{err: errors.New("cannot merge into itself"), want: http.StatusBadRequest, message: "source_ids tidak boleh berisi ID tujuan"},
+		{err: errors.New("connection refused"), want: http.StatusInternalServerError, message: "internal server error"},
+	}
+
+	for _, tt := range tests {
+		ctx, rec := newTestContext(t, http.MethodPost, nil, nil)
+		handler.writeError(ctx, tt.err)
+		if rec.Code != tt.want {
+			t.Errorf("%v: status %d, want %d", tt.err, rec.Code, tt.want)
+		}
+		if msg := decodeBody(t, rec)["error"]; msg != tt.message {
+			t.Errorf("%v: error %q, want %q", tt.err, msg, tt.message)
+		}
+	}
+}
+
+// TestCatalogMergeValidation input yang tidak valid ditolak sebelum menyentuh repository
+// (repository nil akan panic jika terpanggil)
+func TestCatalogMergeValidation(t *testing.T) {
+	handler := NewCatalogHandler(nil, "Director")
+	tests := []struct {
+		name string
+		id   string
+		body any
+	}{
+		{name: "id not a number", id: "abc", body: map[string]any{"source_ids": []int{2}}},
+		{name: "missing source_ids", id: "1", body: map[string]any{}},
+		{name: "empty source_ids", id: "1", body: map[string]any{"source_ids": []int{}}},
+	}
+
+	for _, tt := range tests {
+		ctx, rec := newTestContext(t, http.MethodPost, tt.body, nil, gin.Param{Key: "id", Value: tt.id})
+		handler.Merge(ctx)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want %d, body %s", tt.name, rec.Code, http.StatusBadRequest, rec.Body.String())
+		}
+	}
+}
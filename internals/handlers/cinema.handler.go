@@ -0,0 +1,436 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/internals/utils"
+)
+
+// label baris kursi: huruf kapital, contoh A, B, AA
+var seatRowPattern = regexp.MustCompile(`^[A-Z]{1,5}$`)
+
+type CinemaHandler struct {
+	cr repositories.CinemaStore
+}
+
+func NewCinemaHandler(cr repositories.CinemaStore) *CinemaHandler {
+	return &CinemaHandler{cr: cr}
+}
+
+// GetCinemas godoc
+// @Summary      List cinema (Admin)
+// @Description  Ambil semua cinema beserta jumlah kursinya
+// @Tags         Admin-Cinemas
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {array}   models.Cinema
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /admin/cinemas [get]
+func (c *CinemaHandler) GetCinemas(ctx *gin.Context) {
+	cinemas, err := c.cr.GetCinemas(ctx.Request.Context())
+	if err != nil {
+		utils.Logger(ctx.Request.Context()).Error("handler error", "op", "GetCinemas", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    cinemas,
+	})
+}
+
+// CreateCinema godoc
+// @Summary      Tambah cinema (Admin)
+// @Description  Membuat cinema baru, denah kursi diatur lewat /admin/cinemas/{id}/layout
+// @Tags         Admin-Cinemas
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      models.CinemaRequest  true  "Cinema"
+// @Success      201   {object}  models.Cinema
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /admin/cinemas [post]
+func (c *CinemaHandler) CreateCinema(ctx *gin.Context) {
+	var body models.CinemaRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.CinemaName) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "cinema_name wajib diisi (maksimal 50 karakter)"})
+		return
+	}
+	body.CinemaName = strings.TrimSpace(body.CinemaName)
+
+	cinema, err := c.cr.CreateCinema(ctx.Request.Context(), body)
+	if err != nil {
+		utils.Logger(ctx.Request.Context()).Error("handler error", "op", "CreateCinema", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Cinema berhasil ditambahkan",
+		"data":    cinema,
+	})
+}
+
+// UpdateCinema godoc
+// @Summary      Update cinema (Admin)
+// @Description  Mengubah nama cinema
+// @Tags         Admin-Cinemas
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                   true  "Cinema ID"
+// @Param        body  body      models.CinemaRequest  true  "Cinema"
+// @Success      200   {object}  models.Cinema
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /admin/cinemas/{id} [put]
+func (c *CinemaHandler) UpdateCinema(ctx *gin.Context) {
+	cinemaID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Cinema ID harus berupa angka"})
+		return
+	}
+
+	var body models.CinemaRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.CinemaName) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "cinema_name wajib diisi (maksimal 50 karakter)"})
+		return
+	}
+	body.CinemaName = strings.TrimSpace(body.CinemaName)
+
+	cinema, err := c.cr.UpdateCinema(ctx.Request.Context(), cinemaID, body)
+	if err != nil {
+		if err.Error() == "cinema not found" {
+			ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Cinema tidak ditemukan"})
+			return
+		}
+		utils.Logger(ctx.Request.Context()).Error("handler error", "op", "UpdateCinema", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Cinema berhasil diupdate",
+		"data":    cinema,
+	})
+}
+
+// DeleteCinema godoc
+// @Summary      Hapus cinema (Admin)
+// @Description  Menghapus cinema beserta denah kursi dan harganya. Ditolak jika cinema sudah punya jadwal tayang atau order
+// @Tags         Admin-Cinemas
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      int  true  "Cinema ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /admin/cinemas/{id} [delete]
+func (c *CinemaHandler) DeleteCinema(ctx *gin.Context) {
+	cinemaID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Cinema ID harus berupa angka"})
+		return
+	}
+
+	if err := c.cr.DeleteCinema(ctx.Request.Context(), cinemaID); err != nil {
+		switch err.Error() {
+		case "cinema not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Cinema tidak ditemukan"})
+		case "cinema in use":
+			ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": "Cinema sudah dipakai jadwal tayang atau order, tidak bisa dihapus"})
+		default:
+			utils.Logger(ctx.Request.Context()).Error("handler error", "op", "DeleteCinema", "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Cinema berhasil dihapus",
+	})
+}
+
+// GetSeatLayout godoc
+// @Summary      Denah kursi cinema (Admin)
+// @Description  Ambil denah kursi cinema per baris: jumlah kursi, lorong, love nest, kursi roda dan kursi nonaktif
+// @Tags         Admin-Cinemas
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      int  true  "Cinema ID"
+// @Success      200  {object}  models.SeatLayout
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /admin/cinemas/{id}/layout [get]
+func (c *CinemaHandler) GetSeatLayout(ctx *gin.Context) {
+	cinemaID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Cinema ID harus berupa angka"})
+		return
+	}
+
+	layout, err := c.cr.GetSeatLayout(ctx.Request.Context(), cinemaID)
+	if err != nil {
+		if err.Error() == "cinema not found" {
+			ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Cinema tidak ditemukan"})
+			return
+		}
+		utils.Logger(ctx.Request.Context()).Error("handler error", "op", "GetSeatLayout", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    layout,
+	})
+}
+
+// SaveSeatLayout godoc
+// @Summary      Atur denah kursi cinema (Admin)
+// @Description  Mengganti denah kursi cinema. Setiap baris berisi jumlah kursi (nomor 1..seat_count), posisi lorong, kursi love nest, kursi roda dan kursi nonaktif.
+// @Description  Ditolak jika kursi yang dihapus/dinonaktifkan sudah terjual atau sedang ditahan untuk jadwal mendatang
+// @Tags         Admin-Cinemas
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                       true  "Cinema ID"
+// @Param        body  body      models.SeatLayoutRequest  true  "Seat layout"
+// @Success      200   {object}  models.SeatLayout
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      409   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /admin/cinemas/{id}/layout [put]
+func (c *CinemaHandler) SaveSeatLayout(ctx *gin.Context) {
+	cinemaID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Cinema ID harus berupa angka"})
+		return
+	}
+
+	var body models.SeatLayoutRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "rows wajib diisi (maksimal 30 baris), setiap baris butuh row dan seat_count (1-50)"})
+		return
+	}
+	if err := validateSeatLayout(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	layout, err := c.cr.SaveSeatLayout(ctx.Request.Context(), cinemaID, body)
+	if err != nil {
+		switch err.Error() {
+		case "cinema not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Cinema tidak ditemukan"})
+		case "layout conflicts with active bookings":
+			ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": "Kursi yang dihapus atau dinonaktifkan masih terjual/ditahan untuk jadwal mendatang"})
+		default:
+			utils.Logger(ctx.Request.Context()).Error("handler error", "op", "SaveSeatLayout", "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Denah kursi berhasil disimpan",
+		"data":    layout,
+	})
+}
+
+// GetLocations godoc
+// @Summary      List location (Admin)
+// @Description  Ambil semua location
+// @Tags         Admin-Locations
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {array}   models.Location
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /admin/locations [get]
+func (c *CinemaHandler) GetLocations(ctx *gin.Context) {
+	locations, err := c.cr.GetLocations(ctx.Request.Context())
+	if err != nil {
+		utils.Logger(ctx.Request.Context()).Error("handler error", "op", "GetLocations", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    locations,
+	})
+}
+
+// CreateLocation godoc
+// @Summary      Tambah location (Admin)
+// @Description  Membuat location baru
+// @Tags         Admin-Locations
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      models.LocationRequest  true  "Location"
+// @Success      201   {object}  models.Location
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /admin/locations [post]
+func (c *CinemaHandler) CreateLocation(ctx *gin.Context) {
+	var body models.LocationRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.Name) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "name wajib diisi (maksimal 50 karakter)"})
+		return
+	}
+	body.Name = strings.TrimSpace(body.Name)
+
+	location, err := c.cr.CreateLocation(ctx.Request.Context(), body)
+	if err != nil {
+		utils.Logger(ctx.Request.Context()).Error("handler error", "op", "CreateLocation", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Location berhasil ditambahkan",
+		"data":    location,
+	})
+}
+
+// UpdateLocation godoc
+// @Summary      Update location (Admin)
+// @Description  Mengubah nama location
+// @Tags         Admin-Locations
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                     true  "Location ID"
+// @Param        body  body      models.LocationRequest  true  "Location"
+// @Success      200   {object}  models.Location
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /admin/locations/{id} [put]
+func (c *CinemaHandler) UpdateLocation(ctx *gin.Context) {
+	locationID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Location ID harus berupa angka"})
+		return
+	}
+
+	var body models.LocationRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.Name) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "name wajib diisi (maksimal 50 karakter)"})
+		return
+	}
+	body.Name = strings.TrimSpace(body.Name)
+
+	location, err := c.cr.UpdateLocation(ctx.Request.Context(), locationID, body)
+	if err != nil {
+		if err.Error() == "location not found" {
+			ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Location tidak ditemukan"})
+			return
+		}
+		utils.Logger(ctx.Request.Context()).Error("handler error", "op", "UpdateLocation", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Location berhasil diupdate",
+		"data":    location,
+	})
+}
+
+// DeleteLocation godoc
+// @Summary      Hapus location (Admin)
+// @Description  Menghapus location, ditolak jika masih dipakai jadwal tayang
+// @Tags         Admin-Locations
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      int  true  "Location ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /admin/locations/{id} [delete]
+func (c *CinemaHandler) DeleteLocation(ctx *gin.Context) {
+	locationID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Location ID harus berupa angka"})
+		return
+	}
+
+	if err := c.cr.DeleteLocation(ctx.Request.Context(), locationID); err != nil {
+		switch err.Error() {
+		case "location not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Location tidak ditemukan"})
+		case "location in use":
+			ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": "Location masih dipakai jadwal tayang, tidak bisa dihapus"})
+		default:
+			utils.Logger(ctx.Request.Context()).Error("handler error", "op", "DeleteLocation", "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Location berhasil dihapus",
+	})
+}
+
+// validateSeatLayout memvalidasi label baris (unik, huruf kapital) dan nomor kursi di setiap daftar
+func validateSeatLayout(body *models.SeatLayoutRequest) error {
+	seen := make(map[string]bool, len(body.Rows))
+	for i := range body.Rows {
+		row := &body.Rows[i]
+		row.Row = strings.ToUpper(strings.TrimSpace(row.Row))
+		if !seatRowPattern.MatchString(row.Row) {
+			return fmt.Errorf("row ke-%d harus berupa huruf (A-Z)", i+1)
+		}
+		if seen[row.Row] {
+			return fmt.Errorf("row %s duplikat", row.Row)
+		}
+		seen[row.Row] = true
+
+		lists := []struct {
+			name    string
+			numbers []int
+		}{
+			{"aisles_after", row.AislesAfter},
+			{"love_nest", row.LoveNest},
+			{"wheelchair", row.Wheelchair},
+			{"disabled", row.Disabled},
+		}
+		for _, list := range lists {
+			for _, number := range list.numbers {
+				if number < 1 || number > row.SeatCount {
+					return fmt.Errorf("%s row %s berisi nomor kursi %d di luar 1-%d", list.name, row.Row, number, row.SeatCount)
+				}
+			}
+		}
+		for _, number := range row.LoveNest {
+			if slices.Contains(row.Wheelchair, number) {
+				return fmt.Errorf("kursi %s%d tidak bisa love_nest sekaligus wheelchair", row.Row, number)
+			}
+		}
+	}
+	return nil
+}
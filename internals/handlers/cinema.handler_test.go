@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/raihaninkam/tickitz/internals/models"
+)
+
+func TestValidateSeatLayout(t *testing.T) {
+	tests := []struct {
+		name    string
+		rows    []models.LayoutRow
+		wantErr string
+	}{
+		{
+			name: "valid",
+			rows: []models.LayoutRow{
+				{Row: " a ", SeatCount: 14, AislesAfter: []int{7}, Wheelchair: []int{1, 2}},
+				{Row: "B", SeatCount: 14, LoveNest: []int{7, 8}, Disabled: []int{14}},
+			},
+		},
+		{name: "row not a letter", rows: []models.LayoutRow{{Row: "A1", SeatCount: 10}}, wantErr: "harus berupa huruf"},
+		{name: "duplicate row", rows: []models.LayoutRow{{Row: "A", SeatCount: 10}, {Row: "a", SeatCount: 8}}, wantErr: "duplikat"},
+		{name: "aisle outside row", rows: []models.LayoutRow{{Row: "A", SeatCount: 10, AislesAfter: []int{11}}}, wantErr: "aisles_after row A"},
+		{name: "disabled seat zero", rows: []models.LayoutRow{{Row: "A", SeatCount: 10, Disabled: []int{0}}}, wantErr: "disabled row A"},
+		{name: "love nest and wheelchair", rows: []models.LayoutRow{{Row: "A", SeatCount: 10, LoveNest: []int{3}, Wheelchair: []int{3}}}, wantErr: "A3"},
+	}
+
+	for _, tt := range tests {
+		body := models.SeatLayoutRequest{Rows: tt.rows}
+		err := validateSeatLayout(&body)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+
+	// label baris dinormalisasi sebelum disimpan
+	body := models.SeatLayoutRequest{Rows: []models.LayoutRow{{Row: " f ", SeatCount: 5}}}
+	if err := validateSeatLayout(&body); err != nil || body.Rows[0].Row != "F" {
+		t.Errorf("row normalized to %q (err %v), want F", body.Rows[0].Row, err)
+	}
+}
@@ -0,0 +1,632 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+// fake in-memory untuk unit test handler tanpa Postgres / Redis. Error yang dikembalikan
+// sama dengan pesan error repository asli karena handler mencocokkan teks error-nya
+
+var errNotImplemented = errors.New("not implemented in fake")
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// testHashConfig argon2 ringan supaya test cepat
+func testHashConfig() *pkg.HashConfig {
+	hc := pkg.NewHashConfig()
+	hc.SetConfig(1024, 1, 32, 16, 1)
+	return hc
+}
+
+var testJWT = pkg.JWTConfig{Secret: "test-secret", Issuer: "tickitz-test", AccessTTL: 30 * time.Minute}
+
+// newTestContext context gin dengan body JSON, claims (jika tidak nil) dan path param
+func newTestContext(t *testing.T, method string, body any, claims *pkg.Claims, params ...gin.Param) (*gin.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(rec)
+	ctx.Request = httptest.NewRequest(method, "/", &buf)
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Params = params
+	if claims != nil {
+		ctx.Set("claims", *claims)
+	}
+	return ctx, rec
+}
+
+func decodeJSON(t *testing.T, rec *httptest.ResponseRecorder, out any) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+		t.Fatalf("decode response %q: %v", rec.Body.String(), err)
+	}
+}
+
+func decodeBody(t *testing.T, rec *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+	var body map[string]any
+	decodeJSON(t, rec, &body)
+	return body
+}
+
+func assertStatus(t *testing.T, rec *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if rec.Code != want {
+		t.Fatalf("status %d, want %d, body %s", rec.Code, want, rec.Body.String())
+	}
+}
+
+// auth
+
+type fakeAuthStore struct {
+	mu     sync.Mutex
+	users  map[string]models.Users
+	nextID int
+}
+
+var _ repositories.AuthStore = (*fakeAuthStore)(nil)
+
+func newFakeAuthStore() *fakeAuthStore {
+	return &fakeAuthStore{users: map[string]models.Users{}}
+}
+
+// addUser menyimpan user dengan password yang sudah di-hash
+func (f *fakeAuthStore) addUser(t *testing.T, hc *pkg.HashConfig, email, password string, verified bool) models.Users {
+	t.Helper()
+	hash, err := hc.GenHash(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := f.RegisterUserWithProfile(context.Background(), email, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	user := f.users[email]
+	user.IsVerified = verified
+	f.users[email] = user
+	return models.Users{Id: id, Email: email, Role: user.Role, IsVerified: verified}
+}
+
+func (f *fakeAuthStore) GetEmailUserWithPasswordAndRole(rctx context.Context, email string) (models.Users, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	user, ok := f.users[email]
+	if !ok {
+		return models.Users{}, errors.New("user not found")
+	}
+	return user, nil
+}
+
+func (f *fakeAuthStore) CheckEmailExists(rctx context.Context, email string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.users[email]
+	return ok, nil
+}
+
+func (f *fakeAuthStore) RegisterUserWithProfile(rctx context.Context, email, password string) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.users[email]; ok {
+		return 0, errors.New("email already exists")
+	}
+	f.nextID++
+	f.users[email] = models.Users{Id: f.nextID, Email: email, Role: "user", Password: password}
+	return f.nextID, nil
+}
+
+// fakeSessionStore merotasi refresh token seperti SessionRepository: token lama yang
+// dipakai ulang mencabut seluruh session
+type fakeSessionStore struct {
+	mu       sync.Mutex
+	sessions []models.SessionToken
+	// tokens refresh token -> index session, used menandai token yang sudah dirotasi
+	tokens  map[string]int
+	used    map[string]bool
+	revoked map[string]bool
+}
+
+var _ repositories.SessionStore = (*fakeSessionStore)(nil)
+
+func (f *fakeSessionStore) issue(index int) string {
+	if f.tokens == nil {
+		f.tokens, f.used, f.revoked = map[string]int{}, map[string]bool{}, map[string]bool{}
+	}
+	token := fmt.Sprintf("refresh-%d-%d", index+1, len(f.tokens)+1)
+	f.tokens[token] = index
+	f.sessions[index].RefreshToken = token
+	return token
+}
+
+func (f *fakeSessionStore) CreateSession(rctx context.Context, userID int, role, userAgent, ipAddress string) (models.SessionToken, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sessions = append(f.sessions, models.SessionToken{
+		UserId:    userID,
+		Role:      role,
+		SessionId: fmt.Sprintf("session-%d", len(f.sessions)+1),
+		ExpiresAt: time.Now().Add(24 * time.Hour),
+	})
+	index := len(f.sessions) - 1
+	f.issue(index)
+	return f.sessions[index], nil
+}
+
+func (f *fakeSessionStore) RotateRefreshToken(rctx context.Context, refreshToken, userAgent, ipAddress string) (models.SessionToken, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	index, ok := f.tokens[refreshToken]
+	if !ok {
+		return models.SessionToken{}, errors.New("invalid refresh token")
+	}
+	session := f.sessions[index]
+	if f.revoked[session.SessionId] {
+		return models.SessionToken{}, errors.New("session revoked")
+	}
+	if f.used[refreshToken] {
+		f.revoked[session.SessionId] = true
+		return models.SessionToken{}, errors.New("refresh token reused")
+	}
+	f.used[refreshToken] = true
+	f.issue(index)
+	return f.sessions[index], nil
+}
+
+func (f *fakeSessionStore) isRevoked(sessionID string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.revoked[sessionID]
+}
+
+func (f *fakeSessionStore) ListSessions(rctx context.Context, userID int, currentSessionID string) ([]models.UserSession, error) {
+	return nil, errNotImplemented
+}
+
+func (f *fakeSessionStore) RevokeSession(rctx context.Context, userID int, sessionID string) error {
+	return errNotImplemented
+}
+
+func (f *fakeSessionStore) EndSession(rctx context.Context, sessionID string) error {
+	return errNotImplemented
+}
+
+// fakeLockoutStore mengunci email setelah maxAttempts kali gagal berturut-turut
+type fakeLockoutStore struct {
+	mu          sync.Mutex
+	maxAttempts int
+	lockFor     time.Duration
+	failures    map[string]int
+}
+
+var _ repositories.LockoutStore = (*fakeLockoutStore)(nil)
+
+func newFakeLockoutStore(maxAttempts int) *fakeLockoutStore {
+	return &fakeLockoutStore{maxAttempts: maxAttempts, lockFor: time.Minute, failures: map[string]int{}}
+}
+
+func (f *fakeLockoutStore) LockedFor(rctx context.Context, email string) (time.Duration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failures[email] >= f.maxAttempts {
+		return f.lockFor, nil
+	}
+	return 0, nil
+}
+
+func (f *fakeLockoutStore) RecordFailure(rctx context.Context, email string) (time.Duration, error) {
+	f.mu.Lock()
+	f.failures[email]++
+	f.mu.Unlock()
+	return f.LockedFor(rctx, email)
+}
+
+func (f *fakeLockoutStore) Reset(rctx context.Context, email string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.failures, email)
+	return nil
+}
+
+func (f *fakeLockoutStore) UnlockUser(rctx context.Context, userID int) (string, error) {
+	return "", errNotImplemented
+}
+
+// fakeAccountStore token sekali pakai untuk user di fakeAuthStore
+type fakeAccountStore struct {
+	users  *fakeAuthStore
+	mu     sync.Mutex
+	tokens map[string]*fakeUserToken
+}
+
+type fakeUserToken struct {
+	email   string
+	purpose string
+	used    bool
+}
+
+var _ repositories.AccountStore = (*fakeAccountStore)(nil)
+
+func newFakeAccountStore(users *fakeAuthStore) *fakeAccountStore {
+	return &fakeAccountStore{users: users, tokens: map[string]*fakeUserToken{}}
+}
+
+func (f *fakeAccountStore) issue(email, purpose string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	token := fmt.Sprintf("%s-%d", purpose, len(f.tokens)+1)
+	f.tokens[token] = &fakeUserToken{email: email, purpose: purpose}
+	return token
+}
+
+func (f *fakeAccountStore) consume(token, purpose string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t, ok := f.tokens[token]
+	if !ok || t.purpose != purpose {
+		return "", errors.New("invalid token")
+	}
+	if t.used {
+		return "", errors.New("token already used")
+	}
+	t.used = true
+	return t.email, nil
+}
+
+// updateUser mengubah user di fakeAuthStore
+func (f *fakeAccountStore) updateUser(email string, apply func(*models.Users)) {
+	f.users.mu.Lock()
+	defer f.users.mu.Unlock()
+	user := f.users.users[email]
+	apply(&user)
+	f.users.users[email] = user
+}
+
+func (f *fakeAccountStore) CreateVerificationToken(rctx context.Context, userID int) (string, error) {
+	f.users.mu.Lock()
+	defer f.users.mu.Unlock()
+	for email, user := range f.users.users {
+		if user.Id == userID {
+			return f.issue(email, "verify"), nil
+		}
+	}
+	return "", errors.New("user not found")
+}
+
+func (f *fakeAccountStore) GetUnverifiedUser(rctx context.Context, email string) (models.Users, error) {
+	user, err := f.users.GetEmailUserWithPasswordAndRole(rctx, email)
+	if err != nil {
+		return models.Users{}, err
+	}
+	if user.IsVerified {
+		return models.Users{}, errors.New("user already verified")
+	}
+	return user, nil
+}
+
+func (f *fakeAccountStore) VerifyEmail(rctx context.Context, token string) error {
+	email, err := f.consume(token, "verify")
+	if err != nil {
+		return err
+	}
+	f.updateUser(email, func(user *models.Users) { user.IsVerified = true })
+	return nil
+}
+
+func (f *fakeAccountStore) CreatePasswordResetToken(rctx context.Context, email string) (models.Users, string, error) {
+	user, err := f.users.GetEmailUserWithPasswordAndRole(rctx, email)
+	if err != nil {
+		return models.Users{}, "", err
+	}
+	return user, f.issue(email, "reset"), nil
+}
+
+func (f *fakeAccountStore) ResetPassword(rctx context.Context, token, hashedPassword string) error {
+	email, err := f.consume(token, "reset")
+	if err != nil {
+		return err
+	}
+	f.updateUser(email, func(user *models.Users) {
+		user.Password = hashedPassword
+		user.IsVerified = true
+	})
+	return nil
+}
+
+type fakeRevocationStore struct{}
+
+var _ repositories.TokenRevocationStore = fakeRevocationStore{}
+
+func (fakeRevocationStore) RevokeToken(rctx context.Context, jti string, expiresAt time.Time) error {
+	return nil
+}
+
+func (fakeRevocationStore) IsTokenRevoked(rctx context.Context, jti string) (bool, error) {
+	return false, nil
+}
+
+func (fakeRevocationStore) RevokeSession(rctx context.Context, sessionID string) error {
+	return nil
+}
+
+func (fakeRevocationStore) IsSessionRevoked(rctx context.Context, sessionID string) (bool, error) {
+	return false, nil
+}
+
+func (fakeRevocationStore) Maintain(rctx context.Context) error {
+	return nil
+}
+
+// fakeMailer meneruskan email ke channel, email dikirim handler di background
+type fakeMailer chan pkg.Mail
+
+func (f fakeMailer) Send(ctx context.Context, mail pkg.Mail) error {
+	f <- mail
+	return nil
+}
+
+// seats, hold & order
+
+type fakeSeat struct {
+	sold   bool
+	holder int
+}
+
+// fakeSeatStore denah kursi satu jadwal tayang, dipakai bersama oleh SeatsStore, SeatHoldStore dan OrderStore
+type fakeSeatStore struct {
+	mu           sync.Mutex
+	nowShowingID int
+	seats        map[string]*fakeSeat
+	orders       []models.CreateOrderRequest
+}
+
+var (
+	_ repositories.SeatsStore    = (*fakeSeatStore)(nil)
+	_ repositories.SeatHoldStore = (*fakeSeatStore)(nil)
+	_ repositories.OrderStore    = (*fakeSeatStore)(nil)
+)
+
+func newFakeSeatStore(nowShowingID int, seatIDs ...string) *fakeSeatStore {
+	f := &fakeSeatStore{nowShowingID: nowShowingID, seats: map[string]*fakeSeat{}}
+	for _, id := range seatIDs {
+		f.seats[id] = &fakeSeat{}
+	}
+	return f
+}
+
+// reserve memeriksa semua kursi sebelum mengubah satu pun, seperti transaksi di repository
+func (f *fakeSeatStore) reserve(userID, nowShowingID int, seatIDs []string, apply func(*fakeSeat)) error {
+	if nowShowingID != f.nowShowingID {
+		return errors.New("showing not found")
+	}
+	for _, id := range seatIDs {
+		seat, ok := f.seats[id]
+		if !ok {
+			return errors.New("invalid seat selection")
+		}
+		if seat.sold || (seat.holder != 0 && seat.holder != userID) {
+			return errors.New("seat not available")
+		}
+	}
+	for _, id := range seatIDs {
+		apply(f.seats[id])
+	}
+	return nil
+}
+
+func (f *fakeSeatStore) GetAvailableSeats(rctx context.Context, nowShowingID, userID int) ([]models.AvailSeat, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if nowShowingID != f.nowShowingID {
+		return nil, errors.New("showing not found")
+	}
+	seats := make([]models.AvailSeat, 0, len(f.seats))
+	for id, seat := range f.seats {
+		seats = append(seats, models.AvailSeat{
+			SeatID:    id,
+			ShowingId: nowShowingID,
+			IsSold:    seat.sold,
+			SeatClass: "regular",
+			IsHeld:    seat.holder != 0,
+			HeldByMe:  seat.holder != 0 && seat.holder == userID,
+		})
+	}
+	return seats, nil
+}
+
+func (f *fakeSeatStore) HoldSeats(rctx context.Context, userID int, req models.SeatHoldRequest) (models.SeatHoldResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	err := f.reserve(userID, req.NowShowingID, req.SeatsMap, func(seat *fakeSeat) { seat.holder = userID })
+	if err != nil {
+		return models.SeatHoldResponse{}, err
+	}
+	return models.SeatHoldResponse{NowShowingID: req.NowShowingID, SeatsMap: req.SeatsMap, HeldUntil: time.Now().Add(10 * time.Minute)}, nil
+}
+
+func (f *fakeSeatStore) ReleaseHolds(rctx context.Context, userID, nowShowingID int) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var released int
+	for _, seat := range f.seats {
+		if !seat.sold && seat.holder == userID {
+			seat.holder = 0
+			released++
+		}
+	}
+	return released, nil
+}
+
+func (f *fakeSeatStore) CreateOrder(rctx context.Context, req models.CreateOrderRequest) (models.CreateOrderResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	err := f.reserve(req.UsersID, req.NowShowingID, req.SeatsMap, func(seat *fakeSeat) {
+		seat.sold = true
+		seat.holder = 0
+	})
+	if err != nil {
+		return models.CreateOrderResponse{}, err
+	}
+	f.orders = append(f.orders, req)
+	return models.CreateOrderResponse{
+		ID:        len(f.orders),
+		UsersID:   req.UsersID,
+		Price:     float64(50000 * len(req.SeatsMap)),
+		TicketID:  len(f.orders),
+		SeatsMap:  req.SeatsMap,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// errSeatStore mengembalikan error yang sama untuk setiap pemanggilan, untuk jalur error internal
+type errSeatStore struct{ err error }
+
+func (e errSeatStore) CreateOrder(rctx context.Context, req models.CreateOrderRequest) (models.CreateOrderResponse, error) {
+	return models.CreateOrderResponse{}, e.err
+}
+
+func (e errSeatStore) HoldSeats(rctx context.Context, userID int, req models.SeatHoldRequest) (models.SeatHoldResponse, error) {
+	return models.SeatHoldResponse{}, e.err
+}
+
+func (e errSeatStore) ReleaseHolds(rctx context.Context, userID, nowShowingID int) (int, error) {
+	return 0, e.err
+}
+
+// payment
+
+type fakePaymentStore struct {
+	mu  sync.Mutex
+	trx map[string]*models.PaymentTransaction
+}
+
+var _ repositories.PaymentStore = (*fakePaymentStore)(nil)
+
+func newFakePaymentStore(trx ...models.PaymentTransaction) *fakePaymentStore {
+	f := &fakePaymentStore{trx: make(map[string]*models.PaymentTransaction)}
+	for _, t := range trx {
+		f.trx[t.Reference] = &t
+	}
+	return f
+}
+
+func (f *fakePaymentStore) GetOrderForPayment(rctx context.Context, orderID, userID int) (models.OrderPayment, error) {
+	return models.OrderPayment{}, errNotImplemented
+}
+
+func (f *fakePaymentStore) GetPendingTransaction(rctx context.Context, orderID int) (models.PaymentTransaction, error) {
+	return models.PaymentTransaction{}, errNotImplemented
+}
+
+func (f *fakePaymentStore) CreateTransaction(rctx context.Context, orderID int, provider string, charge pkg.Charge) (models.PaymentTransaction, error) {
+	return models.PaymentTransaction{}, errNotImplemented
+}
+
+// ApplyPaymentResult total order di fake sama dengan amount charge
+func (f *fakePaymentStore) ApplyPaymentResult(rctx context.Context, reference string, status pkg.ChargeStatus, amount int) (models.PaymentTransaction, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	trx, ok := f.trx[reference]
+	if !ok {
+		return models.PaymentTransaction{}, errors.New("transaction not found")
+	}
+	if trx.Status != string(pkg.ChargePending) {
+		return models.PaymentTransaction{}, errors.New("transaction already settled")
+	}
+	if status == pkg.ChargePaid && amount != trx.Amount {
+		return models.PaymentTransaction{}, errors.New("amount mismatch")
+	}
+	trx.Status = string(status)
+	return *trx, nil
+}
+
+// refund
+
+// fakeRefundStore setiap order dibatalkan dengan refund sesuai refunds[orderID] (nil jika tidak ada)
+type fakeRefundStore struct {
+	mu        sync.Mutex
+	refunds   map[int]*models.Refund
+	cancelled map[int]bool
+}
+
+var _ repositories.RefundStore = (*fakeRefundStore)(nil)
+
+func newFakeRefundStore(refunds map[int]*models.Refund) *fakeRefundStore {
+	return &fakeRefundStore{refunds: refunds, cancelled: make(map[int]bool)}
+}
+
+func (f *fakeRefundStore) CancelOrder(rctx context.Context, orderID int, requesterID *int, cancelledBy int, reason string) (models.CancelOrderResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.cancelled[orderID] {
+		return models.CancelOrderResponse{}, errors.New("order already cancelled")
+	}
+	f.cancelled[orderID] = true
+	return models.CancelOrderResponse{OrderId: orderID, Status: "cancelled", Refund: f.refunds[orderID]}, nil
+}
+
+func (f *fakeRefundStore) CompleteRefund(rctx context.Context, refundID int, succeeded bool) (models.Refund, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, refund := range f.refunds {
+		if refund != nil && refund.Id == refundID {
+			refund.Status = "failed"
+			if succeeded {
+				refund.Status = "refunded"
+			}
+			return *refund, nil
+		}
+	}
+	return models.Refund{}, errors.New("refund not found")
+}
+
+// fakeGateway payment gateway yang refund-nya gagal jika refundErr diisi
+type fakeGateway struct {
+	refundErr error
+	refunds   []string
+}
+
+var _ pkg.PaymentGateway = (*fakeGateway)(nil)
+
+func (f *fakeGateway) Name() string { return "fake" }
+
+func (f *fakeGateway) CreateCharge(ctx context.Context, req pkg.ChargeRequest) (pkg.Charge, error) {
+	return pkg.Charge{}, errNotImplemented
+}
+
+func (f *fakeGateway) GetStatus(ctx context.Context, reference string) (pkg.ChargeStatus, error) {
+	return "", errNotImplemented
+}
+
+func (f *fakeGateway) Refund(ctx context.Context, reference string, amount int) (pkg.Refund, error) {
+	f.refunds = append(f.refunds, reference)
+	if f.refundErr != nil {
+		return pkg.Refund{}, f.refundErr
+	}
+	return pkg.Refund{Reference: reference, Amount: amount, Status: pkg.ChargeRefunded}, nil
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/raihaninkam/tickitz/internals/configs"
+	"github.com/raihaninkam/tickitz/internals/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+type HealthHandler struct {
+	db      *pgxpool.Pool
+	rdb     *redis.Client
+	metrics http.Handler
+}
+
+func NewHealthHandler(db *pgxpool.Pool, rdb *redis.Client) *HealthHandler {
+	// statistik pool milik handler ini dibaca saat scrape, metric aplikasi ada di utils.Metrics
+	pool := prometheus.NewRegistry()
+	if db != nil {
+		pool.MustRegister(newPoolCollector(db))
+	}
+	return &HealthHandler{
+		db:      db,
+		rdb:     rdb,
+		metrics: promhttp.HandlerFor(prometheus.Gatherers{utils.Metrics, pool}, promhttp.HandlerOpts{}),
+	}
+}
+
+// Liveness godoc
+// @Summary     Liveness Check
+// @Description Selalu 200 selama proses server masih bisa melayani request (tidak mengecek dependency)
+// @Tags        Health
+// @Produce     json
+// @Success     200 {object} map[string]interface{}
+// @Router      /healthz [get]
+func (h *HealthHandler) Liveness(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// GetHealth godoc
+// @Summary     Readiness / Health Check
+// @Description Status database dan Redis. Redis hanya cache: jika mati status "degraded" (tetap 200) dan cache memakai fallback lokal. Database mati menghasilkan 503
+// @Tags        Health
+// @Produce     json
+// @Success     200 {object} map[string]interface{} "ok / degraded"
+// @Failure     503 {object} map[string]interface{} "down"
+// @Router      /readyz [get]
+// @Router      /health [get]
+func (h *HealthHandler) GetHealth(ctx *gin.Context) {
+	rctx, cancel := context.WithTimeout(ctx.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	database := "up"
+	if h.db == nil || configs.PingDB(h.db) != nil {
+		database = "down"
+	}
+	cache := utils.CheckRedis(rctx, h.rdb)
+
+	status, code := "ok", http.StatusOK
+	switch {
+	case database == "down":
+		status, code = "down", http.StatusServiceUnavailable
+	case cache.Status == "down":
+		status = "degraded"
+	}
+
+	ctx.JSON(code, gin.H{
+		"success":  code == http.StatusOK,
+		"status":   status,
+		"database": database,
+		"redis":    cache,
+	})
+}
+
+// GetMetrics godoc
+// @Summary     Prometheus Metrics
+// @Description Metric format teks Prometheus: request HTTP per route, pool database, cache dan counter bisnis (order, kursi terjual, pembayaran gagal)
+// @Tags        Health
+// @Produce     plain
+// @Success     200 {string} string
+// @Router      /metrics [get]
+func (h *HealthHandler) GetMetrics(ctx *gin.Context) {
+	h.metrics.ServeHTTP(ctx.Writer, ctx.Request)
+}
+
+// poolCollector statistik pgxpool sebagai metric Prometheus
+type poolCollector struct {
+	db    *pgxpool.Pool
+	descs map[string]*prometheus.Desc
+}
+
+func newPoolCollector(db *pgxpool.Pool) *poolCollector {
+	descs := map[string]*prometheus.Desc{}
+	for name, help := range map[string]string{
+		"tickitz_db_pool_max_conns":                      "Batas maksimal koneksi pool database",
+		"tickitz_db_pool_total_conns":                    "Koneksi pool database saat ini",
+		"tickitz_db_pool_acquired_conns":                 "Koneksi pool database yang sedang dipakai",
+		"tickitz_db_pool_idle_conns":                     "Koneksi pool database yang menganggur",
+		"tickitz_db_pool_acquire_total":                  "Jumlah acquire koneksi database",
+		"tickitz_db_pool_empty_acquire_total":            "Acquire yang harus menunggu karena pool kosong",
+		"tickitz_db_pool_canceled_acquire_total":         "Acquire yang batal karena context",
+		"tickitz_db_pool_acquire_duration_seconds_total": "Total waktu menunggu acquire koneksi database",
+	} {
+		descs[name] = prometheus.NewDesc(name, help, nil, nil)
+	}
+	return &poolCollector{db: db, descs: descs}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range c.descs {
+		ch <- desc
+	}
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.db.Stat()
+	gauge := func(name string, value float64) {
+		ch <- prometheus.MustNewConstMetric(c.descs[name], prometheus.GaugeValue, value)
+	}
+	counter := func(name string, value float64) {
+		ch <- prometheus.MustNewConstMetric(c.descs[name], prometheus.CounterValue, value)
+	}
+	gauge("tickitz_db_pool_max_conns", float64(stat.MaxConns()))
+	gauge("tickitz_db_pool_total_conns", float64(stat.TotalConns()))
+	gauge("tickitz_db_pool_acquired_conns", float64(stat.AcquiredConns()))
+	gauge("tickitz_db_pool_idle_conns", float64(stat.IdleConns()))
+	counter("tickitz_db_pool_acquire_total", float64(stat.AcquireCount()))
+	counter("tickitz_db_pool_empty_acquire_total", float64(stat.EmptyAcquireCount()))
+	counter("tickitz_db_pool_canceled_acquire_total", float64(stat.CanceledAcquireCount()))
+	counter("tickitz_db_pool_acquire_duration_seconds_total", stat.AcquireDuration().Seconds())
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/raihaninkam/tickitz/internals/utils"
+)
+
+func TestGetMetrics(t *testing.T) {
+	// route dengan karakter yang wajib di-escape di format teks Prometheus
+	route := "/metrics-test/\"quoted\"\\path"
+	for _, seconds := range []float64{0.003, 0.03, 0.3, 30} {
+		utils.HTTPDuration.WithLabelValues(http.MethodGet, route).Observe(seconds)
+	}
+	utils.HTTPRequests.WithLabelValues(http.MethodGet, route, "200").Inc()
+
+	ctx, rec := newTestContext(t, http.MethodGet, nil, nil)
+	NewHealthHandler(nil, nil).GetMetrics(ctx)
+	assertStatus(t, rec, http.StatusOK)
+	if contentType := rec.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain; version=0.0.4") {
+		t.Errorf("content type %q", contentType)
+	}
+	body := rec.Body.String()
+
+	labels := `method="GET",route="/metrics-test/\"quoted\"\\path"`
+	for _, line := range []string{
+		`tickitz_http_requests_total{` + labels + `,status="200"} 1`,
+		// bucket kumulatif: setiap bucket menghitung semua observasi <= le
+		`tickitz_http_request_duration_seconds_bucket{` + labels + `,le="0.005"} 1`,
+		`tickitz_http_request_duration_seconds_bucket{` + labels + `,le="0.025"} 1`,
+		`tickitz_http_request_duration_seconds_bucket{` + labels + `,le="0.05"} 2`,
+		`tickitz_http_request_duration_seconds_bucket{` + labels + `,le="0.5"} 3`,
+		`tickitz_http_request_duration_seconds_bucket{` + labels + `,le="10"} 3`,
+		`tickitz_http_request_duration_seconds_bucket{` + labels + `,le="+Inf"} 4`,
+		`tickitz_http_request_duration_seconds_sum{` + labels + `} 30.333`,
+		`tickitz_http_request_duration_seconds_count{` + labels + `} 4`,
+		"# TYPE tickitz_orders_created_total counter",
+		"# TYPE tickitz_cache_hit_ratio gauge",
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("metrics missing %q", line)
+		}
+	}
+	// tanpa database statistik pool tidak ditulis
+	if strings.Contains(body, "tickitz_db_pool_") {
+		t.Error("db pool metrics written without a database")
+	}
+}
@@ -1,22 +1,22 @@
 package handlers
 
 import (
-	"log"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/internals/utils"
 )
 
 // all movie
 
 type AllMovie struct {
-	am *repositories.AllMovie
+	am repositories.AllMovieStore
 }
 
-func NewAllMovie(am *repositories.AllMovie) *AllMovie {
+func NewAllMovie(am repositories.AllMovieStore) *AllMovie {
 	return &AllMovie{am: am}
 }
 
@@ -40,7 +40,7 @@ func (h *AllMovie) GetAllMovies(ctx *gin.Context) {
 			})
 			return
 		}
-		log.Println("Internal Server Error.\nCause: ", err.Error())
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   "internal server error",
@@ -58,10 +58,10 @@ func (h *AllMovie) GetAllMovies(ctx *gin.Context) {
 // upcoming movie
 
 type UpcomingMovieHandler struct {
-	umr *repositories.UpcomingMovie
+	umr repositories.UpcomingMovieStore
 }
 
-func NewUpcomingMovieHandler(umr *repositories.UpcomingMovie) *UpcomingMovieHandler {
+func NewUpcomingMovieHandler(umr repositories.UpcomingMovieStore) *UpcomingMovieHandler {
 	return &UpcomingMovieHandler{umr: umr}
 }
 
@@ -85,7 +85,7 @@ func (u *UpcomingMovieHandler) GetUpcomingMovies(ctx *gin.Context) {
 			})
 			return
 		}
-		log.Println("Internal Server Error.\nCause: ", err.Error())
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   "internal server error",
@@ -102,10 +102,10 @@ func (u *UpcomingMovieHandler) GetUpcomingMovies(ctx *gin.Context) {
 // popular movie
 
 type PopularMovieHandler struct {
-	pmr *repositories.PopularMovie
+	pmr repositories.PopularMovieStore
 }
 
-func NewPopularMovieHandler(pmr *repositories.PopularMovie) *PopularMovieHandler {
+func NewPopularMovieHandler(pmr repositories.PopularMovieStore) *PopularMovieHandler {
 	return &PopularMovieHandler{pmr: pmr}
 }
 
@@ -129,7 +129,7 @@ func (p *PopularMovieHandler) GetPopularMovies(ctx *gin.Context) {
 			})
 			return
 		}
-		log.Println("Internal Server Error.\nCause: ", err.Error())
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   "internal server error",
@@ -146,10 +146,10 @@ func (p *PopularMovieHandler) GetPopularMovies(ctx *gin.Context) {
 // movie filter
 
 type MovieFilterHandler struct {
-	mf *repositories.MovieFilter
+	mf repositories.MovieFilterStore
 }
 
-func NewMovieFilterHandler(mf *repositories.MovieFilter) *MovieFilterHandler {
+func NewMovieFilterHandler(mf repositories.MovieFilterStore) *MovieFilterHandler {
 	return &MovieFilterHandler{mf: mf}
 }
 
@@ -196,7 +196,7 @@ func (h *MovieFilterHandler) GetMoviesWithFilter(ctx *gin.Context) {
 			})
 			return
 		}
-		log.Println("Internal Server Error.\nCause: ", err.Error())
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   "internal server error",
@@ -217,10 +217,10 @@ func (h *MovieFilterHandler) GetMoviesWithFilter(ctx *gin.Context) {
 // movie detail
 
 type MovieDetailHandler struct {
-	mr *repositories.MovieDetail
+	mr repositories.MovieDetailStore
 }
 
-func NewMovieDetailHandler(mr *repositories.MovieDetail) *MovieDetailHandler {
+func NewMovieDetailHandler(mr repositories.MovieDetailStore) *MovieDetailHandler {
 	return &MovieDetailHandler{mr: mr}
 }
 
@@ -267,7 +267,7 @@ func (m *MovieDetailHandler) GetDetailMovie(ctx *gin.Context) {
 			})
 			return
 		}
-		log.Println("Internal Server Error.\nCause: ", err.Error())
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   "internal server error",
@@ -286,10 +286,10 @@ func (m *MovieDetailHandler) GetDetailMovie(ctx *gin.Context) {
 // schedule
 
 type ScheduleHandler struct {
-	sr *repositories.Schedule
+	sr repositories.ScheduleStore
 }
 
-func NewScheduleHandler(sr *repositories.Schedule) *ScheduleHandler {
+func NewScheduleHandler(sr repositories.ScheduleStore) *ScheduleHandler {
 	return &ScheduleHandler{sr: sr}
 }
 
@@ -325,7 +325,7 @@ func (s *ScheduleHandler) GetSchedulesByMovieID(ctx *gin.Context) {
 			})
 			return
 		}
-		log.Println("Internal Server Error.\nCause: ", err.Error())
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   "internal server error",
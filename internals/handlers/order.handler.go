@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -9,16 +8,17 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/raihaninkam/tickitz/internals/models"
 	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/internals/utils"
 	"github.com/raihaninkam/tickitz/pkg"
 )
 
 // avail seat
 
 type SeatsHandler struct {
-	sr *repositories.SeatsRepository
+	sr repositories.SeatsStore
 }
 
-func NewSeatsHandler(sr *repositories.SeatsRepository) *SeatsHandler {
+func NewSeatsHandler(sr repositories.SeatsStore) *SeatsHandler {
 	return &SeatsHandler{sr: sr}
 }
 
@@ -48,7 +48,14 @@ func (s *SeatsHandler) GetAvailableSeats(ctx *gin.Context) {
 		return
 	}
 
-	seats, err := s.sr.GetAvailableSeats(ctx.Request.Context(), nowShowingID)
+	var userID int
+	if claims, exists := ctx.Get("claims"); exists {
+		if user, ok := claims.(pkg.Claims); ok {
+			userID = user.UserId
+		}
+	}
+
+	seats, err := s.sr.GetAvailableSeats(ctx.Request.Context(), nowShowingID, userID)
 	if err != nil {
 		if len(seats) == 0 {
 			ctx.JSON(http.StatusOK, gin.H{
@@ -59,7 +66,7 @@ func (s *SeatsHandler) GetAvailableSeats(ctx *gin.Context) {
 			return
 		}
 
-		log.Println("Internal Server Error.\nCause: ", err.Error())
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
 		return
 	}
@@ -74,11 +81,11 @@ func (s *SeatsHandler) GetAvailableSeats(ctx *gin.Context) {
 // order
 
 type OrderHandler struct {
-	or *repositories.OrderRepository
-	sr *repositories.SeatsRepository
+	or repositories.OrderStore
+	sr repositories.SeatsStore
 }
 
-func NewOrderHandler(orderRepo *repositories.OrderRepository, seatsRepo *repositories.SeatsRepository) *OrderHandler {
+func NewOrderHandler(orderRepo repositories.OrderStore, seatsRepo repositories.SeatsStore) *OrderHandler {
 	return &OrderHandler{
 		or: orderRepo,
 		sr: seatsRepo,
@@ -87,7 +94,7 @@ func NewOrderHandler(orderRepo *repositories.OrderRepository, seatsRepo *reposit
 
 // CreateOrder membuat pesanan baru
 // @Summary Create new order
-// @Description Membuat order baru beserta ticket, update showing_seats, dan relasi ke cinema
+// @Description Membuat order baru beserta ticket, update showing_seats, dan relasi ke cinema. Harga dihitung di server, field price opsional dan hanya untuk konfirmasi
 // @Tags Orders
 // @Accept json
 // @Produce json
@@ -99,12 +106,12 @@ func NewOrderHandler(orderRepo *repositories.OrderRepository, seatsRepo *reposit
 // @Failure 500 {object} map[string]interface{} "Internal Server Error"
 // @Router /orders [post]
 func (o *OrderHandler) CreateOrder(ctx *gin.Context) {
-	log.Printf("=== CREATE ORDER HANDLER START ===")
+	logger := utils.Logger(ctx.Request.Context())
 
 	// Ambil claims dari middleware
 	claims, exists := ctx.Get("claims")
 	if !exists {
-		log.Printf("Claims not found in context")
+		logger.Warn("create order: claims not found in context")
 		ctx.JSON(http.StatusUnauthorized, gin.H{
 			"success": false,
 			"error":   "Unauthorized - claims not found",
@@ -114,7 +121,7 @@ func (o *OrderHandler) CreateOrder(ctx *gin.Context) {
 
 	user, ok := claims.(pkg.Claims)
 	if !ok {
-		log.Printf("Claims type assertion failed: %T", claims)
+		logger.Error("create order: invalid claims type")
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   "Internal Server Error (claims invalid)",
@@ -122,12 +129,10 @@ func (o *OrderHandler) CreateOrder(ctx *gin.Context) {
 		return
 	}
 
-	log.Printf("User from JWT: ID=%d", user.UserId)
-
 	// Ambil body request
 	var body models.CreateOrderRequest
 	if err := ctx.ShouldBindJSON(&body); err != nil {
-		log.Printf("Bind error: %v", err)
+		logger.Debug("create order: invalid request body", "error", err)
 
 		if strings.Contains(err.Error(), "required") {
 			ctx.JSON(http.StatusBadRequest, gin.H{
@@ -144,13 +149,11 @@ func (o *OrderHandler) CreateOrder(ctx *gin.Context) {
 		return
 	}
 
-	log.Printf("Request body before user injection: %+v", body)
-
 	// Validasi tambahan
-	if body.Price <= 0 {
+	if body.Price < 0 {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
-			"error":   "Harga harus lebih dari 0",
+			"error":   "Harga tidak valid",
 		})
 		return
 	}
@@ -190,13 +193,11 @@ func (o *OrderHandler) CreateOrder(ctx *gin.Context) {
 	// Inject user ID dari JWT ke order
 	body.UsersID = user.UserId
 
-	log.Printf("Request body after user injection: %+v", body)
+	logger.Debug("create order", "user_id", body.UsersID, "now_showing_id", body.NowShowingID, "payment_id", body.PaymentID, "seats", len(body.SeatsMap))
 
 	// Panggil repository CreateOrder
 	order, err := o.or.CreateOrder(ctx.Request.Context(), body)
 	if err != nil {
-		log.Printf("Repository error: %v", err)
-
 		// Error handling lebih spesifik
 		switch {
 		case strings.Contains(err.Error(), "user not found"):
@@ -229,7 +230,28 @@ func (o *OrderHandler) CreateOrder(ctx *gin.Context) {
 				"success": false,
 				"error":   "Pemilihan kursi tidak valid",
 			})
+		case strings.Contains(err.Error(), "price mismatch"):
+			ctx.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"error":   "Harga tiket sudah berubah, silahkan muat ulang harga",
+			})
+		case strings.Contains(err.Error(), "price not configured"):
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Harga tiket untuk cinema ini belum diatur",
+			})
+		case strings.Contains(err.Error(), "insufficient points"):
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Poin tidak mencukupi",
+			})
+		case strings.Contains(err.Error(), "points exceed order total"):
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Poin yang dipakai melebihi total harga",
+			})
 		default:
+			logger.Error("create order failed", "error", err)
 			ctx.JSON(http.StatusInternalServerError, gin.H{
 				"success": false,
 				"error":   "Terjadi kesalahan internal server",
@@ -238,8 +260,13 @@ func (o *OrderHandler) CreateOrder(ctx *gin.Context) {
 		return
 	}
 
-	log.Printf("Order created successfully: %+v", order)
-	log.Printf("=== CREATE ORDER HANDLER SUCCESS ===")
+	logger.Info("order created", "order_id", order.ID, "user_id", order.UsersID, "seats", len(order.SeatsMap))
+
+	utils.OrdersCreated.Inc()
+	// seluruh harga dibayar poin: order langsung lunas tanpa webhook
+	if order.Price == 0 && order.PoinRedeemed > 0 {
+		utils.SeatsSold.Add(float64(len(order.SeatsMap)))
+	}
 
 	// Response sukses
 	ctx.JSON(http.StatusCreated, gin.H{
@@ -253,10 +280,10 @@ func (o *OrderHandler) CreateOrder(ctx *gin.Context) {
 
 // order history
 type OrderHistoryHandler struct {
-	ohr *repositories.OrderHistory
+	ohr repositories.OrderHistoryStore
 }
 
-func NewOrderHistoryHandler(ohr *repositories.OrderHistory) *OrderHistoryHandler {
+func NewOrderHistoryHandler(ohr repositories.OrderHistoryStore) *OrderHistoryHandler {
 	return &OrderHistoryHandler{ohr: ohr}
 }
 
@@ -309,3 +336,113 @@ func (h *OrderHistoryHandler) GetOrderHistory(ctx *gin.Context) {
 		"data":    orderHistories,
 	})
 }
+
+// seat hold
+
+type SeatHoldHandler struct {
+	hr repositories.SeatHoldStore
+}
+
+func NewSeatHoldHandler(hr repositories.SeatHoldStore) *SeatHoldHandler {
+	return &SeatHoldHandler{hr: hr}
+}
+
+// HoldSeats godoc
+// @Summary      Tahan kursi sebelum checkout
+// @Description  Menahan kursi (status held) untuk user yang login selama waktu yang dikonfigurasi (SEAT_HOLD_MINUTES)
+// @Tags         Seats
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      models.SeatHoldRequest  true  "Seat Hold Request"
+// @Success      201   {object}  models.SeatHoldResponse
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      409   {object}  map[string]interface{}  "Kursi sudah terjual atau ditahan user lain"
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /orders/holds [post]
+func (h *SeatHoldHandler) HoldSeats(ctx *gin.Context) {
+	claims, exists := ctx.Get("claims")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+	user, ok := claims.(pkg.Claims)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+
+	var body models.SeatHoldRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "now_showing_id dan seats_map wajib diisi",
+		})
+		return
+	}
+
+	hold, err := h.hr.HoldSeats(ctx.Request.Context(), user.UserId, body)
+	if err != nil {
+		switch err.Error() {
+		case "showing not found":
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Jadwal tayang tidak ditemukan"})
+		case "invalid seat selection":
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Pemilihan kursi tidak valid"})
+		case "seat not available":
+			ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": "Salah satu kursi sudah terjual atau sedang ditahan"})
+		default:
+			utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Kursi berhasil ditahan",
+		"data":    hold,
+	})
+}
+
+// ReleaseHolds godoc
+// @Summary      Lepas kursi yang ditahan
+// @Description  Melepas semua kursi yang ditahan user yang login pada jadwal tayang tertentu
+// @Tags         Seats
+// @Produce      json
+// @Security     BearerAuth
+// @Param        now_showing_id  path      int  true  "ID Now Showing"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /orders/holds/{now_showing_id} [delete]
+func (h *SeatHoldHandler) ReleaseHolds(ctx *gin.Context) {
+	claims, exists := ctx.Get("claims")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+	user, ok := claims.(pkg.Claims)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+
+	nowShowingID, err := strconv.Atoi(ctx.Param("now_showing_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "now_showing_id harus berupa angka"})
+		return
+	}
+
+	released, err := h.hr.ReleaseHolds(ctx.Request.Context(), user.UserId, nowShowingID)
+	if err != nil {
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"message":  "Kursi berhasil dilepas",
+		"released": released,
+	})
+}
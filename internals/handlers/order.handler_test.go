@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+const testShowingID = 7
+
+var (
+	alice = &pkg.Claims{UserId: 1, Role: "user"}
+	bob   = &pkg.Claims{UserId: 2, Role: "user"}
+)
+
+func orderBody(seats ...string) models.CreateOrderRequest {
+	return models.CreateOrderRequest{PaymentID: 1, NowShowingID: testShowingID, CinemaID: 1, SeatsMap: seats}
+}
+
+func TestGetAvailableSeats(t *testing.T) {
+	seats := newFakeSeatStore(testShowingID, "A1", "A2")
+	if _, err := seats.HoldSeats(t.Context(), bob.UserId, models.SeatHoldRequest{NowShowingID: testShowingID, SeatsMap: []string{"A1"}}); err != nil {
+		t.Fatal(err)
+	}
+	handler := NewSeatsHandler(seats)
+
+	ctx, rec := newTestContext(t, http.MethodGet, nil, alice, gin.Param{Key: "now_showing_id", Value: "abc"})
+	handler.GetAvailableSeats(ctx)
+	assertStatus(t, rec, http.StatusBadRequest)
+
+	var body struct {
+		Data []models.AvailSeat `json:"data"`
+	}
+	ctx, rec = newTestContext(t, http.MethodGet, nil, alice, gin.Param{Key: "now_showing_id", Value: "7"})
+	handler.GetAvailableSeats(ctx)
+	assertStatus(t, rec, http.StatusOK)
+	decodeJSON(t, rec, &body)
+	for _, seat := range body.Data {
+		if seat.SeatID == "A1" && (!seat.IsHeld || seat.HeldByMe) {
+			t.Errorf("A1 for alice: held=%v held_by_me=%v, want held by someone else", seat.IsHeld, seat.HeldByMe)
+		}
+	}
+}
+
+func TestHoldSeats(t *testing.T) {
+	seats := newFakeSeatStore(testShowingID, "A1", "A2", "A3")
+	handler := NewSeatHoldHandler(seats)
+
+	tests := []struct {
+		name   string
+		claims *pkg.Claims
+		body   any
+		want   int
+	}{
+		{name: "no claims", body: models.SeatHoldRequest{NowShowingID: testShowingID, SeatsMap: []string{"A1"}}, want: http.StatusUnauthorized},
+		{name: "empty seats", claims: alice, body: map[string]any{"now_showing_id": testShowingID, "seats_map": []string{}}, want: http.StatusBadRequest},
+		{name: "unknown showing", claims: alice, body: models.SeatHoldRequest{NowShowingID: 99, SeatsMap: []string{"A1"}}, want: http.StatusBadRequest},
+		{name: "unknown seat", claims: alice, body: models.SeatHoldRequest{NowShowingID: testShowingID, SeatsMap: []string{"Z9"}}, want: http.StatusBadRequest},
+		{name: "held", claims: alice, body: models.SeatHoldRequest{NowShowingID: testShowingID, SeatsMap: []string{"A1", "A2"}}, want: http.StatusCreated},
+		{name: "held again by same user", claims: alice, body: models.SeatHoldRequest{NowShowingID: testShowingID, SeatsMap: []string{"A2"}}, want: http.StatusCreated},
+		{name: "held by other user", claims: bob, body: models.SeatHoldRequest{NowShowingID: testShowingID, SeatsMap: []string{"A2", "A3"}}, want: http.StatusConflict},
+	}
+	// kasus dijalankan berurutan di atas denah yang sama
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, rec := newTestContext(t, http.MethodPost, tt.body, tt.claims)
+			handler.HoldSeats(ctx)
+			assertStatus(t, rec, tt.want)
+		})
+	}
+
+	// kursi yang gagal ditahan tidak boleh setengah tertahan
+	if seats.seats["A3"].holder != 0 {
+		t.Error("A3 held although the hold request conflicted")
+	}
+
+	ctx, rec := newTestContext(t, http.MethodDelete, nil, alice, gin.Param{Key: "now_showing_id", Value: "7"})
+	handler.ReleaseHolds(ctx)
+	assertStatus(t, rec, http.StatusOK)
+	if released := decodeBody(t, rec)["released"]; released != float64(2) {
+		t.Errorf("released %v seats, want 2", released)
+	}
+}
+
+func TestCreateOrder(t *testing.T) {
+	t.Run("no claims", func(t *testing.T) {
+		handler := NewOrderHandler(newFakeSeatStore(testShowingID, "A1"), nil)
+		ctx, rec := newTestContext(t, http.MethodPost, orderBody("A1"), nil)
+		handler.CreateOrder(ctx)
+		assertStatus(t, rec, http.StatusUnauthorized)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		handler := NewOrderHandler(newFakeSeatStore(testShowingID, "A1"), nil)
+		for name, body := range map[string]any{
+			"missing payment": models.CreateOrderRequest{NowShowingID: testShowingID, SeatsMap: []string{"A1"}},
+			"no seats":        map[string]any{"payment_id": 1, "now_showing_id": testShowingID, "seats_map": []string{}},
+			"negative points": map[string]any{"payment_id": 1, "now_showing_id": testShowingID, "seats_map": []string{"A1"}, "redeem_points": -5},
+			"malformed":       "not an object",
+		} {
+			ctx, rec := newTestContext(t, http.MethodPost, body, alice)
+			handler.CreateOrder(ctx)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("%s: status %d, want %d", name, rec.Code, http.StatusBadRequest)
+			}
+		}
+	})
+
+	t.Run("seat conflicts", func(t *testing.T) {
+		seats := newFakeSeatStore(testShowingID, "A1", "A2", "A3")
+		if _, err := seats.HoldSeats(t.Context(), bob.UserId, models.SeatHoldRequest{NowShowingID: testShowingID, SeatsMap: []string{"A3"}}); err != nil {
+			t.Fatal(err)
+		}
+		handler := NewOrderHandler(seats, seats)
+
+		ctx, rec := newTestContext(t, http.MethodPost, orderBody("A1", "A2"), alice)
+		handler.CreateOrder(ctx)
+		assertStatus(t, rec, http.StatusCreated)
+		var created struct {
+			Data models.CreateOrderResponse `json:"data"`
+		}
+		decodeJSON(t, rec, &created)
+		if created.Data.UsersID != alice.UserId {
+			t.Errorf("order for user %d, want user from claims %d", created.Data.UsersID, alice.UserId)
+		}
+
+		for name, order := range map[string]models.CreateOrderRequest{
+			"sold seat":              orderBody("A2"),
+			"seat held by other":     orderBody("A3"),
+			"partly unavailable set": orderBody("A1", "A3"),
+		} {
+			ctx, rec := newTestContext(t, http.MethodPost, order, alice)
+			handler.CreateOrder(ctx)
+			if rec.Code != http.StatusConflict {
+				t.Errorf("%s: status %d, want %d", name, rec.Code, http.StatusConflict)
+			}
+		}
+		if len(seats.orders) != 1 {
+			t.Errorf("%d orders stored, want 1", len(seats.orders))
+		}
+	})
+
+	t.Run("repository errors", func(t *testing.T) {
+		for msg, want := range map[string]int{
+			"showing not found":    http.StatusBadRequest,
+			"price not configured": http.StatusBadRequest,
+			"insufficient points":  http.StatusBadRequest,
+			"price mismatch":       http.StatusConflict,
+			"connection refused":   http.StatusInternalServerError,
+		} {
+			handler := NewOrderHandler(errSeatStore{err: errors.New(msg)}, nil)
+			ctx, rec := newTestContext(t, http.MethodPost, orderBody("A1"), alice)
+			handler.CreateOrder(ctx)
+			if rec.Code != want {
+				t.Errorf("%s: status %d, want %d", msg, rec.Code, want)
+			}
+		}
+	})
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/internals/utils"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+type PaymentHandler struct {
+	pr            repositories.PaymentStore
+	gateway       pkg.PaymentGateway
+	webhookSecret string
+}
+
+func NewPaymentHandler(pr repositories.PaymentStore, gateway pkg.PaymentGateway, webhookSecret string) *PaymentHandler {
+	return &PaymentHandler{pr: pr, gateway: gateway, webhookSecret: webhookSecret}
+}
+
+// PayOrder godoc
+// @Summary      Bayar order
+// @Description  Membuat charge ke payment gateway untuk order milik user yang login. Jika masih ada transaksi pending, transaksi tersebut dikembalikan
+// @Tags         Payments
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Order ID"
+// @Success      201  {object}  models.PaymentTransaction
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}  "Order sudah dibayar atau tidak bisa dibayar"
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /orders/{id}/pay [post]
+func (p *PaymentHandler) PayOrder(ctx *gin.Context) {
+	claims, exists := ctx.Get("claims")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+	user, ok := claims.(pkg.Claims)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+
+	orderID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Order ID harus berupa angka"})
+		return
+	}
+
+	order, err := p.pr.GetOrderForPayment(ctx.Request.Context(), orderID, user.UserId)
+	if err != nil {
+		if err.Error() == "order not found" {
+			ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Order tidak ditemukan"})
+			return
+		}
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	if order.IsPaid {
+		ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": "Order sudah dibayar"})
+		return
+	}
+	if order.Status != "pending" {
+		ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": "Order tidak bisa dibayar"})
+		return
+	}
+
+	// transaksi pending yang masih ada dipakai ulang
+	if trx, err := p.pr.GetPendingTransaction(ctx.Request.Context(), order.OrderId); err == nil {
+		ctx.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": "Menunggu pembayaran",
+			"data":    trx,
+		})
+		return
+	} else if err.Error() != "transaction not found" {
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	charge, err := p.gateway.CreateCharge(ctx.Request.Context(), pkg.ChargeRequest{
+		OrderId: order.OrderId,
+		Amount:  order.Price,
+		Method:  order.Method,
+	})
+	if err != nil {
+		utils.Logger(ctx.Request.Context()).Error("payment gateway error", "error", err)
+		utils.PaymentFailures.WithLabelValues("gateway_error").Inc()
+		ctx.JSON(http.StatusBadGateway, gin.H{"success": false, "error": "Gagal membuat pembayaran, coba lagi"})
+		return
+	}
+
+	trx, err := p.pr.CreateTransaction(ctx.Request.Context(), order.OrderId, p.gateway.Name(), charge)
+	if err != nil {
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"success":      true,
+		"message":      "Pembayaran dibuat",
+		"data":         trx,
+		"redirect_url": charge.RedirectURL,
+		"expires_at":   charge.ExpiresAt,
+	})
+}
+
+// PaymentWebhook godoc
+// @Summary      Webhook payment gateway
+// @Description  Menerima hasil pembayaran dari gateway. Body harus ditandatangani HMAC-SHA256 (hex) dengan PAYMENT_WEBHOOK_SECRET di header X-Signature. Status paid hanya diterima jika amount sama dengan total order
+// @Tags         Payments
+// @Accept       json
+// @Produce      json
+// @Param        X-Signature  header    string                        true  "HMAC-SHA256 signature"
+// @Param        body         body      models.PaymentWebhookRequest  true  "Payment result"
+// @Success      200          {object}  map[string]interface{}
+// @Failure      400          {object}  map[string]interface{}
+// @Failure      401          {object}  map[string]interface{}
+// @Failure      404          {object}  map[string]interface{}
+// @Failure      409          {object}  map[string]interface{}  "Transaksi sudah diproses atau jumlah pembayaran tidak sesuai"
+// @Router       /payments/webhook [post]
+func (p *PaymentHandler) PaymentWebhook(ctx *gin.Context) {
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Body tidak valid"})
+		return
+	}
+
+	if !pkg.VerifyWebhookSignature(p.webhookSecret, body, ctx.GetHeader("X-Signature")) {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Signature tidak valid"})
+		return
+	}
+
+	var req models.PaymentWebhookRequest
+	if err := json.Unmarshal(body, &req); err != nil || req.Reference == "" || req.Status == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "reference dan status wajib diisi"})
+		return
+	}
+
+	var status pkg.ChargeStatus
+	switch req.Status {
+	case "paid", "settlement", "success":
+		status = pkg.ChargePaid
+	case "failed", "expired", "cancelled":
+		status = pkg.ChargeFailed
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Status pembayaran tidak dikenal"})
+		return
+	}
+
+	if status == pkg.ChargePaid && req.Amount <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "amount wajib diisi untuk pembayaran berhasil"})
+		return
+	}
+
+	trx, err := p.pr.ApplyPaymentResult(ctx.Request.Context(), req.Reference, status, req.Amount)
+	if err != nil {
+		switch err.Error() {
+		case "transaction not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Transaksi tidak ditemukan"})
+		case "amount mismatch":
+			utils.Logger(ctx.Request.Context()).Warn("payment amount mismatch", "reference", req.Reference, "amount", req.Amount)
+			ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": "Jumlah pembayaran tidak sesuai dengan total order"})
+		case "transaction already settled":
+			ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": "Transaksi sudah diproses"})
+		default:
+			utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    trx,
+	})
+}
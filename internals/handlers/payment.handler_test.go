@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+const testWebhookSecret = "test-webhook-secret"
+
+// webhookContext request webhook dengan body mentah, ditandatangani jika secret tidak kosong
+func webhookContext(body, secret string) (*gin.Context, *httptest.ResponseRecorder) {
+	rec := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(rec)
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/payments/webhook", bytes.NewBufferString(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		ctx.Request.Header.Set("X-Signature", pkg.SignWebhookPayload(secret, []byte(body)))
+	}
+	return ctx, rec
+}
+
+func TestPaymentWebhook(t *testing.T) {
+	pending := func(reference string) models.PaymentTransaction {
+		return models.PaymentTransaction{Id: 1, OrdersId: 10, Reference: reference, Amount: 50000, Status: "pending"}
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		secret string
+		want   int
+		status string
+	}{
+		{name: "unsigned", body: `{"reference":"REF-1","status":"paid","amount":50000}`, want: http.StatusUnauthorized, status: "pending"},
+		{name: "wrong secret", body: `{"reference":"REF-1","status":"paid","amount":50000}`, secret: "other-secret", want: http.StatusUnauthorized, status: "pending"},
+		{name: "missing reference", body: `{"status":"paid","amount":50000}`, secret: testWebhookSecret, want: http.StatusBadRequest, status: "pending"},
+		{name: "unknown status", body: `{"reference":"REF-1","status":"refunding","amount":50000}`, secret: testWebhookSecret, want: http.StatusBadRequest, status: "pending"},
+		{name: "paid without amount", body: `{"reference":"REF-1","status":"paid"}`, secret: testWebhookSecret, want: http.StatusBadRequest, status: "pending"},
+		{name: "paid less than order total", body: `{"reference":"REF-1","status":"paid","amount":5000}`, secret: testWebhookSecret, want: http.StatusConflict, status: "pending"},
+		{name: "unknown reference", body: `{"reference":"REF-2","status":"paid","amount":50000}`, secret: testWebhookSecret, want: http.StatusNotFound, status: "pending"},
+		{name: "paid", body: `{"reference":"REF-1","status":"settlement","amount":50000}`, secret: testWebhookSecret, want: http.StatusOK, status: "paid"},
+		{name: "failed without amount", body: `{"reference":"REF-1","status":"expired"}`, secret: testWebhookSecret, want: http.StatusOK, status: "failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payments := newFakePaymentStore(pending("REF-1"))
+			handler := NewPaymentHandler(payments, nil, testWebhookSecret)
+
+			ctx, rec := webhookContext(tt.body, tt.secret)
+			handler.PaymentWebhook(ctx)
+			assertStatus(t, rec, tt.want)
+			if got := payments.trx["REF-1"].Status; got != tt.status {
+				t.Errorf("transaction status %s, want %s", got, tt.status)
+			}
+		})
+	}
+
+	t.Run("settled only once", func(t *testing.T) {
+		handler := NewPaymentHandler(newFakePaymentStore(pending("REF-1")), nil, testWebhookSecret)
+		body := `{"reference":"REF-1","status":"paid","amount":50000}`
+
+		ctx, rec := webhookContext(body, testWebhookSecret)
+		handler.PaymentWebhook(ctx)
+		assertStatus(t, rec, http.StatusOK)
+
+		ctx, rec = webhookContext(`{"reference":"REF-1","status":"failed"}`, testWebhookSecret)
+		handler.PaymentWebhook(ctx)
+		assertStatus(t, rec, http.StatusConflict)
+	})
+}
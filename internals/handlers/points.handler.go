@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/internals/utils"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+type PointsHandler struct {
+	pr repositories.PointsStore
+}
+
+func NewPointsHandler(pr repositories.PointsStore) *PointsHandler {
+	return &PointsHandler{pr: pr}
+}
+
+// GetMyPoints godoc
+// @Summary      Riwayat poin loyalty
+// @Description  Saldo poin user yang login beserta riwayat earn, redeem, refund, expire dan adjust
+// @Tags         Profile
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  models.PointsHistory
+// @Failure      401  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /profile/points [get]
+func (p *PointsHandler) GetMyPoints(ctx *gin.Context) {
+	claims, exists := ctx.Get("claims")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+	user, ok := claims.(pkg.Claims)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+
+	history, err := p.pr.GetPointsHistory(ctx.Request.Context(), user.UserId)
+	if err != nil {
+		if err.Error() == "user not found" {
+			ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "User tidak ditemukan"})
+			return
+		}
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    history,
+	})
+}
+
+// AdjustPoints godoc
+// @Summary      Adjust poin user (Admin)
+// @Description  Menambah (positif) atau mengurangi (negatif) poin user, tercatat sebagai entry adjust di ledger
+// @Tags         Admin-Users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                         true  "User ID"
+// @Param        body  body      models.PointsAdjustRequest  true  "Adjustment"
+// @Success      200   {object}  models.PointsAdjustResponse
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /admin/users/{id}/points [post]
+func (p *PointsHandler) AdjustPoints(ctx *gin.Context) {
+	claims, exists := ctx.Get("claims")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+	admin, ok := claims.(pkg.Claims)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "User ID harus berupa angka"})
+		return
+	}
+
+	var body models.PointsAdjustRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.Description) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "points (tidak boleh 0) dan description wajib diisi"})
+		return
+	}
+	body.Description = strings.TrimSpace(body.Description)
+
+	result, err := p.pr.AdjustPoints(ctx.Request.Context(), userID, admin.UserId, body)
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "User tidak ditemukan"})
+		case "insufficient points":
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Saldo poin tidak boleh negatif"})
+		default:
+			utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Poin berhasil diupdate",
+		"data":    result,
+	})
+}
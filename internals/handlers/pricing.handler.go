@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/internals/utils"
+)
+
+var (
+	priceSeatClasses = []string{"regular", "love_nest", "wheelchair"}
+	priceDayTypes    = []string{"weekday", "weekend"}
+)
+
+type PricingHandler struct {
+	pr repositories.PricingStore
+}
+
+func NewPricingHandler(pr repositories.PricingStore) *PricingHandler {
+	return &PricingHandler{pr: pr}
+}
+
+// GetQuote godoc
+// @Summary      Hitung harga tiket
+// @Description  Menghitung harga kursi berdasarkan jadwal tayang, cinema, kelas kursi, hari dan jam tayang
+// @Tags         Orders
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      models.PriceQuoteRequest  true  "Quote Request"
+// @Success      200   {object}  models.PriceQuote
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /orders/quote [post]
+func (p *PricingHandler) GetQuote(ctx *gin.Context) {
+	var body models.PriceQuoteRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "now_showing_id dan seats_map wajib diisi",
+		})
+		return
+	}
+
+	quote, err := p.pr.QuotePrice(ctx.Request.Context(), body.NowShowingID, body.SeatsMap)
+	if err != nil {
+		switch err.Error() {
+		case "showing not found":
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Jadwal tayang tidak ditemukan"})
+		case "invalid seat selection":
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Pemilihan kursi tidak valid"})
+		case "price not configured":
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Harga tiket untuk cinema ini belum diatur"})
+		default:
+			utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    quote,
+	})
+}
+
+// GetPricing godoc
+// @Summary      List harga tiket (Admin)
+// @Description  Ambil semua harga dasar tiket dan aturan harga
+// @Tags         Admin-Pricing
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /admin/pricing [get]
+func (p *PricingHandler) GetPricing(ctx *gin.Context) {
+	prices, err := p.pr.GetTicketPrices(ctx.Request.Context())
+	if err != nil {
+		utils.Logger(ctx.Request.Context()).Error("handler error", "op", "GetTicketPrices", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	rules, err := p.pr.GetPriceRules(ctx.Request.Context())
+	if err != nil {
+		utils.Logger(ctx.Request.Context()).Error("handler error", "op", "GetPriceRules", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"base_prices": prices,
+			"rules":       rules,
+		},
+	})
+}
+
+// SetBasePrice godoc
+// @Summary      Atur harga dasar (Admin)
+// @Description  Membuat atau mengganti harga dasar tiket sebuah cinema. cinema_id kosong = harga default
+// @Tags         Admin-Pricing
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      models.TicketPriceRequest  true  "Base price"
+// @Success      200   {object}  models.TicketPrice
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /admin/pricing/base [put]
+func (p *PricingHandler) SetBasePrice(ctx *gin.Context) {
+	var body models.TicketPriceRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Harga wajib diisi dan tidak boleh negatif"})
+		return
+	}
+
+	price, err := p.pr.SetTicketPrice(ctx.Request.Context(), body)
+	if err != nil {
+		if err.Error() == "cinema not found" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Cinema tidak ditemukan"})
+			return
+		}
+		utils.Logger(ctx.Request.Context()).Error("handler error", "op", "SetTicketPrice", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Harga dasar berhasil disimpan",
+		"data":    price,
+	})
+}
+
+// CreatePriceRule godoc
+// @Summary      Tambah aturan harga (Admin)
+// @Description  Aturan menambah/mengurangi harga per kursi berdasarkan cinema, kelas kursi, hari dan jam tayang
+// @Tags         Admin-Pricing
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      models.PriceRuleRequest  true  "Price rule"
+// @Success      201   {object}  models.PriceRule
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /admin/pricing/rules [post]
+func (p *PricingHandler) CreatePriceRule(ctx *gin.Context) {
+	var body models.PriceRuleRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "name dan amount wajib diisi"})
+		return
+	}
+	if err := validatePriceRule(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	rule, err := p.pr.CreatePriceRule(ctx.Request.Context(), body)
+	if err != nil {
+		if err.Error() == "cinema not found" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Cinema tidak ditemukan"})
+			return
+		}
+		utils.Logger(ctx.Request.Context()).Error("handler error", "op", "CreatePriceRule", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Aturan harga berhasil ditambahkan",
+		"data":    rule,
+	})
+}
+
+// UpdatePriceRule godoc
+// @Summary      Update aturan harga (Admin)
+// @Description  Mengganti seluruh isi aturan harga berdasarkan ID
+// @Tags         Admin-Pricing
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        ruleId  path      int                      true  "Rule ID"
+// @Param        body    body      models.PriceRuleRequest  true  "Price rule"
+// @Success      200     {object}  models.PriceRule
+// @Failure      400     {object}  map[string]interface{}
+// @Failure      404     {object}  map[string]interface{}
+// @Failure      500     {object}  map[string]interface{}
+// @Router       /admin/pricing/rules/{ruleId} [put]
+func (p *PricingHandler) UpdatePriceRule(ctx *gin.Context) {
+	ruleId, err := strconv.Atoi(ctx.Param("ruleId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid rule ID"})
+		return
+	}
+
+	var body models.PriceRuleRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "name dan amount wajib diisi"})
+		return
+	}
+	if err := validatePriceRule(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	rule, err := p.pr.UpdatePriceRule(ctx.Request.Context(), ruleId, body)
+	if err != nil {
+		switch err.Error() {
+		case "price rule not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Aturan harga tidak ditemukan"})
+		case "cinema not found":
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Cinema tidak ditemukan"})
+		default:
+			utils.Logger(ctx.Request.Context()).Error("handler error", "op", "UpdatePriceRule", "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Aturan harga berhasil diupdate",
+		"data":    rule,
+	})
+}
+
+// DeletePriceRule godoc
+// @Summary      Hapus aturan harga (Admin)
+// @Tags         Admin-Pricing
+// @Security     BearerAuth
+// @Produce      json
+// @Param        ruleId  path      int  true  "Rule ID"
+// @Success      200     {object}  map[string]interface{}
+// @Failure      404     {object}  map[string]interface{}
+// @Failure      500     {object}  map[string]interface{}
+// @Router       /admin/pricing/rules/{ruleId} [delete]
+func (p *PricingHandler) DeletePriceRule(ctx *gin.Context) {
+	ruleId, err := strconv.Atoi(ctx.Param("ruleId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid rule ID"})
+		return
+	}
+
+	if err := p.pr.DeletePriceRule(ctx.Request.Context(), ruleId); err != nil {
+		if err.Error() == "price rule not found" {
+			ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Aturan harga tidak ditemukan"})
+			return
+		}
+		utils.Logger(ctx.Request.Context()).Error("handler error", "op", "DeletePriceRule", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Aturan harga berhasil dihapus",
+	})
+}
+
+// validatePriceRule memvalidasi seat_class, day_type dan format jam (HH:MM)
+func validatePriceRule(body *models.PriceRuleRequest) error {
+	if strings.TrimSpace(body.Name) == "" {
+		return fmt.Errorf("name wajib diisi")
+	}
+	if body.SeatClass != nil && !slices.Contains(priceSeatClasses, *body.SeatClass) {
+		return fmt.Errorf("seat_class harus salah satu dari: %s", strings.Join(priceSeatClasses, ", "))
+	}
+	if body.DayType != nil && !slices.Contains(priceDayTypes, *body.DayType) {
+		return fmt.Errorf("day_type harus salah satu dari: %s", strings.Join(priceDayTypes, ", "))
+	}
+
+	var start, end time.Time
+	var err error
+	if body.StartTime != nil {
+		if start, err = time.Parse("15:04", *body.StartTime); err != nil {
+			return fmt.Errorf("format start_time harus HH:MM")
+		}
+	}
+	if body.EndTime != nil {
+		if end, err = time.Parse("15:04", *body.EndTime); err != nil {
+			return fmt.Errorf("format end_time harus HH:MM")
+		}
+	}
+	if body.StartTime != nil && body.EndTime != nil && !start.Before(end) {
+		return fmt.Errorf("start_time harus lebih awal dari end_time")
+	}
+	return nil
+}
@@ -2,25 +2,28 @@ package handlers
 
 import (
 	"fmt"
-	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/raihaninkam/tickitz/internals/models"
 	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/internals/utils"
 	"github.com/raihaninkam/tickitz/pkg"
 )
 
 type ProfileHandler struct {
-	pr *repositories.ProfileRepository
+	pr     repositories.ProfileStore
+	upload utils.FileUploadConfig
 }
 
-func NewProfileHandler(pr *repositories.ProfileRepository) *ProfileHandler {
-	return &ProfileHandler{pr: pr}
+func NewProfileHandler(pr repositories.ProfileStore, upload utils.FileUploadConfig) *ProfileHandler {
+	return &ProfileHandler{pr: pr, upload: upload}
 }
 
 // GetMyProfile godoc
@@ -98,13 +101,21 @@ func (h *ProfileHandler) UpdateProfileWithImage(ctx *gin.Context) {
 
 	// UBAH INI: Simpan ke public/images/profiles
 	if body.Images != nil {
-		ext := filepath.Ext(body.Images.Filename)
+		ext := strings.ToLower(filepath.Ext(body.Images.Filename))
+		if body.Images.Size > h.upload.MaxSize {
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": fmt.Sprintf("Ukuran gambar maksimal %d MB", h.upload.MaxSize/(1024*1024))})
+			return
+		}
+		if !slices.Contains(h.upload.AllowedExts, ext) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Format gambar tidak didukung"})
+			return
+		}
 		filename := fmt.Sprintf("%d_profile_%d%s", time.Now().UnixNano(), userID, ext)
 
 		// Ubah path ke public/images/profiles
 		uploadDir := filepath.Join("public", "images", "profiles")
 		if err := os.MkdirAll(uploadDir, 0755); err != nil {
-			log.Println("Failed to create upload dir:", err.Error())
+			utils.Logger(ctx.Request.Context()).Error("failed to create upload dir", "error", err)
 			ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to create directory"})
 			return
 		}
@@ -121,7 +132,7 @@ func (h *ProfileHandler) UpdateProfileWithImage(ctx *gin.Context) {
 
 	profile, err := h.pr.UpdateProfile(ctx.Request.Context(), userID, profileReq)
 	if err != nil {
-		log.Println("UpdateProfile error:", err)
+		utils.Logger(ctx.Request.Context()).Error("handler error", "op", "UpdateProfile", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
 		return
 	}
@@ -131,36 +142,49 @@ func (h *ProfileHandler) UpdateProfileWithImage(ctx *gin.Context) {
 
 // ChangePassword godoc
 // @Summary     Change Password
-// @Description Ubah password user
+// @Description Ubah password user yang sedang login (userId dari JWT), password lama wajib benar
 // @Tags        Profile
 // @Accept      json
 // @Produce     json
+// @Security    BearerAuth
 // @Param       body body models.ChangePasswordRequest true "Password Data"
 // @Success     200 {string} string "Password berhasil diubah"
 // @Router      /profile/change-password [patch]
 func (h *ProfileHandler) ChangePassword(ctx *gin.Context) {
-	var req models.ChangePasswordRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
+	claimsValue, exists := ctx.Get("claims")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+
+	claims, ok := claimsValue.(pkg.Claims)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
 		return
 	}
 
-	// Validasi: email harus disertakan
-	if req.Email == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Email harus disertakan"})
+	var req models.ChangePasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body: " + err.Error()})
 		return
 	}
 
-	// Cari user berdasarkan email
-	user, err := h.pr.GetUserByEmail(ctx.Request.Context(), req.Email)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "User tidak ditemukan"})
+	if err := utils.ValidatePassword(req.NewPassword); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
 		return
 	}
 
 	// Ubah password
-	if err := h.pr.ChangePassword(ctx.Request.Context(), user.Id, req.OldPassword, req.NewPassword); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+	if err := h.pr.ChangePassword(ctx.Request.Context(), claims.UserId, req.OldPassword, req.NewPassword); err != nil {
+		switch err.Error() {
+		case "old password incorrect":
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Password lama salah"})
+		case "user not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "User tidak ditemukan"})
+		default:
+			utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		}
 		return
 	}
 
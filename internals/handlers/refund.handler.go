@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/internals/utils"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+type RefundHandler struct {
+	rr      repositories.RefundStore
+	gateway pkg.PaymentGateway
+}
+
+func NewRefundHandler(rr repositories.RefundStore, gateway pkg.PaymentGateway) *RefundHandler {
+	return &RefundHandler{rr: rr, gateway: gateway}
+}
+
+// CancelOrder godoc
+// @Summary      Batalkan order
+// @Description  User membatalkan ordernya sendiri sampai batas waktu sebelum jam tayang (ORDER_CANCEL_CUTOFF_MINUTES). Kursi dilepas, tiket di-void, poin dikembalikan dan order yang sudah dibayar di-refund
+// @Tags         Orders
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                        true   "Order ID"
+// @Param        body  body      models.CancelOrderRequest  false  "Alasan pembatalan"
+// @Success      200   {object}  models.CancelOrderResponse
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      409   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /orders/{id}/cancel [post]
+func (r *RefundHandler) CancelOrder(ctx *gin.Context) {
+	r.cancel(ctx, false)
+}
+
+// AdminCancelOrder godoc
+// @Summary      Batalkan order (Admin)
+// @Description  Admin membatalkan order milik siapa saja tanpa batas waktu
+// @Tags         Admin-Orders
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                        true   "Order ID"
+// @Param        body  body      models.CancelOrderRequest  false  "Alasan pembatalan"
+// @Success      200   {object}  models.CancelOrderResponse
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      409   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /admin/orders/{id}/cancel [post]
+func (r *RefundHandler) AdminCancelOrder(ctx *gin.Context) {
+	r.cancel(ctx, true)
+}
+
+func (r *RefundHandler) cancel(ctx *gin.Context, asAdmin bool) {
+	claims, exists := ctx.Get("claims")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+	user, ok := claims.(pkg.Claims)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+
+	orderID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Order ID harus berupa angka"})
+		return
+	}
+
+	// body opsional, hanya berisi alasan pembatalan
+	var body models.CancelOrderRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Body tidak valid"})
+			return
+		}
+	}
+
+	var requesterID *int
+	if !asAdmin {
+		requesterID = &user.UserId
+	}
+
+	result, err := r.rr.CancelOrder(ctx.Request.Context(), orderID, requesterID, user.UserId, strings.TrimSpace(body.Reason))
+	if err != nil {
+		switch err.Error() {
+		case "order not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Order tidak ditemukan"})
+		case "order already cancelled":
+			ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": "Order sudah dibatalkan"})
+		case "order cannot be cancelled":
+			ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": "Order tidak bisa dibatalkan"})
+		case "cancellation window closed":
+			ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": "Batas waktu pembatalan sudah lewat"})
+		default:
+			utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		}
+		return
+	}
+
+	message := settleRefund(ctx.Request.Context(), r.rr, r.gateway, &result)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": message,
+		"data":    result,
+	})
+}
+
+// settleRefund meneruskan refund order yang sudah dibatalkan ke payment gateway.
+// Refund ke gateway dilakukan setelah transaksi commit, jika gagal refund tetap
+// tercatat dengan status failed dan dicoba ulang oleh RefundRepository.RunRefundRetrier.
+// Mengembalikan pesan untuk respon
+func settleRefund(rctx context.Context, rr repositories.RefundStore, gateway pkg.PaymentGateway, result *models.CancelOrderResponse) string {
+	if result.Refund == nil {
+		return "Order berhasil dibatalkan"
+	}
+	if result.Refund.Reference == "" {
+		return "Order dibatalkan, refund akan diproses manual"
+	}
+
+	_, refundErr := gateway.Refund(rctx, result.Refund.Reference, result.Refund.Amount)
+	if refundErr != nil {
+		utils.Logger(rctx).Error("refund failed", "refund_id", result.Refund.Id, "error", refundErr)
+	}
+	refund, err := rr.CompleteRefund(rctx, result.Refund.Id, refundErr == nil)
+	if err != nil {
+		utils.Logger(rctx).Error("internal server error", "error", err)
+	} else {
+		result.Refund = &refund
+	}
+	if refundErr != nil {
+		return "Order dibatalkan, refund gagal dan akan dicoba ulang otomatis"
+	}
+	return "Order berhasil dibatalkan"
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raihaninkam/tickitz/internals/models"
+)
+
+func TestCancelOrderRefund(t *testing.T) {
+	tests := []struct {
+		name       string
+		refund     *models.Refund
+		gatewayErr error
+		message    string
+		status     string
+		refunded   bool
+	}{
+		{name: "unpaid order", message: "Order berhasil dibatalkan"},
+		{name: "refunded", refund: &models.Refund{Id: 1, Reference: "REF-1", Amount: 50000, Status: "pending"}, message: "Order berhasil dibatalkan", status: "refunded", refunded: true},
+		{name: "gateway down", refund: &models.Refund{Id: 1, Reference: "REF-1", Amount: 50000, Status: "pending"}, gatewayErr: errors.New("gateway timeout"), message: "Order dibatalkan, refund gagal dan akan dicoba ulang otomatis", status: "failed", refunded: true},
+		{name: "paid before payment transactions", refund: &models.Refund{Id: 1, Amount: 50000, Status: "pending"}, message: "Order dibatalkan, refund akan diproses manual", status: "pending"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const orderID = 10
+			refunds := newFakeRefundStore(map[int]*models.Refund{orderID: tt.refund})
+			gateway := &fakeGateway{refundErr: tt.gatewayErr}
+			handler := NewRefundHandler(refunds, gateway)
+
+			ctx, rec := newTestContext(t, http.MethodPost, nil, alice, gin.Param{Key: "id", Value: strconv.Itoa(orderID)})
+			handler.CancelOrder(ctx)
+			assertStatus(t, rec, http.StatusOK)
+
+			var body struct {
+				Message string                     `json:"message"`
+				Data    models.CancelOrderResponse `json:"data"`
+			}
+			decodeJSON(t, rec, &body)
+			if body.Message != tt.message {
+				t.Errorf("message %q, want %q", body.Message, tt.message)
+			}
+			if tt.refund != nil && (body.Data.Refund == nil || body.Data.Refund.Status != tt.status) {
+				t.Errorf("refund in response %+v, want status %s", body.Data.Refund, tt.status)
+			}
+			if sent := len(gateway.refunds) > 0; sent != tt.refunded {
+				t.Errorf("refund sent to gateway: %v, want %v", sent, tt.refunded)
+			}
+
+			ctx, rec = newTestContext(t, http.MethodPost, nil, alice, gin.Param{Key: "id", Value: strconv.Itoa(orderID)})
+			handler.CancelOrder(ctx)
+			assertStatus(t, rec, http.StatusConflict)
+		})
+	}
+}
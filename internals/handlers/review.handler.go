@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/internals/utils"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+type ReviewHandler struct {
+	rr repositories.ReviewStore
+}
+
+func NewReviewHandler(rr repositories.ReviewStore) *ReviewHandler {
+	return &ReviewHandler{rr: rr}
+}
+
+// GetMovieReviews godoc
+// @Summary     Get Movie Reviews
+// @Description Mengambil review (published) sebuah movie per halaman beserta rata-rata dan sebaran rating
+// @Tags        Reviews
+// @Produce     json
+// @Param       movie_id  path   int     true   "Movie ID"
+// @Param       sort      query  string  false  "Urutan: newest (default), rating_high, rating_low"
+// @Param       page      query  int     false  "Halaman (default: 1)"
+// @Param       limit     query  int     false  "Jumlah per halaman (default: 12, maksimal 50)"
+// @Success     200 {object} map[string]interface{} "Daftar review dan summary"
+// @Failure     400 {object} map[string]interface{} "Bad Request - Movie ID tidak valid"
+// @Failure     500 {object} map[string]interface{} "Internal Server Error"
+// @Router      /movies/{movie_id}/reviews [get]
+func (r *ReviewHandler) GetMovieReviews(ctx *gin.Context) {
+	movieID, ok := parseReviewParam(ctx, "movie_id", "Movie ID")
+	if !ok {
+		return
+	}
+	page, limit := reviewPagination(ctx)
+
+	reviews, total, err := r.rr.GetMovieReviews(ctx.Request.Context(), movieID, ctx.Query("sort"), (page-1)*limit, limit)
+	if err != nil {
+		reviewError(ctx, err)
+		return
+	}
+	summary, err := r.rr.GetReviewSummary(ctx.Request.Context(), movieID)
+	if err != nil {
+		reviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    reviews,
+		"summary": summary,
+		"page":    page,
+		"limit":   limit,
+		"count":   total,
+	})
+}
+
+// UpsertReview godoc
+// @Summary     Post Movie Review
+// @Description Memberi rating 1-5 dan ulasan untuk movie. Hanya bisa jika user punya order paid untuk movie tersebut. Jika sudah pernah, review sebelumnya diganti
+// @Tags        Reviews
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       movie_id  path  int                   true  "Movie ID"
+// @Param       body      body  models.ReviewRequest  true  "Rating dan ulasan"
+// @Success     200 {object} models.Review "Review diupdate"
+// @Success     201 {object} models.Review "Review dibuat"
+// @Failure     400 {object} map[string]interface{} "Bad Request - Rating tidak valid"
+// @Failure     401 {object} map[string]interface{} "Unauthorized"
+// @Failure     403 {object} map[string]interface{} "Forbidden - Belum pernah membeli tiket movie ini"
+// @Failure     404 {object} map[string]interface{} "Not Found - Movie tidak ditemukan"
+// @Failure     500 {object} map[string]interface{} "Internal Server Error"
+// @Router      /movies/{movie_id}/reviews [post]
+func (r *ReviewHandler) UpsertReview(ctx *gin.Context) {
+	user, ok := reviewClaims(ctx)
+	if !ok {
+		return
+	}
+	movieID, ok := parseReviewParam(ctx, "movie_id", "Movie ID")
+	if !ok {
+		return
+	}
+
+	var body models.ReviewRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "rating wajib diisi (1-5), body maksimal 2000 karakter"})
+		return
+	}
+	body.Body = strings.TrimSpace(body.Body)
+
+	review, created, err := r.rr.UpsertReview(ctx.Request.Context(), user.UserId, movieID, body)
+	if err != nil {
+		reviewError(ctx, err)
+		return
+	}
+
+	status, message := http.StatusOK, "Review berhasil diupdate"
+	if created {
+		status, message = http.StatusCreated, "Review berhasil ditambahkan"
+	}
+	ctx.JSON(status, gin.H{
+		"success": true,
+		"message": message,
+		"data":    review,
+	})
+}
+
+// DeleteOwnReview godoc
+// @Summary     Delete My Movie Review
+// @Description Menghapus review milik user untuk movie ini
+// @Tags        Reviews
+// @Security    BearerAuth
+// @Produce     json
+// @Param       movie_id  path  int  true  "Movie ID"
+// @Success     200 {object} map[string]interface{}
+// @Failure     400 {object} map[string]interface{}
+// @Failure     401 {object} map[string]interface{}
+// @Failure     404 {object} map[string]interface{}
+// @Failure     500 {object} map[string]interface{}
+// @Router      /movies/{movie_id}/reviews [delete]
+func (r *ReviewHandler) DeleteOwnReview(ctx *gin.Context) {
+	user, ok := reviewClaims(ctx)
+	if !ok {
+		return
+	}
+	movieID, ok := parseReviewParam(ctx, "movie_id", "Movie ID")
+	if !ok {
+		return
+	}
+
+	if err := r.rr.DeleteOwnReview(ctx.Request.Context(), user.UserId, movieID); err != nil {
+		reviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Review berhasil dihapus",
+	})
+}
+
+// GetReviews godoc
+// @Summary      List review (Admin)
+// @Description  Semua review termasuk yang disembunyikan, untuk moderasi
+// @Tags         Admin-Reviews
+// @Security     BearerAuth
+// @Produce      json
+// @Param        movie_id  query     int     false  "Filter movie"
+// @Param        status    query     string  false  "Filter status: published / hidden"
+// @Param        page      query     int     false  "Halaman (default: 1)"
+// @Param        limit     query     int     false  "Jumlah per halaman (default: 12, maksimal 50)"
+// @Success      200       {array}   models.Review
+// @Failure      400       {object}  map[string]interface{}
+// @Failure      500       {object}  map[string]interface{}
+// @Router       /admin/reviews [get]
+func (r *ReviewHandler) GetReviews(ctx *gin.Context) {
+	var filter models.ReviewFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Filter tidak valid"})
+		return
+	}
+	if filter.Status != "" && filter.Status != "published" && filter.Status != "hidden" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "status harus published atau hidden"})
+		return
+	}
+	page, limit := reviewPagination(ctx)
+
+	reviews, total, err := r.rr.GetReviews(ctx.Request.Context(), filter, (page-1)*limit, limit)
+	if err != nil {
+		reviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    reviews,
+		"page":    page,
+		"limit":   limit,
+		"count":   total,
+	})
+}
+
+// ModerateReview godoc
+// @Summary      Moderasi review (Admin)
+// @Description  Menyembunyikan atau menampilkan kembali review. Review hidden tidak dihitung di rating movie
+// @Tags         Admin-Reviews
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                           true  "Review ID"
+// @Param        body  body      models.ModerateReviewRequest  true  "Status dan catatan moderasi"
+// @Success      200   {object}  models.Review
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /admin/reviews/{id} [patch]
+func (r *ReviewHandler) ModerateReview(ctx *gin.Context) {
+	admin, ok := reviewClaims(ctx)
+	if !ok {
+		return
+	}
+	reviewID, ok := parseReviewParam(ctx, "id", "Review ID")
+	if !ok {
+		return
+	}
+
+	var body models.ModerateReviewRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "status wajib diisi (published / hidden), note maksimal 500 karakter"})
+		return
+	}
+
+	review, err := r.rr.ModerateReview(ctx.Request.Context(), reviewID, admin.UserId, body)
+	if err != nil {
+		reviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Review berhasil dimoderasi",
+		"data":    review,
+	})
+}
+
+// DeleteReview godoc
+// @Summary      Hapus review (Admin)
+// @Tags         Admin-Reviews
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      int  true  "Review ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /admin/reviews/{id} [delete]
+func (r *ReviewHandler) DeleteReview(ctx *gin.Context) {
+	reviewID, ok := parseReviewParam(ctx, "id", "Review ID")
+	if !ok {
+		return
+	}
+
+	if err := r.rr.DeleteReview(ctx.Request.Context(), reviewID); err != nil {
+		reviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Review berhasil dihapus",
+	})
+}
+
+func reviewClaims(ctx *gin.Context) (pkg.Claims, bool) {
+	claims, exists := ctx.Get("claims")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return pkg.Claims{}, false
+	}
+	user, ok := claims.(pkg.Claims)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return pkg.Claims{}, false
+	}
+	return user, true
+}
+
+func parseReviewParam(ctx *gin.Context, param, label string) (int, bool) {
+	id, err := strconv.Atoi(ctx.Param(param))
+	if err != nil || id <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": label + " harus berupa angka"})
+		return 0, false
+	}
+	return id, true
+}
+
+func reviewPagination(ctx *gin.Context) (int, int) {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "12"))
+	if err != nil || limit <= 0 {
+		limit = 12
+	}
+	limit = min(limit, 50)
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	return page, limit
+}
+
+func reviewError(ctx *gin.Context, err error) {
+	switch err.Error() {
+	case "movie not found":
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Movie tidak ditemukan"})
+	case "review not found":
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Review tidak ditemukan"})
+	case "no paid order":
+		ctx.JSON(http.StatusForbidden, gin.H{"success": false, "error": "Review hanya bisa diberikan setelah membeli tiket movie ini"})
+	default:
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+	}
+}
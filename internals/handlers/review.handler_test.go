@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+// TestUpsertReviewValidation input yang tidak valid ditolak sebelum menyentuh repository
+// (repository nil akan panic jika terpanggil)
+func TestUpsertReviewValidation(t *testing.T) {
+	handler := NewReviewHandler(nil)
+	user := &pkg.Claims{UserId: 1, Role: "user"}
+	movie := gin.Param{Key: "movie_id", Value: "1"}
+	tests := []struct {
+		name   string
+		claims *pkg.Claims
+		param  gin.Param
+		body   map[string]any
+		want   int
+	}{
+		{name: "no token", param: movie, body: map[string]any{"rating": 5}, want: http.StatusUnauthorized},
+		{name: "movie id not a number", claims: user, param: gin.Param{Key: "movie_id", Value: "abc"}, body: map[string]any{"rating": 5}, want: http.StatusBadRequest},
+		{name: "movie id zero", claims: user, param: gin.Param{Key: "movie_id", Value: "0"}, body: map[string]any{"rating": 5}, want: http.StatusBadRequest},
+		{name: "missing rating", claims: user, param: movie, body: map[string]any{"body": "Seru"}, want: http.StatusBadRequest},
+		{name: "rating above 5", claims: user, param: movie, body: map[string]any{"rating": 6}, want: http.StatusBadRequest},
+		{name: "body too long", claims: user, param: movie, body: map[string]any{"rating": 4, "body": strings.Repeat("a", 2001)}, want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		ctx, rec := newTestContext(t, http.MethodPost, tt.body, tt.claims, tt.param)
+		handler.UpsertReview(ctx)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status %d, want %d, body %s", tt.name, rec.Code, tt.want, rec.Body.String())
+		}
+	}
+}
+
+func TestReviewError(t *testing.T) {
+	tests := []struct {
+		err  string
+		want int
+	}{
+		{err: "movie not found", want: http.StatusNotFound},
+		{err: "review not found", want: http.StatusNotFound},
+		{err: "no paid order", want: http.StatusForbidden},
+		{err: "connection refused", want: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		ctx, rec := newTestContext(t, http.MethodPost, nil, nil)
+		reviewError(ctx, errors.New(tt.err))
+		if rec.Code != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.err, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestReviewPagination(t *testing.T) {
+	tests := []struct {
+		query     string
+		wantPage  int
+		wantLimit int
+	}{
+		{query: "", wantPage: 1, wantLimit: 12},
+		{query: "?page=3&limit=20", wantPage: 3, wantLimit: 20},
+		{query: "?page=0&limit=-1", wantPage: 1, wantLimit: 12},
+		{query: "?page=abc&limit=500", wantPage: 1, wantLimit: 50},
+	}
+	for _, tt := range tests {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+		page, limit := reviewPagination(ctx)
+		if page != tt.wantPage || limit != tt.wantLimit {
+			t.Errorf("%q: page %d limit %d, want page %d limit %d", tt.query, page, limit, tt.wantPage, tt.wantLimit)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/internals/utils"
+)
+
+type MovieSearchHandler struct {
+	ms repositories.MovieSearchStore
+}
+
+func NewMovieSearchHandler(ms repositories.MovieSearchStore) *MovieSearchHandler {
+	return &MovieSearchHandler{ms: ms}
+}
+
+// SearchMovies godoc
+// @Summary     Search Movies
+// @Description Pencarian movie berdasarkan judul, synopsis, director dan cast (full-text, toleran salah ketik). Hasil diurutkan berdasarkan relevansi, kata yang cocok ditandai <mark> dan disertai facet genre, tahun rilis dan rating
+// @Tags        Movies
+// @Produce     json
+// @Param       q           query    string   true   "Kata kunci pencarian"
+// @Param       genre       query    string   false  "Filter genre, pisahkan dengan koma jika lebih dari satu"
+// @Param       year_from   query    int      false  "Tahun rilis minimal"
+// @Param       year_to     query    int      false  "Tahun rilis maksimal"
+// @Param       min_rating  query    number   false  "Rating minimal"
+// @Param       page        query    int      false  "Halaman (default: 1)"
+// @Param       limit       query    int      false  "Jumlah per halaman (default: 12, maksimal 50)"
+// @Success     200 {object} map[string]interface{} "Hasil pencarian, total dan facets"
+// @Failure     400 {object} map[string]interface{} "Bad Request - Kata kunci atau filter tidak valid"
+// @Failure     500 {object} map[string]interface{} "Internal Server Error"
+// @Router      /movies/search [get]
+func (h *MovieSearchHandler) SearchMovies(ctx *gin.Context) {
+	params := models.MovieSearchParams{Query: strings.TrimSpace(ctx.Query("q"))}
+	if params.Query == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Kata kunci pencarian (q) harus diisi",
+		})
+		return
+	}
+
+	if genreQuery := ctx.Query("genre"); genreQuery != "" {
+		for _, genre := range strings.Split(genreQuery, ",") {
+			if genre = strings.TrimSpace(genre); genre != "" {
+				params.Genres = append(params.Genres, genre)
+			}
+		}
+	}
+
+	var err error
+	if yearFrom := ctx.Query("year_from"); yearFrom != "" {
+		if params.YearFrom, err = strconv.Atoi(yearFrom); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "year_from harus berupa angka"})
+			return
+		}
+	}
+	if yearTo := ctx.Query("year_to"); yearTo != "" {
+		if params.YearTo, err = strconv.Atoi(yearTo); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "year_to harus berupa angka"})
+			return
+		}
+	}
+	if minRating := ctx.Query("min_rating"); minRating != "" {
+		rating, err := strconv.ParseFloat(minRating, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "min_rating harus berupa angka"})
+			return
+		}
+		params.MinRating = &rating
+	}
+
+	// Pagination
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "12"))
+	if err != nil || limit <= 0 {
+		limit = 12
+	}
+	limit = min(limit, 50)
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	params.Limit = limit
+	params.Offset = (page - 1) * limit
+
+	movies, total, err := h.ms.Search(ctx.Request.Context(), params)
+	if err != nil {
+		if err.Error() == "empty query" {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Kata kunci pencarian harus berisi huruf atau angka",
+			})
+			return
+		}
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "internal server error",
+		})
+		return
+	}
+
+	facets, err := h.ms.Facets(ctx.Request.Context(), params)
+	if err != nil {
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "internal server error",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    movies,
+		"facets":  facets,
+		"page":    page,
+		"limit":   limit,
+		"count":   total,
+	})
+}
@@ -0,0 +1,380 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/internals/utils"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+// maxBulkShowtimeDays batas rentang tanggal untuk generate jadwal sekaligus
+const maxBulkShowtimeDays = 31
+
+type ShowtimeHandler struct {
+	sr      repositories.ShowtimeStore
+	rr      repositories.RefundStore
+	gateway pkg.PaymentGateway
+	mailer  pkg.Mailer
+}
+
+func NewShowtimeHandler(sr repositories.ShowtimeStore, rr repositories.RefundStore, gateway pkg.PaymentGateway, mailer pkg.Mailer) *ShowtimeHandler {
+	return &ShowtimeHandler{sr: sr, rr: rr, gateway: gateway, mailer: mailer}
+}
+
+// GetShowtimes godoc
+// @Summary      List jadwal tayang (Admin)
+// @Description  Ambil jadwal tayang, bisa difilter per movie, cinema, tanggal dan status
+// @Tags         Admin-Showtimes
+// @Security     BearerAuth
+// @Produce      json
+// @Param        movie_id   query     int     false  "Movie ID"
+// @Param        cinema_id  query     int     false  "Cinema ID"
+// @Param        date       query     string  false  "Tanggal (YYYY-MM-DD)"
+// @Param        status     query     string  false  "scheduled atau cancelled"
+// @Success      200  {array}   models.NowShowing
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /admin/showtimes [get]
+func (s *ShowtimeHandler) GetShowtimes(ctx *gin.Context) {
+	var filter models.ShowtimeFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "movie_id dan cinema_id harus berupa angka"})
+		return
+	}
+	if filter.Date != "" {
+		if _, err := time.Parse("2006-01-02", filter.Date); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Format date harus YYYY-MM-DD"})
+			return
+		}
+	}
+	if filter.Status != "" && filter.Status != "scheduled" && filter.Status != "cancelled" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "status harus scheduled atau cancelled"})
+		return
+	}
+
+	showtimes, err := s.sr.GetShowtimes(ctx.Request.Context(), filter)
+	if err != nil {
+		utils.Logger(ctx.Request.Context()).Error("handler error", "op", "GetShowtimes", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    showtimes,
+	})
+}
+
+// CreateShowtime godoc
+// @Summary      Tambah jadwal tayang (Admin)
+// @Description  Membuat satu jadwal tayang. Ditolak jika bentrok dengan jadwal lain di cinema yang sama (berdasarkan duration_minutes movie)
+// @Tags         Admin-Showtimes
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      models.ShowtimeRequest  true  "Jadwal tayang"
+// @Success      201   {object}  models.NowShowing
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      409   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /admin/showtimes [post]
+func (s *ShowtimeHandler) CreateShowtime(ctx *gin.Context) {
+	var body models.ShowtimeRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "movie_id, cinemas_id, location_id, date dan time wajib diisi"})
+		return
+	}
+	if msg := validateShowtimeSlot(body.Date, body.Time); msg != "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": msg})
+		return
+	}
+
+	slots := []models.ShowtimeSlot{{Date: body.Date, Time: body.Time}}
+	showtimes, conflicts, err := s.sr.CreateShowtimes(ctx.Request.Context(), body.MovieId, body.CinemasId, body.LocationId, slots)
+	if err != nil {
+		showtimeError(ctx, "CreateShowtime", err, conflicts)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Jadwal tayang berhasil ditambahkan",
+		"data":    showtimes[0],
+	})
+}
+
+// GenerateShowtimes godoc
+// @Summary      Generate jadwal tayang (Admin)
+// @Description  Membuat jadwal setiap hari dari start_date sampai end_date (maksimal 31 hari) pada jam-jam yang diberikan. Jika ada satu slot yang bentrok tidak ada jadwal yang dibuat
+// @Tags         Admin-Showtimes
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      models.ShowtimeBulkRequest  true  "Rentang tanggal dan jam tayang"
+// @Success      201   {array}   models.NowShowing
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      409   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /admin/showtimes/bulk [post]
+func (s *ShowtimeHandler) GenerateShowtimes(ctx *gin.Context) {
+	var body models.ShowtimeBulkRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "movie_id, cinemas_id, location_id, start_date, end_date dan times (1-12 jam) wajib diisi"})
+		return
+	}
+
+	slots, msg := bulkShowtimeSlots(body)
+	if msg != "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": msg})
+		return
+	}
+
+	showtimes, conflicts, err := s.sr.CreateShowtimes(ctx.Request.Context(), body.MovieId, body.CinemasId, body.LocationId, slots)
+	if err != nil {
+		showtimeError(ctx, "GenerateShowtimes", err, conflicts)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": fmt.Sprintf("%d jadwal tayang berhasil ditambahkan", len(showtimes)),
+		"data":    showtimes,
+	})
+}
+
+// RescheduleShowtime godoc
+// @Summary      Ubah jadwal tayang (Admin)
+// @Description  Memindahkan jadwal ke tanggal/jam lain, opsional ke cinema/location lain. Pindah cinema ditolak jika sudah ada order. User yang sudah memesan dikirimi email
+// @Tags         Admin-Showtimes
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                               true  "Showtime ID"
+// @Param        body  body      models.RescheduleShowtimeRequest  true  "Jadwal baru"
+// @Success      200   {object}  models.NowShowing
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      409   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /admin/showtimes/{id} [patch]
+func (s *ShowtimeHandler) RescheduleShowtime(ctx *gin.Context) {
+	showtimeID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Showtime ID harus berupa angka"})
+		return
+	}
+
+	var body models.RescheduleShowtimeRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "date dan time wajib diisi"})
+		return
+	}
+	if msg := validateShowtimeSlot(body.Date, body.Time); msg != "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": msg})
+		return
+	}
+
+	showtime, conflicts, bookings, err := s.sr.RescheduleShowtime(ctx.Request.Context(), showtimeID, body)
+	if err != nil {
+		showtimeError(ctx, "RescheduleShowtime", err, conflicts)
+		return
+	}
+
+	for _, booking := range bookings {
+		sendMailAsync(s.mailer, pkg.Mail{
+			To:      booking.Email,
+			Subject: "Perubahan jadwal tayang " + showtime.MovieTitle,
+			Body: fmt.Sprintf("Halo,\n\nJadwal tayang %s untuk order #%d dipindah ke %s pukul %s di %s, %s.\nTiket kamu tetap berlaku untuk jadwal baru ini. Jika tidak bisa hadir, kamu dapat membatalkan order sesuai ketentuan pembatalan.\n",
+				showtime.MovieTitle, booking.OrderId, showtime.Date, showtime.Time, showtime.CinemaName, showtime.LocationName),
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": fmt.Sprintf("Jadwal tayang berhasil diubah, %d user dikirimi notifikasi", len(bookings)),
+		"data":    showtime,
+	})
+}
+
+// CancelShowtime godoc
+// @Summary      Batalkan jadwal tayang (Admin)
+// @Description  Membatalkan jadwal tayang. Semua order aktif dibatalkan dan di-refund, lalu user dikirimi email. Order yang gagal dibatalkan bisa diproses ulang dengan memanggil endpoint ini lagi
+// @Tags         Admin-Showtimes
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                           true   "Showtime ID"
+// @Param        body  body      models.CancelShowtimeRequest  false  "Alasan pembatalan"
+// @Success      200   {object}  models.CancelShowtimeResponse
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      409   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /admin/showtimes/{id}/cancel [post]
+func (s *ShowtimeHandler) CancelShowtime(ctx *gin.Context) {
+	claims, exists := ctx.Get("claims")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+	admin, ok := claims.(pkg.Claims)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+
+	showtimeID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Showtime ID harus berupa angka"})
+		return
+	}
+
+	// body opsional, hanya berisi alasan pembatalan
+	var body models.CancelShowtimeRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Body tidak valid"})
+			return
+		}
+	}
+	reason := strings.TrimSpace(body.Reason)
+
+	showtime, bookings, err := s.sr.CancelShowtime(ctx.Request.Context(), showtimeID, reason)
+	if err != nil {
+		showtimeError(ctx, "CancelShowtime", err, nil)
+		return
+	}
+
+	orderReason := "Jadwal tayang dibatalkan"
+	if reason != "" {
+		orderReason += ": " + reason
+	}
+
+	response := models.CancelShowtimeResponse{
+		Showtime:        showtime,
+		CancelledOrders: []models.CancelOrderResponse{},
+		FailedOrders:    []int{},
+	}
+	for _, booking := range bookings {
+		result, err := s.rr.CancelOrder(ctx.Request.Context(), booking.OrderId, nil, admin.UserId, orderReason)
+		if err != nil {
+			if err.Error() == "order already cancelled" {
+				continue
+			}
+			utils.Logger(ctx.Request.Context()).Error("cancel showtime order failed", "order_id", booking.OrderId, "error", err)
+			response.FailedOrders = append(response.FailedOrders, booking.OrderId)
+			continue
+		}
+
+		refundMessage := settleRefund(ctx.Request.Context(), s.rr, s.gateway, &result)
+		response.CancelledOrders = append(response.CancelledOrders, result)
+
+		sendMailAsync(s.mailer, pkg.Mail{
+			To:      booking.Email,
+			Subject: "Pembatalan jadwal tayang " + showtime.MovieTitle,
+			Body: fmt.Sprintf("Halo,\n\nMohon maaf, jadwal tayang %s pada %s pukul %s di %s dibatalkan.\n%s.\n\n%s (order #%d).\n",
+				showtime.MovieTitle, showtime.Date, showtime.Time, showtime.CinemaName, orderReason, refundMessage, booking.OrderId),
+		})
+	}
+
+	message := fmt.Sprintf("Jadwal tayang dibatalkan, %d order dibatalkan", len(response.CancelledOrders))
+	if len(response.FailedOrders) > 0 {
+		message += fmt.Sprintf(", %d order gagal dibatalkan dan bisa diproses ulang", len(response.FailedOrders))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": message,
+		"data":    response,
+	})
+}
+
+func validateShowtimeSlot(date, clock string) string {
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return "Format date harus YYYY-MM-DD"
+	}
+	if _, err := time.Parse("15:04", clock); err != nil {
+		return "Format time harus HH:MM"
+	}
+	return ""
+}
+
+// bulkShowtimeSlots membuat slot setiap hari dari StartDate sampai EndDate untuk tiap jam di Times
+func bulkShowtimeSlots(req models.ShowtimeBulkRequest) ([]models.ShowtimeSlot, string) {
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, "Format start_date harus YYYY-MM-DD"
+	}
+	end, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, "Format end_date harus YYYY-MM-DD"
+	}
+	if end.Before(start) {
+		return nil, "end_date tidak boleh sebelum start_date"
+	}
+	if days := int(end.Sub(start).Hours()/24) + 1; days > maxBulkShowtimeDays {
+		return nil, fmt.Sprintf("Rentang tanggal maksimal %d hari", maxBulkShowtimeDays)
+	}
+
+	var times []string
+	for _, clock := range req.Times {
+		if _, err := time.Parse("15:04", clock); err != nil {
+			return nil, fmt.Sprintf("Format time %q harus HH:MM", clock)
+		}
+		if slices.Contains(times, clock) {
+			return nil, fmt.Sprintf("Jam %s diisi lebih dari sekali", clock)
+		}
+		times = append(times, clock)
+	}
+	slices.Sort(times)
+
+	var slots []models.ShowtimeSlot
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		for _, clock := range times {
+			slots = append(slots, models.ShowtimeSlot{Date: day.Format("2006-01-02"), Time: clock})
+		}
+	}
+	return slots, ""
+}
+
+func showtimeError(ctx *gin.Context, op string, err error, conflicts []models.ShowtimeConflict) {
+	switch err.Error() {
+	case "showtime not found":
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Jadwal tayang tidak ditemukan"})
+	case "movie not found":
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Movie tidak ditemukan"})
+	case "cinema not found":
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Cinema tidak ditemukan"})
+	case "location not found":
+		ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Location tidak ditemukan"})
+	case "movie duration not set":
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Durasi movie belum diisi, jadwal tidak bisa dicek bentrok"})
+	case "showtime in the past":
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Jam tayang sudah lewat"})
+	case "showtime overlaps":
+		ctx.JSON(http.StatusConflict, gin.H{
+			"success":   false,
+			"error":     "Jadwal bentrok dengan jadwal lain di cinema yang sama",
+			"conflicts": conflicts,
+		})
+	case "showtime cancelled":
+		ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": "Jadwal tayang sudah dibatalkan"})
+	case "showtime already cancelled":
+		ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": "Jadwal tayang sudah dibatalkan dan tidak ada order yang tersisa"})
+	case "showtime has bookings":
+		ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": "Jadwal sudah memiliki order, cinema tidak bisa dipindah"})
+	default:
+		utils.Logger(ctx.Request.Context()).Error("handler error", "op", op, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+	}
+}
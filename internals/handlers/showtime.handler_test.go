@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/raihaninkam/tickitz/internals/models"
+)
+
+func TestBulkShowtimeSlots(t *testing.T) {
+	slots, msg := bulkShowtimeSlots(models.ShowtimeBulkRequest{
+		StartDate: "2025-10-30",
+		EndDate:   "2025-11-01",
+		Times:     []string{"19:30", "13:00"},
+	})
+	if msg != "" {
+		t.Fatal(msg)
+	}
+	// urut per hari lalu per jam, melewati pergantian bulan
+	want := []models.ShowtimeSlot{
+		{Date: "2025-10-30", Time: "13:00"}, {Date: "2025-10-30", Time: "19:30"},
+		{Date: "2025-10-31", Time: "13:00"}, {Date: "2025-10-31", Time: "19:30"},
+		{Date: "2025-11-01", Time: "13:00"}, {Date: "2025-11-01", Time: "19:30"},
+	}
+	if len(slots) != len(want) {
+		t.Fatalf("got %d slots %v, want %d", len(slots), slots, len(want))
+	}
+	for i := range want {
+		if slots[i] != want[i] {
+			t.Errorf("slot %d = %v, want %v", i, slots[i], want[i])
+		}
+	}
+
+	invalid := []struct {
+		name    string
+		req     models.ShowtimeBulkRequest
+		wantMsg string
+	}{
+		{name: "bad start", req: models.ShowtimeBulkRequest{StartDate: "30-10-2025", EndDate: "2025-11-01", Times: []string{"13:00"}}, wantMsg: "start_date"},
+		{name: "end before start", req: models.ShowtimeBulkRequest{StartDate: "2025-11-02", EndDate: "2025-11-01", Times: []string{"13:00"}}, wantMsg: "sebelum"},
+		{name: "range too long", req: models.ShowtimeBulkRequest{StartDate: "2025-10-01", EndDate: "2025-11-01", Times: []string{"13:00"}}, wantMsg: "maksimal 31 hari"},
+		{name: "bad time", req: models.ShowtimeBulkRequest{StartDate: "2025-10-01", EndDate: "2025-10-01", Times: []string{"25:00"}}, wantMsg: "HH:MM"},
+		{name: "duplicate time", req: models.ShowtimeBulkRequest{StartDate: "2025-10-01", EndDate: "2025-10-01", Times: []string{"13:00", "13:00"}}, wantMsg: "lebih dari sekali"},
+	}
+	for _, tt := range invalid {
+		if _, msg := bulkShowtimeSlots(tt.req); !strings.Contains(msg, tt.wantMsg) {
+			t.Errorf("%s: message %q, want %q", tt.name, msg, tt.wantMsg)
+		}
+	}
+
+	// 31 hari masih diizinkan
+	if slots, msg := bulkShowtimeSlots(models.ShowtimeBulkRequest{StartDate: "2025-10-01", EndDate: "2025-10-31", Times: []string{"13:00"}}); msg != "" || len(slots) != 31 {
+		t.Errorf("31 days: %d slots, message %q", len(slots), msg)
+	}
+}
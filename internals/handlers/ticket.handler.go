@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/internals/utils"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+type TicketHandler struct {
+	tr     repositories.TicketStore
+	signer *pkg.TicketSigner
+}
+
+func NewTicketHandler(tr repositories.TicketStore, signer *pkg.TicketSigner) *TicketHandler {
+	return &TicketHandler{tr: tr, signer: signer}
+}
+
+// GetTicketImage godoc
+// @Summary      QR tiket
+// @Description  Gambar QR (PNG) berisi kode tiket bertanda tangan untuk order yang sudah dibayar
+// @Tags         Orders
+// @Produce      png
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Order ID"
+// @Success      200  {file}    binary
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}  "Order belum dibayar atau tiket dibatalkan"
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /orders/{id}/ticket.png [get]
+func (t *TicketHandler) GetTicketImage(ctx *gin.Context) {
+	claims, exists := ctx.Get("claims")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+	user, ok := claims.(pkg.Claims)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+
+	orderID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Order ID harus berupa angka"})
+		return
+	}
+
+	ticket, err := t.tr.GetOrderTicket(ctx.Request.Context(), orderID, user.UserId)
+	if err != nil {
+		if err.Error() == "order not found" {
+			ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Order tidak ditemukan"})
+			return
+		}
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	if ticket.Status == "void" || ticket.OrderStatus == "cancelled" {
+		ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": "Tiket sudah dibatalkan"})
+		return
+	}
+	if !ticket.IsPaid {
+		ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": "Order belum dibayar"})
+		return
+	}
+
+	image, err := pkg.TicketQRPNG(ticket.Code)
+	if err != nil {
+		utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		return
+	}
+
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.Data(http.StatusOK, "image/png", image)
+}
+
+// CheckIn godoc
+// @Summary      Check-in tiket (Usher)
+// @Description  Memverifikasi kode QR tiket dan menandainya sudah di-scan. Tiket hanya bisa dipakai sekali
+// @Tags         Check-in
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      models.CheckInRequest  true  "Kode tiket dari QR"
+// @Success      200   {object}  models.CheckInResponse
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      409   {object}  map[string]interface{}  "Tiket sudah dipakai, dibatalkan atau belum dibayar"
+// @Failure      410   {object}  map[string]interface{}  "Tiket kadaluarsa"
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /checkin [post]
+func (t *TicketHandler) CheckIn(ctx *gin.Context) {
+	claims, exists := ctx.Get("claims")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+	usher, ok := claims.(pkg.Claims)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Token tidak valid"})
+		return
+	}
+
+	var body models.CheckInRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "code wajib diisi"})
+		return
+	}
+	code := strings.TrimSpace(body.Code)
+
+	payload, err := t.signer.Verify(code, time.Now())
+	if err != nil {
+		if errors.Is(err, pkg.ErrTicketExpired) {
+			ctx.JSON(http.StatusGone, gin.H{"success": false, "error": "Tiket sudah kadaluarsa"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Tiket tidak valid"})
+		return
+	}
+
+	result, err := t.tr.CheckIn(ctx.Request.Context(), code, payload, usher.UserId)
+	if err != nil {
+		switch err.Error() {
+		case "ticket not found":
+			ctx.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Tiket tidak ditemukan"})
+		case "ticket void":
+			ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": "Tiket sudah dibatalkan"})
+		case "order not paid":
+			ctx.JSON(http.StatusConflict, gin.H{"success": false, "error": "Order belum dibayar"})
+		case "ticket already scanned":
+			ctx.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"error":   "Tiket sudah digunakan",
+				"data":    result,
+			})
+		default:
+			utils.Logger(ctx.Request.Context()).Error("internal server error", "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "internal server error"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Check-in berhasil",
+		"data":    result,
+	})
+}
+
+// GetPublicKey godoc
+// @Summary      Public key tiket
+// @Description  Public key ed25519 (base64) untuk memverifikasi kode tiket secara offline di aplikasi scanner
+// @Tags         Check-in
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Router       /checkin/public-key [get]
+func (t *TicketHandler) GetPublicKey(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"algorithm":  "ed25519",
+			"public_key": t.signer.PublicKey(),
+		},
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"slices"
 
 	"github.com/gin-gonic/gin"
+	"github.com/raihaninkam/tickitz/internals/utils"
 	"github.com/raihaninkam/tickitz/pkg"
 )
 
@@ -21,7 +22,7 @@ func Access(roles ...string) func(*gin.Context) {
 		}
 		user, ok := claims.(pkg.Claims)
 		if !ok {
-			// log.Println("Cannot cast claims into pkg.claims")
+			utils.Logger(ctx.Request.Context()).Error("claims in context are not pkg.Claims")
 			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
 				"success": false,
 				"error":   "Internal server error",
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/internals/utils"
 	"github.com/raihaninkam/tickitz/pkg"
 )
 
@@ -38,7 +39,7 @@ import (
 
 // 		// Validasi JWT token menggunakan Claims struct Anda
 // 		claims := &pkg.Claims{}
-// 		err := claims.VerifyToken(tokenString)
+// 		err := claims.VerifyToken(tokenString, cfg)
 // 		if err != nil {
 // 			ctx.JSON(http.StatusUnauthorized, gin.H{
 // 				"success": false,
@@ -58,7 +59,7 @@ import (
 // }
 
 // Middleware untuk JWT dengan blacklist check
-func JWTMiddlewareWithBlacklist(ar *repositories.AuthRepository) gin.HandlerFunc {
+func JWTMiddlewareWithBlacklist(cfg pkg.JWTConfig, store repositories.TokenRevocationStore) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// Ambil token dari header
 		authHeader := ctx.GetHeader("Authorization")
@@ -84,38 +85,61 @@ func JWTMiddlewareWithBlacklist(ar *repositories.AuthRepository) gin.HandlerFunc
 
 		tokenString := tokenParts[1]
 
-		// Cek apakah token sudah di-blacklist
-		isBlacklisted, err := ar.IsTokenBlacklisted(ctx.Request.Context(), tokenString)
+		// Validasi JWT token menggunakan Claims struct Anda
+		claims := &pkg.Claims{}
+		err := claims.VerifyToken(tokenString, cfg)
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{
+			ctx.JSON(http.StatusUnauthorized, gin.H{
 				"success": false,
-				"error":   "internal server error",
+				"error":   "Token tidak valid atau sudah expired",
 			})
 			ctx.Abort()
 			return
 		}
 
-		if isBlacklisted {
-			ctx.JSON(http.StatusUnauthorized, gin.H{
+		// Cek apakah token sudah di-blacklist (logout)
+		isBlacklisted, err := store.IsTokenRevoked(ctx.Request.Context(), claims.RevocationKey(tokenString))
+		if err != nil {
+			utils.Logger(ctx.Request.Context()).Error("token revocation check failed", "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{
 				"success": false,
-				"error":   "Silahkan login kembali",
+				"error":   "internal server error",
 			})
 			ctx.Abort()
 			return
 		}
 
-		// Validasi JWT token menggunakan Claims struct Anda
-		claims := &pkg.Claims{}
-		err = claims.VerifyToken(tokenString)
-		if err != nil {
+		if isBlacklisted {
 			ctx.JSON(http.StatusUnauthorized, gin.H{
 				"success": false,
-				"error":   "Token tidak valid atau sudah expired",
+				"error":   "Silahkan login kembali",
 			})
 			ctx.Abort()
 			return
 		}
 
+		// Cek apakah session (device) asal token sudah dicabut
+		if claims.SessionId != "" {
+			isRevoked, err := store.IsSessionRevoked(ctx.Request.Context(), claims.SessionId)
+			if err != nil {
+				utils.Logger(ctx.Request.Context()).Error("session revocation check failed", "error", err)
+				ctx.JSON(http.StatusInternalServerError, gin.H{
+					"success": false,
+					"error":   "internal server error",
+				})
+				ctx.Abort()
+				return
+			}
+			if isRevoked {
+				ctx.JSON(http.StatusUnauthorized, gin.H{
+					"success": false,
+					"error":   "Silahkan login kembali",
+				})
+				ctx.Abort()
+				return
+			}
+		}
+
 		// Set user info ke context
 		ctx.Set("user_id", claims.UserId)
 		ctx.Set("user_role", claims.Role)
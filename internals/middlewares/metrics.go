@@ -0,0 +1,23 @@
+package middlewares
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raihaninkam/tickitz/internals/utils"
+)
+
+// MetricsMiddleware mencatat jumlah dan durasi request per route (pola route gin, bukan URL asli
+// supaya jumlah label tidak meledak)
+func MetricsMiddleware(ctx *gin.Context) {
+	start := time.Now()
+	ctx.Next()
+
+	route := ctx.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+	utils.HTTPRequests.WithLabelValues(ctx.Request.Method, route, strconv.Itoa(ctx.Writer.Status())).Inc()
+	utils.HTTPDuration.WithLabelValues(ctx.Request.Method, route).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,153 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/utils"
+	"github.com/raihaninkam/tickitz/pkg"
+	"github.com/redis/go-redis/v9"
+)
+
+// RateLimitKeyFunc menentukan identitas yang dibatasi (IP, email, user).
+// String kosong berarti request tidak dihitung untuk aturan ini
+type RateLimitKeyFunc func(ctx *gin.Context) string
+
+// RateLimiter membatasi jumlah request memakai sliding window (sorted set) di Redis
+type RateLimiter struct {
+	rdb *redis.Client
+}
+
+func NewRateLimiter(rdb *redis.Client) *RateLimiter {
+	return &RateLimiter{rdb: rdb}
+}
+
+// slidingWindowScript membuang request di luar window, lalu menambah request baru jika masih di bawah limit.
+// Mengembalikan {diizinkan, jumlah request, sisa waktu tunggu (ms)}
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', key, 0, now - window)
+local count = redis.call('ZCARD', key)
+if count >= limit then
+	local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+	return {0, count, window - (now - tonumber(oldest[2]))}
+end
+redis.call('ZADD', key, now, ARGV[4])
+redis.call('PEXPIRE', key, window)
+return {1, count + 1, 0}
+`)
+
+// Limit membuat middleware rate limit dengan nama aturan (bagian dari key Redis) dan fungsi key.
+// Jika Redis tidak tersedia request tetap diteruskan (fail open)
+func (l *RateLimiter) Limit(name string, rule models.RateLimitRule, keyFn RateLimitKeyFunc) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if l.rdb == nil || rule.Limit <= 0 {
+			ctx.Next()
+			return
+		}
+
+		identity := keyFn(ctx)
+		if identity == "" {
+			ctx.Next()
+			return
+		}
+
+		key := fmt.Sprintf("rate_limit:%s:%s", name, identity)
+		now := time.Now().UnixMilli()
+		rctx := ctx.Request.Context()
+		var res []int64
+		err := utils.WithRedis(rctx, l.rdb, func() (err error) {
+			res, err = slidingWindowScript.Run(rctx, l.rdb, []string{key},
+				now, rule.Window.Milliseconds(), rule.Limit, fmt.Sprintf("%d-%s", now, uuid.NewString()),
+			).Int64Slice()
+			return err
+		})
+		if err != nil {
+			if err != utils.ErrRedisUnavailable {
+				utils.Logger(rctx).Warn("rate limiter failed, request allowed", "rule", name, "error", err)
+			}
+			ctx.Next()
+			return
+		}
+
+		ctx.Header("X-RateLimit-Limit", strconv.Itoa(rule.Limit))
+		ctx.Header("X-RateLimit-Remaining", strconv.Itoa(max(rule.Limit-int(res[1]), 0)))
+
+		if res[0] == 0 {
+			AbortTooManyRequests(ctx, time.Duration(res[2])*time.Millisecond, "Terlalu banyak request")
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// AbortTooManyRequests membalas 429 dengan header Retry-After (detik, dibulatkan ke atas)
+func AbortTooManyRequests(ctx *gin.Context, retryAfter time.Duration, message string) {
+	seconds := max(int(math.Ceil(retryAfter.Seconds())), 1)
+	ctx.Header("Retry-After", strconv.Itoa(seconds))
+	ctx.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+		"success":     false,
+		"error":       fmt.Sprintf("%s, coba lagi dalam %d detik", message, seconds),
+		"retry_after": seconds,
+	})
+}
+
+// KeyByIP membatasi per alamat IP client
+func KeyByIP(ctx *gin.Context) string {
+	return "ip:" + ctx.ClientIP()
+}
+
+// KeyByEmail membatasi per email di body JSON (login, lupa password), body dikembalikan utuh untuk handler
+func KeyByEmail(ctx *gin.Context) string {
+	if ctx.Request.Body == nil || !strings.HasPrefix(ctx.ContentType(), "application/json") {
+		if email := ctx.PostForm("email"); email != "" {
+			return "email:" + strings.ToLower(strings.TrimSpace(email))
+		}
+		return ""
+	}
+
+	original := ctx.Request.Body
+	body, err := io.ReadAll(io.LimitReader(original, 1<<20))
+	ctx.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), original), original}
+	if err != nil {
+		return ""
+	}
+
+	var payload struct {
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Email == "" {
+		return ""
+	}
+	return "email:" + strings.ToLower(strings.TrimSpace(payload.Email))
+}
+
+// KeyByUser membatasi per user dari JWT, request tanpa token valid dibatasi per IP
+func KeyByUser(cfg pkg.JWTConfig) RateLimitKeyFunc {
+	return func(ctx *gin.Context) string {
+		parts := strings.SplitN(ctx.GetHeader("Authorization"), " ", 2)
+		if len(parts) == 2 && strings.EqualFold(parts[0], "bearer") {
+			var claims pkg.Claims
+			if err := claims.VerifyToken(parts[1], cfg); err == nil {
+				return "user:" + strconv.Itoa(claims.UserId)
+			}
+		}
+		return KeyByIP(ctx)
+	}
+}
@@ -0,0 +1,103 @@
+package middlewares
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/redis/go-redis/v9"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newLimitedRouter router dengan satu endpoint POST /login yang dibatasi keyFn, handler mengembalikan body request
+func newLimitedRouter(t *testing.T, rule models.RateLimitRule, keyFn RateLimitKeyFunc) (*gin.Engine, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { rdb.Close() })
+
+	router := gin.New()
+	router.POST("/login", NewRateLimiter(rdb).Limit("login", rule, keyFn), func(ctx *gin.Context) {
+		body, _ := io.ReadAll(ctx.Request.Body)
+		ctx.String(http.StatusOK, string(body))
+	})
+	return router, mr
+}
+
+func postLogin(router *gin.Engine, ip, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = ip + ":12345"
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimitByIP(t *testing.T) {
+	router, _ := newLimitedRouter(t, models.RateLimitRule{Limit: 2, Window: time.Minute}, KeyByIP)
+
+	for i, wantRemaining := range []string{"1", "0"} {
+		rec := postLogin(router, "10.0.0.1", "{}")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status %d, want 200", i+1, rec.Code)
+		}
+		if got := rec.Header().Get("X-RateLimit-Remaining"); got != wantRemaining {
+			t.Errorf("request %d: X-RateLimit-Remaining %s, want %s", i+1, got, wantRemaining)
+		}
+	}
+
+	rec := postLogin(router, "10.0.0.1", "{}")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("over limit: status %d, want 429", rec.Code)
+	}
+	retryAfter, err := strconv.Atoi(rec.Header().Get("Retry-After"))
+	if err != nil || retryAfter < 1 || retryAfter > 60 {
+		t.Errorf("Retry-After %q, want 1..60 seconds", rec.Header().Get("Retry-After"))
+	}
+
+	// IP lain punya kuota sendiri
+	if rec := postLogin(router, "10.0.0.2", "{}"); rec.Code != http.StatusOK {
+		t.Errorf("other ip: status %d, want 200", rec.Code)
+	}
+}
+
+func TestRateLimitByEmailKeepsBody(t *testing.T) {
+	router, _ := newLimitedRouter(t, models.RateLimitRule{Limit: 1, Window: time.Minute}, KeyByEmail)
+	body := `{"email":"Budi@Example.com","password":"rahasia"}`
+
+	rec := postLogin(router, "10.0.0.1", body)
+	if rec.Code != http.StatusOK || rec.Body.String() != body {
+		t.Fatalf("status %d body %q, want 200 with the original body", rec.Code, rec.Body.String())
+	}
+	// email sama dari IP berbeda tetap dibatasi, huruf besar tidak membedakan
+	if rec := postLogin(router, "10.0.0.2", `{"email":"budi@example.com"}`); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("same email: status %d, want 429", rec.Code)
+	}
+	// tanpa email request tidak dihitung
+	for range 3 {
+		if rec := postLogin(router, "10.0.0.1", `{}`); rec.Code != http.StatusOK {
+			t.Errorf("request without email: status %d, want 200", rec.Code)
+		}
+	}
+}
+
+func TestRateLimitFailsOpen(t *testing.T) {
+	router, mr := newLimitedRouter(t, models.RateLimitRule{Limit: 1, Window: time.Minute}, KeyByIP)
+	mr.Close()
+
+	for range 3 {
+		if rec := postLogin(router, "10.0.0.1", "{}"); rec.Code != http.StatusOK {
+			t.Errorf("redis down: status %d, want 200", rec.Code)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package middlewares
+
+import (
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/raihaninkam/tickitz/internals/utils"
+)
+
+// RequestIDHeader header request/response yang berisi request ID
+const RequestIDHeader = "X-Request-ID"
+
+// request ID dari client hanya dipakai jika formatnya aman untuk log
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestLogger memberi setiap request sebuah request ID (dari header X-Request-ID atau UUID baru),
+// mengembalikannya di header response, menyimpan logger ber-request_id ke context request dan
+// mencatat satu baris access log setelah request selesai. Query string tidak dicatat karena bisa
+// berisi token (verifikasi email, reset password)
+func RequestLogger(logger *slog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		requestID := ctx.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		ctx.Header(RequestIDHeader, requestID)
+		ctx.Set("request_id", requestID)
+
+		reqLogger := logger.With(slog.String("request_id", requestID))
+		ctx.Request = ctx.Request.WithContext(utils.WithLogger(ctx.Request.Context(), reqLogger, requestID))
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		attrs := []slog.Attr{
+			slog.String("method", ctx.Request.Method),
+			slog.String("route", ctx.FullPath()),
+			slog.String("path", ctx.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Int64("latency_ms", time.Since(start).Milliseconds()),
+			slog.String("client_ip", ctx.ClientIP()),
+			slog.Int("bytes", max(ctx.Writer.Size(), 0)),
+		}
+		if len(ctx.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", ctx.Errors.String()))
+		}
+		reqLogger.LogAttrs(ctx.Request.Context(), level, "request", attrs...)
+	}
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// AccountTokenRules masa berlaku token sekali pakai dan URL frontend untuk link di email
+type AccountTokenRules struct {
+	VerifyTTL   time.Duration
+	ResetTTL    time.Duration
+	FrontendURL string
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type EmailRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
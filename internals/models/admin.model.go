@@ -3,27 +3,18 @@ package models
 import "time"
 
 type MovieAdmin struct {
-	Id              int        `json:"id"`
-	Title           string     `json:"title" binding:"required"`
-	Synopsis        string     `json:"synopsis"`
-	DurationMinutes int        `json:"duration_minutes"`
-	ReleaseDate     time.Time  `json:"release_date"`
-	PosterImage     *string    `json:"poster_image"`
-	DirectorsId     int        `json:"directors_id"`
-	Rating          *float64   `json:"rating"`
-	BgPath          *string    `json:"bg_path"`
-	GenresId        []int      `json:"genres_id"`
-	Genres          string     `json:"genres"` // Tambahkan ini untuk nama genre
-	CastsId         []int      `json:"casts_id"`
-	Showtimes       []Showtime `json:"showtimes"`
-}
-
-// Model untuk showtime (optional)
-type Showtime struct {
-	Date       string `json:"date"` // Ubah dari time.Time ke string
-	Time       string `json:"time"` // Ubah dari time.Time ke string
-	LocationId int    `json:"location_id"`
-	CinemasId  int    `json:"cinemas_id"`
+	Id              int       `json:"id"`
+	Title           string    `json:"title" binding:"required"`
+	Synopsis        string    `json:"synopsis"`
+	DurationMinutes int       `json:"duration_minutes"`
+	ReleaseDate     time.Time `json:"release_date"`
+	PosterImage     *string   `json:"poster_image"`
+	DirectorsId     int       `json:"directors_id"`
+	Rating          *float64  `json:"rating"`
+	BgPath          *string   `json:"bg_path"`
+	GenresId        []int     `json:"genres_id"`
+	Genres          string    `json:"genres"` // Tambahkan ini untuk nama genre
+	CastsId         []int     `json:"casts_id"`
 }
 
 // Model untuk response
@@ -50,7 +41,6 @@ type MovieUpdateComprehensiveRequest struct {
 	BgPath          *string    `json:"bg_path,omitempty"`
 	GenresId        []int      `json:"genres_id,omitempty"`
 	CastsId         []int      `json:"casts_id,omitempty"`
-	Showtimes       []Showtime `json:"showtimes,omitempty"`
 }
 
 type MovieCreateRequest struct {
@@ -7,6 +7,8 @@ type Users struct {
 	Email    string `db:"email" json:"email"`
 	Role     string `db:"role" json:"role,omitempty"`
 	Password string `db:"password" json:"password,omitempty"`
+	// IsVerified false sampai user memverifikasi email
+	IsVerified bool `db:"is_verified" json:"is_verified"`
 }
 
 type UserAuth struct {
@@ -30,3 +32,18 @@ type LogoutSuccessResponse struct {
 	Message string         `json:"message" example:"Logout berhasil. Token telah diblacklist."`
 	User    LogoutUserInfo `json:"user,omitempty"`
 }
+
+// RateLimitRule maksimal Limit request dalam Window (sliding window)
+type RateLimitRule struct {
+	Limit  int
+	Window time.Duration
+}
+
+// LockoutRules aturan lockout login: setelah MaxAttempts gagal dalam AttemptWindow akun dikunci
+// selama BaseLock, berlipat dua setiap lockout berikutnya sampai MaxLock
+type LockoutRules struct {
+	MaxAttempts   int
+	AttemptWindow time.Duration
+	BaseLock      time.Duration
+	MaxLock       time.Duration
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// CatalogItem genre, cast atau director beserta jumlah movie yang memakainya
+type CatalogItem struct {
+	Id         int    `json:"id"`
+	Name       string `json:"name" example:"Christopher Nolan"`
+	MovieCount int    `json:"movie_count"`
+}
+
+type CatalogRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+// CatalogMergeRequest semua SourceIds digabung ke id di path lalu dihapus
+type CatalogMergeRequest struct {
+	SourceIds []int `json:"source_ids" binding:"required,min=1,max=50"`
+}
+
+type CatalogMergeResponse struct {
+	Target      CatalogItem `json:"target"`
+	Merged      []int       `json:"merged"`
+	MoviesMoved int         `json:"movies_moved"`
+}
+
+// CatalogMovie movie milik sebuah genre, cast atau director
+type CatalogMovie struct {
+	Id           int        `json:"id"`
+	Title        string     `json:"title"`
+	ReleaseDate  *time.Time `json:"release_date"`
+	PosterImage  *string    `json:"poster_image"`
+	Rating       *float64   `json:"rating"`
+	DirectorName *string    `json:"director_name"`
+	Genres       *string    `json:"genres"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+type Cinema struct {
+	Id         int        `json:"id"`
+	CinemaName string     `json:"cinema_name" example:"XXI Plaza Indonesia"`
+	SeatCount  int        `json:"seat_count" example:"98"`
+	CreatedAt  *time.Time `json:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at"`
+}
+
+type CinemaRequest struct {
+	CinemaName string `json:"cinema_name" binding:"required,max=50"`
+}
+
+type Location struct {
+	Id   int    `json:"id"`
+	Name string `json:"name" example:"Jakarta"`
+}
+
+type LocationRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+// LayoutRow satu baris kursi, nomor kursi 1..SeatCount.
+// AislesAfter berisi nomor kursi yang di sebelah kanannya ada lorong
+type LayoutRow struct {
+	Row         string `json:"row" binding:"required,max=5" example:"F"`
+	SeatCount   int    `json:"seat_count" binding:"required,min=1,max=50" example:"14"`
+	AislesAfter []int  `json:"aisles_after" example:"7"`
+	LoveNest    []int  `json:"love_nest" example:"7,8,9,10"`
+	Wheelchair  []int  `json:"wheelchair"`
+	Disabled    []int  `json:"disabled"`
+}
+
+type SeatLayoutRequest struct {
+	Rows []LayoutRow `json:"rows" binding:"required,min=1,max=30,dive"`
+}
+
+type SeatLayout struct {
+	CinemaId   int         `json:"cinema_id"`
+	TotalSeats int         `json:"total_seats"`
+	Rows       []LayoutRow `json:"rows"`
+}
@@ -27,6 +27,8 @@ type PopularMovie struct {
 	PosterImage     string    `db:"poster_image"`
 	BgPath          *string   `db:"bg_path"`
 	AvgRating       *float64  `db:"avg_rating"`
+	ReviewCount     int       `db:"review_count"`
+	TicketsSold     int       `db:"tickets_sold"`
 }
 
 type AllMovie struct {
@@ -81,3 +83,57 @@ type MovieSchedule struct {
 	LocationName string    `db:"location_name" json:"location_name"`
 	CinemaName   string    `db:"cinema_name" json:"cinema_name"` // Tambahan field cinema
 }
+
+// MovieSearchParams parameter GET /movies/search
+type MovieSearchParams struct {
+	Query     string
+	Genres    []string
+	YearFrom  int
+	YearTo    int
+	MinRating *float64
+	Offset    int
+	Limit     int
+}
+
+// MovieSearchResult satu hasil pencarian. TitleHighlight dan Snippet berisi tag <mark> pada kata yang cocok
+type MovieSearchResult struct {
+	Id             int        `json:"id"`
+	Title          string     `json:"title"`
+	TitleHighlight string     `json:"title_highlight"`
+	Snippet        string     `json:"snippet"`
+	ReleaseDate    *time.Time `json:"release_date"`
+	PosterImage    *string    `json:"poster_image"`
+	Rating         *float64   `json:"rating"`
+	DirectorName   *string    `json:"director_name"`
+	Genres         *string    `json:"genres"`
+	Score          float64    `json:"score"`
+	// MatchedBy "fulltext" atau "fuzzy" (hanya cocok lewat kemiripan ejaan)
+	MatchedBy string `json:"matched_by" example:"fulltext"`
+}
+
+type GenreFacet struct {
+	Id    int    `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// YearFacet jumlah hasil per dekade rilis, contoh 2010-2019
+type YearFacet struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Count int `json:"count"`
+}
+
+// RatingFacet jumlah hasil per rentang rating, Min/Max nil untuk movie tanpa rating
+type RatingFacet struct {
+	Label string   `json:"label" example:"8-9"`
+	Min   *float64 `json:"min"`
+	Max   *float64 `json:"max"`
+	Count int      `json:"count"`
+}
+
+type MovieSearchFacets struct {
+	Genres  []GenreFacet  `json:"genres"`
+	Years   []YearFacet   `json:"years"`
+	Ratings []RatingFacet `json:"ratings"`
+}
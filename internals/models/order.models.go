@@ -8,26 +8,45 @@ type AvailSeat struct {
 	SeatID     string `json:"seat_id"`      // "A1", "B2", format yang diharapkan frontend
 	ShowingId  int    `json:"showing_id"`   // now_showing_id
 	IsSold     bool   `json:"is_sold"`      // status apakah kursi sudah terjual
-	IsLoveNest bool   `json:"is_love_nest"` // apakah kursi love nest (F7-F10)
+	IsLoveNest bool   `json:"is_love_nest"` // apakah kursi love nest
+	SeatClass  string `json:"seat_class"`   // regular, love_nest atau wheelchair
+	IsDisabled bool   `json:"is_disabled"`  // kursi ditutup, tidak bisa dipesan
+	AisleAfter bool   `json:"aisle_after"`  // ada lorong di sebelah kanan kursi
+	IsHeld     bool   `json:"is_held"`      // sedang ditahan user lain / diri sendiri sebelum checkout
+	HeldByMe   bool   `json:"held_by_me"`   // ditahan oleh user yang sedang login
+}
+
+type SeatHoldRequest struct {
+	NowShowingID int      `json:"now_showing_id" binding:"required"`
+	SeatsMap     []string `json:"seats_map" binding:"required,min=1"`
+}
+
+type SeatHoldResponse struct {
+	NowShowingID int       `json:"now_showing_id"`
+	SeatsMap     []string  `json:"seats_map"`
+	HeldUntil    time.Time `json:"held_until"`
 }
 
 type CreateOrderRequest struct {
 	UsersID      int      `json:"users_id" binding:"-"`
-	Price        float64  `json:"price" binding:"required,min=0"`
+	Price        float64  `json:"price" binding:"omitempty,min=0"` // opsional, dicocokkan dengan harga dari server
 	PaymentID    int      `json:"payment_id" binding:"required"`
 	NowShowingID int      `json:"now_showing_id" binding:"required"`
 	CinemaID     int      `json:"cinema_id" binding:"-"`
 	SeatsMap     []string `json:"seats_map" binding:"required,min=1"`
+	RedeemPoints int      `json:"redeem_points" binding:"omitempty,min=0"` // poin yang dipakai sebagai potongan harga
 }
 
 type CreateOrderResponse struct {
-	ID        int       `json:"id"`
-	UsersID   int       `json:"users_id"`
-	Price     float64   `json:"price"`
-	QRCode    string    `json:"qr_code"`
-	TicketID  int       `json:"ticket_id"`
-	SeatsMap  []string  `json:"seats_map"`
-	CreatedAt time.Time `json:"created_at"`
+	ID           int       `json:"id"`
+	UsersID      int       `json:"users_id"`
+	Price        float64   `json:"price"`
+	Discount     int       `json:"discount"`
+	PoinRedeemed int       `json:"poin_redeemed"`
+	QRCode       string    `json:"qr_code"`
+	TicketID     int       `json:"ticket_id"`
+	SeatsMap     []string  `json:"seats_map"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 type Order struct {
@@ -54,6 +73,7 @@ type OrderHistory struct {
 	Price        int       `json:"price"`
 	PaymentId    int       `json:"payment_id"`
 	IsPaid       bool      `json:"is_paid"`
+	Status       string    `json:"status"` // pending, paid, failed, cancelled
 	CreatedAt    time.Time `json:"created_at"`
 	NowShowingId int       `json:"now_showing_id"`
 	MovieTitle   string    `json:"movie_title"`
@@ -62,4 +82,5 @@ type OrderHistory struct {
 	CinemaName   string    `json:"cinema_name"`
 	Seats        []Seat    `json:"seats"`
 	QrCode       string    `json:"qr_code"`
+	RefundStatus *string   `json:"refund_status,omitempty"` // pending, refunded, failed; hanya untuk order yang dibatalkan
 }
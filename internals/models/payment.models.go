@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// data order yang dibutuhkan untuk membuat charge pembayaran
+type OrderPayment struct {
+	OrderId int    `json:"order_id"`
+	UsersId int    `json:"users_id"`
+	Price   int    `json:"price"`
+	IsPaid  bool   `json:"is_paid"`
+	Status  string `json:"status"`
+	Method  string `json:"method"`
+}
+
+type PaymentTransaction struct {
+	Id        int        `json:"id"`
+	OrdersId  int        `json:"orders_id"`
+	Provider  string     `json:"provider"`
+	Reference string     `json:"reference"`
+	Amount    int        `json:"amount"`
+	Status    string     `json:"status"`
+	PaidAt    *time.Time `json:"paid_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type PaymentWebhookRequest struct {
+	Reference string `json:"reference" binding:"required"`
+	Status    string `json:"status" binding:"required" example:"paid"`
+	// jumlah yang dibayar, wajib untuk status paid dan harus sama dengan total order
+	Amount int `json:"amount" example:"50000"`
+}
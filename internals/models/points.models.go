@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// aturan poin loyalty
+type LoyaltyRules struct {
+	EarnPerAmount int // 1 poin untuk setiap kelipatan harga ini
+	RedeemValue   int // nilai rupiah 1 poin saat dipakai sebagai potongan
+	ExpiryDays    int // masa berlaku poin earn, 0 = tidak kadaluarsa
+}
+
+type PointsEntry struct {
+	Id          int        `json:"id"`
+	OrdersId    *int       `json:"orders_id"`
+	EntryType   string     `json:"entry_type" example:"earn"`
+	Points      int        `json:"points" example:"5"`
+	Description *string    `json:"description"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type PointsHistory struct {
+	Balance     int           `json:"balance" example:"120"`
+	RedeemValue int           `json:"redeem_value" example:"1000"`
+	Entries     []PointsEntry `json:"entries"`
+}
+
+type PointsAdjustRequest struct {
+	Points      int    `json:"points" binding:"required" example:"-10"`
+	Description string `json:"description" binding:"required" example:"Kompensasi gangguan layanan"`
+}
+
+type PointsAdjustResponse struct {
+	Entry   PointsEntry `json:"entry"`
+	Balance int         `json:"balance"`
+}
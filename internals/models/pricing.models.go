@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// harga dasar tiket per cinema, CinemasId nil = harga default
+type TicketPrice struct {
+	Id        int       `json:"id"`
+	CinemasId *int      `json:"cinemas_id"`
+	Price     int       `json:"price"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type TicketPriceRequest struct {
+	CinemasId *int `json:"cinemas_id"`
+	Price     *int `json:"price" binding:"required,min=0"`
+}
+
+// aturan penyesuaian harga, field nil berarti berlaku untuk semua
+type PriceRule struct {
+	Id        int       `json:"id"`
+	Name      string    `json:"name"`
+	CinemasId *int      `json:"cinemas_id"`
+	SeatClass *string   `json:"seat_class"`
+	DayType   *string   `json:"day_type"`
+	StartTime *string   `json:"start_time"`
+	EndTime   *string   `json:"end_time"`
+	Amount    int       `json:"amount"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type PriceRuleRequest struct {
+	Name      string  `json:"name" binding:"required"`
+	CinemasId *int    `json:"cinemas_id"`
+	SeatClass *string `json:"seat_class"`
+	DayType   *string `json:"day_type"`
+	StartTime *string `json:"start_time" example:"12:00"`
+	EndTime   *string `json:"end_time" example:"18:00"`
+	Amount    *int    `json:"amount" binding:"required"`
+	IsActive  *bool   `json:"is_active"`
+}
+
+type PriceQuoteRequest struct {
+	NowShowingID int      `json:"now_showing_id" binding:"required"`
+	SeatsMap     []string `json:"seats_map" binding:"required,min=1"`
+}
+
+type PriceAdjustment struct {
+	RuleId int    `json:"rule_id"`
+	Name   string `json:"name"`
+	Amount int    `json:"amount"`
+}
+
+type SeatPrice struct {
+	SeatID      string            `json:"seat_id"`
+	SeatClass   string            `json:"seat_class"`
+	BasePrice   int               `json:"base_price"`
+	Adjustments []PriceAdjustment `json:"adjustments"`
+	Price       int               `json:"price"`
+}
+
+type PriceQuote struct {
+	NowShowingID int         `json:"now_showing_id"`
+	CinemaID     int         `json:"cinema_id"`
+	DayType      string      `json:"day_type"`
+	Seats        []SeatPrice `json:"seats"`
+	Total        int         `json:"total"`
+}
@@ -52,7 +52,6 @@ type UserProfileResponse struct {
 }
 
 type ChangePasswordRequest struct {
-	Email       string `json:"email" binding:"required,email"`
 	OldPassword string `json:"old_password" binding:"required,min=6"`
 	NewPassword string `json:"new_password" binding:"required,min=6"`
 	// Hapus ConfirmPassword jika tidak digunakan di backend
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+type CancelOrderRequest struct {
+	Reason string `json:"reason" example:"Tidak jadi menonton"`
+}
+
+type Refund struct {
+	Id                    int       `json:"id"`
+	OrdersId              int       `json:"orders_id"`
+	PaymentTransactionsId *int      `json:"payment_transactions_id"`
+	Reference             string    `json:"-"`
+	Amount                int       `json:"amount"`
+	Reason                *string   `json:"reason"`
+	Status                string    `json:"status"`
+	Attempts              int       `json:"attempts"`
+	CancelledBy           *int      `json:"cancelled_by"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+type CancelOrderResponse struct {
+	OrderId      int       `json:"order_id"`
+	Status       string    `json:"status"`
+	CancelledAt  time.Time `json:"cancelled_at"`
+	ReleasedSeat int       `json:"released_seats"`
+	PoinReversed int       `json:"poin_reversed"`
+	PoinRefunded int       `json:"poin_refunded"`
+	Refund       *Refund   `json:"refund"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+type Review struct {
+	Id             int        `json:"id"`
+	MovieId        int        `json:"movie_id"`
+	MovieTitle     string     `json:"movie_title,omitempty"`
+	UserId         int        `json:"user_id"`
+	UserName       string     `json:"user_name" example:"Budi S."`
+	Rating         int        `json:"rating" example:"5"`
+	Body           *string    `json:"body"`
+	Status         string     `json:"status" example:"published"`
+	ModerationNote *string    `json:"moderation_note,omitempty"`
+	ModeratedAt    *time.Time `json:"moderated_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type ReviewRequest struct {
+	Rating int    `json:"rating" binding:"required,min=1,max=5" example:"5"`
+	Body   string `json:"body" binding:"max=2000" example:"Visualnya keren banget"`
+}
+
+type ReviewFilter struct {
+	MovieId int    `form:"movie_id"`
+	Status  string `form:"status"`
+}
+
+type ModerateReviewRequest struct {
+	Status string `json:"status" binding:"required,oneof=published hidden" example:"hidden"`
+	Note   string `json:"note" binding:"max=500" example:"Mengandung spoiler"`
+}
+
+// ReviewSummary rata-rata dan sebaran rating dari review yang published
+type ReviewSummary struct {
+	MovieId      int         `json:"movie_id"`
+	AvgRating    *float64    `json:"avg_rating"`
+	ReviewCount  int         `json:"review_count"`
+	Distribution map[int]int `json:"distribution"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// hasil login/rotasi refresh token
+type SessionToken struct {
+	UserId       int       `json:"-"`
+	Role         string    `json:"-"`
+	SessionId    string    `json:"session_id"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"refresh_expires_at"`
+}
+
+type UserSession struct {
+	Id         string    `json:"id"`
+	UserAgent  *string   `json:"user_agent"`
+	IpAddress  *string   `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
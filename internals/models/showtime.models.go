@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// NowShowing satu jadwal tayang (baris now_showing) untuk admin
+type NowShowing struct {
+	Id              int        `json:"id"`
+	MovieId         int        `json:"movie_id"`
+	MovieTitle      string     `json:"movie_title"`
+	DurationMinutes int        `json:"duration_minutes"`
+	CinemasId       int        `json:"cinemas_id"`
+	CinemaName      string     `json:"cinema_name"`
+	LocationId      int        `json:"location_id"`
+	LocationName    string     `json:"location_name"`
+	Date            string     `json:"date" example:"2025-10-01"`
+	Time            string     `json:"time" example:"19:30"`
+	StartsAt        time.Time  `json:"starts_at"`
+	EndsAt          time.Time  `json:"ends_at"`
+	Status          string     `json:"status" example:"scheduled"`
+	BookedSeats     int        `json:"booked_seats"`
+	CancelledAt     *time.Time `json:"cancelled_at"`
+	CancelReason    *string    `json:"cancel_reason"`
+}
+
+type ShowtimeFilter struct {
+	MovieId  int    `form:"movie_id"`
+	CinemaId int    `form:"cinema_id"`
+	Date     string `form:"date"`
+	Status   string `form:"status"`
+}
+
+type ShowtimeRequest struct {
+	MovieId    int    `json:"movie_id" binding:"required"`
+	CinemasId  int    `json:"cinemas_id" binding:"required"`
+	LocationId int    `json:"location_id" binding:"required"`
+	Date       string `json:"date" binding:"required" example:"2025-10-01"`
+	Time       string `json:"time" binding:"required" example:"19:30"`
+}
+
+// ShowtimeBulkRequest membuat jadwal setiap hari dari StartDate sampai EndDate pada jam-jam di Times
+type ShowtimeBulkRequest struct {
+	MovieId    int      `json:"movie_id" binding:"required"`
+	CinemasId  int      `json:"cinemas_id" binding:"required"`
+	LocationId int      `json:"location_id" binding:"required"`
+	StartDate  string   `json:"start_date" binding:"required" example:"2025-10-01"`
+	EndDate    string   `json:"end_date" binding:"required" example:"2025-10-14"`
+	Times      []string `json:"times" binding:"required,min=1,max=12" example:"13:00,16:00,19:30"`
+}
+
+// RescheduleShowtimeRequest CinemasId dan LocationId opsional, kosong berarti tidak berubah
+type RescheduleShowtimeRequest struct {
+	Date       string `json:"date" binding:"required" example:"2025-10-02"`
+	Time       string `json:"time" binding:"required" example:"20:00"`
+	CinemasId  *int   `json:"cinemas_id"`
+	LocationId *int   `json:"location_id"`
+}
+
+type CancelShowtimeRequest struct {
+	Reason string `json:"reason" example:"Studio sedang perbaikan"`
+}
+
+// ShowtimeSlot tanggal dan jam tayang yang akan dibuat
+type ShowtimeSlot struct {
+	Date string `json:"date"`
+	Time string `json:"time"`
+}
+
+// ShowtimeConflict jadwal baru yang bentrok dengan jadwal lain di cinema yang sama
+type ShowtimeConflict struct {
+	Date          string    `json:"date"`
+	Time          string    `json:"time"`
+	ConflictsWith int       `json:"conflicts_with"`
+	MovieTitle    string    `json:"movie_title"`
+	StartsAt      time.Time `json:"starts_at"`
+	EndsAt        time.Time `json:"ends_at"`
+}
+
+// ShowtimeBooking order aktif pada sebuah jadwal, dipakai untuk notifikasi dan pembatalan
+type ShowtimeBooking struct {
+	OrderId int    `json:"order_id"`
+	UsersId int    `json:"users_id"`
+	Email   string `json:"-"`
+}
+
+type CancelShowtimeResponse struct {
+	Showtime        NowShowing            `json:"showtime"`
+	CancelledOrders []CancelOrderResponse `json:"cancelled_orders"`
+	FailedOrders    []int                 `json:"failed_orders"`
+}
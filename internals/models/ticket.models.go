@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+type OrderTicket struct {
+	OrderId     int        `json:"order_id"`
+	TicketId    int        `json:"ticket_id"`
+	Code        string     `json:"code"`
+	Status      string     `json:"status"`
+	OrderStatus string     `json:"order_status"`
+	IsPaid      bool       `json:"is_paid"`
+	ScannedAt   *time.Time `json:"scanned_at"`
+}
+
+type CheckInRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type CheckInResponse struct {
+	TicketId     int       `json:"ticket_id"`
+	OrderId      int       `json:"order_id"`
+	NowShowingId int       `json:"now_showing_id"`
+	MovieTitle   string    `json:"movie_title"`
+	CinemaName   string    `json:"cinema_name"`
+	ShowDate     time.Time `json:"show_date"`
+	ShowTime     string    `json:"show_time"`
+	Seats        []string  `json:"seats"`
+	ScannedAt    time.Time `json:"scanned_at"`
+}
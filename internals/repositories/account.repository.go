@@ -0,0 +1,202 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+const (
+	tokenPurposeVerifyEmail   = "verify_email"
+	tokenPurposeResetPassword = "reset_password"
+)
+
+// AccountStore token verifikasi email dan reset password, diimplementasikan AccountRepository
+type AccountStore interface {
+	CreateVerificationToken(rctx context.Context, userID int) (string, error)
+	GetUnverifiedUser(rctx context.Context, email string) (models.Users, error)
+	VerifyEmail(rctx context.Context, token string) error
+	CreatePasswordResetToken(rctx context.Context, email string) (models.Users, string, error)
+	ResetPassword(rctx context.Context, token, hashedPassword string) error
+}
+
+var _ AccountStore = (*AccountRepository)(nil)
+
+// AccountRepository mengelola verifikasi email dan reset password memakai token sekali pakai
+type AccountRepository struct {
+	db          *pgxpool.Pool
+	rules       models.AccountTokenRules
+	revocations TokenRevocationStore
+}
+
+func NewAccountRepository(db *pgxpool.Pool, rules models.AccountTokenRules, revocations TokenRevocationStore) *AccountRepository {
+	return &AccountRepository{db: db, rules: rules, revocations: revocations}
+}
+
+// CreateVerificationToken membuat token verifikasi email baru, token lama yang belum dipakai tidak berlaku lagi
+func (a *AccountRepository) CreateVerificationToken(rctx context.Context, userID int) (string, error) {
+	return a.createUserToken(rctx, userID, tokenPurposeVerifyEmail, a.rules.VerifyTTL)
+}
+
+// GetUnverifiedUser mengambil user yang belum verifikasi email (untuk kirim ulang email verifikasi)
+func (a *AccountRepository) GetUnverifiedUser(rctx context.Context, email string) (models.Users, error) {
+	var user models.Users
+	if err := a.db.QueryRow(rctx, "SELECT id, email, is_verified FROM users WHERE email = $1", email).Scan(&user.Id, &user.Email, &user.IsVerified); err != nil {
+		if err == pgx.ErrNoRows {
+			return models.Users{}, errors.New("user not found")
+		}
+		return models.Users{}, err
+	}
+	if user.IsVerified {
+		return models.Users{}, errors.New("user already verified")
+	}
+	return user, nil
+}
+
+// VerifyEmail memakai token verifikasi dan menandai user sudah terverifikasi
+func (a *AccountRepository) VerifyEmail(rctx context.Context, token string) error {
+	tx, err := a.db.Begin(rctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(rctx)
+
+	userID, err := consumeUserToken(rctx, tx, token, tokenPurposeVerifyEmail)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(rctx, "UPDATE users SET is_verified = true, verified_at = NOW() WHERE id = $1 AND is_verified = false", userID); err != nil {
+		return err
+	}
+	return tx.Commit(rctx)
+}
+
+// CreatePasswordResetToken membuat token reset password untuk user dengan email tersebut
+func (a *AccountRepository) CreatePasswordResetToken(rctx context.Context, email string) (models.Users, string, error) {
+	var user models.Users
+	if err := a.db.QueryRow(rctx, "SELECT id, email FROM users WHERE email = $1", email).Scan(&user.Id, &user.Email); err != nil {
+		if err == pgx.ErrNoRows {
+			return models.Users{}, "", errors.New("user not found")
+		}
+		return models.Users{}, "", err
+	}
+
+	token, err := a.createUserToken(rctx, user.Id, tokenPurposeResetPassword, a.rules.ResetTTL)
+	if err != nil {
+		return models.Users{}, "", err
+	}
+	return user, token, nil
+}
+
+// ResetPassword memakai token reset, menyimpan password baru (sudah di-hash) dan mencabut semua session user.
+// Reset lewat email juga membuktikan kepemilikan email, sehingga user ikut terverifikasi
+func (a *AccountRepository) ResetPassword(rctx context.Context, token, hashedPassword string) error {
+	tx, err := a.db.Begin(rctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(rctx)
+
+	userID, err := consumeUserToken(rctx, tx, token, tokenPurposeResetPassword)
+	if err != nil {
+		return err
+	}
+
+	updateSQL := `UPDATE users
+				  SET password = $2, is_verified = true, verified_at = COALESCE(verified_at, NOW())
+				  WHERE id = $1`
+	if _, err := tx.Exec(rctx, updateSQL, userID, hashedPassword); err != nil {
+		return err
+	}
+
+	// token reset lain milik user tidak berlaku lagi
+	if _, err := tx.Exec(rctx, "UPDATE user_tokens SET used_at = NOW() WHERE users_id = $1 AND purpose = $2 AND used_at IS NULL", userID, tokenPurposeResetPassword); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(rctx, `UPDATE user_sessions SET revoked_at = NOW(), revoke_reason = 'password_reset'
+								 WHERE users_id = $1 AND revoked_at IS NULL
+								 RETURNING id::text`, userID)
+	if err != nil {
+		return err
+	}
+	sessionIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(rctx); err != nil {
+		return err
+	}
+
+	// access token yang masih aktif di semua device langsung ditolak
+	for _, sessionID := range sessionIDs {
+		if err := a.revocations.RevokeSession(rctx, sessionID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *AccountRepository) createUserToken(rctx context.Context, userID int, purpose string, ttl time.Duration) (string, error) {
+	tx, err := a.db.Begin(rctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(rctx)
+
+	if _, err := tx.Exec(rctx, "UPDATE user_tokens SET used_at = NOW() WHERE users_id = $1 AND purpose = $2 AND used_at IS NULL", userID, purpose); err != nil {
+		return "", err
+	}
+
+	token, hash, err := pkg.GenerateRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	sql := `INSERT INTO user_tokens (users_id, purpose, token_hash, expires_at, created_at)
+			VALUES ($1, $2, $3, $4, NOW())`
+	if _, err := tx.Exec(rctx, sql, userID, purpose, hash, time.Now().Add(ttl)); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(rctx); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// consumeUserToken menandai token sudah dipakai dan mengembalikan pemiliknya
+func consumeUserToken(rctx context.Context, q querier, token, purpose string) (int, error) {
+	var tokenID, userID int
+	var expiresAt time.Time
+	var usedAt *time.Time
+	sql := `SELECT id, users_id, expires_at, used_at
+			FROM user_tokens
+			WHERE token_hash = $1 AND purpose = $2
+			FOR UPDATE`
+	if err := q.QueryRow(rctx, sql, pkg.HashRefreshToken(token), purpose).Scan(&tokenID, &userID, &expiresAt, &usedAt); err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, errors.New("invalid token")
+		}
+		return 0, err
+	}
+
+	if usedAt != nil {
+		return 0, errors.New("token already used")
+	}
+	if time.Now().After(expiresAt) {
+		return 0, errors.New("token expired")
+	}
+
+	if _, err := q.Exec(rctx, "UPDATE user_tokens SET used_at = NOW() WHERE id = $1", tokenID); err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
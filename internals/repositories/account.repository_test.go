@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/testutil"
+)
+
+func TestPasswordResetToken(t *testing.T) {
+	env := testutil.Setup(t)
+	env.Seed(t, "demo")
+	ctx := t.Context()
+
+	revocations := NewTokenRevocationStore(env.DB, env.RDB, "redis", 30*time.Minute)
+	accounts := NewAccountRepository(env.DB, models.AccountTokenRules{VerifyTTL: time.Hour, ResetTTL: time.Hour}, revocations)
+	sessions := NewSessionRepository(env.DB, time.Hour, revocations)
+
+	session, err := sessions.CreateSession(ctx, 2, "user", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := accounts.CreatePasswordResetToken(ctx, "nobody@example.com"); err == nil || err.Error() != "user not found" {
+		t.Errorf("unknown email: err %v, want user not found", err)
+	}
+
+	user, first, err := accounts.CreatePasswordResetToken(ctx, "user@tickitz.id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, second, err := accounts.CreatePasswordResetToken(ctx, "user@tickitz.id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// token yang lebih lama tidak berlaku setelah token baru dibuat
+	if err := accounts.ResetPassword(ctx, first, "hash-lama"); err == nil || err.Error() != "token already used" {
+		t.Errorf("superseded token: err %v, want token already used", err)
+	}
+	// token verifikasi tidak bisa dipakai untuk reset password
+	verify, err := accounts.CreateVerificationToken(ctx, user.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := accounts.ResetPassword(ctx, verify, "hash-lain"); err == nil || err.Error() != "invalid token" {
+		t.Errorf("verification token used for reset: err %v, want invalid token", err)
+	}
+
+	if err := accounts.ResetPassword(ctx, second, "hash-baru"); err != nil {
+		t.Fatal(err)
+	}
+	if err := accounts.ResetPassword(ctx, second, "hash-lagi"); err == nil || err.Error() != "token already used" {
+		t.Errorf("reused token: err %v, want token already used", err)
+	}
+
+	var password string
+	if err := env.DB.QueryRow(ctx, "SELECT password FROM users WHERE id = $1", user.Id).Scan(&password); err != nil {
+		t.Fatal(err)
+	}
+	if password != "hash-baru" {
+		t.Errorf("password %q, want hash-baru", password)
+	}
+	if revoked, err := revocations.IsSessionRevoked(ctx, session.SessionId); err != nil || !revoked {
+		t.Errorf("session not revoked after password reset (revoked %v, err %v)", revoked, err)
+	}
+
+	// token expired
+	expired, err := accounts.CreateVerificationToken(ctx, user.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.DB.Exec(ctx, "UPDATE user_tokens SET expires_at = NOW() - INTERVAL '1 minute' WHERE users_id = $1 AND used_at IS NULL", user.Id); err != nil {
+		t.Fatal(err)
+	}
+	if err := accounts.VerifyEmail(ctx, expired); err == nil || err.Error() != "token expired" {
+		t.Errorf("expired token: err %v, want token expired", err)
+	}
+}
@@ -9,10 +9,19 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/raihaninkam/tickitz/internals/models"
-	"github.com/raihaninkam/tickitz/internals/utils"
 	"github.com/redis/go-redis/v9"
 )
 
+// MovieAdminStore CRUD movie oleh admin, diimplementasikan MovieAdmin
+type MovieAdminStore interface {
+	AddMovie(ctx context.Context, req models.MovieAdmin) (*models.MovieEdit, error)
+	GetAllMovies(ctx context.Context) ([]models.MovieAdmin, error)
+	UpdateMovieComprehensive(ctx context.Context, movieId int, req models.MovieUpdateComprehensiveRequest) (*models.MovieEdit, error)
+	DeleteMovie(ctx context.Context, movieId int) error
+}
+
+var _ MovieAdminStore = (*MovieAdmin)(nil)
+
 type MovieAdmin struct {
 	Db  *pgxpool.Pool
 	Rdb *redis.Client
@@ -86,32 +95,12 @@ func (ma *MovieAdmin) AddMovie(ctx context.Context, req models.MovieAdmin) (*mod
 		}
 	}
 
-	// Insert showtimes ke tabel now_showing
-	if len(req.Showtimes) > 0 {
-		sqlShowtime := `
-			INSERT INTO now_showing (date, time, location_id, movie_id, cinemas_id) 
-			VALUES ($1, $2, $3, $4, $5)
-		`
-		for _, showtime := range req.Showtimes {
-			_, err = tx.Exec(ctx, sqlShowtime,
-				showtime.Date,
-				showtime.Time,
-				showtime.LocationId,
-				movie.Id, // movie_id dari hasil insert movie
-				showtime.CinemasId,
-			)
-			if err != nil {
-				return nil, fmt.Errorf("failed to insert showtime: %w", err)
-			}
-		}
-	}
-
 	// Commit transaction
 	if err = tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	_ = utils.InvalidateCache(ctx, ma.Rdb, "all_movies", "upcoming_movies", "popular_movies")
+	invalidateMovieCache(ctx, ma.Rdb, movie.Id)
 
 	return &movie, nil
 }
@@ -279,7 +268,7 @@ func (ma *MovieAdmin) UpdateMovieComprehensive(ctx context.Context, movieId int,
 	if len(setClauses) > 0 {
 		query := fmt.Sprintf(`
 			UPDATE movies 
-			SET %s, update_at = NOW() 
+			SET %s, updated_at = NOW() 
 			WHERE id = $%d AND is_deleted = false
 			RETURNING id, title, synopsis, duration_minutes, release_date, poster_image, directors_id, rating, bg_path
 		`, strings.Join(setClauses, ", "), argID)
@@ -360,47 +349,12 @@ func (ma *MovieAdmin) UpdateMovieComprehensive(ctx context.Context, movieId int,
 		}
 	}
 
-	// Update showtimes jika provided (tidak nil)
-	if req.Showtimes != nil {
-		// Delete existing showtimes
-		_, err = tx.Exec(ctx, "DELETE FROM now_showing WHERE movie_id = $1", movieId)
-		if err != nil {
-			return nil, fmt.Errorf("failed to delete existing showtimes: %w", err)
-		}
-
-		// Insert new showtimes jika array tidak kosong
-		if len(req.Showtimes) > 0 {
-			sqlShowtime := `
-				INSERT INTO now_showing (date, time, location_id, movie_id, cinemas_id) 
-				VALUES ($1, $2, $3, $4, $5)
-			`
-			for _, showtime := range req.Showtimes {
-				_, err = tx.Exec(ctx, sqlShowtime,
-					showtime.Date,
-					showtime.Time,
-					showtime.LocationId,
-					movieId,
-					showtime.CinemasId,
-				)
-				if err != nil {
-					return nil, fmt.Errorf("failed to insert showtime: %w", err)
-				}
-			}
-		}
-	}
-
 	// Commit transaction
 	if err = tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	// Invalidate cache
-	_ = utils.InvalidateCache(ctx, ma.Rdb,
-		"all_movies",
-		"upcoming_movies",
-		"popular_movies",
-		fmt.Sprintf("movie_detail:%d", movieId),
-	)
+	invalidateMovieCache(ctx, ma.Rdb, movieId)
 
 	return &movie, nil
 }
@@ -420,12 +374,7 @@ func (ma *MovieAdmin) DeleteMovie(ctx context.Context, movieId int) error {
 		return errors.New("movie not found or already deleted")
 	}
 
-	_ = utils.InvalidateCache(ctx, ma.Rdb,
-		"all_movies",
-		"upcoming_movies",
-		"popular_movies",
-		fmt.Sprintf("movie_detail:%d", movieId),
-	)
+	invalidateMovieCache(ctx, ma.Rdb, movieId)
 
 	return nil
 }
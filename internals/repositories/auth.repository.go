@@ -3,14 +3,22 @@ package repositories
 import (
 	"context"
 	"errors"
-	"log"
-	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/utils"
 )
 
+// AuthStore login dan register user, diimplementasikan AuthRepository
+type AuthStore interface {
+	GetEmailUserWithPasswordAndRole(rctx context.Context, email string) (models.Users, error)
+	CheckEmailExists(rctx context.Context, email string) (bool, error)
+	RegisterUserWithProfile(rctx context.Context, email, password string) (int, error)
+}
+
+var _ AuthStore = (*AuthRepository)(nil)
+
 type AuthRepository struct {
 	db *pgxpool.Pool
 }
@@ -20,14 +28,14 @@ func NewAuthRepository(db *pgxpool.Pool) *AuthRepository {
 }
 
 func (a *AuthRepository) GetEmailUserWithPasswordAndRole(rctx context.Context, email string) (models.Users, error) {
-	sql := "SELECT id, email, password, role FROM users WHERE email = $1"
+	sql := "SELECT id, email, password, role, is_verified FROM users WHERE email = $1"
 
 	var users models.Users
-	if err := a.db.QueryRow(rctx, sql, email).Scan(&users.Id, &users.Email, &users.Password, &users.Role); err != nil {
+	if err := a.db.QueryRow(rctx, sql, email).Scan(&users.Id, &users.Email, &users.Password, &users.Role, &users.IsVerified); err != nil {
 		if err == pgx.ErrNoRows {
 			return models.Users{}, errors.New("user not found")
 		}
-		log.Println("Internal Server Error.\nCz: ", err.Error())
+		utils.Logger(rctx).Error("get user by email failed", "error", err)
 		return models.Users{}, err
 	}
 
@@ -39,20 +47,21 @@ func (a *AuthRepository) CheckEmailExists(rctx context.Context, email string) (b
 
 	var exists bool
 	if err := a.db.QueryRow(rctx, sql, email).Scan(&exists); err != nil {
-		log.Println("Error checking email existence:", err.Error())
+		utils.Logger(rctx).Error("check email exists failed", "error", err)
 		return false, err
 	}
 
 	return exists, nil
 }
 
-func (a *AuthRepository) RegisterUserWithProfile(rctx context.Context, email, password string) error {
+// RegisterUserWithProfile membuat user (belum terverifikasi) beserta profile kosong, mengembalikan id user
+func (a *AuthRepository) RegisterUserWithProfile(rctx context.Context, email, password string) (int, error) {
 	defaultRole := "user"
 
 	tx, err := a.db.Begin(rctx)
 	if err != nil {
-		log.Println("Failed to start transaction:", err.Error())
-		return err
+		utils.Logger(rctx).Error("register user: begin transaction failed", "error", err)
+		return 0, err
 	}
 	defer tx.Rollback(rctx)
 
@@ -62,10 +71,10 @@ func (a *AuthRepository) RegisterUserWithProfile(rctx context.Context, email, pa
 	if err := tx.QueryRow(rctx, sqlUser, email, password, defaultRole).Scan(&userId); err != nil {
 		if err.Error() == "ERROR: duplicate key value violates unique constraint \"users_email_key\" (SQLSTATE 23505)" ||
 			err.Error() == "UNIQUE constraint failed: users.email" {
-			return errors.New("email already exists")
+			return 0, errors.New("email already exists")
 		}
-		log.Println("Error inserting user:", err.Error())
-		return err
+		utils.Logger(rctx).Error("register user: insert user failed", "error", err)
+		return 0, err
 	}
 
 	// Insert ke tabel profile
@@ -75,53 +84,15 @@ func (a *AuthRepository) RegisterUserWithProfile(rctx context.Context, email, pa
     `
 	_, err = tx.Exec(rctx, sqlProfile, userId)
 	if err != nil {
-		log.Println("Error inserting profile:", err.Error())
-		return err
+		utils.Logger(rctx).Error("register user: insert profile failed", "error", err)
+		return 0, err
 	}
 
 	// Commit jika semua sukses
 	if err := tx.Commit(rctx); err != nil {
-		log.Println("Transaction commit failed:", err.Error())
-		return err
-	}
-
-	return nil
-}
-
-// blacklist token
-
-// Method untuk menambahkan token ke blacklist
-func (a *AuthRepository) AddToBlacklist(rctx context.Context, token string, expiresAt time.Time) error {
-	sql := "INSERT INTO blacklist_tokens (token, expires_at, created_at) VALUES ($1, $2, NOW())"
-
-	_, err := a.db.Exec(rctx, sql, token, expiresAt)
-	if err != nil {
-		log.Println("Error adding token to blacklist:", err.Error())
-		return err
-	}
-	return nil
-}
-
-// Method untuk mengecek apakah token sudah di-blacklist
-func (a *AuthRepository) IsTokenBlacklisted(rctx context.Context, token string) (bool, error) {
-	sql := "SELECT EXISTS(SELECT 1 FROM blacklist_tokens WHERE token = $1 AND expires_at > NOW())"
-
-	var exists bool
-	if err := a.db.QueryRow(rctx, sql, token).Scan(&exists); err != nil {
-		log.Println("Error checking token blacklist:", err.Error())
-		return false, err
+		utils.Logger(rctx).Error("register user: commit failed", "error", err)
+		return 0, err
 	}
 
-	return exists, nil
-}
-
-func (a *AuthRepository) CleanupExpiredTokens(rctx context.Context) error {
-	sql := "DELETE FROM blacklist_tokens WHERE expires_at < NOW()"
-
-	_, err := a.db.Exec(rctx, sql)
-	if err != nil {
-		log.Println("error cleaning up expired tokens:", err.Error())
-		return err
-	}
-	return nil
+	return userId, nil
 }
@@ -0,0 +1,289 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// CatalogKind tabel master yang dipakai movie: genres, casts atau directors
+type CatalogKind struct {
+	table string
+	// linkTable & linkColumn tabel relasi many-to-many ke movies, kosong untuk directors (movies.directors_id)
+	linkTable  string
+	linkColumn string
+}
+
+var (
+	Genres    = CatalogKind{table: "genres", linkTable: "movies_genre", linkColumn: "genres_id"}
+	Casts     = CatalogKind{table: "casts", linkTable: "movies_casts", linkColumn: "casts_id"}
+	Directors = CatalogKind{table: "directors"}
+)
+
+// movieCondition kondisi WHERE untuk movie (alias m) yang memakai item dengan id idExpr
+func (k CatalogKind) movieCondition(idExpr string) string {
+	if k.linkTable == "" {
+		return "m.directors_id = " + idExpr
+	}
+	return fmt.Sprintf("m.id IN (SELECT movies_id FROM %s WHERE %s = %s)", k.linkTable, k.linkColumn, idExpr)
+}
+
+// CatalogStore genres, casts dan directors (CRUD & merge), diimplementasikan CatalogRepository
+type CatalogStore interface {
+	GetAll(rctx context.Context) ([]models.CatalogItem, error)
+	Create(rctx context.Context, name string) (models.CatalogItem, error)
+	Rename(rctx context.Context, id int, name string) (models.CatalogItem, error)
+	Delete(rctx context.Context, id int) error
+	Merge(rctx context.Context, targetID int, sourceIDs []int) (models.CatalogMergeResponse, error)
+	GetMovies(rctx context.Context, id int) ([]models.CatalogMovie, error)
+}
+
+var _ CatalogStore = (*CatalogRepository)(nil)
+
+// CatalogRepository CRUD dan merge untuk genres, casts dan directors
+type CatalogRepository struct {
+	db   *pgxpool.Pool
+	rdb  *redis.Client
+	kind CatalogKind
+}
+
+func NewCatalogRepository(db *pgxpool.Pool, rdb *redis.Client, kind CatalogKind) *CatalogRepository {
+	return &CatalogRepository{db: db, rdb: rdb, kind: kind}
+}
+
+func (c *CatalogRepository) itemSQL() string {
+	return fmt.Sprintf(`SELECT x.id, COALESCE(x."name", ''),
+		(SELECT COUNT(*) FROM movies m WHERE %s AND COALESCE(m.is_deleted, false) = false)::int4
+		FROM %s x`, c.kind.movieCondition("x.id"), c.kind.table)
+}
+
+func (c *CatalogRepository) getItem(rctx context.Context, q querier, id int) (models.CatalogItem, error) {
+	var item models.CatalogItem
+	if err := q.QueryRow(rctx, c.itemSQL()+" WHERE x.id = $1", id).Scan(&item.Id, &item.Name, &item.MovieCount); err != nil {
+		if err == pgx.ErrNoRows {
+			return models.CatalogItem{}, errors.New("not found")
+		}
+		return models.CatalogItem{}, err
+	}
+	return item, nil
+}
+
+func (c *CatalogRepository) GetAll(rctx context.Context) ([]models.CatalogItem, error) {
+	rows, err := c.db.Query(rctx, c.itemSQL()+` ORDER BY x."name"`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.CatalogItem{}
+	for rows.Next() {
+		var item models.CatalogItem
+		if err := rows.Scan(&item.Id, &item.Name, &item.MovieCount); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (c *CatalogRepository) Create(rctx context.Context, name string) (models.CatalogItem, error) {
+	item := models.CatalogItem{Name: name}
+	sql := fmt.Sprintf(`INSERT INTO %s ("name") VALUES ($1) RETURNING id`, c.kind.table)
+	if err := c.db.QueryRow(rctx, sql, name).Scan(&item.Id); err != nil {
+		return models.CatalogItem{}, catalogWriteError(err)
+	}
+	return item, nil
+}
+
+// Rename mengubah nama, cache movie yang memakai item ini ikut dihapus karena menyimpan nama lama
+func (c *CatalogRepository) Rename(rctx context.Context, id int, name string) (models.CatalogItem, error) {
+	sql := fmt.Sprintf(`UPDATE %s SET "name" = $1 WHERE id = $2`, c.kind.table)
+	res, err := c.db.Exec(rctx, sql, name, id)
+	if err != nil {
+		return models.CatalogItem{}, catalogWriteError(err)
+	}
+	if res.RowsAffected() == 0 {
+		return models.CatalogItem{}, errors.New("not found")
+	}
+
+	item, err := c.getItem(rctx, c.db, id)
+	if err != nil {
+		return models.CatalogItem{}, err
+	}
+	c.invalidateMovies(rctx, id)
+	return item, nil
+}
+
+// Delete menghapus item yang tidak dipakai movie manapun, item yang masih dipakai harus di-merge
+func (c *CatalogRepository) Delete(rctx context.Context, id int) error {
+	tx, err := c.db.Begin(rctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(rctx)
+
+	if err := c.lockItems(rctx, tx, []int{id}); err != nil {
+		return err
+	}
+
+	// movie yang sudah di-soft delete tetap dihitung karena FK/relasinya masih ada
+	var inUse bool
+	inUseSQL := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM movies m WHERE %s)", c.kind.movieCondition("$1"))
+	if err := tx.QueryRow(rctx, inUseSQL, id).Scan(&inUse); err != nil {
+		return err
+	}
+	if inUse {
+		return errors.New("in use")
+	}
+	if c.kind.linkTable != "" {
+		// relasi yatim (movie sudah tidak ada)
+		if _, err := tx.Exec(rctx, fmt.Sprintf("DELETE FROM %s WHERE %s = $1", c.kind.linkTable, c.kind.linkColumn), id); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(rctx, fmt.Sprintf("DELETE FROM %s WHERE id = $1", c.kind.table), id); err != nil {
+		return err
+	}
+	return tx.Commit(rctx)
+}
+
+// Merge memindahkan semua movie dari sourceIDs ke targetID lalu menghapus sourceIDs.
+// Dipakai untuk menggabungkan data ganda, misalnya "Sci-Fi" dan "Science Fiction"
+func (c *CatalogRepository) Merge(rctx context.Context, targetID int, sourceIDs []int) (models.CatalogMergeResponse, error) {
+	sourceIDs = slices.Compact(slices.Sorted(slices.Values(sourceIDs)))
+	if slices.Contains(sourceIDs, targetID) {
+		return models.CatalogMergeResponse{}, errors.New("cannot merge into itself")
+	}
+
+	tx, err := c.db.Begin(rctx)
+	if err != nil {
+		return models.CatalogMergeResponse{}, err
+	}
+	defer tx.Rollback(rctx)
+
+	if err := c.lockItems(rctx, tx, append([]int{targetID}, sourceIDs...)); err != nil {
+		return models.CatalogMergeResponse{}, err
+	}
+
+	var moved []int
+	if c.kind.linkTable == "" {
+		moved, err = collectIDs(tx.Query(rctx, "UPDATE movies SET directors_id = $1 WHERE directors_id = ANY($2) RETURNING id", targetID, sourceIDs))
+		if err != nil {
+			return models.CatalogMergeResponse{}, err
+		}
+	} else {
+		// movie yang sudah punya target tidak ditambah lagi, sisanya dipindah ke target
+		insertSQL := fmt.Sprintf(`INSERT INTO %[1]s (movies_id, %[2]s)
+			SELECT DISTINCT l.movies_id, $1::int4 FROM %[1]s l
+			WHERE l.%[2]s = ANY($2)
+			  AND NOT EXISTS (SELECT 1 FROM %[1]s t WHERE t.movies_id = l.movies_id AND t.%[2]s = $1)`,
+			c.kind.linkTable, c.kind.linkColumn)
+		if _, err := tx.Exec(rctx, insertSQL, targetID, sourceIDs); err != nil {
+			return models.CatalogMergeResponse{}, err
+		}
+
+		deleteSQL := fmt.Sprintf("DELETE FROM %s WHERE %s = ANY($1) RETURNING movies_id", c.kind.linkTable, c.kind.linkColumn)
+		moved, err = collectIDs(tx.Query(rctx, deleteSQL, sourceIDs))
+		if err != nil {
+			return models.CatalogMergeResponse{}, err
+		}
+		moved = slices.Compact(slices.Sorted(slices.Values(moved)))
+	}
+
+	if _, err := tx.Exec(rctx, fmt.Sprintf("DELETE FROM %s WHERE id = ANY($1)", c.kind.table), sourceIDs); err != nil {
+		return models.CatalogMergeResponse{}, err
+	}
+
+	target, err := c.getItem(rctx, tx, targetID)
+	if err != nil {
+		return models.CatalogMergeResponse{}, err
+	}
+	if err := tx.Commit(rctx); err != nil {
+		return models.CatalogMergeResponse{}, err
+	}
+
+	invalidateMovieCache(rctx, c.rdb, moved...)
+	return models.CatalogMergeResponse{
+		Target:      target,
+		Merged:      sourceIDs,
+		MoviesMoved: len(moved),
+	}, nil
+}
+
+// GetMovies daftar movie (yang belum dihapus) milik sebuah genre, cast atau director
+func (c *CatalogRepository) GetMovies(rctx context.Context, id int) ([]models.CatalogMovie, error) {
+	if _, err := c.getItem(rctx, c.db, id); err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT m.id, m.title, m.release_date, m.poster_image, m.rating, d."name",
+			(SELECT STRING_AGG(g."name", ', ' ORDER BY g."name")
+			 FROM movies_genre mg JOIN genres g ON g.id = mg.genres_id
+			 WHERE mg.movies_id = m.id)
+		FROM movies m
+		LEFT JOIN directors d ON d.id = m.directors_id
+		WHERE %s AND COALESCE(m.is_deleted, false) = false
+		ORDER BY m.release_date DESC NULLS LAST, m.id DESC`, c.kind.movieCondition("$1"))
+
+	rows, err := c.db.Query(rctx, sql, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movies := []models.CatalogMovie{}
+	for rows.Next() {
+		var movie models.CatalogMovie
+		if err := rows.Scan(&movie.Id, &movie.Title, &movie.ReleaseDate, &movie.PosterImage, &movie.Rating, &movie.DirectorName, &movie.Genres); err != nil {
+			return nil, err
+		}
+		movies = append(movies, movie)
+	}
+	return movies, rows.Err()
+}
+
+// lockItems mengunci baris item, error "not found" jika ada id yang tidak ada
+func (c *CatalogRepository) lockItems(rctx context.Context, q querier, ids []int) error {
+	sql := fmt.Sprintf("SELECT id FROM %s WHERE id = ANY($1) ORDER BY id FOR UPDATE", c.kind.table)
+	found, err := collectIDs(q.Query(rctx, sql, ids))
+	if err != nil {
+		return err
+	}
+	if len(found) != len(slices.Compact(slices.Sorted(slices.Values(ids)))) {
+		return errors.New("not found")
+	}
+	return nil
+}
+
+// invalidateMovies menghapus cache movie yang memakai item id
+func (c *CatalogRepository) invalidateMovies(rctx context.Context, id int) {
+	sql := fmt.Sprintf("SELECT m.id FROM movies m WHERE %s", c.kind.movieCondition("$1"))
+	movieIDs, err := collectIDs(c.db.Query(rctx, sql, id))
+	if err != nil {
+		return
+	}
+	invalidateMovieCache(rctx, c.rdb, movieIDs...)
+}
+
+func collectIDs(rows pgx.Rows, err error) ([]int, error) {
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
+
+func catalogWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return errors.New("name already exists")
+	}
+	return err
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/raihaninkam/tickitz/internals/testutil"
+)
+
+func TestCatalogMovieCondition(t *testing.T) {
+	tests := []struct {
+		kind CatalogKind
+		want string
+	}{
+		{kind: Genres, want: "m.id IN (SELECT movies_id FROM movies_genre WHERE genres_id = $1)"},
+		{kind: Casts, want: "m.id IN (SELECT movies_id FROM movies_casts WHERE casts_id = $1)"},
+		{kind: Directors, want: "m.directors_id = $1"},
+	}
+	for _, tt := range tests {
+		if got := tt.kind.movieCondition("$1"); got != tt.want {
+			t.Errorf("%s: condition %q, want %q", tt.kind.table, got, tt.want)
+		}
+	}
+}
+
+func TestCatalogWriteError(t *testing.T) {
+	unique := &pgconn.PgError{Code: "23505"}
+	if err := catalogWriteError(unique); err == nil || err.Error() != "name already exists" {
+		t.Errorf("unique violation: err %v, want name already exists", err)
+	}
+	other := errors.New("connection refused")
+	if err := catalogWriteError(other); err != other {
+		t.Errorf("other error: got %v, want it unchanged", err)
+	}
+}
+
+func TestCatalogMergeGenres(t *testing.T) {
+	env := testutil.Setup(t)
+	env.Seed(t, "demo")
+	ctx := t.Context()
+	genres := NewCatalogRepository(env.DB, env.RDB, Genres)
+
+	if _, err := genres.Create(ctx, "Sci-Fi"); err == nil || err.Error() != "name already exists" {
+		t.Errorf("duplicate name: err %v, want name already exists", err)
+	}
+
+	// "Science Fiction" ganda dari "Sci-Fi" (id 2): Inception sudah punya keduanya, Jurassic Park hanya yang ganda
+	duplicate, err := genres.Create(ctx, "Science Fiction")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.DB.Exec(ctx, "INSERT INTO movies_genre (movies_id, genres_id) VALUES (1, $1), (3, $1)", duplicate.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := genres.Merge(ctx, 2, []int{duplicate.Id, 2}); err == nil || err.Error() != "cannot merge into itself" {
+		t.Errorf("merge into itself: err %v", err)
+	}
+	if _, err := genres.Merge(ctx, 2, []int{duplicate.Id, 999}); err == nil || err.Error() != "not found" {
+		t.Errorf("unknown source: err %v, want not found", err)
+	}
+
+	res, err := genres.Merge(ctx, 2, []int{duplicate.Id, duplicate.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(res.Merged, []int{duplicate.Id}) || res.MoviesMoved != 2 {
+		t.Errorf("merged %v moved %d, want [%d] and 2 movies", res.Merged, res.MoviesMoved, duplicate.Id)
+	}
+	// Inception, Avatar, Interstellar + Jurassic Park
+	if res.Target.Id != 2 || res.Target.MovieCount != 4 {
+		t.Errorf("target %+v, want Sci-Fi with 4 movies", res.Target)
+	}
+
+	var links int
+	if err := env.DB.QueryRow(ctx, "SELECT COUNT(*) FROM movies_genre WHERE movies_id = 1 AND genres_id = 2").Scan(&links); err != nil {
+		t.Fatal(err)
+	}
+	if links != 1 {
+		t.Errorf("inception linked to sci-fi %d times, want 1", links)
+	}
+	if _, err := genres.GetMovies(ctx, duplicate.Id); err == nil || err.Error() != "not found" {
+		t.Errorf("merged genre still exists: err %v", err)
+	}
+
+	// item yang masih dipakai harus di-merge, bukan dihapus
+	if err := genres.Delete(ctx, 2); err == nil || err.Error() != "in use" {
+		t.Errorf("delete genre in use: err %v, want in use", err)
+	}
+	unused, err := genres.Create(ctx, "Horror")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := genres.Delete(ctx, unused.Id); err != nil {
+		t.Errorf("delete unused genre: %v", err)
+	}
+}
+
+func TestCatalogMergeDirectors(t *testing.T) {
+	env := testutil.Setup(t)
+	env.Seed(t, "demo")
+	ctx := t.Context()
+	directors := NewCatalogRepository(env.DB, env.RDB, Directors)
+
+	// Steven Spielberg (Jurassic Park) digabung ke Christopher Nolan
+	res, err := directors.Merge(ctx, 1, []int{3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.MoviesMoved != 1 || res.Target.MovieCount != 4 {
+		t.Errorf("moved %d, target %+v, want 1 movie moved and 4 movies", res.MoviesMoved, res.Target)
+	}
+
+	movies, err := directors.GetMovies(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	for _, movie := range movies {
+		titles = append(titles, movie.Title)
+	}
+	if !slices.Contains(titles, "Jurassic Park") {
+		t.Errorf("movies of target %v, want Jurassic Park", titles)
+	}
+}
@@ -0,0 +1,355 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/models"
+)
+
+// CinemaStore cinema, location dan denah kursi, diimplementasikan CinemaRepository
+type CinemaStore interface {
+	GetCinemas(rctx context.Context) ([]models.Cinema, error)
+	CreateCinema(rctx context.Context, req models.CinemaRequest) (models.Cinema, error)
+	UpdateCinema(rctx context.Context, cinemaID int, req models.CinemaRequest) (models.Cinema, error)
+	DeleteCinema(rctx context.Context, cinemaID int) error
+	GetLocations(rctx context.Context) ([]models.Location, error)
+	CreateLocation(rctx context.Context, req models.LocationRequest) (models.Location, error)
+	UpdateLocation(rctx context.Context, locationID int, req models.LocationRequest) (models.Location, error)
+	DeleteLocation(rctx context.Context, locationID int) error
+	GetSeatLayout(rctx context.Context, cinemaID int) (models.SeatLayout, error)
+	SaveSeatLayout(rctx context.Context, cinemaID int, req models.SeatLayoutRequest) (models.SeatLayout, error)
+}
+
+var _ CinemaStore = (*CinemaRepository)(nil)
+
+// CinemaRepository mengelola data cinema, location dan denah kursi (seats) per cinema
+type CinemaRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCinemaRepository(db *pgxpool.Pool) *CinemaRepository {
+	return &CinemaRepository{db: db}
+}
+
+// cinemas
+
+const cinemaColumns = `c.id, c.cinema_name,
+	(SELECT COUNT(*) FROM seats s WHERE s.cinemas_id = c.id AND s.deleted_at IS NULL)::int4,
+	c.created_at, c.updated_at`
+
+func scanCinema(row pgx.Row) (models.Cinema, error) {
+	var cinema models.Cinema
+	err := row.Scan(&cinema.Id, &cinema.CinemaName, &cinema.SeatCount, &cinema.CreatedAt, &cinema.UpdatedAt)
+	return cinema, err
+}
+
+func (c *CinemaRepository) GetCinemas(rctx context.Context) ([]models.Cinema, error) {
+	rows, err := c.db.Query(rctx, "SELECT "+cinemaColumns+" FROM cinemas c ORDER BY c.id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cinemas := []models.Cinema{}
+	for rows.Next() {
+		cinema, err := scanCinema(rows)
+		if err != nil {
+			return nil, err
+		}
+		cinemas = append(cinemas, cinema)
+	}
+	return cinemas, rows.Err()
+}
+
+func (c *CinemaRepository) CreateCinema(rctx context.Context, req models.CinemaRequest) (models.Cinema, error) {
+	sql := `WITH c AS (
+				INSERT INTO cinemas (cinema_name, created_at, updated_at) VALUES ($1, NOW(), NOW())
+				RETURNING id, cinema_name, created_at, updated_at
+			)
+			SELECT c.id, c.cinema_name, 0, c.created_at, c.updated_at FROM c`
+	return scanCinema(c.db.QueryRow(rctx, sql, req.CinemaName))
+}
+
+func (c *CinemaRepository) UpdateCinema(rctx context.Context, cinemaID int, req models.CinemaRequest) (models.Cinema, error) {
+	res, err := c.db.Exec(rctx, "UPDATE cinemas SET cinema_name = $1, updated_at = NOW() WHERE id = $2", req.CinemaName, cinemaID)
+	if err != nil {
+		return models.Cinema{}, err
+	}
+	if res.RowsAffected() == 0 {
+		return models.Cinema{}, errors.New("cinema not found")
+	}
+	return scanCinema(c.db.QueryRow(rctx, "SELECT "+cinemaColumns+" FROM cinemas c WHERE c.id = $1", cinemaID))
+}
+
+// DeleteCinema menghapus cinema beserta denah kursinya, ditolak jika sudah punya jadwal tayang atau order
+func (c *CinemaRepository) DeleteCinema(rctx context.Context, cinemaID int) error {
+	tx, err := c.db.Begin(rctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(rctx)
+
+	if err := lockCinema(rctx, tx, cinemaID); err != nil {
+		return err
+	}
+
+	var inUse bool
+	inUseSQL := `SELECT EXISTS(SELECT 1 FROM now_showing WHERE cinemas_id = $1)
+				 OR EXISTS(SELECT 1 FROM orders WHERE cinemas_id = $1)
+				 OR EXISTS(SELECT 1 FROM orders_cinema WHERE cinema_id = $1)`
+	if err := tx.QueryRow(rctx, inUseSQL, cinemaID).Scan(&inUse); err != nil {
+		return err
+	}
+	if inUse {
+		return errors.New("cinema in use")
+	}
+
+	if _, err := tx.Exec(rctx, "DELETE FROM seats WHERE cinemas_id = $1", cinemaID); err != nil {
+		return err
+	}
+	// ticket_prices & price_rules ikut terhapus (ON DELETE CASCADE)
+	if _, err := tx.Exec(rctx, "DELETE FROM cinemas WHERE id = $1", cinemaID); err != nil {
+		return err
+	}
+	return tx.Commit(rctx)
+}
+
+// locations
+
+func (c *CinemaRepository) GetLocations(rctx context.Context) ([]models.Location, error) {
+	rows, err := c.db.Query(rctx, `SELECT id, "name" FROM "location" ORDER BY "name"`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	locations := []models.Location{}
+	for rows.Next() {
+		var location models.Location
+		if err := rows.Scan(&location.Id, &location.Name); err != nil {
+			return nil, err
+		}
+		locations = append(locations, location)
+	}
+	return locations, rows.Err()
+}
+
+func (c *CinemaRepository) CreateLocation(rctx context.Context, req models.LocationRequest) (models.Location, error) {
+	location := models.Location{Name: req.Name}
+	if err := c.db.QueryRow(rctx, `INSERT INTO "location" ("name") VALUES ($1) RETURNING id`, req.Name).Scan(&location.Id); err != nil {
+		return models.Location{}, err
+	}
+	return location, nil
+}
+
+func (c *CinemaRepository) UpdateLocation(rctx context.Context, locationID int, req models.LocationRequest) (models.Location, error) {
+	res, err := c.db.Exec(rctx, `UPDATE "location" SET "name" = $1 WHERE id = $2`, req.Name, locationID)
+	if err != nil {
+		return models.Location{}, err
+	}
+	if res.RowsAffected() == 0 {
+		return models.Location{}, errors.New("location not found")
+	}
+	return models.Location{Id: locationID, Name: req.Name}, nil
+}
+
+// DeleteLocation ditolak jika location masih dipakai jadwal tayang
+func (c *CinemaRepository) DeleteLocation(rctx context.Context, locationID int) error {
+	var inUse bool
+	if err := c.db.QueryRow(rctx, "SELECT EXISTS(SELECT 1 FROM now_showing WHERE location_id = $1)", locationID).Scan(&inUse); err != nil {
+		return err
+	}
+	if inUse {
+		return errors.New("location in use")
+	}
+
+	res, err := c.db.Exec(rctx, `DELETE FROM "location" WHERE id = $1`, locationID)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return errors.New("location not found")
+	}
+	return nil
+}
+
+// seat layout
+
+// GetSeatLayout menyusun denah kursi cinema per baris
+func (c *CinemaRepository) GetSeatLayout(rctx context.Context, cinemaID int) (models.SeatLayout, error) {
+	if err := ensureCinemaExists(rctx, c.db, cinemaID); err != nil {
+		return models.SeatLayout{}, err
+	}
+	return getSeatLayout(rctx, c.db, cinemaID)
+}
+
+// SaveSeatLayout mengganti denah kursi cinema. Kursi yang sudah ada dipertahankan (id tetap) supaya
+// riwayat showing_seats tidak rusak; kursi yang hilang dari denah dihapus, atau ditandai deleted_at jika
+// pernah dipesan. Ditolak jika kursi yang dihapus/dinonaktifkan masih terjual atau ditahan untuk jadwal mendatang
+func (c *CinemaRepository) SaveSeatLayout(rctx context.Context, cinemaID int, req models.SeatLayoutRequest) (models.SeatLayout, error) {
+	tx, err := c.db.Begin(rctx)
+	if err != nil {
+		return models.SeatLayout{}, err
+	}
+	defer tx.Rollback(rctx)
+
+	if err := lockCinema(rctx, tx, cinemaID); err != nil {
+		return models.SeatLayout{}, err
+	}
+
+	var seatRows, seatClasses []string
+	var seatNumbers []int
+	var disabled, aisles []bool
+	for _, row := range req.Rows {
+		for number := 1; number <= row.SeatCount; number++ {
+			class := "regular"
+			if slices.Contains(row.LoveNest, number) {
+				class = "love_nest"
+			} else if slices.Contains(row.Wheelchair, number) {
+				class = "wheelchair"
+			}
+			seatRows = append(seatRows, row.Row)
+			seatNumbers = append(seatNumbers, number)
+			seatClasses = append(seatClasses, class)
+			disabled = append(disabled, slices.Contains(row.Disabled, number))
+			aisles = append(aisles, slices.Contains(row.AislesAfter, number))
+		}
+	}
+
+	const layoutSQL = `unnest($2::text[], $3::int4[], $4::text[], $5::bool[], $6::bool[])
+					   AS l(seat_row, seat_number, seat_class, is_disabled, aisle_after)`
+
+	conflictSQL := `SELECT EXISTS(
+						SELECT 1
+						FROM seats s
+						JOIN showing_seats ss ON ss.seat_id = s.id
+						JOIN now_showing ns ON ns.id = ss.now_showing_id
+						LEFT JOIN ` + layoutSQL + ` ON l.seat_row = s.row AND l.seat_number = s.seat_number
+						WHERE s.cinemas_id = $1 AND s.deleted_at IS NULL
+						  AND (l.seat_row IS NULL OR l.is_disabled)
+						  AND ns.date >= CURRENT_DATE
+						  AND (ss.status = 'sold' OR (ss.status = 'held' AND ss.held_until > NOW()))
+					)`
+	args := []any{cinemaID, seatRows, seatNumbers, seatClasses, disabled, aisles}
+
+	var conflict bool
+	if err := tx.QueryRow(rctx, conflictSQL, args...).Scan(&conflict); err != nil {
+		return models.SeatLayout{}, err
+	}
+	if conflict {
+		return models.SeatLayout{}, errors.New("layout conflicts with active bookings")
+	}
+
+	// kursi yang tidak ada lagi di denah
+	removedSQL := `SELECT s.id FROM seats s
+				   WHERE s.cinemas_id = $1 AND s.deleted_at IS NULL
+					 AND NOT EXISTS (
+						SELECT 1 FROM unnest($2::text[], $3::int4[]) AS l(seat_row, seat_number)
+						WHERE l.seat_row = s.row AND l.seat_number = s.seat_number
+					 )`
+	if _, err := tx.Exec(rctx, `UPDATE seats SET deleted_at = NOW()
+								WHERE id IN (`+removedSQL+`)
+								  AND EXISTS (SELECT 1 FROM showing_seats ss WHERE ss.seat_id = seats.id)`,
+		cinemaID, seatRows, seatNumbers); err != nil {
+		return models.SeatLayout{}, err
+	}
+	if _, err := tx.Exec(rctx, `DELETE FROM seats WHERE id IN (`+removedSQL+`)`, cinemaID, seatRows, seatNumbers); err != nil {
+		return models.SeatLayout{}, err
+	}
+
+	// kursi yang sudah ada (termasuk yang pernah dihapus) diperbarui, sisanya dibuat baru
+	updateSQL := `UPDATE seats s
+				  SET seat_class = l.seat_class, is_disabled = l.is_disabled, aisle_after = l.aisle_after, deleted_at = NULL
+				  FROM ` + layoutSQL + `
+				  WHERE s.cinemas_id = $1 AND s.row = l.seat_row AND s.seat_number = l.seat_number`
+	if _, err := tx.Exec(rctx, updateSQL, args...); err != nil {
+		return models.SeatLayout{}, err
+	}
+
+	insertSQL := `INSERT INTO seats (cinemas_id, "row", seat_number, seats_map, seat_class, is_disabled, aisle_after)
+				  SELECT $1, l.seat_row, l.seat_number, CONCAT(l.seat_row, l.seat_number), l.seat_class, l.is_disabled, l.aisle_after
+				  FROM ` + layoutSQL + `
+				  WHERE NOT EXISTS (
+					SELECT 1 FROM seats s WHERE s.cinemas_id = $1 AND s.row = l.seat_row AND s.seat_number = l.seat_number
+				  )`
+	if _, err := tx.Exec(rctx, insertSQL, args...); err != nil {
+		return models.SeatLayout{}, err
+	}
+
+	if _, err := tx.Exec(rctx, "UPDATE cinemas SET updated_at = NOW() WHERE id = $1", cinemaID); err != nil {
+		return models.SeatLayout{}, err
+	}
+
+	layout, err := getSeatLayout(rctx, tx, cinemaID)
+	if err != nil {
+		return models.SeatLayout{}, err
+	}
+	if err := tx.Commit(rctx); err != nil {
+		return models.SeatLayout{}, err
+	}
+	return layout, nil
+}
+
+func getSeatLayout(rctx context.Context, q querier, cinemaID int) (models.SeatLayout, error) {
+	sql := `SELECT s.row, s.seat_number, s.seat_class, s.is_disabled, s.aisle_after
+			FROM seats s
+			WHERE s.cinemas_id = $1 AND s.deleted_at IS NULL
+			ORDER BY length(s.row), s.row, s.seat_number`
+
+	rows, err := q.Query(rctx, sql, cinemaID)
+	if err != nil {
+		return models.SeatLayout{}, err
+	}
+	defer rows.Close()
+
+	layout := models.SeatLayout{CinemaId: cinemaID, Rows: []models.LayoutRow{}}
+	for rows.Next() {
+		var seatRow, class string
+		var number int
+		var isDisabled, aisleAfter bool
+		if err := rows.Scan(&seatRow, &number, &class, &isDisabled, &aisleAfter); err != nil {
+			return models.SeatLayout{}, err
+		}
+
+		if len(layout.Rows) == 0 || layout.Rows[len(layout.Rows)-1].Row != seatRow {
+			layout.Rows = append(layout.Rows, models.LayoutRow{
+				Row:         seatRow,
+				AislesAfter: []int{},
+				LoveNest:    []int{},
+				Wheelchair:  []int{},
+				Disabled:    []int{},
+			})
+		}
+		row := &layout.Rows[len(layout.Rows)-1]
+		row.SeatCount = max(row.SeatCount, number)
+		switch class {
+		case "love_nest":
+			row.LoveNest = append(row.LoveNest, number)
+		case "wheelchair":
+			row.Wheelchair = append(row.Wheelchair, number)
+		}
+		if isDisabled {
+			row.Disabled = append(row.Disabled, number)
+		}
+		if aisleAfter {
+			row.AislesAfter = append(row.AislesAfter, number)
+		}
+		layout.TotalSeats++
+	}
+	return layout, rows.Err()
+}
+
+func lockCinema(rctx context.Context, q querier, cinemaID int) error {
+	var id int
+	if err := q.QueryRow(rctx, "SELECT id FROM cinemas WHERE id = $1 FOR UPDATE", cinemaID).Scan(&id); err != nil {
+		if err == pgx.ErrNoRows {
+			return errors.New("cinema not found")
+		}
+		return err
+	}
+	return nil
+}
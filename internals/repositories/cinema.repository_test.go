@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/testutil"
+)
+
+// fullRows baris first..last dengan seatCount kursi
+func fullRows(first, last byte, seatCount int) []models.LayoutRow {
+	var rows []models.LayoutRow
+	for row := first; row <= last; row++ {
+		rows = append(rows, models.LayoutRow{Row: string(row), SeatCount: seatCount})
+	}
+	return rows
+}
+
+func TestSaveSeatLayout(t *testing.T) {
+	env := testutil.Setup(t)
+	env.Seed(t, "demo")
+	ctx := t.Context()
+	cinemas := NewCinemaRepository(env.DB)
+
+	layout, err := cinemas.GetSeatLayout(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if layout.TotalSeats != 84 || len(layout.Rows) != 6 {
+		t.Fatalf("demo layout has %d seats in %d rows, want 84 in 6", layout.TotalSeats, len(layout.Rows))
+	}
+
+	// A1 terjual untuk showing 1 (besok)
+	var soldSeatID int
+	if err := env.DB.QueryRow(ctx, `SELECT id FROM seats WHERE cinemas_id = 1 AND "row" = 'A' AND seat_number = 1`).Scan(&soldSeatID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.DB.Exec(ctx, `INSERT INTO showing_seats (now_showing_id, seat_id, status, user_id, created_at, updated_at)
+								   VALUES (1, $1, 'sold', 2, NOW(), NOW())`, soldSeatID); err != nil {
+		t.Fatal(err)
+	}
+
+	withoutA := models.SeatLayoutRequest{Rows: fullRows('B', 'F', 14)}
+	if _, err := cinemas.SaveSeatLayout(ctx, 1, withoutA); err == nil || err.Error() != "layout conflicts with active bookings" {
+		t.Errorf("removing a sold seat: err %v, want layout conflicts with active bookings", err)
+	}
+	disabledA1 := models.SeatLayoutRequest{Rows: fullRows('A', 'F', 14)}
+	disabledA1.Rows[0].Disabled = []int{1}
+	if _, err := cinemas.SaveSeatLayout(ctx, 1, disabledA1); err == nil || err.Error() != "layout conflicts with active bookings" {
+		t.Errorf("disabling a sold seat: err %v, want layout conflicts with active bookings", err)
+	}
+
+	// baris A diperpendek (kursi belum pernah dipesan dihapus), baris G ditambah
+	changed := models.SeatLayoutRequest{Rows: append(fullRows('A', 'F', 14), models.LayoutRow{Row: "G", SeatCount: 10, LoveNest: []int{9, 10}})}
+	changed.Rows[0].SeatCount = 12
+	changed.Rows[0].Disabled = []int{2}
+	layout, err = cinemas.SaveSeatLayout(ctx, 1, changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if layout.TotalSeats != 82+10 {
+		t.Errorf("total seats %d, want 92", layout.TotalSeats)
+	}
+	if got := layout.Rows[0]; got.SeatCount != 12 || len(got.Disabled) != 1 || got.Disabled[0] != 2 {
+		t.Errorf("row A %+v, want 12 seats with A2 disabled", got)
+	}
+	if got := layout.Rows[len(layout.Rows)-1]; got.Row != "G" || len(got.LoveNest) != 2 {
+		t.Errorf("last row %+v, want G with 2 love nest seats", got)
+	}
+
+	// id kursi yang sudah terjual tidak berubah
+	var seatID int
+	if err := env.DB.QueryRow(ctx, `SELECT id FROM seats WHERE cinemas_id = 1 AND "row" = 'A' AND seat_number = 1 AND deleted_at IS NULL`).Scan(&seatID); err != nil {
+		t.Fatal(err)
+	}
+	if seatID != soldSeatID {
+		t.Errorf("sold seat id changed from %d to %d", soldSeatID, seatID)
+	}
+
+	if _, err := cinemas.SaveSeatLayout(ctx, 9999, changed); err == nil || err.Error() != "cinema not found" {
+		t.Errorf("unknown cinema: err %v, want cinema not found", err)
+	}
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// LockoutStore penguncian akun setelah login gagal berulang, diimplementasikan LockoutRepository
+type LockoutStore interface {
+	LockedFor(rctx context.Context, email string) (time.Duration, error)
+	RecordFailure(rctx context.Context, email string) (time.Duration, error)
+	Reset(rctx context.Context, email string) error
+	UnlockUser(rctx context.Context, userID int) (string, error)
+}
+
+var _ LockoutStore = (*LockoutRepository)(nil)
+
+// LockoutRepository mencatat login gagal per email di Redis dan mengunci akun secara bertahap
+type LockoutRepository struct {
+	db    *pgxpool.Pool
+	rdb   *redis.Client
+	rules models.LockoutRules
+}
+
+func NewLockoutRepository(db *pgxpool.Pool, rdb *redis.Client, rules models.LockoutRules) *LockoutRepository {
+	return &LockoutRepository{db: db, rdb: rdb, rules: rules}
+}
+
+// lockoutLevelTTL lama level lockout diingat, lockout berikutnya dalam rentang ini durasinya berlipat
+const lockoutLevelTTL = 24 * time.Hour
+
+func lockoutKeys(email string) (failKey, lockKey, levelKey string) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	return "login_fail:" + email, "login_lock:" + email, "login_lock_level:" + email
+}
+
+// LockedFor mengembalikan sisa waktu lockout akun, 0 jika tidak terkunci
+func (l *LockoutRepository) LockedFor(rctx context.Context, email string) (time.Duration, error) {
+	_, lockKey, _ := lockoutKeys(email)
+	var ttl time.Duration
+	err := utils.WithRedis(rctx, l.rdb, func() (err error) {
+		ttl, err = l.rdb.PTTL(rctx, lockKey).Result()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	// -2 key tidak ada, -1 tanpa expire (tidak pernah di-set oleh repository ini)
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// RecordFailure mencatat login gagal. Jika batas percobaan tercapai akun dikunci
+// dan durasi lockout dikembalikan (BaseLock, 2x, 4x, ... maksimal MaxLock)
+func (l *LockoutRepository) RecordFailure(rctx context.Context, email string) (time.Duration, error) {
+	failKey, lockKey, levelKey := lockoutKeys(email)
+
+	var lock time.Duration
+	err := utils.WithRedis(rctx, l.rdb, func() error {
+		failures, err := l.rdb.Incr(rctx, failKey).Result()
+		if err != nil {
+			return err
+		}
+		// window dihitung sejak kegagalan pertama
+		if failures == 1 {
+			if err := l.rdb.Expire(rctx, failKey, l.rules.AttemptWindow).Err(); err != nil {
+				return err
+			}
+		}
+		if failures < int64(l.rules.MaxAttempts) {
+			return nil
+		}
+
+		pipe := l.rdb.TxPipeline()
+		level := pipe.Incr(rctx, levelKey)
+		pipe.Expire(rctx, levelKey, lockoutLevelTTL)
+		pipe.Del(rctx, failKey)
+		if _, err := pipe.Exec(rctx); err != nil {
+			return err
+		}
+
+		lock = l.rules.BaseLock
+		for i := int64(1); i < level.Val() && lock < l.rules.MaxLock; i++ {
+			lock *= 2
+		}
+		lock = min(lock, l.rules.MaxLock)
+		return l.rdb.Set(rctx, lockKey, 1, lock).Err()
+	})
+	if err != nil {
+		return 0, err
+	}
+	return lock, nil
+}
+
+// Reset menghapus catatan login gagal setelah login berhasil
+func (l *LockoutRepository) Reset(rctx context.Context, email string) error {
+	failKey, _, levelKey := lockoutKeys(email)
+	return utils.WithRedis(rctx, l.rdb, func() error {
+		return l.rdb.Del(rctx, failKey, levelKey).Err()
+	})
+}
+
+// UnlockUser membuka lockout akun oleh admin, mengembalikan email user
+func (l *LockoutRepository) UnlockUser(rctx context.Context, userID int) (string, error) {
+	var email string
+	if err := l.db.QueryRow(rctx, "SELECT email FROM users WHERE id = $1", userID).Scan(&email); err != nil {
+		if err == pgx.ErrNoRows {
+			return "", errors.New("user not found")
+		}
+		return "", err
+	}
+
+	failKey, lockKey, levelKey := lockoutKeys(email)
+	err := utils.WithRedis(rctx, l.rdb, func() error {
+		return l.rdb.Del(rctx, failKey, lockKey, levelKey).Err()
+	})
+	if err != nil {
+		return "", err
+	}
+	return email, nil
+}
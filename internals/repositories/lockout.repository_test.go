@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/utils"
+)
+
+func TestLockoutEscalation(t *testing.T) {
+	ctx := t.Context()
+	mr, rdb := newTestRedis(t)
+	lockout := NewLockoutRepository(nil, rdb, models.LockoutRules{
+		MaxAttempts:   3,
+		AttemptWindow: time.Minute,
+		BaseLock:      time.Minute,
+		MaxLock:       4 * time.Minute,
+	})
+
+	// kunci sesuai level: 1m, 2m, 4m lalu tetap di MaxLock
+	for i, want := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 4 * time.Minute} {
+		var lock time.Duration
+		for range 3 {
+			var err error
+			if lock, err = lockout.RecordFailure(ctx, " Budi@Example.com"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if lock != want {
+			t.Errorf("lockout %d: locked for %v, want %v", i+1, lock, want)
+		}
+		// email tidak peka huruf besar / spasi
+		if locked, err := lockout.LockedFor(ctx, "budi@example.com"); err != nil || locked <= 0 || locked > want {
+			t.Errorf("lockout %d: LockedFor = %v (err %v), want up to %v", i+1, locked, err, want)
+		}
+		mr.FastForward(want)
+		if locked, _ := lockout.LockedFor(ctx, "budi@example.com"); locked != 0 {
+			t.Errorf("lockout %d: still locked for %v after it expired", i+1, locked)
+		}
+	}
+
+	// login berhasil mengembalikan level ke awal
+	if err := lockout.Reset(ctx, "budi@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		if _, err := lockout.RecordFailure(ctx, "budi@example.com"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if locked, _ := lockout.LockedFor(ctx, "budi@example.com"); locked > time.Minute {
+		t.Errorf("locked for %v after reset, want base lock", locked)
+	}
+}
+
+func TestLockoutAttemptWindow(t *testing.T) {
+	ctx := t.Context()
+	mr, rdb := newTestRedis(t)
+	lockout := NewLockoutRepository(nil, rdb, models.LockoutRules{
+		MaxAttempts:   3,
+		AttemptWindow: time.Minute,
+		BaseLock:      time.Minute,
+		MaxLock:       time.Hour,
+	})
+
+	// kegagalan di luar window tidak dihitung
+	for range 2 {
+		if lock, err := lockout.RecordFailure(ctx, "budi@example.com"); err != nil || lock != 0 {
+			t.Fatalf("locked for %v (err %v) before max attempts", lock, err)
+		}
+	}
+	mr.FastForward(time.Minute + time.Second)
+	if lock, err := lockout.RecordFailure(ctx, "budi@example.com"); err != nil || lock != 0 {
+		t.Errorf("failures outside the window counted: locked for %v (err %v)", lock, err)
+	}
+
+	// email lain tidak ikut terkunci
+	for range 3 {
+		if _, err := lockout.RecordFailure(ctx, "andi@example.com"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if locked, _ := lockout.LockedFor(ctx, "budi@example.com"); locked != 0 {
+		t.Errorf("budi locked for %v by failures of another email", locked)
+	}
+}
+
+func TestLockoutSkipsRedisWhileDown(t *testing.T) {
+	ctx := t.Context()
+	mr, rdb := newTestRedis(t)
+	lockout := NewLockoutRepository(nil, rdb, models.LockoutRules{MaxAttempts: 3, AttemptWindow: time.Minute, BaseLock: time.Minute, MaxLock: time.Hour})
+
+	mr.Close()
+	for range 3 {
+		if _, err := lockout.RecordFailure(ctx, "budi@example.com"); err == nil {
+			t.Fatal("record failure succeeded while redis is down")
+		}
+	}
+	// setelah breaker open login tidak lagi menunggu Redis
+	if _, err := lockout.LockedFor(ctx, "budi@example.com"); !errors.Is(err, utils.ErrRedisUnavailable) {
+		t.Errorf("LockedFor: err %v, want %v", err, utils.ErrRedisUnavailable)
+	}
+	if err := lockout.Reset(ctx, "budi@example.com"); !errors.Is(err, utils.ErrRedisUnavailable) {
+		t.Errorf("Reset: err %v, want %v", err, utils.ErrRedisUnavailable)
+	}
+}
@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/raihaninkam/tickitz/internals/testutil"
+)
+
+// test yang memakai testutil.Setup berjalan di atas database baru, test lain tetap unit test biasa
+func TestMain(m *testing.M) {
+	testutil.Main(m)
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
@@ -15,8 +14,34 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// tag cache movie: semua list movie (all, upcoming, popular) memakai movieListTag,
+// detail dan jadwal sebuah movie memakai movieTag(id)
+const movieListTag = "movies:list"
+
+func movieTag(movieID int) string {
+	return fmt.Sprintf("movie:%d", movieID)
+}
+
+// invalidateMovieCache menghapus cache list movie dan cache milik movie yang berubah
+func invalidateMovieCache(rctx context.Context, rdb *redis.Client, movieIDs ...int) {
+	tags := []string{movieListTag}
+	for _, movieID := range movieIDs {
+		tags = append(tags, movieTag(movieID))
+	}
+	if err := utils.InvalidateTags(rctx, rdb, tags...); err != nil {
+		utils.Logger(rctx).Warn("movie cache invalidation failed", "error", err)
+	}
+}
+
 // all movie
 
+// AllMovieStore daftar semua movie, diimplementasikan AllMovie
+type AllMovieStore interface {
+	GetAllMovies(ctx context.Context) ([]models.AllMovie, error)
+}
+
+var _ AllMovieStore = (*AllMovie)(nil)
+
 type AllMovie struct {
 	Db  *pgxpool.Pool
 	Rdb *redis.Client
@@ -27,13 +52,15 @@ func NewAllMovies(db *pgxpool.Pool, rdb *redis.Client) *AllMovie {
 }
 
 func (am *AllMovie) GetAllMovies(ctx context.Context) ([]models.AllMovie, error) {
-	redisKey := "all_movies"
-
-	// coba ambil dari cache
-	if cached, ok := utils.GetFromCache[models.AllMovie](ctx, am.Rdb, redisKey); ok {
-		return cached, nil
-	}
+	return utils.Cached(ctx, am.Rdb, "all_movies", utils.CacheOptions{
+		TTL:         15 * time.Minute,
+		StaleTTL:    5 * time.Minute,
+		NegativeTTL: 30 * time.Second,
+		Tags:        []string{movieListTag},
+	}, am.loadAllMovies)
+}
 
+func (am *AllMovie) loadAllMovies(ctx context.Context) ([]models.AllMovie, error) {
 	sql := `
 		SELECT 
 			m.id, m.title, m.synopsis, m.duration_minutes, m.release_date, 
@@ -77,17 +104,21 @@ func (am *AllMovie) GetAllMovies(ctx context.Context) ([]models.AllMovie, error)
 	}
 
 	if len(movies) == 0 {
-		return nil, errors.New("no movies found")
+		return nil, utils.Negative(errors.New("no movies found"))
 	}
 
-	// simpan ke cache 15 menit
-	utils.SetToCache(ctx, am.Rdb, redisKey, movies, 15*time.Minute)
-
 	return movies, nil
 }
 
 // upcoming movie
 
+// UpcomingMovieStore movie yang akan tayang, diimplementasikan UpcomingMovie
+type UpcomingMovieStore interface {
+	GetUpcomingMovies(ctx context.Context) ([]models.UpcomingMovie, error)
+}
+
+var _ UpcomingMovieStore = (*UpcomingMovie)(nil)
+
 type UpcomingMovie struct {
 	Db  *pgxpool.Pool
 	Rdb *redis.Client
@@ -98,12 +129,15 @@ func NewUpcomingMovie(db *pgxpool.Pool, rdb *redis.Client) *UpcomingMovie {
 }
 
 func (u *UpcomingMovie) GetUpcomingMovies(ctx context.Context) ([]models.UpcomingMovie, error) {
-	redisKey := "upcoming_movies"
-
-	if cached, ok := utils.GetFromCache[models.UpcomingMovie](ctx, u.Rdb, redisKey); ok {
-		return cached, nil
-	}
+	return utils.Cached(ctx, u.Rdb, "upcoming_movies", utils.CacheOptions{
+		TTL:         24 * time.Hour,
+		StaleTTL:    time.Hour,
+		NegativeTTL: time.Minute,
+		Tags:        []string{movieListTag},
+	}, u.loadUpcomingMovies)
+}
 
+func (u *UpcomingMovie) loadUpcomingMovies(ctx context.Context) ([]models.UpcomingMovie, error) {
 	sql := `
 		SELECT m.id, m.title, m.directors_id, d.name as director_name,
 		       m.rating, m.synopsis, m.duration_minutes, 
@@ -141,16 +175,21 @@ func (u *UpcomingMovie) GetUpcomingMovies(ctx context.Context) ([]models.Upcomin
 	}
 
 	if len(movies) == 0 {
-		return nil, errors.New("no upcoming movies found")
+		return nil, utils.Negative(errors.New("no upcoming movies found"))
 	}
 
-	utils.SetToCache(ctx, u.Rdb, redisKey, movies, 24*time.Hour)
-
 	return movies, nil
 }
 
 // get popular movie
 
+// PopularMovieStore movie terpopuler, diimplementasikan PopularMovie
+type PopularMovieStore interface {
+	GetPopularMovies(ctx context.Context) ([]models.PopularMovie, error)
+}
+
+var _ PopularMovieStore = (*PopularMovie)(nil)
+
 type PopularMovie struct {
 	Db  *pgxpool.Pool
 	Rdb *redis.Client
@@ -161,12 +200,15 @@ func NewPopularMovie(db *pgxpool.Pool, rdb *redis.Client) *PopularMovie {
 }
 
 func (u *PopularMovie) GetPopularMovies(ctx context.Context) ([]models.PopularMovie, error) {
-	redisKey := "popular_movies"
-
-	if cached, ok := utils.GetFromCache[models.PopularMovie](ctx, u.Rdb, redisKey); ok {
-		return cached, nil
-	}
+	return utils.Cached(ctx, u.Rdb, "popular_movies", utils.CacheOptions{
+		TTL:         10 * time.Minute,
+		StaleTTL:    5 * time.Minute,
+		NegativeTTL: 30 * time.Second,
+		Tags:        []string{movieListTag},
+	}, u.loadPopularMovies)
+}
 
+func (u *PopularMovie) loadPopularMovies(ctx context.Context) ([]models.PopularMovie, error) {
 	sql := `
         SELECT 
             m.id,
@@ -179,10 +221,13 @@ func (u *PopularMovie) GetPopularMovies(ctx context.Context) ([]models.PopularMo
             m.release_date,
             m.poster_image,
             m.bg_path,
-            m.rating as avg_rating
+            ms.avg_rating,
+            COALESCE(ms.review_count, 0),
+            COALESCE(ms.tickets_sold, 0)
         FROM movies m
         JOIN directors d ON m.directors_id = d.id
-        ORDER BY m.rating DESC NULLS LAST
+        LEFT JOIN movie_stats ms ON ms.movies_id = m.id
+        ORDER BY ms.popularity_score DESC NULLS LAST, ms.bayes_rating DESC NULLS LAST, m.rating DESC NULLS LAST
     `
 
 	rows, err := u.Db.Query(ctx, sql)
@@ -206,6 +251,8 @@ func (u *PopularMovie) GetPopularMovies(ctx context.Context) ([]models.PopularMo
 			&pmv.PosterImage,
 			&pmv.BgPath,
 			&pmv.AvgRating,
+			&pmv.ReviewCount,
+			&pmv.TicketsSold,
 		); err != nil {
 			return nil, err
 		}
@@ -213,16 +260,21 @@ func (u *PopularMovie) GetPopularMovies(ctx context.Context) ([]models.PopularMo
 	}
 
 	if len(popularMovies) == 0 {
-		return nil, errors.New("no popular movies found")
+		return nil, utils.Negative(errors.New("no popular movies found"))
 	}
 
-	utils.SetToCache(ctx, u.Rdb, redisKey, popularMovies, 10*time.Minute)
-
 	return popularMovies, nil
 }
 
 // movie filter
 
+// MovieFilterStore daftar movie dengan filter & pagination, diimplementasikan MovieFilter
+type MovieFilterStore interface {
+	GetMoviesWithFilter(ctx context.Context, title string, genres []string, offset, limit int) ([]models.MovieFilter, int, error)
+}
+
+var _ MovieFilterStore = (*MovieFilter)(nil)
+
 type MovieFilter struct {
 	Db *pgxpool.Pool
 }
@@ -370,16 +422,35 @@ func (mf *MovieFilter) GetMoviesWithFilter(ctx context.Context, title string, ge
 
 // movie detail
 
+// MovieDetailStore detail satu movie, diimplementasikan MovieDetail
+type MovieDetailStore interface {
+	GetDetailMovie(rctx context.Context, movieID int) (models.MovieDetail, error)
+}
+
+var _ MovieDetailStore = (*MovieDetail)(nil)
+
 type MovieDetail struct {
-	db *pgxpool.Pool
+	db  *pgxpool.Pool
+	rdb *redis.Client
 }
 
-func NewMovieDetail(db *pgxpool.Pool) *MovieDetail {
-	return &MovieDetail{db: db}
+func NewMovieDetail(db *pgxpool.Pool, rdb *redis.Client) *MovieDetail {
+	return &MovieDetail{db: db, rdb: rdb}
 }
 
 // GetDetailMovie mengambil detail movie berdasarkan ID dengan director, genres, dan casts
 func (m *MovieDetail) GetDetailMovie(rctx context.Context, movieID int) (models.MovieDetail, error) {
+	return utils.Cached(rctx, m.rdb, fmt.Sprintf("movie_detail:%d", movieID), utils.CacheOptions{
+		TTL:         time.Hour,
+		StaleTTL:    10 * time.Minute,
+		NegativeTTL: 30 * time.Second,
+		Tags:        []string{movieTag(movieID)},
+	}, func(rctx context.Context) (models.MovieDetail, error) {
+		return m.loadDetailMovie(rctx, movieID)
+	})
+}
+
+func (m *MovieDetail) loadDetailMovie(rctx context.Context, movieID int) (models.MovieDetail, error) {
 	sql := `SELECT 
     m.id,
     m.title,
@@ -417,9 +488,9 @@ GROUP BY m.id, d.name
 		&movie.Casts,
 	); err != nil {
 		if err == pgx.ErrNoRows {
-			return models.MovieDetail{}, errors.New("movie not found")
+			return models.MovieDetail{}, utils.Negative(errors.New("movie not found"))
 		}
-		log.Println("Internal Server Error.\nCz: ", err.Error())
+		utils.Logger(rctx).Error("get movie detail failed", "error", err)
 		return models.MovieDetail{}, err
 	}
 	return movie, nil
@@ -427,15 +498,34 @@ GROUP BY m.id, d.name
 
 // movie schedule
 
+// ScheduleStore jadwal tayang per movie, diimplementasikan Schedule
+type ScheduleStore interface {
+	GetSchedulesByMovieID(ctx context.Context, movieID int) ([]models.MovieSchedule, error)
+}
+
+var _ ScheduleStore = (*Schedule)(nil)
+
 type Schedule struct {
-	Db *pgxpool.Pool
+	Db  *pgxpool.Pool
+	Rdb *redis.Client
 }
 
-func NewSchedule(db *pgxpool.Pool) *Schedule {
-	return &Schedule{Db: db}
+func NewSchedule(db *pgxpool.Pool, rdb *redis.Client) *Schedule {
+	return &Schedule{Db: db, Rdb: rdb}
 }
 
 func (s *Schedule) GetSchedulesByMovieID(ctx context.Context, movieID int) ([]models.MovieSchedule, error) {
+	return utils.Cached(ctx, s.Rdb, fmt.Sprintf("movie_schedules:%d", movieID), utils.CacheOptions{
+		TTL:         5 * time.Minute,
+		StaleTTL:    time.Minute,
+		NegativeTTL: 30 * time.Second,
+		Tags:        []string{movieTag(movieID)},
+	}, func(ctx context.Context) ([]models.MovieSchedule, error) {
+		return s.loadSchedules(ctx, movieID)
+	})
+}
+
+func (s *Schedule) loadSchedules(ctx context.Context, movieID int) ([]models.MovieSchedule, error) {
 	sql := `
         SELECT 
             ns.id,
@@ -450,7 +540,7 @@ func (s *Schedule) GetSchedulesByMovieID(ctx context.Context, movieID int) ([]mo
         JOIN movies m ON ns.movie_id = m.id
         JOIN location l ON ns.location_id = l.id
         JOIN cinemas c ON ns.cinemas_id = c.id
-        WHERE ns.movie_id = $1
+        WHERE ns.movie_id = $1 AND ns.status = 'scheduled'
         ORDER BY ns.date, ns.time
     `
 
@@ -479,7 +569,7 @@ func (s *Schedule) GetSchedulesByMovieID(ctx context.Context, movieID int) ([]mo
 	}
 
 	if len(schedules) == 0 {
-		return nil, errors.New("no schedules found for this movie")
+		return nil, utils.Negative(errors.New("no schedules found for this movie"))
 	}
 
 	return schedules, nil
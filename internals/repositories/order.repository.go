@@ -2,19 +2,29 @@ package repositories
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/hex"
 	"errors"
-	"log"
+	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/utils"
+	"github.com/raihaninkam/tickitz/pkg"
+	"github.com/redis/go-redis/v9"
 )
 
 // availseat
 
+// SeatsStore status kursi per jadwal tayang, diimplementasikan SeatsRepository
+type SeatsStore interface {
+	GetAvailableSeats(rctx context.Context, nowShowingID, userID int) ([]models.AvailSeat, error)
+}
+
+var _ SeatsStore = (*SeatsRepository)(nil)
+
 type SeatsRepository struct {
 	db *pgxpool.Pool
 }
@@ -24,25 +34,22 @@ func NewSeatsRepository(db *pgxpool.Pool) *SeatsRepository {
 }
 
 // GetAvailableSeats mengambil semua kursi yang statusnya 'available' untuk now_showing_id tertentu
-func (s *SeatsRepository) GetAvailableSeats(rctx context.Context, nowShowingID int) ([]models.AvailSeat, error) {
-	log.Printf("=== DYNAMIC SEATS GENERATION ===")
-	log.Printf("Generating seats for now_showing_id: %d", nowShowingID)
+// userID dipakai untuk menandai kursi yang sedang ditahan oleh user tersebut
+func (s *SeatsRepository) GetAvailableSeats(rctx context.Context, nowShowingID, userID int) ([]models.AvailSeat, error) {
+	logger := utils.Logger(rctx).With("now_showing_id", nowShowingID)
 
 	// Step 1: Ambil cinema_id dari now_showing
 	var cinemaID int
-	getCinemaSQL := "SELECT cinemas_id FROM now_showing WHERE id = $1"
+	getCinemaSQL := "SELECT cinemas_id FROM now_showing WHERE id = $1 AND status = 'scheduled'"
 	err := s.db.QueryRow(rctx, getCinemaSQL, nowShowingID).Scan(&cinemaID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			log.Printf("now_showing_id %d not found", nowShowingID)
 			return nil, errors.New("showing not found")
 		}
-		log.Printf("Error getting cinema_id: %v", err)
+		logger.Error("get showing cinema failed", "error", err)
 		return nil, err
 	}
 
-	log.Printf("Cinema ID for now_showing %d: %d", nowShowingID, cinemaID)
-
 	// Step 2: Generate semua seats virtual dari master seats table
 	// dan check status dari showing_seats jika ada booking
 	sql := `
@@ -50,22 +57,21 @@ func (s *SeatsRepository) GetAvailableSeats(rctx context.Context, nowShowingID i
 		CONCAT(s.row, s.seat_number) as seat_id,
 		$1 as showing_id,
 		COALESCE(ss.status = 'sold', false) as is_sold,
-		CASE 
-			WHEN s.row = 'F' AND s.seat_number BETWEEN 7 AND 10 THEN true 
-			ELSE false 
-		END as is_love_nest
+		s.seat_class = 'love_nest' as is_love_nest,
+		s.seat_class,
+		s.is_disabled,
+		s.aisle_after,
+		COALESCE(ss.status = 'held' AND ss.held_until > NOW(), false) as is_held,
+		COALESCE(ss.status = 'held' AND ss.held_until > NOW() AND ss.user_id = $3, false) as held_by_me
 	FROM seats s
 	LEFT JOIN showing_seats ss ON (ss.seat_id = s.id AND ss.now_showing_id = $1)
-	WHERE s.cinemas_id = $2
-	ORDER BY s.row, s.seat_number;
+	WHERE s.cinemas_id = $2 AND s.deleted_at IS NULL
+	ORDER BY length(s.row), s.row, s.seat_number;
 	`
 
-	log.Printf("Executing SQL: %s", sql)
-	log.Printf("Parameters: nowShowingID=%d, cinemaID=%d", nowShowingID, cinemaID)
-
-	rows, err := s.db.Query(rctx, sql, nowShowingID, cinemaID)
+	rows, err := s.db.Query(rctx, sql, nowShowingID, cinemaID, userID)
 	if err != nil {
-		log.Printf("SQL Query Error: %v", err)
+		logger.Error("query seats failed", "cinema_id", cinemaID, "error", err)
 		return nil, err
 	}
 	defer rows.Close()
@@ -73,96 +79,83 @@ func (s *SeatsRepository) GetAvailableSeats(rctx context.Context, nowShowingID i
 	var availableSeats []models.AvailSeat
 	for rows.Next() {
 		var seat models.AvailSeat
-		if err := rows.Scan(&seat.SeatID, &seat.ShowingId, &seat.IsSold, &seat.IsLoveNest); err != nil {
-			log.Printf("Row Scan Error: %v", err)
+		if err := rows.Scan(&seat.SeatID, &seat.ShowingId, &seat.IsSold, &seat.IsLoveNest, &seat.SeatClass, &seat.IsDisabled, &seat.AisleAfter, &seat.IsHeld, &seat.HeldByMe); err != nil {
+			logger.Error("scan seat failed", "error", err)
 			return nil, err
 		}
 		availableSeats = append(availableSeats, seat)
 	}
 
-	log.Printf("Total seats generated: %d", len(availableSeats))
-
 	if len(availableSeats) == 0 {
-		log.Printf("No seats found for cinema_id: %d", cinemaID)
+		logger.Warn("no seats found for cinema", "cinema_id", cinemaID)
 		return nil, errors.New("no seats found for this cinema")
 	}
 
-	// Debug summary
-	soldCount := 0
-	loveNestCount := 0
-	for _, seat := range availableSeats {
-		if seat.IsSold {
-			soldCount++
-		}
-		if seat.IsLoveNest {
-			loveNestCount++
+	if logger.Enabled(rctx, slog.LevelDebug) {
+		soldCount := 0
+		for _, seat := range availableSeats {
+			if seat.IsSold {
+				soldCount++
+			}
 		}
+		logger.Debug("seats generated", "cinema_id", cinemaID, "total", len(availableSeats), "sold", soldCount)
 	}
 
-	log.Printf("Seats summary - Total: %d, Sold: %d, Love Nest: %d, Available: %d",
-		len(availableSeats), soldCount, loveNestCount, len(availableSeats)-soldCount)
-
-	log.Printf("Sample seat: %+v", availableSeats[0])
-	log.Printf("=== END DYNAMIC SEATS GENERATION ===")
-
 	return availableSeats, nil
 }
 
 ///////////////////////////////////////////////////////////////////////////
 
-type OrderRepository struct {
-	db *pgxpool.Pool
+// OrderStore checkout order (kursi, tiket, poin), diimplementasikan OrderRepository
+type OrderStore interface {
+	CreateOrder(rctx context.Context, req models.CreateOrderRequest) (models.CreateOrderResponse, error)
 }
 
-func NewOrderRepository(db *pgxpool.Pool) *OrderRepository {
-	return &OrderRepository{db: db}
+var _ OrderStore = (*OrderRepository)(nil)
+
+type OrderRepository struct {
+	db      *pgxpool.Pool
+	rdb     *redis.Client
+	loyalty models.LoyaltyRules
+	signer  *pkg.TicketSigner
 }
 
-// generateQRCode generates a random QR code string
-func (o *OrderRepository) generateQRCode() string {
-	bytes := make([]byte, 16)
-	rand.Read(bytes)
-	return hex.EncodeToString(bytes)
+func NewOrderRepository(db *pgxpool.Pool, rdb *redis.Client, loyalty models.LoyaltyRules, signer *pkg.TicketSigner) *OrderRepository {
+	return &OrderRepository{db: db, rdb: rdb, loyalty: loyalty, signer: signer}
 }
 
 // CreateOrder creates a new order with all related records in a transaction
 func (o *OrderRepository) CreateOrder(rctx context.Context, req models.CreateOrderRequest) (models.CreateOrderResponse, error) {
-	log.Printf("=== CREATE ORDER START ===")
-	log.Printf("Request: %+v", req)
+	logger := utils.Logger(rctx).With("user_id", req.UsersID, "now_showing_id", req.NowShowingID)
 
 	// Start transaction
 	tx, err := o.db.Begin(rctx)
 	if err != nil {
-		log.Printf("Transaction begin error: %v", err)
 		return models.CreateOrderResponse{}, err
 	}
 	defer tx.Rollback(rctx)
 
 	var response models.CreateOrderResponse
 	var orderID, ticketID int
-	qrCode := o.generateQRCode()
 
 	// Validate user exists
 	var userExists bool
 	userCheckSQL := "SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)"
 	if err := tx.QueryRow(rctx, userCheckSQL, req.UsersID).Scan(&userExists); err != nil {
-		log.Printf("User check error: %v", err)
 		return models.CreateOrderResponse{}, err
 	}
 	if !userExists {
-		log.Printf("User not found: %d", req.UsersID)
 		return models.CreateOrderResponse{}, errors.New("user not found")
 	}
 
 	// Validate showing exists and get cinema_id
 	var showingExists bool
 	var actualCinemaID int
-	showingCheckSQL := "SELECT EXISTS(SELECT 1 FROM now_showing WHERE id = $1), cinemas_id FROM now_showing WHERE id = $1"
+	showingCheckSQL := "SELECT EXISTS(SELECT 1 FROM now_showing WHERE id = $1), cinemas_id FROM now_showing WHERE id = $1 AND status = 'scheduled'"
 	if err := tx.QueryRow(rctx, showingCheckSQL, req.NowShowingID).Scan(&showingExists, &actualCinemaID); err != nil {
 		if err == pgx.ErrNoRows {
 			return models.CreateOrderResponse{}, errors.New("showing not found")
 		}
-		log.Printf("Showing check error: %v", err)
 		return models.CreateOrderResponse{}, err
 	}
 	if !showingExists {
@@ -171,7 +164,7 @@ func (o *OrderRepository) CreateOrder(rctx context.Context, req models.CreateOrd
 
 	// Validate cinema matches
 	if actualCinemaID != req.CinemaID {
-		log.Printf("Cinema mismatch: request=%d, actual=%d", req.CinemaID, actualCinemaID)
+		logger.Debug("cinema mismatch", "cinema_id", req.CinemaID, "actual_cinema_id", actualCinemaID)
 		return models.CreateOrderResponse{}, errors.New("cinema not found")
 	}
 
@@ -179,141 +172,413 @@ func (o *OrderRepository) CreateOrder(rctx context.Context, req models.CreateOrd
 	var paymentExists bool
 	paymentCheckSQL := "SELECT EXISTS(SELECT 1 FROM payment WHERE id = $1)"
 	if err := tx.QueryRow(rctx, paymentCheckSQL, req.PaymentID).Scan(&paymentExists); err != nil {
-		log.Printf("Payment check error: %v", err)
 		return models.CreateOrderResponse{}, err
 	}
 	if !paymentExists {
 		return models.CreateOrderResponse{}, errors.New("payment method not found")
 	}
 
-	log.Printf("Validations passed. Creating order...")
+	// Hitung harga di server, harga dari client hanya dipakai untuk konfirmasi
+	quote, err := quoteShowingPrice(rctx, tx, req.NowShowingID, req.SeatsMap)
+	if err != nil {
+		return models.CreateOrderResponse{}, err
+	}
+	if !confirmsQuote(req.Price, quote.Total) {
+		logger.Debug("price mismatch", "price", req.Price, "computed", quote.Total)
+		return models.CreateOrderResponse{}, errors.New("price mismatch")
+	}
+	price := quote.Total
+
+	// Poin loyalty sebagai potongan harga
+	discount := 0
+	if req.RedeemPoints > 0 {
+		balance, err := lockUserPoin(rctx, tx, req.UsersID)
+		if err != nil {
+			return models.CreateOrderResponse{}, err
+		}
+		if balance < req.RedeemPoints {
+			return models.CreateOrderResponse{}, errors.New("insufficient points")
+		}
+		discount = req.RedeemPoints * o.loyalty.RedeemValue
+		if discount > price {
+			return models.CreateOrderResponse{}, errors.New("points exceed order total")
+		}
+		price -= discount
+	}
 
 	// 1. Insert into ORDERS
-	orderSQL := `INSERT INTO orders (users_id, price, payment_id, now_showing_id, cinemas_id, created_at, updated_at)
-				 VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+	orderSQL := `INSERT INTO orders (users_id, price, payment_id, now_showing_id, cinemas_id, poin_redeemed, discount, created_at, updated_at)
+				 VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
 				 RETURNING id`
 
-	if err := tx.QueryRow(rctx, orderSQL, req.UsersID, req.Price, req.PaymentID, req.NowShowingID, req.CinemaID).Scan(&orderID); err != nil {
-		log.Printf("Order insert error: %v", err)
+	if err := tx.QueryRow(rctx, orderSQL, req.UsersID, price, req.PaymentID, req.NowShowingID, req.CinemaID, req.RedeemPoints, discount).Scan(&orderID); err != nil {
 		return models.CreateOrderResponse{}, err
 	}
-	log.Printf("Order created with ID: %d", orderID)
 
-	// 2. Create ticket
-	ticketSQL := `INSERT INTO ticket (qr_code, created_at, updated_at)
-				  VALUES ($1, NOW(), NOW())
+	if req.RedeemPoints > 0 {
+		if _, err := addPointsEntry(rctx, tx, pointsEntry{
+			UserID:      req.UsersID,
+			OrderID:     &orderID,
+			EntryType:   "redeem",
+			Points:      -req.RedeemPoints,
+			Description: fmt.Sprintf("Potongan harga order #%d", orderID),
+		}); err != nil {
+			return models.CreateOrderResponse{}, err
+		}
+		if _, err := syncUserPoin(rctx, tx, req.UsersID); err != nil {
+			return models.CreateOrderResponse{}, err
+		}
+
+		// seluruh harga tertutup poin, tidak perlu lewat payment gateway
+		if price == 0 {
+			if _, err := tx.Exec(rctx, `UPDATE orders SET "isPaid" = true, status = 'paid' WHERE id = $1`, orderID); err != nil {
+				return models.CreateOrderResponse{}, err
+			}
+		}
+	}
+
+	// 2. Create ticket, kode QR ditandatangani setelah kursi tersimpan
+	ticketSQL := `INSERT INTO ticket (created_at, updated_at)
+				  VALUES (NOW(), NOW())
 				  RETURNING id`
 
-	if err := tx.QueryRow(rctx, ticketSQL, qrCode).Scan(&ticketID); err != nil {
-		log.Printf("Ticket insert error: %v", err)
+	if err := tx.QueryRow(rctx, ticketSQL).Scan(&ticketID); err != nil {
 		return models.CreateOrderResponse{}, err
 	}
-	log.Printf("Ticket created with ID: %d, QR: %s", ticketID, qrCode)
 
 	// 3. Link ticket to order
 	ordersTicketSQL := `INSERT INTO orders_ticket (orders_id, ticket_id, created_at, updated_at)
 						VALUES ($1, $2, NOW(), NOW())`
 
 	if _, err := tx.Exec(rctx, ordersTicketSQL, orderID, ticketID); err != nil {
-		log.Printf("Orders-ticket link error: %v", err)
 		return models.CreateOrderResponse{}, err
 	}
-	log.Printf("Order-ticket relationship created")
 
 	// 4. Handle showing_seats - INSERT or UPDATE based on existence
-	log.Printf("Processing %d seats: %v", len(req.SeatsMap), req.SeatsMap)
-
-	for i, seatIdentifier := range req.SeatsMap {
-		log.Printf("Processing seat %d/%d: %s", i+1, len(req.SeatsMap), seatIdentifier)
-
+	soldKeys := make([]string, 0, len(req.SeatsMap))
+	for _, seatIdentifier := range req.SeatsMap {
 		// Get the actual seat ID from seats table based on seat identifier (like "A1")
 		var actualSeatID int
-		getSeatIDSQL := `SELECT id FROM seats WHERE CONCAT(row, seat_number) = $1 AND cinemas_id = $2`
+		getSeatIDSQL := `SELECT id FROM seats WHERE CONCAT(row, seat_number) = $1 AND cinemas_id = $2 AND deleted_at IS NULL AND is_disabled = false`
 
 		if err := tx.QueryRow(rctx, getSeatIDSQL, seatIdentifier, req.CinemaID).Scan(&actualSeatID); err != nil {
 			if err == pgx.ErrNoRows {
-				log.Printf("Seat %s not found in cinema %d", seatIdentifier, req.CinemaID)
+				logger.Debug("seat not found", "seat", seatIdentifier, "cinema_id", req.CinemaID)
 				return models.CreateOrderResponse{}, errors.New("invalid seat selection")
 			}
-			log.Printf("Error getting seat ID for %s: %v", seatIdentifier, err)
 			return models.CreateOrderResponse{}, err
 		}
-		log.Printf("Seat %s mapped to ID: %d", seatIdentifier, actualSeatID)
 
 		// Check if record already exists in showing_seats
 		var existingRecordCount int
 		checkSQL := `SELECT COUNT(*) FROM showing_seats WHERE now_showing_id = $1 AND seat_id = $2`
 
 		if err := tx.QueryRow(rctx, checkSQL, req.NowShowingID, actualSeatID).Scan(&existingRecordCount); err != nil {
-			log.Printf("Error checking existing showing_seats for seat %s: %v", seatIdentifier, err)
 			return models.CreateOrderResponse{}, err
 		}
 
 		if existingRecordCount > 0 {
-			log.Printf("Seat %s already has record in showing_seats, checking status", seatIdentifier)
-
-			// Record exists, check if already sold
+			// Record exists, check if already sold or held by someone else
 			var currentStatus string
 			var currentUserID *int
-			statusCheckSQL := `SELECT status, user_id FROM showing_seats WHERE now_showing_id = $1 AND seat_id = $2`
+			var heldUntil *time.Time
+			statusCheckSQL := `SELECT status, user_id, held_until FROM showing_seats WHERE now_showing_id = $1 AND seat_id = $2 FOR UPDATE`
 
-			if err := tx.QueryRow(rctx, statusCheckSQL, req.NowShowingID, actualSeatID).Scan(&currentStatus, &currentUserID); err != nil {
-				log.Printf("Error checking seat status for %s: %v", seatIdentifier, err)
+			if err := tx.QueryRow(rctx, statusCheckSQL, req.NowShowingID, actualSeatID).Scan(&currentStatus, &currentUserID, &heldUntil); err != nil {
 				return models.CreateOrderResponse{}, err
 			}
 
 			if currentStatus == "sold" {
-				log.Printf("Seat %s is already sold to user %v", seatIdentifier, currentUserID)
+				logger.Debug("seat already sold", "seat", seatIdentifier)
+				return models.CreateOrderResponse{}, errors.New("seat not available")
+			}
+
+			if isHeldByOther(currentStatus, currentUserID, heldUntil, req.UsersID) {
+				logger.Debug("seat held by another user", "seat", seatIdentifier, "held_until", heldUntil)
 				return models.CreateOrderResponse{}, errors.New("seat not available")
 			}
 
 			// Update existing record
 			updateSQL := `UPDATE showing_seats 
-						  SET status = 'sold', user_id = $1, updated_at = NOW()
-						  WHERE now_showing_id = $2 AND seat_id = $3`
+						  SET status = 'sold', user_id = $1, orders_id = $2, held_until = NULL, updated_at = NOW()
+						  WHERE now_showing_id = $3 AND seat_id = $4`
 
-			if _, err := tx.Exec(rctx, updateSQL, req.UsersID, req.NowShowingID, actualSeatID); err != nil {
-				log.Printf("Error updating showing_seats for seat %s: %v", seatIdentifier, err)
+			if _, err := tx.Exec(rctx, updateSQL, req.UsersID, orderID, req.NowShowingID, actualSeatID); err != nil {
 				return models.CreateOrderResponse{}, err
 			}
-			log.Printf("Updated seat %s status to sold", seatIdentifier)
 		} else {
-			log.Printf("Seat %s has no record in showing_seats, inserting new", seatIdentifier)
-
 			// Record doesn't exist, insert new record
-			insertSQL := `INSERT INTO showing_seats (now_showing_id, seat_id, status, user_id, created_at, updated_at)
-						  VALUES ($1, $2, 'sold', $3, NOW(), NOW())`
+			insertSQL := `INSERT INTO showing_seats (now_showing_id, seat_id, status, user_id, orders_id, created_at, updated_at)
+						  VALUES ($1, $2, 'sold', $3, $4, NOW(), NOW())`
 
-			if _, err := tx.Exec(rctx, insertSQL, req.NowShowingID, actualSeatID, req.UsersID); err != nil {
-				log.Printf("Error inserting into showing_seats for seat %s: %v", seatIdentifier, err)
-				return models.CreateOrderResponse{}, err
+			if _, err := tx.Exec(rctx, insertSQL, req.NowShowingID, actualSeatID, req.UsersID, orderID); err != nil {
+				return models.CreateOrderResponse{}, seatWriteError(err)
 			}
-			log.Printf("Inserted new record for seat %s", seatIdentifier)
 		}
+		soldKeys = append(soldKeys, seatHoldKey(req.NowShowingID, actualSeatID))
+	}
 
-		log.Printf("Successfully processed seat %s (ID: %d) for user %d", seatIdentifier, actualSeatID, req.UsersID)
+	// 5. Sign ticket QR code (order, jadwal, kursi, masa berlaku)
+	qrCode, err := signOrderTicket(rctx, tx, o.signer, ticketID, orderID)
+	if err != nil {
+		return models.CreateOrderResponse{}, err
 	}
 
 	// Commit transaction
 	if err := tx.Commit(rctx); err != nil {
-		log.Printf("Transaction commit error: %v", err)
 		return models.CreateOrderResponse{}, err
 	}
-	log.Printf("Transaction committed successfully")
+	deleteSeatHoldKeys(rctx, o.rdb, soldKeys)
+	logger.Debug("order committed", "order_id", orderID, "ticket_id", ticketID, "seats", len(req.SeatsMap), "price", price, "discount", discount)
 
 	// Prepare response
 	response = models.CreateOrderResponse{
-		ID:        orderID,
-		UsersID:   req.UsersID,
-		Price:     req.Price,
-		QRCode:    qrCode,
-		TicketID:  ticketID,
-		SeatsMap:  req.SeatsMap,
-		CreatedAt: time.Now(),
+		ID:           orderID,
+		UsersID:      req.UsersID,
+		Price:        float64(price),
+		Discount:     discount,
+		PoinRedeemed: req.RedeemPoints,
+		QRCode:       qrCode,
+		TicketID:     ticketID,
+		SeatsMap:     req.SeatsMap,
+		CreatedAt:    time.Now(),
 	}
 
-	log.Printf("=== CREATE ORDER SUCCESS ===")
-	log.Printf("Response: %+v", response)
-
 	return response, nil
 }
+
+///////////////////////////////////////////////////////////////////////////
+
+// seat hold
+
+// SeatHoldStore penahanan kursi sebelum checkout, diimplementasikan SeatHoldRepository
+type SeatHoldStore interface {
+	HoldSeats(rctx context.Context, userID int, req models.SeatHoldRequest) (models.SeatHoldResponse, error)
+	ReleaseHolds(rctx context.Context, userID, nowShowingID int) (int, error)
+}
+
+var _ SeatHoldStore = (*SeatHoldRepository)(nil)
+
+type SeatHoldRepository struct {
+	db           *pgxpool.Pool
+	rdb          *redis.Client
+	holdDuration time.Duration
+}
+
+func NewSeatHoldRepository(db *pgxpool.Pool, rdb *redis.Client, holdDuration time.Duration) *SeatHoldRepository {
+	return &SeatHoldRepository{db: db, rdb: rdb, holdDuration: holdDuration}
+}
+
+func seatHoldKey(nowShowingID, seatID int) string {
+	return fmt.Sprintf("seat_hold:%d:%d", nowShowingID, seatID)
+}
+
+// isHeldByOther true jika kursi masih ditahan (belum expired) oleh user lain
+func isHeldByOther(status string, holderID *int, heldUntil *time.Time, userID int) bool {
+	if status != "held" || heldUntil == nil || !heldUntil.After(time.Now()) {
+		return false
+	}
+	return holderID == nil || *holderID != userID
+}
+
+// HoldSeats menahan kursi untuk user selama holdDuration.
+// Redis (SET NX + TTL) dipakai sebagai kunci cepat, baris showing_seats tetap menjadi sumber kebenaran
+func (h *SeatHoldRepository) HoldSeats(rctx context.Context, userID int, req models.SeatHoldRequest) (models.SeatHoldResponse, error) {
+	tx, err := h.db.Begin(rctx)
+	if err != nil {
+		return models.SeatHoldResponse{}, err
+	}
+	defer tx.Rollback(rctx)
+
+	var cinemaID int
+	if err := tx.QueryRow(rctx, "SELECT cinemas_id FROM now_showing WHERE id = $1 AND status = 'scheduled'", req.NowShowingID).Scan(&cinemaID); err != nil {
+		if err == pgx.ErrNoRows {
+			return models.SeatHoldResponse{}, errors.New("showing not found")
+		}
+		return models.SeatHoldResponse{}, err
+	}
+
+	heldUntil := time.Now().Add(h.holdDuration)
+	var acquiredKeys []string
+	committed := false
+	// lepas kunci redis yang sudah diambil jika transaksi gagal
+	defer func() {
+		if !committed && len(acquiredKeys) > 0 {
+			deleteSeatHoldKeys(context.WithoutCancel(rctx), h.rdb, acquiredKeys)
+		}
+	}()
+
+	seen := make(map[string]bool, len(req.SeatsMap))
+	for _, seatIdentifier := range req.SeatsMap {
+		if seen[seatIdentifier] {
+			return models.SeatHoldResponse{}, errors.New("invalid seat selection")
+		}
+		seen[seatIdentifier] = true
+
+		var seatID int
+		getSeatIDSQL := `SELECT id FROM seats WHERE CONCAT(row, seat_number) = $1 AND cinemas_id = $2 AND deleted_at IS NULL AND is_disabled = false`
+		if err := tx.QueryRow(rctx, getSeatIDSQL, seatIdentifier, cinemaID).Scan(&seatID); err != nil {
+			if err == pgx.ErrNoRows {
+				return models.SeatHoldResponse{}, errors.New("invalid seat selection")
+			}
+			return models.SeatHoldResponse{}, err
+		}
+
+		var status string
+		var holderID *int
+		var currentHeldUntil *time.Time
+		exists := true
+		statusSQL := `SELECT status, user_id, held_until FROM showing_seats WHERE now_showing_id = $1 AND seat_id = $2 FOR UPDATE`
+		if err := tx.QueryRow(rctx, statusSQL, req.NowShowingID, seatID).Scan(&status, &holderID, &currentHeldUntil); err != nil {
+			if err != pgx.ErrNoRows {
+				return models.SeatHoldResponse{}, err
+			}
+			exists = false
+		}
+
+		if status == "sold" || isHeldByOther(status, holderID, currentHeldUntil, userID) {
+			return models.SeatHoldResponse{}, errors.New("seat not available")
+		}
+
+		// kunci redis, jika redis bermasalah tetap lanjut dengan DB
+		key := seatHoldKey(req.NowShowingID, seatID)
+		acquired, heldByOther := false, false
+		err := utils.WithRedis(rctx, h.rdb, func() error {
+			ok, err := h.rdb.SetNX(rctx, key, userID, h.holdDuration).Result()
+			if err != nil || ok {
+				acquired = ok
+				return err
+			}
+			holder, err := h.rdb.Get(rctx, key).Int()
+			if err != nil && err != redis.Nil {
+				return err
+			}
+			if err == nil && holder != userID {
+				heldByOther = true
+				return nil
+			}
+			return h.rdb.Expire(rctx, key, h.holdDuration).Err()
+		})
+		switch {
+		case heldByOther:
+			return models.SeatHoldResponse{}, errors.New("seat not available")
+		case acquired:
+			acquiredKeys = append(acquiredKeys, key)
+		case err != nil && err != utils.ErrRedisUnavailable:
+			utils.Logger(rctx).Warn("redis seat hold failed", "error", err)
+		}
+
+		if exists {
+			updateSQL := `UPDATE showing_seats
+						  SET status = 'held', user_id = $1, held_until = $2, updated_at = NOW()
+						  WHERE now_showing_id = $3 AND seat_id = $4`
+			if _, err := tx.Exec(rctx, updateSQL, userID, heldUntil, req.NowShowingID, seatID); err != nil {
+				return models.SeatHoldResponse{}, err
+			}
+		} else {
+			insertSQL := `INSERT INTO showing_seats (now_showing_id, seat_id, status, user_id, held_until, created_at, updated_at)
+						  VALUES ($1, $2, 'held', $3, $4, NOW(), NOW())`
+			if _, err := tx.Exec(rctx, insertSQL, req.NowShowingID, seatID, userID, heldUntil); err != nil {
+				return models.SeatHoldResponse{}, seatWriteError(err)
+			}
+		}
+	}
+
+	if err := tx.Commit(rctx); err != nil {
+		return models.SeatHoldResponse{}, err
+	}
+	committed = true
+
+	return models.SeatHoldResponse{
+		NowShowingID: req.NowShowingID,
+		SeatsMap:     req.SeatsMap,
+		HeldUntil:    heldUntil,
+	}, nil
+}
+
+// ReleaseHolds melepas semua kursi yang ditahan user pada jadwal tayang tertentu
+func (h *SeatHoldRepository) ReleaseHolds(rctx context.Context, userID, nowShowingID int) (int, error) {
+	sql := `UPDATE showing_seats
+			SET status = 'available', user_id = NULL, held_until = NULL, updated_at = NOW()
+			WHERE now_showing_id = $1 AND user_id = $2 AND status = 'held'
+			RETURNING now_showing_id, seat_id`
+
+	return h.releaseWhere(rctx, sql, nowShowingID, userID)
+}
+
+// ReleaseExpiredHolds mengembalikan kursi yang masa tahannya sudah habis
+func (h *SeatHoldRepository) ReleaseExpiredHolds(rctx context.Context) (int, error) {
+	sql := `UPDATE showing_seats
+			SET status = 'available', user_id = NULL, held_until = NULL, updated_at = NOW()
+			WHERE status = 'held' AND held_until <= NOW()
+			RETURNING now_showing_id, seat_id`
+
+	return h.releaseWhere(rctx, sql)
+}
+
+func (h *SeatHoldRepository) releaseWhere(rctx context.Context, sql string, args ...any) (int, error) {
+	rows, err := h.db.Query(rctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var nowShowingID, seatID int
+		if err := rows.Scan(&nowShowingID, &seatID); err != nil {
+			return 0, err
+		}
+		keys = append(keys, seatHoldKey(nowShowingID, seatID))
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	deleteSeatHoldKeys(rctx, h.rdb, keys)
+	return len(keys), nil
+}
+
+// deleteSeatHoldKeys menghapus kunci redis kursi yang sudah terjual atau dilepas,
+// tanpa ini kursi tetap terkunci SETNX sampai TTL hold habis
+func deleteSeatHoldKeys(rctx context.Context, rdb *redis.Client, keys []string) {
+	if rdb == nil || len(keys) == 0 {
+		return
+	}
+	err := utils.WithRedis(rctx, rdb, func() error { return rdb.Del(rctx, keys...).Err() })
+	if err != nil && err != utils.ErrRedisUnavailable {
+		utils.Logger(rctx).Warn("redis release seat hold failed", "error", err, "keys", len(keys))
+	}
+}
+
+// RunSweeper menjalankan ReleaseExpiredHolds setiap interval sampai ctx selesai
+func (h *SeatHoldRepository) RunSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			released, err := h.ReleaseExpiredHolds(ctx)
+			if err != nil {
+				slog.Error("seat hold sweeper failed", "error", err)
+				continue
+			}
+			if released > 0 {
+				slog.Info("seat hold sweeper released seats", "released", released)
+			}
+		}
+	}
+}
+
+// seatWriteError baris showing_seats yang sama dibuat request lain di saat bersamaan
+// (unique now_showing_id, seat_id), kursi dianggap sudah tidak tersedia
+func seatWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return errors.New("seat not available")
+	}
+	return err
+}
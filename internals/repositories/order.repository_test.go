@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestRedis redis in-memory untuk unit test, ditutup otomatis di akhir test
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { rdb.Close() })
+	return mr, rdb
+}
+
+func TestIsHeldByOther(t *testing.T) {
+	future := time.Now().Add(10 * time.Minute)
+	past := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name      string
+		status    string
+		holder    *int
+		heldUntil *time.Time
+		want      bool
+	}{
+		{name: "available", status: "available", want: false},
+		{name: "sold", status: "sold", holder: ptr(2), want: false},
+		{name: "held by me", status: "held", holder: ptr(1), heldUntil: &future, want: false},
+		{name: "held by other", status: "held", holder: ptr(2), heldUntil: &future, want: true},
+		{name: "held without holder", status: "held", heldUntil: &future, want: true},
+		{name: "hold expired", status: "held", holder: ptr(2), heldUntil: &past, want: false},
+		{name: "held without deadline", status: "held", holder: ptr(2), want: false},
+	}
+	for _, tt := range tests {
+		if got := isHeldByOther(tt.status, tt.holder, tt.heldUntil, 1); got != tt.want {
+			t.Errorf("%s: isHeldByOther = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDeleteSeatHoldKeys(t *testing.T) {
+	mr, rdb := newTestRedis(t)
+	for _, key := range []string{seatHoldKey(7, 1), seatHoldKey(7, 2), seatHoldKey(8, 1)} {
+		mr.Set(key, "1")
+	}
+
+	deleteSeatHoldKeys(t.Context(), rdb, []string{seatHoldKey(7, 1), seatHoldKey(7, 2)})
+	if mr.Exists(seatHoldKey(7, 1)) || mr.Exists(seatHoldKey(7, 2)) {
+		t.Error("released seat hold keys still in redis")
+	}
+	if !mr.Exists(seatHoldKey(8, 1)) {
+		t.Error("seat hold of another showing deleted")
+	}
+
+	// tanpa redis atau tanpa kursi tidak melakukan apa-apa
+	deleteSeatHoldKeys(t.Context(), nil, []string{seatHoldKey(8, 1)})
+	deleteSeatHoldKeys(t.Context(), rdb, nil)
+	if !mr.Exists(seatHoldKey(8, 1)) {
+		t.Error("seat hold deleted without keys")
+	}
+
+	// redis mati tidak menggagalkan order yang sudah commit
+	mr.Close()
+	deleteSeatHoldKeys(t.Context(), rdb, []string{seatHoldKey(8, 1)})
+}
@@ -0,0 +1,327 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/utils"
+	"github.com/raihaninkam/tickitz/pkg"
+	"github.com/redis/go-redis/v9"
+)
+
+// PaymentStore transaksi pembayaran order, diimplementasikan PaymentRepository
+type PaymentStore interface {
+	GetOrderForPayment(rctx context.Context, orderID, userID int) (models.OrderPayment, error)
+	GetPendingTransaction(rctx context.Context, orderID int) (models.PaymentTransaction, error)
+	CreateTransaction(rctx context.Context, orderID int, provider string, charge pkg.Charge) (models.PaymentTransaction, error)
+	ApplyPaymentResult(rctx context.Context, reference string, status pkg.ChargeStatus, amount int) (models.PaymentTransaction, error)
+}
+
+var _ PaymentStore = (*PaymentRepository)(nil)
+
+type PaymentRepository struct {
+	db      *pgxpool.Pool
+	rdb     *redis.Client
+	loyalty models.LoyaltyRules
+}
+
+func NewPaymentRepository(db *pgxpool.Pool, rdb *redis.Client, loyalty models.LoyaltyRules) *PaymentRepository {
+	return &PaymentRepository{db: db, rdb: rdb, loyalty: loyalty}
+}
+
+// GetOrderForPayment mengambil order milik user beserta metode pembayarannya
+func (p *PaymentRepository) GetOrderForPayment(rctx context.Context, orderID, userID int) (models.OrderPayment, error) {
+	sql := `SELECT o.id, o.users_id, o.price, o."isPaid", o.status, p.method
+			FROM orders o
+			JOIN payment p ON p.id = o.payment_id
+			WHERE o.id = $1 AND o.users_id = $2`
+
+	var order models.OrderPayment
+	if err := p.db.QueryRow(rctx, sql, orderID, userID).Scan(
+		&order.OrderId,
+		&order.UsersId,
+		&order.Price,
+		&order.IsPaid,
+		&order.Status,
+		&order.Method,
+	); err != nil {
+		if err == pgx.ErrNoRows {
+			return models.OrderPayment{}, errors.New("order not found")
+		}
+		return models.OrderPayment{}, err
+	}
+	return order, nil
+}
+
+const paymentTransactionColumns = `id, orders_id, provider, reference, amount, status, paid_at, created_at, updated_at`
+
+func scanPaymentTransaction(row pgx.Row) (models.PaymentTransaction, error) {
+	var trx models.PaymentTransaction
+	err := row.Scan(
+		&trx.Id,
+		&trx.OrdersId,
+		&trx.Provider,
+		&trx.Reference,
+		&trx.Amount,
+		&trx.Status,
+		&trx.PaidAt,
+		&trx.CreatedAt,
+		&trx.UpdatedAt,
+	)
+	return trx, err
+}
+
+// GetPendingTransaction mengembalikan transaksi pending terakhir milik order (jika ada)
+func (p *PaymentRepository) GetPendingTransaction(rctx context.Context, orderID int) (models.PaymentTransaction, error) {
+	sql := `SELECT ` + paymentTransactionColumns + `
+			FROM payment_transactions
+			WHERE orders_id = $1 AND status = 'pending'
+			ORDER BY created_at DESC
+			LIMIT 1`
+
+	trx, err := scanPaymentTransaction(p.db.QueryRow(rctx, sql, orderID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.PaymentTransaction{}, errors.New("transaction not found")
+		}
+		return models.PaymentTransaction{}, err
+	}
+	return trx, nil
+}
+
+func (p *PaymentRepository) CreateTransaction(rctx context.Context, orderID int, provider string, charge pkg.Charge) (models.PaymentTransaction, error) {
+	sql := `INSERT INTO payment_transactions (orders_id, provider, reference, amount, status, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+			RETURNING ` + paymentTransactionColumns
+
+	return scanPaymentTransaction(p.db.QueryRow(rctx, sql, orderID, provider, charge.Reference, charge.Amount, string(charge.Status)))
+}
+
+// ApplyPaymentResult memproses hasil pembayaran dari webhook.
+// paid: order ditandai lunas dan user mendapat poin jika amount sama dengan total order;
+// failed: order gagal, kursi dan poin redeem dikembalikan
+func (p *PaymentRepository) ApplyPaymentResult(rctx context.Context, reference string, status pkg.ChargeStatus, amount int) (models.PaymentTransaction, error) {
+	tx, err := p.db.Begin(rctx)
+	if err != nil {
+		return models.PaymentTransaction{}, err
+	}
+	defer tx.Rollback(rctx)
+
+	sql := `SELECT ` + paymentTransactionColumns + ` FROM payment_transactions WHERE reference = $1 FOR UPDATE`
+	trx, err := scanPaymentTransaction(tx.QueryRow(rctx, sql, reference))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.PaymentTransaction{}, errors.New("transaction not found")
+		}
+		return models.PaymentTransaction{}, err
+	}
+
+	// webhook bisa dikirim ulang oleh gateway, status yang sama tidak diproses dua kali
+	if trx.Status == string(status) {
+		return trx, nil
+	}
+	if trx.Status != string(pkg.ChargePending) {
+		return models.PaymentTransaction{}, errors.New("transaction already settled")
+	}
+
+	var seatsSold int
+	var releasedKeys []string
+	switch status {
+	case pkg.ChargePaid:
+		var orderTotal int
+		if err := tx.QueryRow(rctx, "SELECT COALESCE(price, 0) FROM orders WHERE id = $1", trx.OrdersId).Scan(&orderTotal); err != nil {
+			return models.PaymentTransaction{}, err
+		}
+		if !paidAmountMatches(amount, trx.Amount, orderTotal) {
+			return models.PaymentTransaction{}, errors.New("amount mismatch")
+		}
+		updateTrxSQL := `UPDATE payment_transactions SET status = 'paid', paid_at = NOW(), updated_at = NOW()
+						 WHERE id = $1
+						 RETURNING ` + paymentTransactionColumns
+		if trx, err = scanPaymentTransaction(tx.QueryRow(rctx, updateTrxSQL, trx.Id)); err != nil {
+			return models.PaymentTransaction{}, err
+		}
+		if _, err := tx.Exec(rctx, `UPDATE orders SET "isPaid" = true, status = 'paid', updated_at = NOW() WHERE id = $1`, trx.OrdersId); err != nil {
+			return models.PaymentTransaction{}, err
+		}
+		if err := awardOrderPoints(rctx, tx, p.loyalty, trx.OrdersId); err != nil {
+			return models.PaymentTransaction{}, err
+		}
+		if err := tx.QueryRow(rctx, "SELECT COUNT(*) FROM showing_seats WHERE orders_id = $1", trx.OrdersId).Scan(&seatsSold); err != nil {
+			return models.PaymentTransaction{}, err
+		}
+	case pkg.ChargeFailed:
+		updateTrxSQL := `UPDATE payment_transactions SET status = 'failed', updated_at = NOW()
+						 WHERE id = $1
+						 RETURNING ` + paymentTransactionColumns
+		if trx, err = scanPaymentTransaction(tx.QueryRow(rctx, updateTrxSQL, trx.Id)); err != nil {
+			return models.PaymentTransaction{}, err
+		}
+		if _, err := tx.Exec(rctx, `UPDATE orders SET status = 'failed', updated_at = NOW() WHERE id = $1`, trx.OrdersId); err != nil {
+			return models.PaymentTransaction{}, err
+		}
+		if releasedKeys, err = releaseOrderSeats(rctx, tx, trx.OrdersId); err != nil {
+			return models.PaymentTransaction{}, err
+		}
+		if _, _, err := reverseOrderPoints(rctx, tx, trx.OrdersId); err != nil {
+			return models.PaymentTransaction{}, err
+		}
+	default:
+		return models.PaymentTransaction{}, errors.New("invalid payment status")
+	}
+
+	if err := tx.Commit(rctx); err != nil {
+		return models.PaymentTransaction{}, err
+	}
+	deleteSeatHoldKeys(rctx, p.rdb, releasedKeys)
+
+	if status == pkg.ChargePaid {
+		utils.SeatsSold.Add(float64(seatsSold))
+	} else {
+		utils.PaymentFailures.WithLabelValues("failed").Inc()
+	}
+	return trx, nil
+}
+
+// paidAmountMatches jumlah yang dibayar harus sama persis dengan charge dan total order
+func paidAmountMatches(paid, charged, orderTotal int) bool {
+	return paid == charged && charged == orderTotal
+}
+
+// ExpireUnpaidOrders membatalkan order pending yang tidak dibayar dalam ttl: kursi dilepas,
+// tiket di-void dan poin redeem dikembalikan. Order yang masih punya charge pending
+// yang dibuat dalam ttl dibiarkan sampai charge tersebut juga kadaluarsa
+func (p *PaymentRepository) ExpireUnpaidOrders(rctx context.Context, ttl time.Duration) (int, error) {
+	sql := `SELECT o.id FROM orders o
+			WHERE o.status = 'pending' AND NOT COALESCE(o."isPaid", false)
+			  AND o.created_at <= NOW() - make_interval(secs => $1)
+			  AND NOT EXISTS (
+				SELECT 1 FROM payment_transactions pt
+				WHERE pt.orders_id = o.id AND pt.status = 'pending'
+				  AND pt.created_at > NOW() - make_interval(secs => $1)
+			  )
+			ORDER BY o.id
+			LIMIT 100`
+
+	rows, err := p.db.Query(rctx, sql, ttl.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	orderIDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, orderID := range orderIDs {
+		ok, err := p.expireOrder(rctx, orderID, ttl)
+		if err != nil {
+			return expired, fmt.Errorf("expire order %d: %w", orderID, err)
+		}
+		if ok {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// expireOrder membatalkan satu order, false jika order sudah dibayar atau diubah request lain
+func (p *PaymentRepository) expireOrder(rctx context.Context, orderID int, ttl time.Duration) (bool, error) {
+	tx, err := p.db.Begin(rctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(rctx)
+
+	// kondisi diulang di dalam lock, webhook paid bisa masuk setelah query daftar order
+	lockSQL := `SELECT o.id FROM orders o
+				WHERE o.id = $1 AND o.status = 'pending' AND NOT COALESCE(o."isPaid", false)
+				  AND NOT EXISTS (
+					SELECT 1 FROM payment_transactions pt
+					WHERE pt.orders_id = o.id AND pt.status = 'pending'
+					  AND pt.created_at > NOW() - make_interval(secs => $2)
+				  )
+				FOR UPDATE OF o`
+	if err := tx.QueryRow(rctx, lockSQL, orderID, ttl.Seconds()).Scan(&orderID); err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+
+	// charge yang kadaluarsa ditutup, webhook paid setelah ini ditolak sebagai transaksi yang sudah diproses
+	if _, err := tx.Exec(rctx, "UPDATE payment_transactions SET status = 'failed', updated_at = NOW() WHERE orders_id = $1 AND status = 'pending'", orderID); err != nil {
+		return false, err
+	}
+	releasedKeys, err := releaseOrderSeats(rctx, tx, orderID)
+	if err != nil {
+		return false, err
+	}
+	if err := voidOrderTickets(rctx, tx, orderID); err != nil {
+		return false, err
+	}
+	if _, _, err := reverseOrderPoints(rctx, tx, orderID); err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(rctx, "UPDATE orders SET status = 'cancelled', cancelled_at = NOW(), updated_at = NOW() WHERE id = $1", orderID); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(rctx); err != nil {
+		return false, err
+	}
+	deleteSeatHoldKeys(rctx, p.rdb, releasedKeys)
+	return true, nil
+}
+
+// RunExpirySweeper menjalankan ExpireUnpaidOrders setiap interval sampai ctx selesai
+func (p *PaymentRepository) RunExpirySweeper(ctx context.Context, interval, ttl time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := p.ExpireUnpaidOrders(ctx, ttl)
+			if err != nil {
+				slog.Error("payment expiry sweeper failed", "error", err)
+			}
+			if expired > 0 {
+				slog.Info("payment expiry sweeper cancelled unpaid orders", "orders", expired)
+			}
+		}
+	}
+}
+
+// releaseOrderSeats mengembalikan kursi milik order menjadi available,
+// hasilnya key seat hold redis kursi tersebut untuk dihapus setelah commit
+func releaseOrderSeats(rctx context.Context, q querier, orderID int) ([]string, error) {
+	sql := `UPDATE showing_seats
+			SET status = 'available', user_id = NULL, orders_id = NULL, held_until = NULL, updated_at = NOW()
+			WHERE orders_id = $1
+			RETURNING now_showing_id, seat_id`
+
+	rows, err := q.Query(rctx, sql, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var nowShowingID, seatID int
+		if err := rows.Scan(&nowShowingID, &seatID); err != nil {
+			return nil, err
+		}
+		keys = append(keys, seatHoldKey(nowShowingID, seatID))
+	}
+	return keys, rows.Err()
+}
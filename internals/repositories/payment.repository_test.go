@@ -0,0 +1,22 @@
+package repositories
+
+import "testing"
+
+func TestPaidAmountMatches(t *testing.T) {
+	tests := []struct {
+		paid, charged, total int
+		want                 bool
+	}{
+		{paid: 50000, charged: 50000, total: 50000, want: true},
+		{paid: 5000, charged: 50000, total: 50000, want: false},
+		{paid: 60000, charged: 50000, total: 50000, want: false},
+		// charge dibuat sebelum harga order berubah
+		{paid: 50000, charged: 50000, total: 45000, want: false},
+		{paid: 0, charged: 50000, total: 50000, want: false},
+	}
+	for _, tt := range tests {
+		if got := paidAmountMatches(tt.paid, tt.charged, tt.total); got != tt.want {
+			t.Errorf("paidAmountMatches(%d, %d, %d) = %v, want %v", tt.paid, tt.charged, tt.total, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,363 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/models"
+)
+
+// PointsStore ledger poin loyalty, diimplementasikan PointsRepository
+type PointsStore interface {
+	GetPointsHistory(rctx context.Context, userID int) (models.PointsHistory, error)
+	AdjustPoints(rctx context.Context, userID, adminID int, req models.PointsAdjustRequest) (models.PointsAdjustResponse, error)
+}
+
+var _ PointsStore = (*PointsRepository)(nil)
+
+// PointsRepository mengelola ledger poin loyalty.
+// users.poin selalu disinkronkan dengan SUM(points_ledger.points) milik user
+type PointsRepository struct {
+	db    *pgxpool.Pool
+	rules models.LoyaltyRules
+}
+
+func NewPointsRepository(db *pgxpool.Pool, rules models.LoyaltyRules) *PointsRepository {
+	return &PointsRepository{db: db, rules: rules}
+}
+
+const pointsEntryColumns = `id, orders_id, entry_type, points, description, expires_at, created_at`
+
+func scanPointsEntry(row pgx.Row) (models.PointsEntry, error) {
+	var entry models.PointsEntry
+	err := row.Scan(
+		&entry.Id,
+		&entry.OrdersId,
+		&entry.EntryType,
+		&entry.Points,
+		&entry.Description,
+		&entry.ExpiresAt,
+		&entry.CreatedAt,
+	)
+	return entry, err
+}
+
+// GetPointsHistory mengambil saldo dan riwayat poin user, terbaru lebih dulu
+func (p *PointsRepository) GetPointsHistory(rctx context.Context, userID int) (models.PointsHistory, error) {
+	history := models.PointsHistory{RedeemValue: p.rules.RedeemValue, Entries: []models.PointsEntry{}}
+
+	if err := p.db.QueryRow(rctx, "SELECT COALESCE(poin, 0)::int4 FROM users WHERE id = $1", userID).Scan(&history.Balance); err != nil {
+		if err == pgx.ErrNoRows {
+			return models.PointsHistory{}, errors.New("user not found")
+		}
+		return models.PointsHistory{}, err
+	}
+
+	sql := `SELECT ` + pointsEntryColumns + `
+			FROM points_ledger
+			WHERE users_id = $1
+			ORDER BY created_at DESC, id DESC`
+	rows, err := p.db.Query(rctx, sql, userID)
+	if err != nil {
+		return models.PointsHistory{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry, err := scanPointsEntry(rows)
+		if err != nil {
+			return models.PointsHistory{}, err
+		}
+		history.Entries = append(history.Entries, entry)
+	}
+	return history, rows.Err()
+}
+
+// AdjustPoints menambah/mengurangi poin user secara manual oleh admin
+func (p *PointsRepository) AdjustPoints(rctx context.Context, userID, adminID int, req models.PointsAdjustRequest) (models.PointsAdjustResponse, error) {
+	tx, err := p.db.Begin(rctx)
+	if err != nil {
+		return models.PointsAdjustResponse{}, err
+	}
+	defer tx.Rollback(rctx)
+
+	balance, err := lockUserPoin(rctx, tx, userID)
+	if err != nil {
+		return models.PointsAdjustResponse{}, err
+	}
+	if balance+req.Points < 0 {
+		return models.PointsAdjustResponse{}, errors.New("insufficient points")
+	}
+
+	entry, err := addPointsEntry(rctx, tx, pointsEntry{
+		UserID:      userID,
+		EntryType:   "adjust",
+		Points:      req.Points,
+		Description: req.Description,
+		CreatedBy:   &adminID,
+	})
+	if err != nil {
+		return models.PointsAdjustResponse{}, err
+	}
+
+	response := models.PointsAdjustResponse{Entry: entry}
+	if response.Balance, err = syncUserPoin(rctx, tx, userID); err != nil {
+		return models.PointsAdjustResponse{}, err
+	}
+
+	if err := tx.Commit(rctx); err != nil {
+		return models.PointsAdjustResponse{}, err
+	}
+	return response, nil
+}
+
+// ExpirePoints mencatat entry expire untuk poin earn yang sudah lewat masa berlaku.
+// Poin yang di-redeem dan yang sudah expire dianggap memakai poin earn paling lama lebih dulu,
+// poin redeem yang dikembalikan (refund) bisa dipakai lagi
+func (p *PointsRepository) ExpirePoints(rctx context.Context) (int, error) {
+	sql := `SELECT l.users_id,
+				SUM(CASE WHEN l.entry_type = 'earn' AND l.expires_at <= NOW() THEN l.points ELSE 0 END) AS expired_earned,
+				-SUM(CASE WHEN l.entry_type IN ('redeem', 'refund', 'expire') THEN l.points ELSE 0 END) AS used,
+				MAX(COALESCE(u.poin, 0))::int4 AS balance
+			FROM points_ledger l
+			JOIN users u ON u.id = l.users_id
+			GROUP BY l.users_id
+			HAVING SUM(CASE WHEN l.entry_type = 'earn' AND l.expires_at <= NOW() THEN l.points ELSE 0 END)
+				> -SUM(CASE WHEN l.entry_type IN ('redeem', 'refund', 'expire') THEN l.points ELSE 0 END)`
+
+	rows, err := p.db.Query(rctx, sql)
+	if err != nil {
+		return 0, err
+	}
+	expired := map[int]int{}
+	for rows.Next() {
+		var userID, expiredEarned, used, balance int
+		if err := rows.Scan(&userID, &expiredEarned, &used, &balance); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if points := pointsToExpire(expiredEarned, used, balance); points > 0 {
+			expired[userID] = points
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for userID, points := range expired {
+		if err := p.expireUserPoints(rctx, userID, points); err != nil {
+			return total, err
+		}
+		total += points
+	}
+	return total, nil
+}
+
+// pointsToExpire poin earn kadaluarsa yang belum terpakai (redeem bersih dan expire sebelumnya),
+// tidak pernah lebih dari saldo user
+func pointsToExpire(expiredEarned, used, balance int) int {
+	return max(min(expiredEarned-used, balance), 0)
+}
+
+func (p *PointsRepository) expireUserPoints(rctx context.Context, userID, points int) error {
+	tx, err := p.db.Begin(rctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(rctx)
+
+	balance, err := lockUserPoin(rctx, tx, userID)
+	if err != nil {
+		return err
+	}
+	points = min(points, balance)
+	if points <= 0 {
+		return nil
+	}
+
+	if _, err := addPointsEntry(rctx, tx, pointsEntry{
+		UserID:      userID,
+		EntryType:   "expire",
+		Points:      -points,
+		Description: "Poin kadaluarsa",
+	}); err != nil {
+		return err
+	}
+	if _, err := syncUserPoin(rctx, tx, userID); err != nil {
+		return err
+	}
+	return tx.Commit(rctx)
+}
+
+// RunExpirySweeper menjalankan ExpirePoints setiap interval sampai ctx selesai
+func (p *PointsRepository) RunExpirySweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := p.ExpirePoints(ctx)
+			if err != nil {
+				slog.Error("points expiry sweeper failed", "error", err)
+				continue
+			}
+			if expired > 0 {
+				slog.Info("points expiry sweeper expired points", "points", expired)
+			}
+		}
+	}
+}
+
+// helper ledger, dipakai juga oleh order, payment dan refund di dalam transaksinya
+
+type pointsEntry struct {
+	UserID      int
+	OrderID     *int
+	EntryType   string
+	Points      int
+	Description string
+	ExpiresAt   *time.Time
+	CreatedBy   *int
+}
+
+func addPointsEntry(rctx context.Context, q querier, entry pointsEntry) (models.PointsEntry, error) {
+	sql := `INSERT INTO points_ledger (users_id, orders_id, entry_type, points, description, expires_at, created_by, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+			RETURNING ` + pointsEntryColumns
+
+	return scanPointsEntry(q.QueryRow(rctx, sql,
+		entry.UserID, entry.OrderID, entry.EntryType, entry.Points, entry.Description, entry.ExpiresAt, entry.CreatedBy,
+	))
+}
+
+// syncUserPoin menghitung ulang users.poin dari ledger
+func syncUserPoin(rctx context.Context, q querier, userID int) (int, error) {
+	sql := `UPDATE users
+			SET poin = (SELECT COALESCE(SUM(points), 0) FROM points_ledger WHERE users_id = $1)
+			WHERE id = $1
+			RETURNING poin::int4`
+
+	var balance int
+	if err := q.QueryRow(rctx, sql, userID).Scan(&balance); err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
+
+// lockUserPoin mengunci baris user supaya saldo tidak berubah selama transaksi
+func lockUserPoin(rctx context.Context, q querier, userID int) (int, error) {
+	var balance int
+	if err := q.QueryRow(rctx, "SELECT COALESCE(poin, 0)::int4 FROM users WHERE id = $1 FOR UPDATE", userID).Scan(&balance); err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, errors.New("user not found")
+		}
+		return 0, err
+	}
+	return balance, nil
+}
+
+// awardOrderPoints memberikan poin earn untuk order yang sudah dibayar (sekali per order)
+func awardOrderPoints(rctx context.Context, q querier, rules models.LoyaltyRules, orderID int) error {
+	var userID, price int
+	var awarded float64
+	if err := q.QueryRow(rctx, "SELECT users_id, COALESCE(price, 0), poin_awarded FROM orders WHERE id = $1 FOR UPDATE", orderID).Scan(&userID, &price, &awarded); err != nil {
+		return err
+	}
+	if awarded > 0 || rules.EarnPerAmount <= 0 {
+		return nil
+	}
+
+	points := price / rules.EarnPerAmount
+	if points <= 0 {
+		return nil
+	}
+
+	var expiresAt *time.Time
+	if rules.ExpiryDays > 0 {
+		t := time.Now().AddDate(0, 0, rules.ExpiryDays)
+		expiresAt = &t
+	}
+
+	if _, err := lockUserPoin(rctx, q, userID); err != nil {
+		return err
+	}
+	if _, err := addPointsEntry(rctx, q, pointsEntry{
+		UserID:      userID,
+		OrderID:     &orderID,
+		EntryType:   "earn",
+		Points:      points,
+		Description: fmt.Sprintf("Poin dari order #%d", orderID),
+		ExpiresAt:   expiresAt,
+	}); err != nil {
+		return err
+	}
+	if _, err := q.Exec(rctx, "UPDATE orders SET poin_awarded = $1 WHERE id = $2", points, orderID); err != nil {
+		return err
+	}
+	_, err := syncUserPoin(rctx, q, userID)
+	return err
+}
+
+// reverseOrderPoints membatalkan poin milik order yang gagal/dibatalkan:
+// poin yang di-redeem dikembalikan dan poin earn ditarik (maksimal sebesar saldo).
+// Mengembalikan jumlah poin earn yang ditarik dan poin redeem yang dikembalikan
+func reverseOrderPoints(rctx context.Context, q querier, orderID int) (int, int, error) {
+	var userID, redeemed int
+	var awarded float64
+	if err := q.QueryRow(rctx, "SELECT users_id, poin_awarded, poin_redeemed FROM orders WHERE id = $1", orderID).Scan(&userID, &awarded, &redeemed); err != nil {
+		return 0, 0, err
+	}
+	if awarded <= 0 && redeemed <= 0 {
+		return 0, 0, nil
+	}
+
+	balance, err := lockUserPoin(rctx, q, userID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if redeemed > 0 {
+		if _, err := addPointsEntry(rctx, q, pointsEntry{
+			UserID:      userID,
+			OrderID:     &orderID,
+			EntryType:   "refund",
+			Points:      redeemed,
+			Description: fmt.Sprintf("Pengembalian poin order #%d", orderID),
+		}); err != nil {
+			return 0, 0, err
+		}
+		balance += redeemed
+	}
+
+	// entry earn negatif memakai expires_at yang sama, sehingga perhitungan kadaluarsa tetap benar
+	reversed := min(int(awarded), balance)
+	if reversed > 0 {
+		sql := `INSERT INTO points_ledger (users_id, orders_id, entry_type, points, description, expires_at, created_at)
+				SELECT users_id, orders_id, 'earn', $2, $3, expires_at, NOW()
+				FROM points_ledger
+				WHERE orders_id = $1 AND entry_type = 'earn' AND points > 0
+				ORDER BY id
+				LIMIT 1`
+		if _, err := q.Exec(rctx, sql, orderID, -reversed, fmt.Sprintf("Pembatalan poin order #%d", orderID)); err != nil {
+			return 0, 0, err
+		}
+	}
+	if _, err := q.Exec(rctx, "UPDATE orders SET poin_awarded = 0 WHERE id = $1", orderID); err != nil {
+		return 0, 0, err
+	}
+
+	if _, err := syncUserPoin(rctx, q, userID); err != nil {
+		return 0, 0, err
+	}
+	return reversed, redeemed, nil
+}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/testutil"
+)
+
+func TestPointsToExpire(t *testing.T) {
+	tests := []struct {
+		name                         string
+		expiredEarned, used, balance int
+		want                         int
+	}{
+		{name: "nothing used", expiredEarned: 100, used: 0, balance: 140, want: 100},
+		{name: "partly redeemed", expiredEarned: 100, used: 30, balance: 110, want: 70},
+		{name: "redeemed more than expired", expiredEarned: 50, used: 60, balance: 40, want: 0},
+		{name: "already expired", expiredEarned: 100, used: 100, balance: 40, want: 0},
+		// saldo berkurang lewat adjust admin, yang expire tidak boleh membuat saldo negatif
+		{name: "capped at balance", expiredEarned: 100, used: 0, balance: 25, want: 25},
+		{name: "negative balance", expiredEarned: 100, used: 0, balance: -5, want: 0},
+	}
+	for _, tt := range tests {
+		if got := pointsToExpire(tt.expiredEarned, tt.used, tt.balance); got != tt.want {
+			t.Errorf("%s: pointsToExpire(%d, %d, %d) = %d, want %d", tt.name, tt.expiredEarned, tt.used, tt.balance, got, tt.want)
+		}
+	}
+}
+
+func TestExpirePoints(t *testing.T) {
+	env := testutil.Setup(t)
+	env.Seed(t, "demo")
+	ctx := t.Context()
+	yesterday := time.Now().AddDate(0, 0, -1)
+	nextYear := time.Now().AddDate(1, 0, 0)
+
+	// user 2: poin redeem dari order yang dibatalkan kembali ke pool earn yang sudah kadaluarsa
+	// user 1: redeem memakai poin earn paling lama lebih dulu, tidak ada yang tersisa untuk expire
+	ledger := map[int][]pointsEntry{
+		2: {
+			{EntryType: "earn", Points: 100, ExpiresAt: &yesterday},
+			{EntryType: "redeem", Points: -30},
+			{EntryType: "refund", Points: 30},
+			{EntryType: "earn", Points: 40, ExpiresAt: &nextYear},
+		},
+		1: {
+			{EntryType: "earn", Points: 50, ExpiresAt: &yesterday},
+			{EntryType: "earn", Points: 50, ExpiresAt: &nextYear},
+			{EntryType: "redeem", Points: -60},
+		},
+	}
+	for userID, entries := range ledger {
+		for _, entry := range entries {
+			entry.UserID = userID
+			if _, err := addPointsEntry(ctx, env.DB, entry); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := syncUserPoin(ctx, env.DB, userID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	points := NewPointsRepository(env.DB, models.LoyaltyRules{})
+	expired, err := points.ExpirePoints(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expired != 100 {
+		t.Errorf("expired %d points, want 100", expired)
+	}
+	if expired, err := points.ExpirePoints(ctx); err != nil || expired != 0 {
+		t.Errorf("second run expired %d points (err %v), want 0", expired, err)
+	}
+
+	for userID, want := range map[int]int{2: 40, 1: 40} {
+		history, err := points.GetPointsHistory(ctx, userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if history.Balance != want {
+			t.Errorf("user %d balance %d, want %d", userID, history.Balance, want)
+		}
+	}
+}
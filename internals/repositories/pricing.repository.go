@@ -0,0 +1,345 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/models"
+)
+
+// querier dipenuhi oleh *pgxpool.Pool maupun pgx.Tx,
+// sehingga perhitungan harga bisa dipakai di dalam transaksi order
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// PricingStore harga dasar tiket, aturan harga dan quote, diimplementasikan PricingRepository
+type PricingStore interface {
+	QuotePrice(rctx context.Context, nowShowingID int, seats []string) (models.PriceQuote, error)
+	GetTicketPrices(rctx context.Context) ([]models.TicketPrice, error)
+	SetTicketPrice(rctx context.Context, req models.TicketPriceRequest) (models.TicketPrice, error)
+	GetPriceRules(rctx context.Context) ([]models.PriceRule, error)
+	CreatePriceRule(rctx context.Context, req models.PriceRuleRequest) (models.PriceRule, error)
+	UpdatePriceRule(rctx context.Context, ruleId int, req models.PriceRuleRequest) (models.PriceRule, error)
+	DeletePriceRule(rctx context.Context, ruleId int) error
+}
+
+var _ PricingStore = (*PricingRepository)(nil)
+
+type PricingRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPricingRepository(db *pgxpool.Pool) *PricingRepository {
+	return &PricingRepository{db: db}
+}
+
+// QuotePrice menghitung harga kursi untuk sebuah jadwal tayang
+func (p *PricingRepository) QuotePrice(rctx context.Context, nowShowingID int, seats []string) (models.PriceQuote, error) {
+	return quoteShowingPrice(rctx, p.db, nowShowingID, seats)
+}
+
+// quoteShowingPrice menghitung total harga dari now_showing, cinema, kelas kursi,
+// hari (weekday/weekend) dan jam tayang berdasarkan tabel ticket_prices & price_rules
+func quoteShowingPrice(rctx context.Context, q querier, nowShowingID int, seats []string) (models.PriceQuote, error) {
+	quote := models.PriceQuote{NowShowingID: nowShowingID}
+
+	var showDate time.Time
+	var showTime string
+	showingSQL := "SELECT cinemas_id, date, time::text FROM now_showing WHERE id = $1 AND status = 'scheduled'"
+	if err := q.QueryRow(rctx, showingSQL, nowShowingID).Scan(&quote.CinemaID, &showDate, &showTime); err != nil {
+		if err == pgx.ErrNoRows {
+			return models.PriceQuote{}, errors.New("showing not found")
+		}
+		return models.PriceQuote{}, err
+	}
+
+	quote.DayType = dayType(showDate)
+
+	// harga dasar: milik cinema jika ada, jika tidak pakai harga default
+	var basePrice int
+	baseSQL := `SELECT price FROM ticket_prices
+				WHERE cinemas_id = $1 OR cinemas_id IS NULL
+				ORDER BY cinemas_id NULLS LAST
+				LIMIT 1`
+	if err := q.QueryRow(rctx, baseSQL, quote.CinemaID).Scan(&basePrice); err != nil {
+		if err == pgx.ErrNoRows {
+			return models.PriceQuote{}, errors.New("price not configured")
+		}
+		return models.PriceQuote{}, err
+	}
+
+	// kelas kursi yang dipilih, sekaligus validasi kursi ada di cinema tersebut
+	seen := make(map[string]bool, len(seats))
+	for _, seat := range seats {
+		if seen[seat] {
+			return models.PriceQuote{}, errors.New("invalid seat selection")
+		}
+		seen[seat] = true
+	}
+
+	seatSQL := `SELECT CONCAT(s.row, s.seat_number), s.seat_class
+				FROM seats s
+				WHERE s.cinemas_id = $1 AND CONCAT(s.row, s.seat_number) = ANY($2)
+				  AND s.deleted_at IS NULL AND s.is_disabled = false`
+	seatRows, err := q.Query(rctx, seatSQL, quote.CinemaID, seats)
+	if err != nil {
+		return models.PriceQuote{}, err
+	}
+	seatClasses := make(map[string]string, len(seats))
+	for seatRows.Next() {
+		var seatID, class string
+		if err := seatRows.Scan(&seatID, &class); err != nil {
+			seatRows.Close()
+			return models.PriceQuote{}, err
+		}
+		seatClasses[seatID] = class
+	}
+	seatRows.Close()
+	if err := seatRows.Err(); err != nil {
+		return models.PriceQuote{}, err
+	}
+	if len(seatClasses) != len(seats) {
+		return models.PriceQuote{}, errors.New("invalid seat selection")
+	}
+
+	// aturan yang berlaku untuk cinema, hari dan jam tayang ini
+	rulesSQL := `SELECT id, name, seat_class, amount
+				 FROM price_rules
+				 WHERE is_active = true
+				   AND (cinemas_id IS NULL OR cinemas_id = $1)
+				   AND (day_type IS NULL OR day_type = $2)
+				   AND (start_time IS NULL OR $3::time >= start_time)
+				   AND (end_time IS NULL OR $3::time < end_time)
+				 ORDER BY id`
+	ruleRows, err := q.Query(rctx, rulesSQL, quote.CinemaID, quote.DayType, showTime)
+	if err != nil {
+		return models.PriceQuote{}, err
+	}
+	var rules []models.PriceRule
+	for ruleRows.Next() {
+		var rule models.PriceRule
+		if err := ruleRows.Scan(&rule.Id, &rule.Name, &rule.SeatClass, &rule.Amount); err != nil {
+			ruleRows.Close()
+			return models.PriceQuote{}, err
+		}
+		rules = append(rules, rule)
+	}
+	ruleRows.Close()
+	if err := ruleRows.Err(); err != nil {
+		return models.PriceQuote{}, err
+	}
+
+	quote.Seats, quote.Total = priceSeats(seats, seatClasses, basePrice, rules)
+	return quote, nil
+}
+
+// priceSeats harga per kursi: harga dasar ditambah semua aturan yang cocok dengan kelas kursinya
+// (aturan tanpa seat_class berlaku untuk semua kursi), tidak pernah di bawah 0
+func priceSeats(seats []string, seatClasses map[string]string, basePrice int, rules []models.PriceRule) ([]models.SeatPrice, int) {
+	prices := make([]models.SeatPrice, 0, len(seats))
+	total := 0
+	for _, seat := range seats {
+		seatPrice := models.SeatPrice{
+			SeatID:      seat,
+			SeatClass:   seatClasses[seat],
+			BasePrice:   basePrice,
+			Adjustments: []models.PriceAdjustment{},
+			Price:       basePrice,
+		}
+		for _, rule := range rules {
+			if rule.SeatClass != nil && *rule.SeatClass != seatPrice.SeatClass {
+				continue
+			}
+			seatPrice.Adjustments = append(seatPrice.Adjustments, models.PriceAdjustment{
+				RuleId: rule.Id,
+				Name:   rule.Name,
+				Amount: rule.Amount,
+			})
+			seatPrice.Price += rule.Amount
+		}
+		seatPrice.Price = max(seatPrice.Price, 0)
+		prices = append(prices, seatPrice)
+		total += seatPrice.Price
+	}
+	return prices, total
+}
+
+// confirmsQuote harga dari client (opsional, 0 berarti tidak dikirim) harus sama persis dengan total dari server
+func confirmsQuote(clientPrice float64, total int) bool {
+	return clientPrice == 0 || clientPrice == float64(total)
+}
+
+func dayType(date time.Time) string {
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		return "weekend"
+	}
+	return "weekday"
+}
+
+// base price
+
+func (p *PricingRepository) GetTicketPrices(rctx context.Context) ([]models.TicketPrice, error) {
+	sql := "SELECT id, cinemas_id, price, updated_at FROM ticket_prices ORDER BY cinemas_id NULLS FIRST"
+
+	rows, err := p.db.Query(rctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prices := []models.TicketPrice{}
+	for rows.Next() {
+		var price models.TicketPrice
+		if err := rows.Scan(&price.Id, &price.CinemasId, &price.Price, &price.UpdatedAt); err != nil {
+			return nil, err
+		}
+		prices = append(prices, price)
+	}
+	return prices, rows.Err()
+}
+
+// SetTicketPrice membuat atau mengganti harga dasar sebuah cinema (nil = default)
+func (p *PricingRepository) SetTicketPrice(rctx context.Context, req models.TicketPriceRequest) (models.TicketPrice, error) {
+	if req.CinemasId != nil {
+		if err := ensureCinemaExists(rctx, p.db, *req.CinemasId); err != nil {
+			return models.TicketPrice{}, err
+		}
+	}
+
+	sql := `INSERT INTO ticket_prices (cinemas_id, price, created_at, updated_at)
+			VALUES ($1, $2, NOW(), NOW())
+			ON CONFLICT ((COALESCE(cinemas_id, 0)))
+			DO UPDATE SET price = EXCLUDED.price, updated_at = NOW()
+			RETURNING id, cinemas_id, price, updated_at`
+
+	var price models.TicketPrice
+	if err := p.db.QueryRow(rctx, sql, req.CinemasId, *req.Price).Scan(&price.Id, &price.CinemasId, &price.Price, &price.UpdatedAt); err != nil {
+		return models.TicketPrice{}, err
+	}
+	return price, nil
+}
+
+// price rules
+
+const priceRuleColumns = `id, name, cinemas_id, seat_class, day_type,
+	to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI'),
+	amount, is_active, created_at, updated_at`
+
+func scanPriceRule(row pgx.Row) (models.PriceRule, error) {
+	var rule models.PriceRule
+	err := row.Scan(
+		&rule.Id,
+		&rule.Name,
+		&rule.CinemasId,
+		&rule.SeatClass,
+		&rule.DayType,
+		&rule.StartTime,
+		&rule.EndTime,
+		&rule.Amount,
+		&rule.IsActive,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	return rule, err
+}
+
+func (p *PricingRepository) GetPriceRules(rctx context.Context) ([]models.PriceRule, error) {
+	sql := "SELECT " + priceRuleColumns + " FROM price_rules ORDER BY id"
+
+	rows, err := p.db.Query(rctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.PriceRule{}
+	for rows.Next() {
+		rule, err := scanPriceRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (p *PricingRepository) CreatePriceRule(rctx context.Context, req models.PriceRuleRequest) (models.PriceRule, error) {
+	if req.CinemasId != nil {
+		if err := ensureCinemaExists(rctx, p.db, *req.CinemasId); err != nil {
+			return models.PriceRule{}, err
+		}
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	sql := `INSERT INTO price_rules (name, cinemas_id, seat_class, day_type, start_time, end_time, amount, is_active, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+			RETURNING ` + priceRuleColumns
+
+	return scanPriceRule(p.db.QueryRow(rctx, sql,
+		req.Name, req.CinemasId, req.SeatClass, req.DayType, req.StartTime, req.EndTime, *req.Amount, isActive,
+	))
+}
+
+// UpdatePriceRule mengganti seluruh isi aturan harga
+func (p *PricingRepository) UpdatePriceRule(rctx context.Context, ruleId int, req models.PriceRuleRequest) (models.PriceRule, error) {
+	if req.CinemasId != nil {
+		if err := ensureCinemaExists(rctx, p.db, *req.CinemasId); err != nil {
+			return models.PriceRule{}, err
+		}
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	sql := `UPDATE price_rules
+			SET name = $1, cinemas_id = $2, seat_class = $3, day_type = $4,
+				start_time = $5, end_time = $6, amount = $7, is_active = $8, updated_at = NOW()
+			WHERE id = $9
+			RETURNING ` + priceRuleColumns
+
+	rule, err := scanPriceRule(p.db.QueryRow(rctx, sql,
+		req.Name, req.CinemasId, req.SeatClass, req.DayType, req.StartTime, req.EndTime, *req.Amount, isActive, ruleId,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.PriceRule{}, errors.New("price rule not found")
+		}
+		return models.PriceRule{}, err
+	}
+	return rule, nil
+}
+
+func (p *PricingRepository) DeletePriceRule(rctx context.Context, ruleId int) error {
+	res, err := p.db.Exec(rctx, "DELETE FROM price_rules WHERE id = $1", ruleId)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return errors.New("price rule not found")
+	}
+	return nil
+}
+
+func ensureCinemaExists(rctx context.Context, q querier, cinemaId int) error {
+	var exists bool
+	if err := q.QueryRow(rctx, "SELECT EXISTS(SELECT 1 FROM cinemas WHERE id = $1)", cinemaId).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return errors.New("cinema not found")
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/raihaninkam/tickitz/internals/models"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestPriceSeats(t *testing.T) {
+	rules := []models.PriceRule{
+		{Id: 1, Name: "Love Nest", SeatClass: ptr("love_nest"), Amount: 15000},
+		{Id: 2, Name: "Weekend", Amount: 10000},
+		{Id: 3, Name: "Promo wheelchair", SeatClass: ptr("wheelchair"), Amount: -70000},
+	}
+	classes := map[string]string{"A1": "wheelchair", "C5": "regular", "F7": "love_nest"}
+
+	prices, total := priceSeats([]string{"C5", "F7", "A1"}, classes, 50000, rules)
+
+	want := []struct {
+		seat        string
+		price       int
+		adjustments []int
+	}{
+		{seat: "C5", price: 60000, adjustments: []int{2}},
+		{seat: "F7", price: 75000, adjustments: []int{1, 2}},
+		// potongan lebih besar dari harga tidak membuat harga negatif
+		{seat: "A1", price: 0, adjustments: []int{2, 3}},
+	}
+	if len(prices) != len(want) {
+		t.Fatalf("got %d seat prices, want %d", len(prices), len(want))
+	}
+	for i, w := range want {
+		got := prices[i]
+		if got.SeatID != w.seat || got.Price != w.price || got.BasePrice != 50000 || got.SeatClass != classes[w.seat] {
+			t.Errorf("seat %d = %+v, want %s priced %d", i, got, w.seat, w.price)
+		}
+		if len(got.Adjustments) != len(w.adjustments) {
+			t.Errorf("%s: %d adjustments, want rules %v", w.seat, len(got.Adjustments), w.adjustments)
+			continue
+		}
+		for j, ruleID := range w.adjustments {
+			if got.Adjustments[j].RuleId != ruleID {
+				t.Errorf("%s: adjustment %d from rule %d, want rule %d", w.seat, j, got.Adjustments[j].RuleId, ruleID)
+			}
+		}
+	}
+	if total != 135000 {
+		t.Errorf("total %d, want 135000", total)
+	}
+}
+
+func TestPriceSeatsWithoutRules(t *testing.T) {
+	prices, total := priceSeats([]string{"B2", "B3"}, map[string]string{"B2": "regular", "B3": "regular"}, 45000, nil)
+	if total != 90000 {
+		t.Errorf("total %d, want 90000", total)
+	}
+	for _, p := range prices {
+		if p.Price != 45000 || p.Adjustments == nil || len(p.Adjustments) != 0 {
+			t.Errorf("%s = %+v, want base price with empty adjustments", p.SeatID, p)
+		}
+	}
+}
+
+func TestConfirmsQuote(t *testing.T) {
+	tests := []struct {
+		price float64
+		total int
+		want  bool
+	}{
+		{price: 0, total: 50000, want: true},
+		{price: 50000, total: 50000, want: true},
+		{price: 50000.9, total: 50000, want: false},
+		{price: 49999.99, total: 50000, want: false},
+		{price: 45000, total: 50000, want: false},
+	}
+	for _, tt := range tests {
+		if got := confirmsQuote(tt.price, tt.total); got != tt.want {
+			t.Errorf("confirmsQuote(%v, %d) = %v, want %v", tt.price, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestDayType(t *testing.T) {
+	tests := map[string]string{
+		"2025-09-19": "weekday", // Jumat
+		"2025-09-20": "weekend",
+		"2025-09-21": "weekend",
+		"2025-09-22": "weekday",
+	}
+	for date, want := range tests {
+		d, err := time.Parse(time.DateOnly, date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := dayType(d); got != want {
+			t.Errorf("dayType(%s) = %s, want %s", date, got, want)
+		}
+	}
+}
@@ -4,14 +4,23 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"log"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/utils"
 	"github.com/raihaninkam/tickitz/pkg"
 )
 
+// ProfileStore profil dan password user, diimplementasikan ProfileRepository
+type ProfileStore interface {
+	GetProfileResponse(ctx context.Context, userID int) (models.UserProfileResponse, error)
+	UpdateProfile(ctx context.Context, userId int, req models.ProfileUpdateRequest) (*models.Profile, error)
+	ChangePassword(ctx context.Context, userId int, oldPass, newPass string) error
+}
+
+var _ ProfileStore = (*ProfileRepository)(nil)
+
 type ProfileRepository struct {
 	db *pgxpool.Pool
 	hc *pkg.HashConfig
@@ -42,7 +51,7 @@ func (p *ProfileRepository) GetProfileResponse(ctx context.Context, userID int)
 		if err == pgx.ErrNoRows {
 			return models.UserProfileResponse{}, errors.New("user profile not found")
 		}
-		log.Println("GetProfileResponse error:", err)
+		utils.Logger(ctx).Error("get profile failed", "error", err)
 		return models.UserProfileResponse{}, err
 	}
 	return profile, nil
@@ -125,6 +134,13 @@ func (p *ProfileRepository) ChangePassword(ctx context.Context, userId int, oldP
 
 // order history
 
+// OrderHistoryStore riwayat order user, diimplementasikan OrderHistory
+type OrderHistoryStore interface {
+	GetOrderHistory(ctx context.Context, userId int) ([]models.OrderHistory, error)
+}
+
+var _ OrderHistoryStore = (*OrderHistory)(nil)
+
 type OrderHistory struct {
 	Db *pgxpool.Pool
 }
@@ -141,28 +157,31 @@ func (o *OrderHistory) GetOrderHistory(ctx context.Context, userId int) ([]model
 			o.price,
 			o.payment_id,
 			o."isPaid",
+			o.status,
 			o.created_at,
 			o.now_showing_id,
 			m.title AS movie_title,
 			ns.date AS show_date,
 			ns.time AS show_time,
 			c.cinema_name,
-			json_agg(json_build_object(
+			COALESCE(json_agg(json_build_object(
 				'row', s.row,
 				'seat_number', s.seat_number
-			)) AS seats,
-			t.qr_code
+			)) FILTER (WHERE s.id IS NOT NULL), '[]') AS seats,
+			t.qr_code,
+			(SELECT r.status FROM refunds r WHERE r.orders_id = o.id ORDER BY r.id DESC LIMIT 1) AS refund_status
 		FROM orders o
 		JOIN now_showing ns ON o.now_showing_id = ns.id
 		JOIN movies m ON ns.movie_id = m.id
 		JOIN cinemas c ON ns.cinemas_id = c.id
-		JOIN showing_seats ss ON o.now_showing_id = ss.now_showing_id AND ss.user_id = o.users_id
-		JOIN seats s ON ss.seat_id = s.id
+		-- kursi order yang dibatalkan / gagal sudah dilepas, order tetap tampil dengan daftar kursi kosong
+		LEFT JOIN showing_seats ss ON ss.orders_id = o.id
+		LEFT JOIN seats s ON ss.seat_id = s.id
 		JOIN orders_ticket ot ON o.id = ot.orders_id
 		JOIN ticket t ON ot.ticket_id = t.id
 		WHERE o.users_id = $1
 		GROUP BY 
-			o.id, o.users_id, o.price, o.payment_id, o."isPaid",
+			o.id, o.users_id, o.price, o.payment_id, o."isPaid", o.status,
 			o.created_at, o.now_showing_id,
 			m.title, ns.date, ns.time, c.cinema_name, t.qr_code
 		ORDER BY o.created_at DESC;
@@ -170,7 +189,7 @@ func (o *OrderHistory) GetOrderHistory(ctx context.Context, userId int) ([]model
 
 	rows, err := o.Db.Query(ctx, sql, userId)
 	if err != nil {
-		log.Printf("query error: %v", err)
+		utils.Logger(ctx).Error("order history query failed", "error", err)
 		return nil, err
 	}
 	defer rows.Close()
@@ -186,6 +205,7 @@ func (o *OrderHistory) GetOrderHistory(ctx context.Context, userId int) ([]model
 			&oh.Price,
 			&oh.PaymentId,
 			&oh.IsPaid,
+			&oh.Status,
 			&oh.CreatedAt,
 			&oh.NowShowingId,
 			&oh.MovieTitle,
@@ -194,14 +214,15 @@ func (o *OrderHistory) GetOrderHistory(ctx context.Context, userId int) ([]model
 			&oh.CinemaName,
 			&seatsJSON,
 			&oh.QrCode,
+			&oh.RefundStatus,
 		); err != nil {
-			log.Printf("scan error: %v", err)
+			utils.Logger(ctx).Error("order history scan failed", "error", err)
 			return nil, err
 		}
 
 		// unmarshal kursi
 		if err := json.Unmarshal(seatsJSON, &oh.Seats); err != nil {
-			log.Printf("unmarshal seats error: %v | data: %s", err, string(seatsJSON))
+			utils.Logger(ctx).Error("order history seats decode failed", "error", err, "seats", string(seatsJSON))
 			return nil, err
 		}
 
@@ -0,0 +1,284 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/pkg"
+	"github.com/redis/go-redis/v9"
+)
+
+// RefundStore pembatalan order dan refund, diimplementasikan RefundRepository
+type RefundStore interface {
+	CancelOrder(rctx context.Context, orderID int, requesterID *int, cancelledBy int, reason string) (models.CancelOrderResponse, error)
+	CompleteRefund(rctx context.Context, refundID int, succeeded bool) (models.Refund, error)
+}
+
+var _ RefundStore = (*RefundRepository)(nil)
+
+type RefundRepository struct {
+	db     *pgxpool.Pool
+	rdb    *redis.Client
+	cutoff time.Duration
+}
+
+func NewRefundRepository(db *pgxpool.Pool, rdb *redis.Client, cutoff time.Duration) *RefundRepository {
+	return &RefundRepository{db: db, rdb: rdb, cutoff: cutoff}
+}
+
+// CancelOrder membatalkan order dalam satu transaksi: kursi dilepas, tiket di-void,
+// poin dikembalikan dan refund dicatat (pending) jika order sudah dibayar dengan uang.
+// requesterID nil berarti dibatalkan admin, tanpa cek pemilik dan batas waktu
+func (r *RefundRepository) CancelOrder(rctx context.Context, orderID int, requesterID *int, cancelledBy int, reason string) (models.CancelOrderResponse, error) {
+	tx, err := r.db.Begin(rctx)
+	if err != nil {
+		return models.CancelOrderResponse{}, err
+	}
+	defer tx.Rollback(rctx)
+
+	var usersID int
+	var isPaid, beforeCutoff bool
+	var status string
+	orderSQL := `SELECT o.users_id, COALESCE(o."isPaid", false), o.status,
+					(ns.date + ns.time) > (NOW()::timestamp + make_interval(mins => $2))
+				 FROM orders o
+				 JOIN now_showing ns ON ns.id = o.now_showing_id
+				 WHERE o.id = $1
+				 FOR UPDATE OF o`
+	if err := tx.QueryRow(rctx, orderSQL, orderID, int(r.cutoff.Minutes())).Scan(&usersID, &isPaid, &status, &beforeCutoff); err != nil {
+		if err == pgx.ErrNoRows {
+			return models.CancelOrderResponse{}, errors.New("order not found")
+		}
+		return models.CancelOrderResponse{}, err
+	}
+
+	if requesterID != nil && *requesterID != usersID {
+		return models.CancelOrderResponse{}, errors.New("order not found")
+	}
+	switch status {
+	case "cancelled":
+		return models.CancelOrderResponse{}, errors.New("order already cancelled")
+	case "failed":
+		return models.CancelOrderResponse{}, errors.New("order cannot be cancelled")
+	}
+	if requesterID != nil && !beforeCutoff {
+		return models.CancelOrderResponse{}, errors.New("cancellation window closed")
+	}
+
+	releasedKeys, err := releaseOrderSeats(rctx, tx, orderID)
+	if err != nil {
+		return models.CancelOrderResponse{}, err
+	}
+
+	if err := voidOrderTickets(rctx, tx, orderID); err != nil {
+		return models.CancelOrderResponse{}, err
+	}
+
+	poinReversed, poinRefunded, err := reverseOrderPoints(rctx, tx, orderID)
+	if err != nil {
+		return models.CancelOrderResponse{}, err
+	}
+
+	var refund *models.Refund
+	if isPaid {
+		var trxID *int
+		var reference string
+		var amount int
+		trxSQL := `SELECT id, reference, amount FROM payment_transactions
+				   WHERE orders_id = $1 AND status = 'paid'
+				   ORDER BY paid_at DESC
+				   LIMIT 1`
+		if err := tx.QueryRow(rctx, trxSQL, orderID).Scan(&trxID, &reference, &amount); err != nil {
+			if err != pgx.ErrNoRows {
+				return models.CancelOrderResponse{}, err
+			}
+			// order lunas sebelum ada payment_transactions, refund diproses manual
+			trxID = nil
+			if err := tx.QueryRow(rctx, "SELECT COALESCE(price, 0) FROM orders WHERE id = $1", orderID).Scan(&amount); err != nil {
+				return models.CancelOrderResponse{}, err
+			}
+		}
+
+		// order yang seluruhnya dibayar poin tidak punya uang untuk dikembalikan, cukup poinnya
+		if amount > 0 {
+			var reasonArg *string
+			if reason != "" {
+				reasonArg = &reason
+			}
+			refundSQL := `INSERT INTO refunds (orders_id, payment_transactions_id, amount, reason, status, cancelled_by, created_at, updated_at)
+						  VALUES ($1, $2, $3, $4, 'pending', $5, NOW(), NOW())
+						  RETURNING ` + refundColumns
+			row, err := scanRefund(tx.QueryRow(rctx, refundSQL, orderID, trxID, amount, reasonArg, cancelledBy))
+			if err != nil {
+				return models.CancelOrderResponse{}, err
+			}
+			row.Reference = reference
+			refund = &row
+		}
+	} else {
+		// charge yang belum dibayar ditutup, webhook paid setelah ini akan ditolak
+		if _, err := tx.Exec(rctx, "UPDATE payment_transactions SET status = 'failed', updated_at = NOW() WHERE orders_id = $1 AND status = 'pending'", orderID); err != nil {
+			return models.CancelOrderResponse{}, err
+		}
+	}
+
+	response := models.CancelOrderResponse{
+		OrderId:      orderID,
+		ReleasedSeat: len(releasedKeys),
+		PoinReversed: poinReversed,
+		PoinRefunded: poinRefunded,
+		Refund:       refund,
+	}
+	cancelSQL := `UPDATE orders SET status = 'cancelled', cancelled_at = NOW(), updated_at = NOW()
+				  WHERE id = $1
+				  RETURNING status, cancelled_at`
+	if err := tx.QueryRow(rctx, cancelSQL, orderID).Scan(&response.Status, &response.CancelledAt); err != nil {
+		return models.CancelOrderResponse{}, err
+	}
+
+	if err := tx.Commit(rctx); err != nil {
+		return models.CancelOrderResponse{}, err
+	}
+	deleteSeatHoldKeys(rctx, r.rdb, releasedKeys)
+	return response, nil
+}
+
+// voidOrderTickets menandai tiket order tidak berlaku lagi
+func voidOrderTickets(rctx context.Context, q querier, orderID int) error {
+	sql := `UPDATE ticket SET status = 'void', voided_at = NOW(), updated_at = NOW()
+			WHERE id IN (SELECT ticket_id FROM orders_ticket WHERE orders_id = $1)`
+	_, err := q.Exec(rctx, sql, orderID)
+	return err
+}
+
+// CompleteRefund menyimpan hasil refund dari payment gateway
+func (r *RefundRepository) CompleteRefund(rctx context.Context, refundID int, succeeded bool) (models.Refund, error) {
+	tx, err := r.db.Begin(rctx)
+	if err != nil {
+		return models.Refund{}, err
+	}
+	defer tx.Rollback(rctx)
+
+	status := "failed"
+	if succeeded {
+		status = "refunded"
+	}
+
+	sql := `UPDATE refunds SET status = $1, updated_at = NOW()
+			WHERE id = $2
+			RETURNING ` + refundColumns
+	refund, err := scanRefund(tx.QueryRow(rctx, sql, status, refundID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.Refund{}, errors.New("refund not found")
+		}
+		return models.Refund{}, err
+	}
+
+	if succeeded && refund.PaymentTransactionsId != nil {
+		if _, err := tx.Exec(rctx, "UPDATE payment_transactions SET status = 'refunded', updated_at = NOW() WHERE id = $1", *refund.PaymentTransactionsId); err != nil {
+			return models.Refund{}, err
+		}
+	}
+
+	if err := tx.Commit(rctx); err != nil {
+		return models.Refund{}, err
+	}
+	return refund, nil
+}
+
+const refundColumns = `id, orders_id, payment_transactions_id, amount, reason, status, attempts, cancelled_by, created_at, updated_at`
+
+func scanRefund(row pgx.Row) (models.Refund, error) {
+	var refund models.Refund
+	err := row.Scan(
+		&refund.Id,
+		&refund.OrdersId,
+		&refund.PaymentTransactionsId,
+		&refund.Amount,
+		&refund.Reason,
+		&refund.Status,
+		&refund.Attempts,
+		&refund.CancelledBy,
+		&refund.CreatedAt,
+		&refund.UpdatedAt,
+	)
+	return refund, err
+}
+
+// maxRefundAttempts refund yang tetap gagal setelah ini tidak dicoba lagi dan harus diproses manual
+const maxRefundAttempts = 5
+
+// RetryFailedRefunds mengirim ulang refund berstatus failed ke payment gateway. Jeda antar percobaan
+// berlipat dua mulai dari backoff (backoff, 2x, 4x, ...) sampai maxRefundAttempts.
+// Mengembalikan jumlah refund yang dicoba dan yang berhasil
+func (r *RefundRepository) RetryFailedRefunds(rctx context.Context, gateway pkg.PaymentGateway, backoff time.Duration) (int, int, error) {
+	// refund diklaim (kembali pending) sebelum dikirim supaya tidak diproses dua instance sekaligus
+	claimSQL := `UPDATE refunds r
+				 SET status = 'pending', attempts = r.attempts + 1, updated_at = NOW()
+				 FROM payment_transactions pt
+				 WHERE r.id = (
+					SELECT id FROM refunds
+					WHERE status = 'failed' AND payment_transactions_id IS NOT NULL AND attempts < $1
+					  AND updated_at <= NOW() - make_interval(secs => $2 * power(2, attempts))
+					ORDER BY updated_at
+					LIMIT 1
+					FOR UPDATE SKIP LOCKED
+				 ) AND pt.id = r.payment_transactions_id
+				 RETURNING r.id, pt.reference, r.amount, r.attempts`
+
+	retried, refunded := 0, 0
+	for {
+		var refundID, amount, attempts int
+		var reference string
+		if err := r.db.QueryRow(rctx, claimSQL, maxRefundAttempts, backoff.Seconds()).Scan(&refundID, &reference, &amount, &attempts); err != nil {
+			if err == pgx.ErrNoRows {
+				return retried, refunded, nil
+			}
+			return retried, refunded, err
+		}
+		retried++
+
+		_, refundErr := gateway.Refund(rctx, reference, amount)
+		if refundErr != nil {
+			logger := slog.With("refund_id", refundID, "attempts", attempts, "error", refundErr)
+			if attempts >= maxRefundAttempts {
+				logger.Error("refund retry exhausted, needs manual processing")
+			} else {
+				logger.Warn("refund retry failed")
+			}
+		}
+		if _, err := r.CompleteRefund(rctx, refundID, refundErr == nil); err != nil {
+			return retried, refunded, err
+		}
+		if refundErr == nil {
+			refunded++
+		}
+	}
+}
+
+// RunRefundRetrier menjalankan RetryFailedRefunds setiap interval sampai ctx selesai
+func (r *RefundRepository) RunRefundRetrier(ctx context.Context, gateway pkg.PaymentGateway, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			retried, refunded, err := r.RetryFailedRefunds(ctx, gateway, interval)
+			if err != nil {
+				slog.Error("refund retrier failed", "error", err)
+			}
+			if retried > 0 {
+				slog.Info("refund retrier processed failed refunds", "retried", retried, "refunded", refunded)
+			}
+		}
+	}
+}
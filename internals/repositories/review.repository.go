@@ -0,0 +1,290 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// ReviewStore review movie dan moderasinya, diimplementasikan ReviewRepository
+type ReviewStore interface {
+	UpsertReview(rctx context.Context, userID, movieID int, req models.ReviewRequest) (models.Review, bool, error)
+	DeleteOwnReview(rctx context.Context, userID, movieID int) error
+	GetMovieReviews(rctx context.Context, movieID int, sort string, offset, limit int) ([]models.Review, int, error)
+	GetReviewSummary(rctx context.Context, movieID int) (models.ReviewSummary, error)
+	GetReviews(rctx context.Context, filter models.ReviewFilter, offset, limit int) ([]models.Review, int, error)
+	ModerateReview(rctx context.Context, reviewID, adminID int, req models.ModerateReviewRequest) (models.Review, error)
+	DeleteReview(rctx context.Context, reviewID int) error
+}
+
+var _ ReviewStore = (*ReviewRepository)(nil)
+
+// ReviewRepository review movie dari user dan agregat movie_stats (materialized view)
+type ReviewRepository struct {
+	db  *pgxpool.Pool
+	rdb *redis.Client
+}
+
+func NewReviewRepository(db *pgxpool.Pool, rdb *redis.Client) *ReviewRepository {
+	return &ReviewRepository{db: db, rdb: rdb}
+}
+
+// nama yang ditampilkan: nama depan + inisial nama belakang, contoh "Budi S."
+const reviewColumns = `r.id, r.movies_id, m.title, r.users_id,
+	COALESCE(NULLIF(CONCAT_WS(' ', p.first_name, NULLIF(LEFT(p.last_name, 1) || '.', '.')), ''), 'Pengguna Tickitz'),
+	r.rating, r.body, r.status, r.moderation_note, r.moderated_at, r.created_at, r.updated_at`
+
+const reviewFrom = ` FROM movie_reviews r
+	JOIN movies m ON m.id = r.movies_id
+	LEFT JOIN profile p ON p.id = r.users_id`
+
+// reviewSorts urutan listing review publik
+var reviewSorts = map[string]string{
+	"newest":      "r.created_at DESC, r.id DESC",
+	"rating_high": "r.rating DESC, r.created_at DESC, r.id DESC",
+	"rating_low":  "r.rating ASC, r.created_at DESC, r.id DESC",
+}
+
+func scanReview(row pgx.Row) (models.Review, error) {
+	var review models.Review
+	err := row.Scan(
+		&review.Id,
+		&review.MovieId,
+		&review.MovieTitle,
+		&review.UserId,
+		&review.UserName,
+		&review.Rating,
+		&review.Body,
+		&review.Status,
+		&review.ModerationNote,
+		&review.ModeratedAt,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+	)
+	return review, err
+}
+
+func getReview(rctx context.Context, q querier, reviewID int) (models.Review, error) {
+	review, err := scanReview(q.QueryRow(rctx, "SELECT "+reviewColumns+reviewFrom+" WHERE r.id = $1", reviewID))
+	if err == pgx.ErrNoRows {
+		return models.Review{}, errors.New("review not found")
+	}
+	return review, err
+}
+
+// UpsertReview membuat review atau mengubah review user sebelumnya untuk movie yang sama.
+// Hanya user dengan order paid untuk movie ini yang boleh memberi review.
+// Review yang disembunyikan admin tetap hidden walaupun diubah
+func (r *ReviewRepository) UpsertReview(rctx context.Context, userID, movieID int, req models.ReviewRequest) (models.Review, bool, error) {
+	var exists bool
+	if err := r.db.QueryRow(rctx, "SELECT EXISTS(SELECT 1 FROM movies WHERE id = $1 AND COALESCE(is_deleted, false) = false)", movieID).Scan(&exists); err != nil {
+		return models.Review{}, false, err
+	}
+	if !exists {
+		return models.Review{}, false, errors.New("movie not found")
+	}
+
+	var eligible bool
+	eligibleSQL := `SELECT EXISTS(
+						SELECT 1 FROM orders o
+						JOIN now_showing ns ON ns.id = o.now_showing_id
+						WHERE o.users_id = $1 AND ns.movie_id = $2 AND o.status = 'paid'
+					)`
+	if err := r.db.QueryRow(rctx, eligibleSQL, userID, movieID).Scan(&eligible); err != nil {
+		return models.Review{}, false, err
+	}
+	if !eligible {
+		return models.Review{}, false, errors.New("no paid order")
+	}
+
+	var body *string
+	if req.Body != "" {
+		body = &req.Body
+	}
+
+	var reviewID int
+	var created bool
+	upsertSQL := `INSERT INTO movie_reviews (movies_id, users_id, rating, body, created_at, updated_at)
+				  VALUES ($1, $2, $3, $4, NOW(), NOW())
+				  ON CONFLICT (movies_id, users_id)
+				  DO UPDATE SET rating = EXCLUDED.rating, body = EXCLUDED.body, updated_at = NOW()
+				  RETURNING id, (xmax = 0)`
+	if err := r.db.QueryRow(rctx, upsertSQL, movieID, userID, req.Rating, body).Scan(&reviewID, &created); err != nil {
+		return models.Review{}, false, err
+	}
+
+	review, err := getReview(rctx, r.db, reviewID)
+	return review, created, err
+}
+
+// DeleteOwnReview menghapus review milik user pada sebuah movie
+func (r *ReviewRepository) DeleteOwnReview(rctx context.Context, userID, movieID int) error {
+	res, err := r.db.Exec(rctx, "DELETE FROM movie_reviews WHERE movies_id = $1 AND users_id = $2", movieID, userID)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return errors.New("review not found")
+	}
+	return nil
+}
+
+// GetMovieReviews review published sebuah movie per halaman
+func (r *ReviewRepository) GetMovieReviews(rctx context.Context, movieID int, sort string, offset, limit int) ([]models.Review, int, error) {
+	orderBy, ok := reviewSorts[sort]
+	if !ok {
+		orderBy = reviewSorts["newest"]
+	}
+
+	sql := "SELECT " + reviewColumns + ", COUNT(*) OVER ()" + reviewFrom + `
+		WHERE r.movies_id = $1 AND r.status = 'published'
+		ORDER BY ` + orderBy + `
+		LIMIT $2 OFFSET $3`
+	return r.queryReviews(rctx, sql, movieID, limit, offset)
+}
+
+// GetReviewSummary rata-rata dan sebaran rating (dihitung langsung, bukan dari movie_stats)
+func (r *ReviewRepository) GetReviewSummary(rctx context.Context, movieID int) (models.ReviewSummary, error) {
+	summary := models.ReviewSummary{
+		MovieId:      movieID,
+		Distribution: map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 0},
+	}
+
+	sql := `SELECT rating, COUNT(*)::int4 FROM movie_reviews
+			WHERE movies_id = $1 AND status = 'published'
+			GROUP BY rating`
+	rows, err := r.db.Query(rctx, sql, movieID)
+	if err != nil {
+		return models.ReviewSummary{}, err
+	}
+	defer rows.Close()
+
+	total := 0
+	for rows.Next() {
+		var rating, count int
+		if err := rows.Scan(&rating, &count); err != nil {
+			return models.ReviewSummary{}, err
+		}
+		summary.Distribution[rating] = count
+		summary.ReviewCount += count
+		total += rating * count
+	}
+	if err := rows.Err(); err != nil {
+		return models.ReviewSummary{}, err
+	}
+
+	if summary.ReviewCount > 0 {
+		avg := float64(total) / float64(summary.ReviewCount)
+		summary.AvgRating = &avg
+	}
+	return summary, nil
+}
+
+// GetReviews semua review untuk moderasi admin, bisa difilter per movie dan status
+func (r *ReviewRepository) GetReviews(rctx context.Context, filter models.ReviewFilter, offset, limit int) ([]models.Review, int, error) {
+	sql := "SELECT " + reviewColumns + ", COUNT(*) OVER ()" + reviewFrom + `
+		WHERE ($1 = 0 OR r.movies_id = $1) AND ($2 = '' OR r.status = $2)
+		ORDER BY r.created_at DESC, r.id DESC
+		LIMIT $3 OFFSET $4`
+	return r.queryReviews(rctx, sql, filter.MovieId, filter.Status, limit, offset)
+}
+
+// ModerateReview mengubah status review (published / hidden) oleh admin
+func (r *ReviewRepository) ModerateReview(rctx context.Context, reviewID, adminID int, req models.ModerateReviewRequest) (models.Review, error) {
+	var note *string
+	if req.Note != "" {
+		note = &req.Note
+	}
+
+	sql := `UPDATE movie_reviews
+			SET status = $1, moderation_note = $2, moderated_by = $3, moderated_at = NOW(), updated_at = NOW()
+			WHERE id = $4`
+	res, err := r.db.Exec(rctx, sql, req.Status, note, adminID, reviewID)
+	if err != nil {
+		return models.Review{}, err
+	}
+	if res.RowsAffected() == 0 {
+		return models.Review{}, errors.New("review not found")
+	}
+	return getReview(rctx, r.db, reviewID)
+}
+
+func (r *ReviewRepository) DeleteReview(rctx context.Context, reviewID int) error {
+	res, err := r.db.Exec(rctx, "DELETE FROM movie_reviews WHERE id = $1", reviewID)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return errors.New("review not found")
+	}
+	return nil
+}
+
+func (r *ReviewRepository) queryReviews(rctx context.Context, sql string, args ...any) ([]models.Review, int, error) {
+	rows, err := r.db.Query(rctx, sql, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	reviews := []models.Review{}
+	total := 0
+	for rows.Next() {
+		var review models.Review
+		if err := rows.Scan(
+			&review.Id,
+			&review.MovieId,
+			&review.MovieTitle,
+			&review.UserId,
+			&review.UserName,
+			&review.Rating,
+			&review.Body,
+			&review.Status,
+			&review.ModerationNote,
+			&review.ModeratedAt,
+			&review.CreatedAt,
+			&review.UpdatedAt,
+			&total,
+		); err != nil {
+			return nil, 0, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, total, rows.Err()
+}
+
+// RefreshStats menghitung ulang movie_stats lalu menghapus cache popular movie
+func (r *ReviewRepository) RefreshStats(rctx context.Context) error {
+	if _, err := r.db.Exec(rctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY movie_stats"); err != nil {
+		return fmt.Errorf("refresh movie_stats: %w", err)
+	}
+	if r.rdb != nil {
+		_ = utils.InvalidateCache(rctx, r.rdb, "popular_movies")
+	}
+	return nil
+}
+
+// RunStatsRefresher menjalankan RefreshStats setiap interval sampai ctx selesai
+func (r *ReviewRepository) RunStatsRefresher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.RefreshStats(ctx); err != nil {
+				slog.Error("movie stats refresher failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"math"
+	"testing"
+
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/testutil"
+)
+
+func TestMovieReviewsAndStats(t *testing.T) {
+	env := testutil.Setup(t)
+	env.Seed(t, "demo")
+	ctx := t.Context()
+	reviews := NewReviewRepository(env.DB, env.RDB)
+
+	if _, _, err := reviews.UpsertReview(ctx, 2, 1, models.ReviewRequest{Rating: 5}); err == nil || err.Error() != "no paid order" {
+		t.Errorf("review without paid order: err %v, want no paid order", err)
+	}
+	if _, _, err := reviews.UpsertReview(ctx, 2, 999, models.ReviewRequest{Rating: 5}); err == nil || err.Error() != "movie not found" {
+		t.Errorf("unknown movie: err %v, want movie not found", err)
+	}
+
+	// user 1 dan 2 sudah membayar showing 1 (Inception)
+	if _, err := env.DB.Exec(ctx, `INSERT INTO orders (users_id, price, payment_id, now_showing_id, cinemas_id, status, created_at, updated_at)
+								   VALUES (1, 50000, 1, 1, 1, 'paid', NOW(), NOW()), (2, 50000, 1, 1, 1, 'paid', NOW(), NOW())`); err != nil {
+		t.Fatal(err)
+	}
+
+	first, created, err := reviews.UpsertReview(ctx, 2, 1, models.ReviewRequest{Rating: 5, Body: "Keren"})
+	if err != nil || !created {
+		t.Fatalf("first review: created %v, err %v", created, err)
+	}
+	updated, created, err := reviews.UpsertReview(ctx, 2, 1, models.ReviewRequest{Rating: 4})
+	if err != nil || created || updated.Id != first.Id || updated.Rating != 4 {
+		t.Errorf("second review of the same user: %+v created %v err %v, want update of review %d", updated, created, err, first.Id)
+	}
+	other, _, err := reviews.UpsertReview(ctx, 1, 1, models.ReviewRequest{Rating: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := reviews.GetReviewSummary(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.ReviewCount != 2 || summary.AvgRating == nil || *summary.AvgRating != 3 || summary.Distribution[4] != 1 || summary.Distribution[2] != 1 {
+		t.Errorf("summary %+v, want 2 reviews averaging 3", summary)
+	}
+
+	// review yang disembunyikan admin tidak dihitung dan tetap hidden walaupun diubah
+	if _, err := reviews.ModerateReview(ctx, other.Id, 1, models.ModerateReviewRequest{Status: "hidden", Note: "spoiler"}); err != nil {
+		t.Fatal(err)
+	}
+	if edited, _, err := reviews.UpsertReview(ctx, 1, 1, models.ReviewRequest{Rating: 3}); err != nil || edited.Status != "hidden" {
+		t.Errorf("edited hidden review: status %q, err %v, want hidden", edited.Status, err)
+	}
+	published, total, err := reviews.GetMovieReviews(ctx, 1, "newest", 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(published) != 1 || published[0].Id != first.Id {
+		t.Errorf("published reviews %+v (total %d), want only review %d", published, total, first.Id)
+	}
+
+	if err := reviews.RefreshStats(ctx); err != nil {
+		t.Fatal(err)
+	}
+	var reviewCount int
+	var avgRating, bayesRating float64
+	if err := env.DB.QueryRow(ctx, "SELECT review_count, avg_rating, bayes_rating FROM movie_stats WHERE movies_id = 1").Scan(&reviewCount, &avgRating, &bayesRating); err != nil {
+		t.Fatal(err)
+	}
+	// satu review bintang 4 ditarik ke 3.0 dengan bobot 10 review
+	if reviewCount != 1 || avgRating != 4 || math.Abs(bayesRating-34.0/11) > 1e-9 {
+		t.Errorf("movie_stats review_count %d avg %v bayes %v, want 1, 4 and %v", reviewCount, avgRating, bayesRating, 34.0/11)
+	}
+
+	if err := reviews.DeleteOwnReview(ctx, 2, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := reviews.DeleteOwnReview(ctx, 2, 1); err == nil || err.Error() != "review not found" {
+		t.Errorf("delete twice: err %v, want review not found", err)
+	}
+}
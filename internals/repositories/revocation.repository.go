@@ -0,0 +1,350 @@
+package repositories
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// TokenRevocationStore menyimpan access token (berdasarkan jti) dan session yang sudah dicabut.
+// Dicek di setiap request oleh JWTMiddlewareWithBlacklist
+type TokenRevocationStore interface {
+	// RevokeToken mencabut access token sampai expiresAt (masa berlaku token)
+	RevokeToken(rctx context.Context, jti string, expiresAt time.Time) error
+	IsTokenRevoked(rctx context.Context, jti string) (bool, error)
+	// RevokeSession menandai semua access token milik session sebagai dicabut
+	RevokeSession(rctx context.Context, sessionID string) error
+	IsSessionRevoked(rctx context.Context, sessionID string) (bool, error)
+	// Maintain housekeeping berkala: hapus data kadaluarsa dan sinkronisasi cache
+	Maintain(rctx context.Context) error
+}
+
+// NewTokenRevocationStore memilih backend sesuai TOKEN_REVOCATION_STORE.
+// "redis" (default) memakai Redis dengan Postgres sebagai fallback, "postgres" hanya memakai Postgres
+func NewTokenRevocationStore(db *pgxpool.Pool, rdb *redis.Client, backend string, accessTTL time.Duration) TokenRevocationStore {
+	pg := NewPostgresRevocationStore(db)
+	if backend == "postgres" || rdb == nil {
+		return pg
+	}
+	return NewFallbackRevocationStore(NewRedisRevocationStore(rdb, accessTTL), pg)
+}
+
+// RunRevocationMaintenance menjalankan store.Maintain sekali saat start lalu setiap interval sampai ctx selesai
+func RunRevocationMaintenance(ctx context.Context, store TokenRevocationStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := store.Maintain(ctx); err != nil {
+			slog.Error("token revocation maintenance failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RedisRevocationStore menyimpan pencabutan di Redis dengan TTL sisa masa berlaku token,
+// sehingga key terhapus otomatis begitu token memang sudah expired
+type RedisRevocationStore struct {
+	rdb       *redis.Client
+	accessTTL time.Duration
+}
+
+func NewRedisRevocationStore(rdb *redis.Client, accessTTL time.Duration) *RedisRevocationStore {
+	return &RedisRevocationStore{rdb: rdb, accessTTL: accessTTL}
+}
+
+func revokedTokenKey(jti string) string {
+	return "revoked:jti:" + jti
+}
+
+func revokedSessionKey(sessionID string) string {
+	return "revoked:sid:" + sessionID
+}
+
+func (r *RedisRevocationStore) RevokeToken(rctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return utils.WithRedis(rctx, r.rdb, func() error {
+		return r.rdb.Set(rctx, revokedTokenKey(jti), 1, ttl).Err()
+	})
+}
+
+func (r *RedisRevocationStore) IsTokenRevoked(rctx context.Context, jti string) (bool, error) {
+	return r.exists(rctx, revokedTokenKey(jti))
+}
+
+// RevokeSession cukup disimpan selama umur access token, token yang lebih lama sudah pasti expired
+func (r *RedisRevocationStore) RevokeSession(rctx context.Context, sessionID string) error {
+	return utils.WithRedis(rctx, r.rdb, func() error {
+		return r.rdb.Set(rctx, revokedSessionKey(sessionID), 1, r.accessTTL).Err()
+	})
+}
+
+func (r *RedisRevocationStore) IsSessionRevoked(rctx context.Context, sessionID string) (bool, error) {
+	return r.exists(rctx, revokedSessionKey(sessionID))
+}
+
+func (r *RedisRevocationStore) exists(rctx context.Context, key string) (bool, error) {
+	var n int64
+	err := utils.WithRedis(rctx, r.rdb, func() (err error) {
+		n, err = r.rdb.Exists(rctx, key).Result()
+		return err
+	})
+	return n > 0, err
+}
+
+// Maintain tidak perlu apa-apa, key dibersihkan oleh TTL Redis
+func (r *RedisRevocationStore) Maintain(rctx context.Context) error {
+	return nil
+}
+
+// PostgresRevocationStore memakai tabel blacklist_tokens dan user_sessions.revoked_at
+type PostgresRevocationStore struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRevocationStore(db *pgxpool.Pool) *PostgresRevocationStore {
+	return &PostgresRevocationStore{db: db}
+}
+
+func (p *PostgresRevocationStore) RevokeToken(rctx context.Context, jti string, expiresAt time.Time) error {
+	sql := `INSERT INTO blacklist_tokens (jti, expires_at, created_at) VALUES ($1, $2, NOW())
+			ON CONFLICT (jti) DO NOTHING`
+	_, err := p.db.Exec(rctx, sql, jti, expiresAt)
+	return err
+}
+
+func (p *PostgresRevocationStore) IsTokenRevoked(rctx context.Context, jti string) (bool, error) {
+	sql := "SELECT EXISTS(SELECT 1 FROM blacklist_tokens WHERE jti = $1 AND expires_at > NOW())"
+
+	var exists bool
+	if err := p.db.QueryRow(rctx, sql, jti).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+func (p *PostgresRevocationStore) RevokeSession(rctx context.Context, sessionID string) error {
+	return revokeSession(rctx, p.db, sessionID, "revoked")
+}
+
+func (p *PostgresRevocationStore) IsSessionRevoked(rctx context.Context, sessionID string) (bool, error) {
+	sql := "SELECT EXISTS(SELECT 1 FROM user_sessions WHERE id = $1 AND revoked_at IS NOT NULL)"
+
+	var revoked bool
+	if err := p.db.QueryRow(rctx, sql, sessionID).Scan(&revoked); err != nil {
+		return false, err
+	}
+	return revoked, nil
+}
+
+// Maintain menghapus token blacklist yang sudah expired
+func (p *PostgresRevocationStore) Maintain(rctx context.Context) error {
+	res, err := p.db.Exec(rctx, "DELETE FROM blacklist_tokens WHERE expires_at < NOW()")
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() > 0 {
+		utils.Logger(rctx).Info("token revocation maintenance removed expired tokens", "tokens", res.RowsAffected())
+	}
+	return nil
+}
+
+// activeRevocations token blacklist yang belum expired dan session yang dicabut dalam sessionTTL terakhir,
+// session yang lebih lama tidak mungkin masih punya access token aktif
+func (p *PostgresRevocationStore) activeRevocations(rctx context.Context, sessionTTL time.Duration) ([]revocation, error) {
+	sql := `SELECT 'jti', jti, expires_at FROM blacklist_tokens WHERE expires_at > NOW()
+			UNION ALL
+			SELECT 'sid', id::text, revoked_at + make_interval(secs => $1) FROM user_sessions
+			WHERE revoked_at IS NOT NULL AND revoked_at > NOW() - make_interval(secs => $1)`
+	rows, err := p.db.Query(rctx, sql, sessionTTL.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revocations := []revocation{}
+	for rows.Next() {
+		var kind, id string
+		var expiresAt time.Time
+		if err := rows.Scan(&kind, &id, &expiresAt); err != nil {
+			return nil, err
+		}
+		key := revokedTokenKey(id)
+		if kind == "sid" {
+			key = revokedSessionKey(id)
+		}
+		if ttl := time.Until(expiresAt); ttl > 0 {
+			revocations = append(revocations, revocation{key: key, ttl: ttl})
+		}
+	}
+	return revocations, rows.Err()
+}
+
+// revocation satu pencabutan yang masih berlaku: key Redis beserta sisa umurnya
+type revocation struct {
+	key string
+	ttl time.Duration
+}
+
+// revocationSource sumber pencabutan yang lengkap untuk FallbackRevocationStore (Postgres)
+type revocationSource interface {
+	TokenRevocationStore
+	activeRevocations(rctx context.Context, sessionTTL time.Duration) ([]revocation, error)
+}
+
+var _ revocationSource = (*PostgresRevocationStore)(nil)
+
+// revocationSyncedKey penanda bahwa Redis berisi semua pencabutan yang ada di Postgres. Penanda ini
+// ikut hilang saat Redis restart atau di-flush, sehingga key yang tidak ada tidak lagi dianggap "tidak dicabut"
+const revocationSyncedKey = "revoked:synced"
+
+// lookup cek key pencabutan sekaligus penanda sync dalam satu round trip
+func (r *RedisRevocationStore) lookup(rctx context.Context, key string) (revoked, synced bool, err error) {
+	var revokedCmd, syncedCmd *redis.IntCmd
+	err = utils.WithRedis(rctx, r.rdb, func() error {
+		_, err := r.rdb.Pipelined(rctx, func(pipe redis.Pipeliner) error {
+			revokedCmd = pipe.Exists(rctx, key)
+			syncedCmd = pipe.Exists(rctx, revocationSyncedKey)
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		return false, false, err
+	}
+	return revokedCmd.Val() > 0, syncedCmd.Val() > 0, nil
+}
+
+// restore menulis ulang pencabutan dari Postgres lalu memasang penanda sync
+func (r *RedisRevocationStore) restore(rctx context.Context, revocations []revocation) error {
+	return utils.WithRedis(rctx, r.rdb, func() error {
+		_, err := r.rdb.Pipelined(rctx, func(pipe redis.Pipeliner) error {
+			for _, rv := range revocations {
+				pipe.Set(rctx, rv.key, 1, rv.ttl)
+			}
+			pipe.Set(rctx, revocationSyncedKey, 1, 0)
+			return nil
+		})
+		return err
+	})
+}
+
+// FallbackRevocationStore membaca dari Redis dan memakai Postgres sebagai sumber kebenaran.
+// Pencabutan ditulis ke keduanya. Redis hanya dipercaya untuk menjawab "tidak dicabut" jika penanda
+// sync ada dan tidak ada penulisan ke Redis yang gagal dari instance ini; selain itu Postgres yang dicek
+// dan Redis dibangun ulang di background
+type FallbackRevocationStore struct {
+	cache *RedisRevocationStore
+	db    revocationSource
+	// dirty true jika penulisan pencabutan ke Redis gagal dan belum ditutup oleh resync
+	dirty   atomic.Bool
+	syncing atomic.Bool
+}
+
+func NewFallbackRevocationStore(cache *RedisRevocationStore, db *PostgresRevocationStore) *FallbackRevocationStore {
+	return &FallbackRevocationStore{cache: cache, db: db}
+}
+
+func (f *FallbackRevocationStore) RevokeToken(rctx context.Context, jti string, expiresAt time.Time) error {
+	if err := f.db.RevokeToken(rctx, jti, expiresAt); err != nil {
+		return err
+	}
+	if err := f.cache.RevokeToken(rctx, jti, expiresAt); err != nil {
+		utils.Logger(rctx).Warn("redis revoke token failed, using postgres fallback", "error", err)
+		f.dirty.Store(true)
+	}
+	return nil
+}
+
+func (f *FallbackRevocationStore) IsTokenRevoked(rctx context.Context, jti string) (bool, error) {
+	return f.isRevoked(rctx, revokedTokenKey(jti), func() (bool, error) {
+		return f.db.IsTokenRevoked(rctx, jti)
+	})
+}
+
+func (f *FallbackRevocationStore) RevokeSession(rctx context.Context, sessionID string) error {
+	if err := f.db.RevokeSession(rctx, sessionID); err != nil {
+		return err
+	}
+	if err := f.cache.RevokeSession(rctx, sessionID); err != nil {
+		utils.Logger(rctx).Warn("redis revoke session failed, using postgres fallback", "error", err)
+		f.dirty.Store(true)
+	}
+	return nil
+}
+
+func (f *FallbackRevocationStore) IsSessionRevoked(rctx context.Context, sessionID string) (bool, error) {
+	return f.isRevoked(rctx, revokedSessionKey(sessionID), func() (bool, error) {
+		return f.db.IsSessionRevoked(rctx, sessionID)
+	})
+}
+
+func (f *FallbackRevocationStore) isRevoked(rctx context.Context, key string, fromDB func() (bool, error)) (bool, error) {
+	revoked, synced, err := f.cache.lookup(rctx, key)
+	switch {
+	case err != nil:
+		if err != utils.ErrRedisUnavailable {
+			utils.Logger(rctx).Warn("redis revocation check failed, using postgres fallback", "error", err)
+		}
+		return fromDB()
+	case revoked:
+		return true, nil
+	case synced && !f.dirty.Load():
+		return false, nil
+	}
+
+	// Redis baru restart / di-flush atau ada pencabutan yang belum masuk Redis
+	f.resync()
+	return fromDB()
+}
+
+// resync membangun ulang Redis dari Postgres di background, paling banyak satu proses sekaligus
+func (f *FallbackRevocationStore) resync() {
+	if !f.syncing.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer f.syncing.Store(false)
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := f.sync(ctx); err != nil {
+			slog.Error("token revocation resync failed", "error", err)
+		}
+	}()
+}
+
+// sync menyalin pencabutan yang masih berlaku ke Redis. dirty dibersihkan lebih dulu supaya
+// penulisan yang gagal selama sync berjalan tetap tercatat
+func (f *FallbackRevocationStore) sync(rctx context.Context) error {
+	f.dirty.Store(false)
+	revocations, err := f.db.activeRevocations(rctx, f.cache.accessTTL)
+	if err == nil {
+		err = f.cache.restore(rctx, revocations)
+	}
+	if err != nil {
+		f.dirty.Store(true)
+	}
+	return err
+}
+
+// Maintain membersihkan Postgres lalu menyalin pencabutan yang masih berlaku ke Redis.
+// Saat pertama jalan ini juga memindahkan blacklist lama (hasil migrasi jti) ke Redis
+func (f *FallbackRevocationStore) Maintain(rctx context.Context) error {
+	if err := f.db.Maintain(rctx); err != nil {
+		return err
+	}
+	return f.sync(rctx)
+}
@@ -0,0 +1,188 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/raihaninkam/tickitz/internals/testutil"
+)
+
+// fakeRevocationSource pengganti Postgres untuk FallbackRevocationStore, menghitung query yang masuk
+type fakeRevocationSource struct {
+	mu       sync.Mutex
+	tokens   map[string]time.Time
+	sessions map[string]bool
+	queries  int
+}
+
+func newFakeRevocationSource() *fakeRevocationSource {
+	return &fakeRevocationSource{tokens: map[string]time.Time{}, sessions: map[string]bool{}}
+}
+
+func (f *fakeRevocationSource) RevokeToken(rctx context.Context, jti string, expiresAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tokens[jti] = expiresAt
+	return nil
+}
+
+func (f *fakeRevocationSource) IsTokenRevoked(rctx context.Context, jti string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queries++
+	expiresAt, ok := f.tokens[jti]
+	return ok && time.Now().Before(expiresAt), nil
+}
+
+func (f *fakeRevocationSource) RevokeSession(rctx context.Context, sessionID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sessions[sessionID] = true
+	return nil
+}
+
+func (f *fakeRevocationSource) IsSessionRevoked(rctx context.Context, sessionID string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queries++
+	return f.sessions[sessionID], nil
+}
+
+func (f *fakeRevocationSource) Maintain(rctx context.Context) error {
+	return nil
+}
+
+func (f *fakeRevocationSource) activeRevocations(rctx context.Context, sessionTTL time.Duration) ([]revocation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var revocations []revocation
+	for jti, expiresAt := range f.tokens {
+		revocations = append(revocations, revocation{key: revokedTokenKey(jti), ttl: time.Until(expiresAt)})
+	}
+	for sessionID := range f.sessions {
+		revocations = append(revocations, revocation{key: revokedSessionKey(sessionID), ttl: sessionTTL})
+	}
+	return revocations, nil
+}
+
+func (f *fakeRevocationSource) queryCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.queries
+}
+
+// waitResync menunggu resync background selesai
+func waitResync(t *testing.T, store *FallbackRevocationStore) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for store.syncing.Load() {
+		if time.Now().After(deadline) {
+			t.Fatal("revocation resync did not finish")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestFallbackRevocationStore(t *testing.T) {
+	ctx := t.Context()
+	mr, rdb := newTestRedis(t)
+	pg := newFakeRevocationSource()
+	store := &FallbackRevocationStore{cache: NewRedisRevocationStore(rdb, 30*time.Minute), db: pg}
+	expiresAt := time.Now().Add(10 * time.Minute)
+
+	// Redis kosong (belum pernah sync): pencabutan yang hanya ada di Postgres tetap berlaku
+	pg.RevokeToken(ctx, "jti-old", expiresAt)
+	pg.RevokeSession(ctx, "session-old")
+	if revoked, err := store.IsTokenRevoked(ctx, "jti-old"); err != nil || !revoked {
+		t.Fatalf("token revoked only in postgres accepted (revoked %v, err %v)", revoked, err)
+	}
+	waitResync(t, store)
+	if !mr.Exists(revocationSyncedKey) || !mr.Exists(revokedTokenKey("jti-old")) || !mr.Exists(revokedSessionKey("session-old")) {
+		t.Fatalf("redis not rebuilt from postgres, keys %v", mr.Keys())
+	}
+
+	// setelah sync, miss dijawab Redis tanpa query ke Postgres
+	before := pg.queryCount()
+	if revoked, err := store.IsTokenRevoked(ctx, "jti-fresh"); err != nil || revoked {
+		t.Errorf("unrevoked token: revoked %v, err %v", revoked, err)
+	}
+	if revoked, err := store.IsSessionRevoked(ctx, "session-old"); err != nil || !revoked {
+		t.Errorf("revoked session: revoked %v, err %v", revoked, err)
+	}
+	if pg.queryCount() != before {
+		t.Errorf("postgres queried %d times while redis is synced", pg.queryCount()-before)
+	}
+
+	// Redis restart tanpa data: penanda sync hilang, Postgres kembali dicek lalu Redis dibangun ulang
+	mr.FlushAll()
+	if revoked, err := store.IsSessionRevoked(ctx, "session-old"); err != nil || !revoked {
+		t.Errorf("session accepted after redis flush (revoked %v, err %v)", revoked, err)
+	}
+	waitResync(t, store)
+	if !mr.Exists(revokedSessionKey("session-old")) {
+		t.Error("redis not rebuilt after flush")
+	}
+
+	// penulisan ke Redis gagal: penanda sync masih ada tetapi miss tidak lagi dipercaya
+	mr.Close()
+	if err := store.RevokeToken(ctx, "jti-during-outage", expiresAt); err != nil {
+		t.Fatal(err)
+	}
+	if revoked, err := store.IsTokenRevoked(ctx, "jti-during-outage"); err != nil || !revoked {
+		t.Errorf("token revoked while redis down accepted (revoked %v, err %v)", revoked, err)
+	}
+	if err := mr.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	if revoked, err := store.IsTokenRevoked(ctx, "jti-during-outage"); err != nil || !revoked {
+		t.Errorf("token revoked while redis down accepted after reconnect (revoked %v, err %v)", revoked, err)
+	}
+	waitResync(t, store)
+	if !mr.Exists(revokedTokenKey("jti-during-outage")) || store.dirty.Load() {
+		t.Error("redis not resynced after reconnect")
+	}
+}
+
+func TestRevocationMaintainRestoresRedis(t *testing.T) {
+	env := testutil.Setup(t)
+	env.Seed(t, "demo")
+	ctx := t.Context()
+
+	store := NewTokenRevocationStore(env.DB, env.RDB, "redis", 30*time.Minute)
+	sessions := NewSessionRepository(env.DB, time.Hour, store)
+	session, err := sessions.CreateSession(ctx, 2, "user", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RevokeToken(ctx, "jti-active", time.Now().Add(10*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := sessions.EndSession(ctx, session.SessionId); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.DB.Exec(ctx, "INSERT INTO blacklist_tokens (jti, expires_at, created_at) VALUES ('jti-expired', NOW() - INTERVAL '1 minute', NOW())"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Redis kehilangan semua data, Maintain mengisinya kembali dari Postgres
+	if err := env.RDB.FlushDB(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Maintain(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{revocationSyncedKey, revokedTokenKey("jti-active"), revokedSessionKey(session.SessionId)} {
+		if n, err := env.RDB.Exists(ctx, key).Result(); err != nil || n == 0 {
+			t.Errorf("key %s not restored (err %v)", key, err)
+		}
+	}
+	if n, _ := env.RDB.Exists(ctx, revokedTokenKey("jti-expired")).Result(); n != 0 {
+		t.Error("expired token restored to redis")
+	}
+	var remaining int
+	if err := env.DB.QueryRow(ctx, "SELECT COUNT(*) FROM blacklist_tokens WHERE jti = 'jti-expired'").Scan(&remaining); err != nil || remaining != 0 {
+		t.Errorf("expired token not removed from postgres (count %d, err %v)", remaining, err)
+	}
+}
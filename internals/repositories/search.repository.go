@@ -0,0 +1,286 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/models"
+)
+
+// fuzzyThreshold batas word_similarity (pg_trgm) agar movie dianggap cocok meski salah ketik
+const fuzzyThreshold = 0.3
+
+// maxSearchTerms kata yang dipakai dari query, sisanya diabaikan
+const maxSearchTerms = 8
+
+// MovieSearchStore pencarian movie beserta facet-nya, diimplementasikan MovieSearch
+type MovieSearchStore interface {
+	Search(rctx context.Context, params models.MovieSearchParams) ([]models.MovieSearchResult, int, error)
+	Facets(rctx context.Context, params models.MovieSearchParams) (models.MovieSearchFacets, error)
+}
+
+var _ MovieSearchStore = (*MovieSearch)(nil)
+
+type MovieSearch struct {
+	Db *pgxpool.Pool
+}
+
+func NewMovieSearch(db *pgxpool.Pool) *MovieSearch {
+	return &MovieSearch{Db: db}
+}
+
+// searchTSQuery mengubah input user menjadi tsquery prefix ("inter stel" -> "inter:* & stel:*").
+// Hanya huruf dan angka yang diambil sehingga aman diberikan ke to_tsquery
+func searchTSQuery(query string) string {
+	terms := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(terms) > maxSearchTerms {
+		terms = terms[:maxSearchTerms]
+	}
+	for i, term := range terms {
+		terms[i] = term + ":*"
+	}
+	return strings.Join(terms, " & ")
+}
+
+// matchedMoviesSQL CTE movie yang cocok dengan query beserta skornya.
+// Skor = ts_rank (judul > director/cast > synopsis) + kemiripan ejaan judul/nama
+func matchedMoviesSQL(params models.MovieSearchParams) (string, []any, error) {
+	tsquery := searchTSQuery(params.Query)
+	if tsquery == "" {
+		return "", nil, errors.New("empty query")
+	}
+
+	args := []any{tsquery, strings.TrimSpace(params.Query)}
+	conditions := []string{"COALESCE(m.is_deleted, false) = false"}
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if len(params.Genres) > 0 {
+		addCondition(`m.id IN (
+			SELECT mg.movies_id FROM movies_genre mg
+			JOIN genres g ON g.id = mg.genres_id
+			WHERE g."name" = ANY($%d))`, params.Genres)
+	}
+	if params.YearFrom > 0 {
+		addCondition("EXTRACT(YEAR FROM m.release_date) >= $%d", params.YearFrom)
+	}
+	if params.YearTo > 0 {
+		addCondition("EXTRACT(YEAR FROM m.release_date) <= $%d", params.YearTo)
+	}
+	if params.MinRating != nil {
+		addCondition("m.rating >= $%d", *params.MinRating)
+	}
+
+	sql := fmt.Sprintf(`WITH q AS (
+			SELECT to_tsquery('simple', $1) AS tsq, $2::text AS raw
+		),
+		scored AS (
+			SELECT m.id,
+				m.search_vector @@ q.tsq AS text_match,
+				ts_rank(m.search_vector, q.tsq) AS rank,
+				GREATEST(
+					word_similarity(q.raw, m.title),
+					COALESCE(word_similarity(q.raw, d."name"), 0) * 0.8,
+					COALESCE((SELECT MAX(word_similarity(q.raw, c."name"))
+						FROM movies_casts mc JOIN casts c ON c.id = mc.casts_id
+						WHERE mc.movies_id = m.id), 0) * 0.8
+				) AS similarity
+			FROM movies m
+			CROSS JOIN q
+			LEFT JOIN directors d ON d.id = m.directors_id
+			WHERE %s
+		),
+		matched AS (
+			SELECT id, text_match, (CASE WHEN text_match THEN rank * 2 ELSE 0 END) + similarity AS score
+			FROM scored
+			WHERE text_match OR similarity >= %v
+		)`, strings.Join(conditions, " AND "), fuzzyThreshold)
+	return sql, args, nil
+}
+
+// Search mencari movie dan mengembalikan hasil per halaman beserta total hasil
+func (s *MovieSearch) Search(rctx context.Context, params models.MovieSearchParams) ([]models.MovieSearchResult, int, error) {
+	matched, args, err := matchedMoviesSQL(params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, params.Limit, params.Offset)
+	// ts_headline cukup mahal, karena itu hanya dihitung untuk baris di halaman ini
+	sql := matched + fmt.Sprintf(`,
+		page AS (
+			SELECT matched.*, COUNT(*) OVER () AS total
+			FROM matched
+			JOIN movies m ON m.id = matched.id
+			ORDER BY matched.score DESC, m.release_date DESC NULLS LAST, m.id
+			LIMIT $%d OFFSET $%d
+		)
+		SELECT m.id, m.title,
+			CASE WHEN page.text_match
+				THEN ts_headline('simple', m.title, q.tsq, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true')
+				ELSE m.title END,
+			CASE WHEN page.text_match
+				THEN ts_headline('simple', COALESCE(m.synopsis, ''), q.tsq, 'StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=" ... "')
+				ELSE left(COALESCE(m.synopsis, ''), 200) END,
+			m.release_date, m.poster_image, m.rating, d."name",
+			(SELECT STRING_AGG(g."name", ', ' ORDER BY g."name")
+			 FROM movies_genre mg JOIN genres g ON g.id = mg.genres_id
+			 WHERE mg.movies_id = m.id),
+			page.score::float8, page.text_match, page.total
+		FROM page
+		CROSS JOIN q
+		JOIN movies m ON m.id = page.id
+		LEFT JOIN directors d ON d.id = m.directors_id
+		ORDER BY page.score DESC, m.release_date DESC NULLS LAST, m.id`, len(args)-1, len(args))
+
+	rows, err := s.Db.Query(rctx, sql, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	results := []models.MovieSearchResult{}
+	total := 0
+	for rows.Next() {
+		var result models.MovieSearchResult
+		var textMatch bool
+		if err := rows.Scan(
+			&result.Id,
+			&result.Title,
+			&result.TitleHighlight,
+			&result.Snippet,
+			&result.ReleaseDate,
+			&result.PosterImage,
+			&result.Rating,
+			&result.DirectorName,
+			&result.Genres,
+			&result.Score,
+			&textMatch,
+			&total,
+		); err != nil {
+			return nil, 0, err
+		}
+		result.MatchedBy = "fuzzy"
+		if textMatch {
+			result.MatchedBy = "fulltext"
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// halaman di luar jumlah hasil: total tetap dihitung untuk pagination
+	if len(results) == 0 && params.Offset > 0 {
+		if err := s.Db.QueryRow(rctx, matched+" SELECT COUNT(*) FROM matched", args[:len(args)-2]...).Scan(&total); err != nil {
+			return nil, 0, err
+		}
+	}
+	return results, total, nil
+}
+
+// Facets menghitung jumlah hasil per genre, dekade rilis dan rentang rating dari semua hasil pencarian
+func (s *MovieSearch) Facets(rctx context.Context, params models.MovieSearchParams) (models.MovieSearchFacets, error) {
+	matched, args, err := matchedMoviesSQL(params)
+	if err != nil {
+		return models.MovieSearchFacets{}, err
+	}
+
+	sql := matched + `
+		SELECT 'genre', g.id, COALESCE(g."name", ''), COUNT(*)::int4
+		FROM matched
+		JOIN movies_genre mg ON mg.movies_id = matched.id
+		JOIN genres g ON g.id = mg.genres_id
+		GROUP BY g.id, g."name"
+		UNION ALL
+		SELECT 'year', (EXTRACT(YEAR FROM m.release_date)::int4 / 10) * 10, '', COUNT(*)::int4
+		FROM matched
+		JOIN movies m ON m.id = matched.id
+		WHERE m.release_date IS NOT NULL
+		GROUP BY 2
+		UNION ALL
+		SELECT 'rating',
+			CASE WHEN m.rating IS NULL THEN -1 WHEN m.rating < 5 THEN 0 ELSE LEAST(FLOOR(m.rating)::int4, 9) END,
+			'', COUNT(*)::int4
+		FROM matched
+		JOIN movies m ON m.id = matched.id
+		GROUP BY 2`
+
+	rows, err := s.Db.Query(rctx, sql, args...)
+	if err != nil {
+		return models.MovieSearchFacets{}, err
+	}
+	defer rows.Close()
+
+	facets := models.MovieSearchFacets{
+		Genres:  []models.GenreFacet{},
+		Years:   []models.YearFacet{},
+		Ratings: []models.RatingFacet{},
+	}
+	for rows.Next() {
+		var kind, name string
+		var key, count int
+		if err := rows.Scan(&kind, &key, &name, &count); err != nil {
+			return models.MovieSearchFacets{}, err
+		}
+		switch kind {
+		case "genre":
+			facets.Genres = append(facets.Genres, models.GenreFacet{Id: key, Name: name, Count: count})
+		case "year":
+			facets.Years = append(facets.Years, models.YearFacet{From: key, To: key + 9, Count: count})
+		case "rating":
+			facets.Ratings = append(facets.Ratings, ratingFacet(key, count))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return models.MovieSearchFacets{}, err
+	}
+
+	sortFacets(&facets)
+	return facets, nil
+}
+
+// ratingFacet label bucket rating: -1 tanpa rating, 0 di bawah 5, 5..9 rentang satu poin
+func ratingFacet(bucket, count int) models.RatingFacet {
+	switch bucket {
+	case -1:
+		return models.RatingFacet{Label: "unrated", Count: count}
+	case 0:
+		minRating, maxRating := 0.0, 5.0
+		return models.RatingFacet{Label: "0-5", Min: &minRating, Max: &maxRating, Count: count}
+	default:
+		minRating, maxRating := float64(bucket), float64(bucket+1)
+		return models.RatingFacet{Label: fmt.Sprintf("%d-%d", bucket, bucket+1), Min: &minRating, Max: &maxRating, Count: count}
+	}
+}
+
+// sortFacets genre berdasarkan jumlah terbanyak, dekade dan rating dari yang tertinggi (tanpa rating di akhir)
+func sortFacets(facets *models.MovieSearchFacets) {
+	slices.SortFunc(facets.Genres, func(a, b models.GenreFacet) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	slices.SortFunc(facets.Years, func(a, b models.YearFacet) int {
+		return b.From - a.From
+	})
+	slices.SortFunc(facets.Ratings, func(a, b models.RatingFacet) int {
+		switch {
+		case a.Min == nil:
+			return 1
+		case b.Min == nil:
+			return -1
+		default:
+			return int(*b.Min - *a.Min)
+		}
+	})
+}
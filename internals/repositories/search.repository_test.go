@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/testutil"
+)
+
+func TestSearchTSQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "Inter Stel", want: "inter:* & stel:*"},
+		{query: "spider-man: no way", want: "spider:* & man:* & no:* & way:*"},
+		// karakter operator tsquery dibuang, tidak bisa menyisipkan sintaks
+		{query: "a') | !b & <-> c:*", want: "a:* & b:* & c:*"},
+		{query: "  ?!  ", want: ""},
+		{query: "jurassic 1993", want: "jurassic:* & 1993:*"},
+		{query: "satu dua tiga empat lima enam tujuh delapan sembilan", want: "satu:* & dua:* & tiga:* & empat:* & lima:* & enam:* & tujuh:* & delapan:*"},
+	}
+	for _, tt := range tests {
+		if got := searchTSQuery(tt.query); got != tt.want {
+			t.Errorf("searchTSQuery(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+
+	if _, _, err := matchedMoviesSQL(models.MovieSearchParams{Query: "--"}); err == nil || err.Error() != "empty query" {
+		t.Errorf("query without letters: err %v, want empty query", err)
+	}
+}
+
+func TestSortFacets(t *testing.T) {
+	facets := models.MovieSearchFacets{
+		Genres: []models.GenreFacet{{Name: "Drama", Count: 1}, {Name: "Sci-Fi", Count: 3}, {Name: "Action", Count: 1}},
+		Years:  []models.YearFacet{{From: 1990}, {From: 2010}, {From: 2000}},
+		Ratings: []models.RatingFacet{
+			ratingFacet(-1, 1), ratingFacet(0, 2), ratingFacet(8, 4), ratingFacet(9, 1),
+		},
+	}
+	sortFacets(&facets)
+
+	var genres, ratings []string
+	for _, genre := range facets.Genres {
+		genres = append(genres, genre.Name)
+	}
+	for _, rating := range facets.Ratings {
+		ratings = append(ratings, rating.Label)
+	}
+	if got := strings.Join(genres, ","); got != "Sci-Fi,Action,Drama" {
+		t.Errorf("genres %s, want by count then name", got)
+	}
+	if facets.Years[0].From != 2010 || facets.Years[2].From != 1990 {
+		t.Errorf("years %v, want newest decade first", facets.Years)
+	}
+	if got := strings.Join(ratings, ","); got != "9-10,8-9,0-5,unrated" {
+		t.Errorf("ratings %s, want highest first and unrated last", got)
+	}
+}
+
+func TestMovieSearch(t *testing.T) {
+	env := testutil.Setup(t)
+	env.Seed(t, "demo")
+	ctx := t.Context()
+	search := NewMovieSearch(env.DB)
+
+	find := func(params models.MovieSearchParams) ([]models.MovieSearchResult, int) {
+		t.Helper()
+		if params.Limit == 0 {
+			params.Limit = 12
+		}
+		results, total, err := search.Search(ctx, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return results, total
+	}
+
+	// judul cocok lebih relevan daripada synopsis
+	results, _ := find(models.MovieSearchParams{Query: "inception"})
+	if len(results) == 0 || results[0].Title != "Inception" || results[0].MatchedBy != "fulltext" {
+		t.Fatalf("results %+v, want Inception first via fulltext", results)
+	}
+	if !strings.Contains(results[0].TitleHighlight, "<mark>Inception</mark>") {
+		t.Errorf("title highlight %q", results[0].TitleHighlight)
+	}
+
+	// salah ketik tetap ditemukan lewat kemiripan ejaan
+	results, _ = find(models.MovieSearchParams{Query: "intersteller"})
+	if len(results) == 0 || results[0].Title != "Interstellar" || results[0].MatchedBy != "fuzzy" {
+		t.Errorf("typo results %+v, want Interstellar via fuzzy", results)
+	}
+
+	// nama director, dengan filter genre dan tahun
+	results, total := find(models.MovieSearchParams{Query: "nolan"})
+	if total != 3 {
+		t.Errorf("nolan: %d results, want 3", total)
+	}
+	results, total = find(models.MovieSearchParams{Query: "nolan", Genres: []string{"Drama"}, YearFrom: 2010})
+	if total != 1 || results[0].Title != "Interstellar" {
+		t.Errorf("nolan drama since 2010: %d results %+v, want Interstellar", total, results)
+	}
+
+	// halaman di luar hasil tetap mengembalikan total
+	results, total = find(models.MovieSearchParams{Query: "nolan", Limit: 2, Offset: 10})
+	if len(results) != 0 || total != 3 {
+		t.Errorf("page past the end: %d results, total %d, want 0 and 3", len(results), total)
+	}
+
+	facets, err := search.Facets(ctx, models.MovieSearchParams{Query: "nolan"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Action, Drama dan Sci-Fi masing-masing 2 movie, urut nama
+	if len(facets.Genres) != 3 || facets.Genres[0].Name != "Action" || facets.Genres[0].Count != 2 {
+		t.Errorf("genre facets %+v, want Action, Drama, Sci-Fi with 2 movies each", facets.Genres)
+	}
+	if len(facets.Years) != 2 || facets.Years[0].From != 2010 || facets.Years[0].Count != 2 {
+		t.Errorf("year facets %+v, want 2010s (2) then 2000s (1)", facets.Years)
+	}
+}
@@ -0,0 +1,234 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/utils"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+// SessionStore session login dan refresh token, diimplementasikan SessionRepository
+type SessionStore interface {
+	CreateSession(rctx context.Context, userID int, role, userAgent, ipAddress string) (models.SessionToken, error)
+	RotateRefreshToken(rctx context.Context, refreshToken, userAgent, ipAddress string) (models.SessionToken, error)
+	ListSessions(rctx context.Context, userID int, currentSessionID string) ([]models.UserSession, error)
+	RevokeSession(rctx context.Context, userID int, sessionID string) error
+	EndSession(rctx context.Context, sessionID string) error
+}
+
+var _ SessionStore = (*SessionRepository)(nil)
+
+// SessionRepository mengelola session login per device dan refresh token-nya.
+// Setiap session adalah satu family refresh token; token dirotasi setiap kali dipakai
+type SessionRepository struct {
+	db          *pgxpool.Pool
+	refreshTTL  time.Duration
+	revocations TokenRevocationStore
+}
+
+func NewSessionRepository(db *pgxpool.Pool, refreshTTL time.Duration, revocations TokenRevocationStore) *SessionRepository {
+	return &SessionRepository{db: db, refreshTTL: refreshTTL, revocations: revocations}
+}
+
+// CreateSession membuat session baru saat login beserta refresh token pertamanya
+func (s *SessionRepository) CreateSession(rctx context.Context, userID int, role, userAgent, ipAddress string) (models.SessionToken, error) {
+	tx, err := s.db.Begin(rctx)
+	if err != nil {
+		return models.SessionToken{}, err
+	}
+	defer tx.Rollback(rctx)
+
+	session := models.SessionToken{
+		UserId:    userID,
+		Role:      role,
+		SessionId: uuid.NewString(),
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	}
+
+	sessionSQL := `INSERT INTO user_sessions (id, users_id, user_agent, ip_address, created_at, last_used_at, expires_at)
+				   VALUES ($1, $2, $3, $4, NOW(), NOW(), $5)`
+	if _, err := tx.Exec(rctx, sessionSQL, session.SessionId, userID, nullIfEmpty(userAgent), nullIfEmpty(ipAddress), session.ExpiresAt); err != nil {
+		return models.SessionToken{}, err
+	}
+
+	if session.RefreshToken, err = insertRefreshToken(rctx, tx, session.SessionId, nil, session.ExpiresAt); err != nil {
+		return models.SessionToken{}, err
+	}
+
+	if err := tx.Commit(rctx); err != nil {
+		return models.SessionToken{}, err
+	}
+	return session, nil
+}
+
+// RotateRefreshToken menukar refresh token dengan yang baru.
+// Token yang sudah pernah dipakai berarti bocor: seluruh session (family) langsung dicabut
+func (s *SessionRepository) RotateRefreshToken(rctx context.Context, refreshToken, userAgent, ipAddress string) (models.SessionToken, error) {
+	tx, err := s.db.Begin(rctx)
+	if err != nil {
+		return models.SessionToken{}, err
+	}
+	defer tx.Rollback(rctx)
+
+	var tokenID int
+	var expiresAt time.Time
+	var usedAt, revokedAt *time.Time
+	var session models.SessionToken
+	sql := `SELECT rt.id, rt.expires_at, rt.used_at, us.id::text, us.revoked_at, u.id, COALESCE(u.role, '')
+			FROM refresh_tokens rt
+			JOIN user_sessions us ON us.id = rt.session_id
+			JOIN users u ON u.id = us.users_id
+			WHERE rt.token_hash = $1
+			FOR UPDATE OF rt, us`
+	if err := tx.QueryRow(rctx, sql, pkg.HashRefreshToken(refreshToken)).Scan(
+		&tokenID,
+		&expiresAt,
+		&usedAt,
+		&session.SessionId,
+		&revokedAt,
+		&session.UserId,
+		&session.Role,
+	); err != nil {
+		if err == pgx.ErrNoRows {
+			return models.SessionToken{}, errors.New("invalid refresh token")
+		}
+		return models.SessionToken{}, err
+	}
+
+	if revokedAt != nil {
+		return models.SessionToken{}, errors.New("session revoked")
+	}
+
+	if usedAt != nil {
+		// reuse detection: cabut seluruh family, commit supaya pencabutan tetap tersimpan
+		utils.Logger(rctx).Warn("refresh token reuse detected, revoking session", "session_id", session.SessionId, "user_id", session.UserId)
+		if err := revokeSession(rctx, tx, session.SessionId, "token_reuse"); err != nil {
+			return models.SessionToken{}, err
+		}
+		if err := tx.Commit(rctx); err != nil {
+			return models.SessionToken{}, err
+		}
+		if err := s.revocations.RevokeSession(rctx, session.SessionId); err != nil {
+			return models.SessionToken{}, err
+		}
+		return models.SessionToken{}, errors.New("refresh token reused")
+	}
+
+	if time.Now().After(expiresAt) {
+		return models.SessionToken{}, errors.New("refresh token expired")
+	}
+
+	if _, err := tx.Exec(rctx, "UPDATE refresh_tokens SET used_at = NOW() WHERE id = $1", tokenID); err != nil {
+		return models.SessionToken{}, err
+	}
+
+	session.ExpiresAt = time.Now().Add(s.refreshTTL)
+	if session.RefreshToken, err = insertRefreshToken(rctx, tx, session.SessionId, &tokenID, session.ExpiresAt); err != nil {
+		return models.SessionToken{}, err
+	}
+
+	updateSQL := `UPDATE user_sessions
+				  SET last_used_at = NOW(), expires_at = $2,
+					  user_agent = COALESCE($3, user_agent), ip_address = COALESCE($4, ip_address)
+				  WHERE id = $1`
+	if _, err := tx.Exec(rctx, updateSQL, session.SessionId, session.ExpiresAt, nullIfEmpty(userAgent), nullIfEmpty(ipAddress)); err != nil {
+		return models.SessionToken{}, err
+	}
+
+	if err := tx.Commit(rctx); err != nil {
+		return models.SessionToken{}, err
+	}
+	return session, nil
+}
+
+// ListSessions mengambil session aktif milik user, currentSessionID ditandai current
+func (s *SessionRepository) ListSessions(rctx context.Context, userID int, currentSessionID string) ([]models.UserSession, error) {
+	sql := `SELECT id::text, user_agent, ip_address, created_at, last_used_at, expires_at
+			FROM user_sessions
+			WHERE users_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+			ORDER BY last_used_at DESC`
+
+	rows, err := s.db.Query(rctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []models.UserSession{}
+	for rows.Next() {
+		var session models.UserSession
+		if err := rows.Scan(
+			&session.Id,
+			&session.UserAgent,
+			&session.IpAddress,
+			&session.CreatedAt,
+			&session.LastUsedAt,
+			&session.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		session.Current = session.Id == currentSessionID
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// RevokeSession mencabut session milik user (logout device)
+func (s *SessionRepository) RevokeSession(rctx context.Context, userID int, sessionID string) error {
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return errors.New("session not found")
+	}
+
+	sql := `UPDATE user_sessions SET revoked_at = NOW(), revoke_reason = 'user'
+			WHERE id = $1 AND users_id = $2 AND revoked_at IS NULL`
+	res, err := s.db.Exec(rctx, sql, sessionID, userID)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return errors.New("session not found")
+	}
+	// access token milik session ini langsung ditolak tanpa menunggu expired
+	return s.revocations.RevokeSession(rctx, sessionID)
+}
+
+// EndSession mencabut session saat logout
+func (s *SessionRepository) EndSession(rctx context.Context, sessionID string) error {
+	if err := revokeSession(rctx, s.db, sessionID, "logout"); err != nil {
+		return err
+	}
+	return s.revocations.RevokeSession(rctx, sessionID)
+}
+
+func insertRefreshToken(rctx context.Context, q querier, sessionID string, parentID *int, expiresAt time.Time) (string, error) {
+	token, hash, err := pkg.GenerateRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	sql := `INSERT INTO refresh_tokens (session_id, token_hash, parent_id, expires_at, created_at)
+			VALUES ($1, $2, $3, $4, NOW())`
+	if _, err := q.Exec(rctx, sql, sessionID, hash, parentID, expiresAt); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func revokeSession(rctx context.Context, q querier, sessionID, reason string) error {
+	sql := "UPDATE user_sessions SET revoked_at = NOW(), revoke_reason = $2 WHERE id = $1 AND revoked_at IS NULL"
+	_, err := q.Exec(rctx, sql, sessionID, reason)
+	return err
+}
+
+func nullIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/raihaninkam/tickitz/internals/testutil"
+)
+
+func TestRotateRefreshTokenReuse(t *testing.T) {
+	env := testutil.Setup(t)
+	env.Seed(t, "demo")
+	ctx := t.Context()
+
+	revocations := NewTokenRevocationStore(env.DB, env.RDB, "redis", 30*time.Minute)
+	sessions := NewSessionRepository(env.DB, 24*time.Hour, revocations)
+
+	session, err := sessions.CreateSession(ctx, 2, "user", "test-agent", "127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessions.RotateRefreshToken(ctx, "bukan-token", "", ""); err == nil || err.Error() != "invalid refresh token" {
+		t.Errorf("unknown token: err %v, want invalid refresh token", err)
+	}
+
+	rotated, err := sessions.RotateRefreshToken(ctx, session.RefreshToken, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated.SessionId != session.SessionId || rotated.RefreshToken == session.RefreshToken {
+		t.Fatalf("rotation returned session %s token %q", rotated.SessionId, rotated.RefreshToken)
+	}
+
+	// token lama dipakai ulang: seluruh family dicabut walaupun transaksi mengembalikan error
+	if _, err := sessions.RotateRefreshToken(ctx, session.RefreshToken, "", ""); err == nil || err.Error() != "refresh token reused" {
+		t.Fatalf("reused token: err %v, want refresh token reused", err)
+	}
+	if _, err := sessions.RotateRefreshToken(ctx, rotated.RefreshToken, "", ""); err == nil || err.Error() != "session revoked" {
+		t.Errorf("token of revoked session: err %v, want session revoked", err)
+	}
+
+	var reason string
+	if err := env.DB.QueryRow(ctx, "SELECT revoke_reason FROM user_sessions WHERE id = $1", session.SessionId).Scan(&reason); err != nil {
+		t.Fatal(err)
+	}
+	if reason != "token_reuse" {
+		t.Errorf("revoke_reason %q, want token_reuse", reason)
+	}
+	if revoked, err := revocations.IsSessionRevoked(ctx, session.SessionId); err != nil || !revoked {
+		t.Errorf("access tokens of session still accepted (revoked %v, err %v)", revoked, err)
+	}
+	active, err := sessions.ListSessions(ctx, 2, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(active) != 0 {
+		t.Errorf("%d active sessions after reuse, want 0", len(active))
+	}
+}
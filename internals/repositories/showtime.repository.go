@@ -0,0 +1,417 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// ShowtimeStore jadwal tayang yang dikelola admin, diimplementasikan ShowtimeRepository
+type ShowtimeStore interface {
+	GetShowtimes(rctx context.Context, filter models.ShowtimeFilter) ([]models.NowShowing, error)
+	CreateShowtimes(rctx context.Context, movieID, cinemaID, locationID int, slots []models.ShowtimeSlot) ([]models.NowShowing, []models.ShowtimeConflict, error)
+	RescheduleShowtime(rctx context.Context, showtimeID int, req models.RescheduleShowtimeRequest) (models.NowShowing, []models.ShowtimeConflict, []models.ShowtimeBooking, error)
+	CancelShowtime(rctx context.Context, showtimeID int, reason string) (models.NowShowing, []models.ShowtimeBooking, error)
+}
+
+var _ ShowtimeStore = (*ShowtimeRepository)(nil)
+
+// ShowtimeRepository mengelola jadwal tayang (now_showing) terpisah dari create/update movie
+type ShowtimeRepository struct {
+	db  *pgxpool.Pool
+	rdb *redis.Client
+}
+
+func NewShowtimeRepository(db *pgxpool.Pool, rdb *redis.Client) *ShowtimeRepository {
+	return &ShowtimeRepository{db: db, rdb: rdb}
+}
+
+const showtimeColumns = `ns.id, ns.movie_id, m.title, COALESCE(m.duration_minutes, 0),
+	ns.cinemas_id, c.cinema_name, ns.location_id, l."name",
+	to_char(ns."date", 'YYYY-MM-DD'), to_char(ns."time", 'HH24:MI'),
+	ns."date" + ns."time", ns."date" + ns."time" + make_interval(mins => COALESCE(m.duration_minutes, 0)),
+	ns.status,
+	(SELECT COUNT(*) FROM showing_seats ss WHERE ss.now_showing_id = ns.id AND ss.orders_id IS NOT NULL)::int4,
+	ns.cancelled_at, ns.cancel_reason`
+
+const showtimeFrom = ` FROM now_showing ns
+	JOIN movies m ON m.id = ns.movie_id
+	JOIN cinemas c ON c.id = ns.cinemas_id
+	JOIN "location" l ON l.id = ns.location_id`
+
+func scanShowtime(row pgx.Row) (models.NowShowing, error) {
+	var showtime models.NowShowing
+	err := row.Scan(
+		&showtime.Id,
+		&showtime.MovieId,
+		&showtime.MovieTitle,
+		&showtime.DurationMinutes,
+		&showtime.CinemasId,
+		&showtime.CinemaName,
+		&showtime.LocationId,
+		&showtime.LocationName,
+		&showtime.Date,
+		&showtime.Time,
+		&showtime.StartsAt,
+		&showtime.EndsAt,
+		&showtime.Status,
+		&showtime.BookedSeats,
+		&showtime.CancelledAt,
+		&showtime.CancelReason,
+	)
+	return showtime, err
+}
+
+func getShowtime(rctx context.Context, q querier, showtimeID int) (models.NowShowing, error) {
+	showtime, err := scanShowtime(q.QueryRow(rctx, "SELECT "+showtimeColumns+showtimeFrom+" WHERE ns.id = $1", showtimeID))
+	if err == pgx.ErrNoRows {
+		return models.NowShowing{}, errors.New("showtime not found")
+	}
+	return showtime, err
+}
+
+func (s *ShowtimeRepository) GetShowtimes(rctx context.Context, filter models.ShowtimeFilter) ([]models.NowShowing, error) {
+	var conditions []string
+	var args []any
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.MovieId > 0 {
+		addCondition("ns.movie_id = $%d", filter.MovieId)
+	}
+	if filter.CinemaId > 0 {
+		addCondition("ns.cinemas_id = $%d", filter.CinemaId)
+	}
+	if filter.Date != "" {
+		addCondition(`ns."date" = $%d::date`, filter.Date)
+	}
+	if filter.Status != "" {
+		addCondition("ns.status = $%d", filter.Status)
+	}
+
+	sql := "SELECT " + showtimeColumns + showtimeFrom
+	if len(conditions) > 0 {
+		sql += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	sql += ` ORDER BY ns."date", ns."time", ns.cinemas_id`
+
+	rows, err := s.db.Query(rctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	showtimes := []models.NowShowing{}
+	for rows.Next() {
+		showtime, err := scanShowtime(rows)
+		if err != nil {
+			return nil, err
+		}
+		showtimes = append(showtimes, showtime)
+	}
+	return showtimes, rows.Err()
+}
+
+// CreateShowtimes membuat satu atau banyak jadwal sekaligus dalam satu transaksi.
+// Jika ada slot yang bentrok tidak ada jadwal yang dibuat dan daftar bentrokan dikembalikan
+func (s *ShowtimeRepository) CreateShowtimes(rctx context.Context, movieID, cinemaID, locationID int, slots []models.ShowtimeSlot) ([]models.NowShowing, []models.ShowtimeConflict, error) {
+	tx, err := s.db.Begin(rctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback(rctx)
+
+	duration, err := movieDuration(rctx, tx, movieID)
+	if err != nil {
+		return nil, nil, err
+	}
+	// lock cinema supaya dua admin tidak menjadwalkan studio yang sama bersamaan
+	if err := lockCinema(rctx, tx, cinemaID); err != nil {
+		return nil, nil, err
+	}
+	if err := checkLocation(rctx, tx, locationID); err != nil {
+		return nil, nil, err
+	}
+
+	var ids []int
+	var conflicts []models.ShowtimeConflict
+	for _, slot := range slots {
+		if err := checkFutureSlot(rctx, tx, slot); err != nil {
+			return nil, nil, err
+		}
+
+		found, err := findShowtimeConflicts(rctx, tx, cinemaID, slot, duration, 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(found) > 0 {
+			conflicts = append(conflicts, found...)
+			continue
+		}
+
+		var id int
+		insertSQL := `INSERT INTO now_showing ("date", "time", location_id, movie_id, cinemas_id, status, created_at, updated_at)
+					  VALUES ($1::date, $2::time, $3, $4, $5, 'scheduled', NOW(), NOW())
+					  RETURNING id`
+		if err := tx.QueryRow(rctx, insertSQL, slot.Date, slot.Time, locationID, movieID, cinemaID).Scan(&id); err != nil {
+			return nil, nil, err
+		}
+		ids = append(ids, id)
+	}
+	if len(conflicts) > 0 {
+		return nil, conflicts, errors.New("showtime overlaps")
+	}
+
+	showtimes := make([]models.NowShowing, 0, len(ids))
+	for _, id := range ids {
+		showtime, err := getShowtime(rctx, tx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		showtimes = append(showtimes, showtime)
+	}
+
+	if err := tx.Commit(rctx); err != nil {
+		return nil, nil, err
+	}
+	invalidateMovieCache(rctx, s.rdb, movieID)
+	return showtimes, nil, nil
+}
+
+// RescheduleShowtime memindahkan jadwal ke tanggal/jam (dan cinema/location) baru.
+// Pindah cinema ditolak jika sudah ada kursi terjual karena nomor kursi terikat ke denah cinema lama.
+// Order aktif dikembalikan untuk dikirimi notifikasi
+func (s *ShowtimeRepository) RescheduleShowtime(rctx context.Context, showtimeID int, req models.RescheduleShowtimeRequest) (models.NowShowing, []models.ShowtimeConflict, []models.ShowtimeBooking, error) {
+	tx, err := s.db.Begin(rctx)
+	if err != nil {
+		return models.NowShowing{}, nil, nil, err
+	}
+	defer tx.Rollback(rctx)
+
+	current, err := lockShowtime(rctx, tx, showtimeID)
+	if err != nil {
+		return models.NowShowing{}, nil, nil, err
+	}
+	if current.Status == "cancelled" {
+		return models.NowShowing{}, nil, nil, errors.New("showtime cancelled")
+	}
+
+	bookings, err := activeBookings(rctx, tx, showtimeID)
+	if err != nil {
+		return models.NowShowing{}, nil, nil, err
+	}
+
+	cinemaID, locationID := current.CinemasId, current.LocationId
+	if req.CinemasId != nil && *req.CinemasId != cinemaID {
+		if current.BookedSeats > 0 || len(bookings) > 0 {
+			return models.NowShowing{}, nil, nil, errors.New("showtime has bookings")
+		}
+		cinemaID = *req.CinemasId
+	}
+	if req.LocationId != nil {
+		locationID = *req.LocationId
+	}
+
+	if err := lockCinema(rctx, tx, cinemaID); err != nil {
+		return models.NowShowing{}, nil, nil, err
+	}
+	if err := checkLocation(rctx, tx, locationID); err != nil {
+		return models.NowShowing{}, nil, nil, err
+	}
+
+	slot := models.ShowtimeSlot{Date: req.Date, Time: req.Time}
+	if err := checkFutureSlot(rctx, tx, slot); err != nil {
+		return models.NowShowing{}, nil, nil, err
+	}
+	conflicts, err := findShowtimeConflicts(rctx, tx, cinemaID, slot, current.DurationMinutes, showtimeID)
+	if err != nil {
+		return models.NowShowing{}, nil, nil, err
+	}
+	if len(conflicts) > 0 {
+		return models.NowShowing{}, conflicts, nil, errors.New("showtime overlaps")
+	}
+
+	updateSQL := `UPDATE now_showing
+				  SET "date" = $1::date, "time" = $2::time, cinemas_id = $3, location_id = $4, updated_at = NOW()
+				  WHERE id = $5`
+	if _, err := tx.Exec(rctx, updateSQL, req.Date, req.Time, cinemaID, locationID, showtimeID); err != nil {
+		return models.NowShowing{}, nil, nil, err
+	}
+
+	showtime, err := getShowtime(rctx, tx, showtimeID)
+	if err != nil {
+		return models.NowShowing{}, nil, nil, err
+	}
+	if err := tx.Commit(rctx); err != nil {
+		return models.NowShowing{}, nil, nil, err
+	}
+	invalidateMovieCache(rctx, s.rdb, showtime.MovieId)
+	return showtime, nil, bookings, nil
+}
+
+// CancelShowtime menandai jadwal dibatalkan sehingga tidak bisa dipesan lagi dan mengembalikan order aktif
+// yang harus dibatalkan + di-refund. Memanggil ulang pada jadwal yang sudah dibatalkan mengembalikan
+// order yang belum berhasil dibatalkan sebelumnya
+func (s *ShowtimeRepository) CancelShowtime(rctx context.Context, showtimeID int, reason string) (models.NowShowing, []models.ShowtimeBooking, error) {
+	tx, err := s.db.Begin(rctx)
+	if err != nil {
+		return models.NowShowing{}, nil, err
+	}
+	defer tx.Rollback(rctx)
+
+	current, err := lockShowtime(rctx, tx, showtimeID)
+	if err != nil {
+		return models.NowShowing{}, nil, err
+	}
+
+	bookings, err := activeBookings(rctx, tx, showtimeID)
+	if err != nil {
+		return models.NowShowing{}, nil, err
+	}
+
+	if current.Status == "cancelled" {
+		if len(bookings) == 0 {
+			return models.NowShowing{}, nil, errors.New("showtime already cancelled")
+		}
+	} else {
+		var reasonArg *string
+		if reason != "" {
+			reasonArg = &reason
+		}
+		cancelSQL := `UPDATE now_showing SET status = 'cancelled', cancelled_at = NOW(), cancel_reason = $1, updated_at = NOW()
+					  WHERE id = $2`
+		if _, err := tx.Exec(rctx, cancelSQL, reasonArg, showtimeID); err != nil {
+			return models.NowShowing{}, nil, err
+		}
+	}
+
+	showtime, err := getShowtime(rctx, tx, showtimeID)
+	if err != nil {
+		return models.NowShowing{}, nil, err
+	}
+	if err := tx.Commit(rctx); err != nil {
+		return models.NowShowing{}, nil, err
+	}
+	invalidateMovieCache(rctx, s.rdb, showtime.MovieId)
+	return showtime, bookings, nil
+}
+
+func lockShowtime(rctx context.Context, q querier, showtimeID int) (models.NowShowing, error) {
+	var id int
+	if err := q.QueryRow(rctx, "SELECT id FROM now_showing WHERE id = $1 FOR UPDATE", showtimeID).Scan(&id); err != nil {
+		if err == pgx.ErrNoRows {
+			return models.NowShowing{}, errors.New("showtime not found")
+		}
+		return models.NowShowing{}, err
+	}
+	return getShowtime(rctx, q, showtimeID)
+}
+
+// movieDuration durasi movie dalam menit, dipakai untuk menghitung jam selesai tayang
+func movieDuration(rctx context.Context, q querier, movieID int) (int, error) {
+	var duration *int
+	sql := "SELECT duration_minutes FROM movies WHERE id = $1 AND COALESCE(is_deleted, false) = false"
+	if err := q.QueryRow(rctx, sql, movieID).Scan(&duration); err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, errors.New("movie not found")
+		}
+		return 0, err
+	}
+	if duration == nil || *duration <= 0 {
+		return 0, errors.New("movie duration not set")
+	}
+	return *duration, nil
+}
+
+func checkLocation(rctx context.Context, q querier, locationID int) error {
+	var exists bool
+	if err := q.QueryRow(rctx, `SELECT EXISTS(SELECT 1 FROM "location" WHERE id = $1)`, locationID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return errors.New("location not found")
+	}
+	return nil
+}
+
+// checkFutureSlot menolak jadwal yang jam tayangnya sudah lewat
+func checkFutureSlot(rctx context.Context, q querier, slot models.ShowtimeSlot) error {
+	var future bool
+	if err := q.QueryRow(rctx, "SELECT ($1::date + $2::time) > NOW()::timestamp", slot.Date, slot.Time).Scan(&future); err != nil {
+		return err
+	}
+	if !future {
+		return errors.New("showtime in the past")
+	}
+	return nil
+}
+
+// findShowtimeConflicts mencari jadwal aktif di cinema yang sama yang rentang tayangnya
+// (jam mulai sampai jam mulai + duration_minutes) beririsan dengan slot baru
+func findShowtimeConflicts(rctx context.Context, q querier, cinemaID int, slot models.ShowtimeSlot, duration, excludeID int) ([]models.ShowtimeConflict, error) {
+	sql := `WITH slot AS (
+				SELECT ($2::date + $3::time) AS starts_at,
+					   ($2::date + $3::time) + make_interval(mins => $4) AS ends_at
+			)
+			SELECT ns.id, m.title,
+				   ns."date" + ns."time",
+				   ns."date" + ns."time" + make_interval(mins => COALESCE(m.duration_minutes, 0))
+			FROM now_showing ns
+			JOIN movies m ON m.id = ns.movie_id
+			CROSS JOIN slot
+			WHERE ns.cinemas_id = $1
+			  AND ns.status = 'scheduled'
+			  AND ns.id <> $5
+			  AND ns."date" + ns."time" < slot.ends_at
+			  AND ns."date" + ns."time" + make_interval(mins => COALESCE(m.duration_minutes, 0)) > slot.starts_at
+			ORDER BY ns."date", ns."time"`
+
+	rows, err := q.Query(rctx, sql, cinemaID, slot.Date, slot.Time, duration, excludeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var conflicts []models.ShowtimeConflict
+	for rows.Next() {
+		conflict := models.ShowtimeConflict{Date: slot.Date, Time: slot.Time}
+		if err := rows.Scan(&conflict.ConflictsWith, &conflict.MovieTitle, &conflict.StartsAt, &conflict.EndsAt); err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts, rows.Err()
+}
+
+// activeBookings order yang belum dibatalkan/gagal pada sebuah jadwal
+func activeBookings(rctx context.Context, q querier, showtimeID int) ([]models.ShowtimeBooking, error) {
+	sql := `SELECT o.id, o.users_id, u.email
+			FROM orders o
+			JOIN users u ON u.id = o.users_id
+			WHERE o.now_showing_id = $1
+			  AND COALESCE(o.status, 'pending') NOT IN ('cancelled', 'failed')
+			ORDER BY o.id`
+
+	rows, err := q.Query(rctx, sql, showtimeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bookings []models.ShowtimeBooking
+	for rows.Next() {
+		var booking models.ShowtimeBooking
+		if err := rows.Scan(&booking.OrderId, &booking.UsersId, &booking.Email); err != nil {
+			return nil, err
+		}
+		bookings = append(bookings, booking)
+	}
+	return bookings, rows.Err()
+}
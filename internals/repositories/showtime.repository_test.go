@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/testutil"
+)
+
+func TestShowtimeOverlap(t *testing.T) {
+	env := testutil.Setup(t)
+	env.Seed(t, "demo")
+	ctx := t.Context()
+	showtimes := NewShowtimeRepository(env.DB, env.RDB)
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+
+	// cinema 1 besok: Inception 13:00-15:28 (showing 1) dan The Dark Knight 19:00-21:32 (showing 2)
+	tests := []struct {
+		name      string
+		movieID   int
+		slots     []models.ShowtimeSlot
+		conflicts []int
+	}{
+		{name: "starts during showing", movieID: 3, slots: []models.ShowtimeSlot{{Date: tomorrow, Time: "15:00"}}, conflicts: []int{1}},
+		{name: "ends during showing", movieID: 3, slots: []models.ShowtimeSlot{{Date: tomorrow, Time: "11:00"}}, conflicts: []int{1}},
+		{name: "ends during evening showing", movieID: 2, slots: []models.ShowtimeSlot{{Date: tomorrow, Time: "16:30"}}, conflicts: []int{2}},
+		{name: "one slot of many overlaps", movieID: 3, slots: []models.ShowtimeSlot{{Date: tomorrow, Time: "08:00"}, {Date: tomorrow, Time: "20:00"}}, conflicts: []int{2}},
+	}
+	for _, tt := range tests {
+		created, conflicts, err := showtimes.CreateShowtimes(ctx, tt.movieID, 1, 1, tt.slots)
+		if err == nil || err.Error() != "showtime overlaps" {
+			t.Errorf("%s: err %v, want showtime overlaps", tt.name, err)
+			continue
+		}
+		if len(created) != 0 {
+			t.Errorf("%s: %d showtimes created despite the conflict", tt.name, len(created))
+		}
+		var got []int
+		for _, conflict := range conflicts {
+			got = append(got, conflict.ConflictsWith)
+		}
+		if len(got) != len(tt.conflicts) || got[0] != tt.conflicts[0] {
+			t.Errorf("%s: conflicts with %v, want %v", tt.name, got, tt.conflicts)
+		}
+	}
+
+	// Jurassic Park 127 menit tepat di antara dua jadwal, dan jam yang sama di cinema lain
+	created, _, err := showtimes.CreateShowtimes(ctx, 3, 1, 1, []models.ShowtimeSlot{{Date: tomorrow, Time: "15:28"}})
+	if err != nil {
+		t.Fatalf("back-to-back slot: %v", err)
+	}
+	if _, _, err := showtimes.CreateShowtimes(ctx, 3, 3, 1, []models.ShowtimeSlot{{Date: tomorrow, Time: "13:00"}}); err != nil {
+		t.Errorf("same time in another cinema: %v", err)
+	}
+
+	// slot dalam satu permintaan juga saling dicek
+	dayAfter := time.Now().AddDate(0, 0, 3).Format("2006-01-02")
+	if _, conflicts, err := showtimes.CreateShowtimes(ctx, 1, 1, 1, []models.ShowtimeSlot{{Date: dayAfter, Time: "10:00"}, {Date: dayAfter, Time: "11:00"}}); err == nil || len(conflicts) != 1 {
+		t.Errorf("overlapping slots in one request: err %v, conflicts %v", err, conflicts)
+	}
+
+	// jadwal yang dibatalkan tidak lagi menghalangi, reschedule tidak bentrok dengan dirinya sendiri
+	if _, _, err := showtimes.CancelShowtime(ctx, 2, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := showtimes.CreateShowtimes(ctx, 3, 1, 1, []models.ShowtimeSlot{{Date: tomorrow, Time: "19:30"}}); err != nil {
+		t.Errorf("slot of a cancelled showing: %v", err)
+	}
+	if _, _, _, err := showtimes.RescheduleShowtime(ctx, created[0].Id, models.RescheduleShowtimeRequest{Date: tomorrow, Time: "15:30"}); err != nil {
+		t.Errorf("reschedule overlapping its own slot: %v", err)
+	}
+	if _, conflicts, _, err := showtimes.RescheduleShowtime(ctx, created[0].Id, models.RescheduleShowtimeRequest{Date: tomorrow, Time: "14:00"}); err == nil || len(conflicts) != 1 || conflicts[0].ConflictsWith != 1 {
+		t.Errorf("reschedule onto showing 1: err %v, conflicts %v", err, conflicts)
+	}
+
+	if _, _, err := showtimes.CreateShowtimes(ctx, 3, 1, 1, []models.ShowtimeSlot{{Date: "2020-01-01", Time: "13:00"}}); err == nil || err.Error() != "showtime in the past" {
+		t.Errorf("past slot: err %v, want showtime in the past", err)
+	}
+}
@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+// TicketStore tiket order dan check-in, diimplementasikan TicketRepository
+type TicketStore interface {
+	GetOrderTicket(rctx context.Context, orderID, userID int) (models.OrderTicket, error)
+	CheckIn(rctx context.Context, code string, payload pkg.TicketPayload, usherID int) (models.CheckInResponse, error)
+}
+
+var _ TicketStore = (*TicketRepository)(nil)
+
+type TicketRepository struct {
+	db     *pgxpool.Pool
+	signer *pkg.TicketSigner
+}
+
+func NewTicketRepository(db *pgxpool.Pool, signer *pkg.TicketSigner) *TicketRepository {
+	return &TicketRepository{db: db, signer: signer}
+}
+
+// GetOrderTicket mengambil tiket milik order user.
+// Tiket lama yang belum bertanda tangan atau ditandatangani kunci sebelum rotasi langsung diberi kode baru
+func (t *TicketRepository) GetOrderTicket(rctx context.Context, orderID, userID int) (models.OrderTicket, error) {
+	sql := `SELECT o.id, tk.id, COALESCE(tk.qr_code, ''), tk.status, o.status, COALESCE(o."isPaid", false), tk.scanned_at
+			FROM orders o
+			JOIN orders_ticket ot ON ot.orders_id = o.id
+			JOIN ticket tk ON tk.id = ot.ticket_id
+			WHERE o.id = $1 AND o.users_id = $2`
+
+	var ticket models.OrderTicket
+	if err := t.db.QueryRow(rctx, sql, orderID, userID).Scan(
+		&ticket.OrderId,
+		&ticket.TicketId,
+		&ticket.Code,
+		&ticket.Status,
+		&ticket.OrderStatus,
+		&ticket.IsPaid,
+		&ticket.ScannedAt,
+	); err != nil {
+		if err == pgx.ErrNoRows {
+			return models.OrderTicket{}, errors.New("order not found")
+		}
+		return models.OrderTicket{}, err
+	}
+
+	if ticket.Status == "active" && needsSigning(t.signer, ticket.Code) {
+		code, err := signOrderTicket(rctx, t.db, t.signer, ticket.TicketId, ticket.OrderId)
+		if err != nil {
+			return models.OrderTicket{}, err
+		}
+		ticket.Code = code
+	}
+	return ticket, nil
+}
+
+// CheckIn menandai tiket sudah di-scan. Payload harus sudah diverifikasi dengan signer,
+// kode juga dicocokkan dengan yang tersimpan supaya kode lama tidak bisa dipakai
+func (t *TicketRepository) CheckIn(rctx context.Context, code string, payload pkg.TicketPayload, usherID int) (models.CheckInResponse, error) {
+	tx, err := t.db.Begin(rctx)
+	if err != nil {
+		return models.CheckInResponse{}, err
+	}
+	defer tx.Rollback(rctx)
+
+	var storedCode, ticketStatus, orderStatus string
+	var isPaid bool
+	var scannedAt *time.Time
+	var response models.CheckInResponse
+	sql := `SELECT tk.id, COALESCE(tk.qr_code, ''), tk.status, tk.scanned_at,
+				o.id, o.status, COALESCE(o."isPaid", false), o.now_showing_id,
+				m.title, c.cinema_name, ns.date, ns.time::text
+			FROM ticket tk
+			JOIN orders_ticket ot ON ot.ticket_id = tk.id
+			JOIN orders o ON o.id = ot.orders_id
+			JOIN now_showing ns ON ns.id = o.now_showing_id
+			JOIN movies m ON m.id = ns.movie_id
+			JOIN cinemas c ON c.id = ns.cinemas_id
+			WHERE tk.id = $1
+			FOR UPDATE OF tk`
+	if err := tx.QueryRow(rctx, sql, payload.TicketId).Scan(
+		&response.TicketId,
+		&storedCode,
+		&ticketStatus,
+		&scannedAt,
+		&response.OrderId,
+		&orderStatus,
+		&isPaid,
+		&response.NowShowingId,
+		&response.MovieTitle,
+		&response.CinemaName,
+		&response.ShowDate,
+		&response.ShowTime,
+	); err != nil {
+		if err == pgx.ErrNoRows {
+			return models.CheckInResponse{}, errors.New("ticket not found")
+		}
+		return models.CheckInResponse{}, err
+	}
+
+	if storedCode != code || response.OrderId != payload.OrderId {
+		return models.CheckInResponse{}, errors.New("ticket not found")
+	}
+	if ticketStatus == "void" || orderStatus == "cancelled" {
+		return models.CheckInResponse{}, errors.New("ticket void")
+	}
+	if !isPaid {
+		return models.CheckInResponse{}, errors.New("order not paid")
+	}
+	if scannedAt != nil {
+		response.Seats = payload.Seats
+		response.ScannedAt = *scannedAt
+		return response, errors.New("ticket already scanned")
+	}
+
+	updateSQL := `UPDATE ticket SET scanned_at = NOW(), scanned_by = $1, updated_at = NOW()
+				  WHERE id = $2
+				  RETURNING scanned_at`
+	if err := tx.QueryRow(rctx, updateSQL, usherID, response.TicketId).Scan(&response.ScannedAt); err != nil {
+		return models.CheckInResponse{}, err
+	}
+
+	if err := tx.Commit(rctx); err != nil {
+		return models.CheckInResponse{}, err
+	}
+	response.Seats = payload.Seats
+	return response, nil
+}
+
+// needsSigning true jika kode tidak bisa diverifikasi kunci saat ini. Kode yang hanya kadaluarsa tidak diganti
+func needsSigning(signer *pkg.TicketSigner, code string) bool {
+	_, err := signer.Verify(code, time.Now())
+	return errors.Is(err, pkg.ErrTicketInvalid)
+}
+
+// signOrderTicket membuat kode tiket bertanda tangan dari data order dan menyimpannya
+func signOrderTicket(rctx context.Context, q querier, signer *pkg.TicketSigner, ticketID, orderID int) (string, error) {
+	var nowShowingID int
+	var showtime time.Time
+	showSQL := `SELECT ns.id, (ns.date + ns.time) AT TIME ZONE current_setting('TimeZone')
+				FROM orders o
+				JOIN now_showing ns ON ns.id = o.now_showing_id
+				WHERE o.id = $1`
+	if err := q.QueryRow(rctx, showSQL, orderID).Scan(&nowShowingID, &showtime); err != nil {
+		return "", err
+	}
+
+	seatSQL := `SELECT CONCAT(s.row, s.seat_number)
+				FROM showing_seats ss
+				JOIN seats s ON s.id = ss.seat_id
+				WHERE ss.orders_id = $1
+				ORDER BY s.row, s.seat_number`
+	rows, err := q.Query(rctx, seatSQL, orderID)
+	if err != nil {
+		return "", err
+	}
+	seats := []string{}
+	for rows.Next() {
+		var seat string
+		if err := rows.Scan(&seat); err != nil {
+			rows.Close()
+			return "", err
+		}
+		seats = append(seats, seat)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	code, err := signer.Sign(pkg.TicketPayload{
+		TicketId:     ticketID,
+		OrderId:      orderID,
+		NowShowingId: nowShowingID,
+		Seats:        seats,
+		ExpiresAt:    signer.ExpiryFor(showtime).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := q.Exec(rctx, "UPDATE ticket SET qr_code = $1, updated_at = NOW() WHERE id = $2", code, ticketID); err != nil {
+		return "", err
+	}
+	return code, nil
+}
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+func TestNeedsSigning(t *testing.T) {
+	seed := make([]byte, 32)
+	current, err := pkg.NewTicketSigner(seed, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed = make([]byte, 32)
+	seed[0] = 1
+	rotated, err := pkg.NewTicketSigner(seed, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func(signer *pkg.TicketSigner, expiresAt time.Time) string {
+		code, err := signer.Sign(pkg.TicketPayload{TicketId: 1, OrderId: 1, NowShowingId: 1, Seats: []string{"A1"}, ExpiresAt: expiresAt.Unix()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name string
+		code string
+		want bool
+	}{
+		{name: "signed with current key", code: sign(current, time.Now().Add(time.Hour)), want: false},
+		{name: "signed before key rotation", code: sign(rotated, time.Now().Add(time.Hour)), want: true},
+		{name: "legacy random code", code: "9f86d081884c7d659a2feaa0c55ad015", want: true},
+		{name: "empty", code: "", want: true},
+		// tiket yang sudah lewat masa berlaku tidak dihidupkan lagi
+		{name: "expired", code: sign(current, time.Now().Add(-time.Hour)), want: false},
+	}
+	for _, tt := range tests {
+		if got := needsSigning(current, tt.code); got != tt.want {
+			t.Errorf("%s: needsSigning = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
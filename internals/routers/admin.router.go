@@ -3,19 +3,18 @@ package routers
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/configs"
 	"github.com/raihaninkam/tickitz/internals/handlers"
 	"github.com/raihaninkam/tickitz/internals/middlewares"
 	"github.com/raihaninkam/tickitz/internals/repositories"
 	"github.com/redis/go-redis/v9"
 )
 
-func InitAdminMovieRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
+func InitAdminMovieRouter(router *gin.Engine, cfg *configs.Config, db *pgxpool.Pool, rdb *redis.Client, revocationStore repositories.TokenRevocationStore) {
 	adminMovieRouter := router.Group("/admin")
 
-	authRepo := repositories.NewAuthRepository(db)
-
 	movieRepo := repositories.NewMovieAdmin(db, rdb)
-	movieHandler := handlers.NewMovieAdminHandler(movieRepo)
+	movieHandler := handlers.NewMovieAdminHandler(movieRepo, cfg.Upload)
 
 	// CREATE
 	// @Summary      Tambah Movie
@@ -29,8 +28,8 @@ func InitAdminMovieRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Clien
 	// @Failure      400   {object}  map[string]interface{}
 	// @Failure      401   {object}  map[string]interface{}
 	// @Router       /admin/movies/add [post]
-	adminMovieRouter.POST("/movies/add", middlewares.JWTMiddlewareWithBlacklist(authRepo),
-		middlewares.VerifyToken,
+	adminMovieRouter.POST("/movies/add", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore),
+		middlewares.VerifyToken(cfg.JWT),
 		middlewares.Access("admin"),
 		movieHandler.AddMovie,
 	)
@@ -44,8 +43,8 @@ func InitAdminMovieRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Clien
 	// @Success      200  {object}  map[string]interface{}
 	// @Failure      401  {object}  map[string]interface{}
 	// @Router       /admin/movies [get]
-	adminMovieRouter.GET("/movies", middlewares.JWTMiddlewareWithBlacklist(authRepo),
-		middlewares.VerifyToken,
+	adminMovieRouter.GET("/movies", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore),
+		middlewares.VerifyToken(cfg.JWT),
 		middlewares.Access("admin"),
 		movieHandler.GetAllMovies,
 	)
@@ -64,8 +63,8 @@ func InitAdminMovieRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Clien
 	// @Failure      404      {object}  map[string]interface{}
 	// @Failure      401      {object}  map[string]interface{}
 	// @Router       /admin/movies/{movieId} [patch]
-	adminMovieRouter.PATCH("/movies/:movieId", middlewares.JWTMiddlewareWithBlacklist(authRepo),
-		middlewares.VerifyToken,
+	adminMovieRouter.PATCH("/movies/:movieId", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore),
+		middlewares.VerifyToken(cfg.JWT),
 		middlewares.Access("admin"),
 		movieHandler.UpdateMovie,
 	)
@@ -81,9 +80,17 @@ func InitAdminMovieRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Clien
 	// @Failure      404      {object}  map[string]interface{}
 	// @Failure      401      {object}  map[string]interface{}
 	// @Router       /admin/movies/delete/{movieId} [delete]
-	adminMovieRouter.DELETE("/movies/delete/:movieId", middlewares.JWTMiddlewareWithBlacklist(authRepo),
-		middlewares.VerifyToken,
+	adminMovieRouter.DELETE("/movies/delete/:movieId", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore),
+		middlewares.VerifyToken(cfg.JWT),
 		middlewares.Access("admin"),
 		movieHandler.DeleteMovie,
 	)
+
+	// cache stats
+	cacheHandler := handlers.NewCacheHandler()
+	adminMovieRouter.GET("/cache/stats", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore),
+		middlewares.VerifyToken(cfg.JWT),
+		middlewares.Access("admin"),
+		cacheHandler.GetCacheStats,
+	)
 }
@@ -3,18 +3,43 @@ package routers
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/configs"
 	"github.com/raihaninkam/tickitz/internals/handlers"
 	"github.com/raihaninkam/tickitz/internals/middlewares"
 	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/pkg"
+	"github.com/redis/go-redis/v9"
 )
 
-func InitAuthRouter(router *gin.Engine, db *pgxpool.Pool) {
-	authRouter := router.Group("/auth")
+func InitAuthRouter(router *gin.Engine, cfg *configs.Config, db *pgxpool.Pool, rdb *redis.Client, mailer pkg.Mailer, revocationStore repositories.TokenRevocationStore, limits ...gin.HandlerFunc) {
+	authRouter := router.Group("/auth", limits...)
 
 	authRepository := repositories.NewAuthRepository(db)
-	authHandler := handlers.NewAuthHandler(authRepository)
+	sessionRepository := repositories.NewSessionRepository(db, cfg.RefreshTokenTTL, revocationStore)
+	accountRepository := repositories.NewAccountRepository(db, cfg.AccountTokens, revocationStore)
+	accountHandler := handlers.NewAccountHandler(accountRepository, mailer, cfg.AccountTokens, &cfg.Hash)
+	lockoutRepository := repositories.NewLockoutRepository(db, rdb, cfg.Lockout)
+	authHandler := handlers.NewAuthHandler(authRepository, sessionRepository, revocationStore, accountHandler, lockoutRepository, &cfg.Hash, cfg.JWT)
 
 	authRouter.POST("/login", authHandler.Login)
 	authRouter.POST("/register", authHandler.Register)
-	authRouter.POST("/logout", middlewares.VerifyToken, middlewares.Access("user", "admin"), authHandler.SecureLogout)
+	authRouter.POST("/logout", middlewares.VerifyToken(cfg.JWT), middlewares.Access("user", "admin"), authHandler.SecureLogout)
+
+	// verifikasi email & reset password (token sekali pakai lewat email)
+	authRouter.POST("/verify", accountHandler.VerifyEmail)
+	authRouter.POST("/verify/resend", accountHandler.ResendVerification)
+	authRouter.POST("/password/forgot", accountHandler.ForgotPassword)
+	authRouter.POST("/password/reset", accountHandler.ResetPassword)
+
+	// refresh token & session per device
+	authRouter.POST("/refresh", authHandler.RefreshToken)
+	authRouter.GET("/sessions", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore), middlewares.VerifyToken(cfg.JWT), authHandler.GetSessions)
+	authRouter.DELETE("/sessions/:id", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore), middlewares.VerifyToken(cfg.JWT), authHandler.RevokeSession)
+
+	// buka lockout login user (admin)
+	router.POST("/admin/users/:id/unlock", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore),
+		middlewares.VerifyToken(cfg.JWT),
+		middlewares.Access("admin"),
+		authHandler.UnlockUser,
+	)
 }
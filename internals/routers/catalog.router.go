@@ -0,0 +1,43 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/configs"
+	"github.com/raihaninkam/tickitz/internals/handlers"
+	"github.com/raihaninkam/tickitz/internals/middlewares"
+	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/redis/go-redis/v9"
+)
+
+// InitCatalogRouter endpoint publik dan admin untuk genres, casts dan directors
+func InitCatalogRouter(router *gin.Engine, cfg *configs.Config, db *pgxpool.Pool, rdb *redis.Client, revocationStore repositories.TokenRevocationStore) {
+	adminRouter := router.Group("/admin", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore),
+		middlewares.VerifyToken(cfg.JWT),
+		middlewares.Access("admin"),
+	)
+
+	catalogs := []struct {
+		path  string
+		label string
+		kind  repositories.CatalogKind
+	}{
+		{"/genres", "Genre", repositories.Genres},
+		{"/casts", "Cast", repositories.Casts},
+		{"/directors", "Director", repositories.Directors},
+	}
+
+	for _, catalog := range catalogs {
+		catalogRepo := repositories.NewCatalogRepository(db, rdb, catalog.kind)
+		catalogHandler := handlers.NewCatalogHandler(catalogRepo, catalog.label)
+
+		publicRouter := router.Group(catalog.path)
+		publicRouter.GET("", catalogHandler.GetAll)
+		publicRouter.GET("/:id/movies", catalogHandler.GetMovies)
+
+		adminRouter.POST(catalog.path, catalogHandler.Create)
+		adminRouter.PUT(catalog.path+"/:id", catalogHandler.Rename)
+		adminRouter.DELETE(catalog.path+"/:id", catalogHandler.Delete)
+		adminRouter.POST(catalog.path+"/:id/merge", catalogHandler.Merge)
+	}
+}
@@ -0,0 +1,34 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/configs"
+	"github.com/raihaninkam/tickitz/internals/handlers"
+	"github.com/raihaninkam/tickitz/internals/middlewares"
+	"github.com/raihaninkam/tickitz/internals/repositories"
+)
+
+func InitAdminCinemaRouter(router *gin.Engine, cfg *configs.Config, db *pgxpool.Pool, revocationStore repositories.TokenRevocationStore) {
+	adminRouter := router.Group("/admin", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore),
+		middlewares.VerifyToken(cfg.JWT),
+		middlewares.Access("admin"),
+	)
+
+	cinemaRepo := repositories.NewCinemaRepository(db)
+	cinemaHandler := handlers.NewCinemaHandler(cinemaRepo)
+
+	// cinema & denah kursi
+	adminRouter.GET("/cinemas", cinemaHandler.GetCinemas)
+	adminRouter.POST("/cinemas", cinemaHandler.CreateCinema)
+	adminRouter.PUT("/cinemas/:id", cinemaHandler.UpdateCinema)
+	adminRouter.DELETE("/cinemas/:id", cinemaHandler.DeleteCinema)
+	adminRouter.GET("/cinemas/:id/layout", cinemaHandler.GetSeatLayout)
+	adminRouter.PUT("/cinemas/:id/layout", cinemaHandler.SaveSeatLayout)
+
+	// location
+	adminRouter.GET("/locations", cinemaHandler.GetLocations)
+	adminRouter.POST("/locations", cinemaHandler.CreateLocation)
+	adminRouter.PUT("/locations/:id", cinemaHandler.UpdateLocation)
+	adminRouter.DELETE("/locations/:id", cinemaHandler.DeleteLocation)
+}
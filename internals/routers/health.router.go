@@ -0,0 +1,17 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/handlers"
+	"github.com/redis/go-redis/v9"
+)
+
+// InitHealthRouter liveness, readiness dan metrics untuk orchestrator / Prometheus
+func InitHealthRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
+	healthHandler := handlers.NewHealthHandler(db, rdb)
+	router.GET("/healthz", healthHandler.Liveness)
+	router.GET("/readyz", healthHandler.GetHealth)
+	router.GET("/health", healthHandler.GetHealth)
+	router.GET("/metrics", healthHandler.GetMetrics)
+}
@@ -31,13 +31,18 @@ func InitMovieRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
 	movieFilterHandler := handlers.NewMovieFilterHandler(movieFilterRepository)
 	movieRouter.GET("/filter", movieFilterHandler.GetMoviesWithFilter)
 
+	// search movie (full-text + fuzzy)
+	movieSearchRepository := repositories.NewMovieSearch(db)
+	movieSearchHandler := handlers.NewMovieSearchHandler(movieSearchRepository)
+	movieRouter.GET("/search", movieSearchHandler.SearchMovies)
+
 	// movie detail (by id)
-	movieDetailRepository := repositories.NewMovieDetail(db)
+	movieDetailRepository := repositories.NewMovieDetail(db, rdb)
 	movieDetailHandler := handlers.NewMovieDetailHandler(movieDetailRepository)
 	movieRouter.GET("/:movie_id", movieDetailHandler.GetDetailMovie)
 
 	// movie schedule
-	scheduleRepository := repositories.NewSchedule(db)
+	scheduleRepository := repositories.NewSchedule(db, rdb)
 	scheduleHandler := handlers.NewScheduleHandler(scheduleRepository)
 
 	// movieRouter.GET("/schedule:", scheduleHandler.GetSchedulesByDate)
@@ -3,33 +3,69 @@ package routers
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/configs"
 	"github.com/raihaninkam/tickitz/internals/handlers"
 	"github.com/raihaninkam/tickitz/internals/middlewares"
 
 	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/pkg"
+	"github.com/redis/go-redis/v9"
 )
 
-func InitOrderRouter(router *gin.Engine, db *pgxpool.Pool) {
-	orderRouter := router.Group("/orders")
+func InitOrderRouter(router *gin.Engine, cfg *configs.Config, db *pgxpool.Pool, rdb *redis.Client, gateway pkg.PaymentGateway, signer *pkg.TicketSigner, revocationStore repositories.TokenRevocationStore, limits ...gin.HandlerFunc) {
+	orderRouter := router.Group("/orders", limits...)
 
-	authRepo := repositories.NewAuthRepository(db)
-	// router.Use(middlewares.JWTMiddlewareWithBlacklist(authRepo))
+	// router.Use(middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore))
 
 	// order
-	orderRepo := repositories.NewOrderRepository(db)
+	orderRepo := repositories.NewOrderRepository(db, rdb, cfg.Loyalty, signer)
 	orderHandler := handlers.NewOrderHandler(orderRepo, &repositories.SeatsRepository{})
 
-	orderRouter.POST("", middlewares.JWTMiddlewareWithBlacklist(authRepo), middlewares.VerifyToken, middlewares.Access("user"), orderHandler.CreateOrder)
+	orderRouter.POST("", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore), middlewares.VerifyToken(cfg.JWT), middlewares.Access("user"), orderHandler.CreateOrder)
+
+	// price quote
+	pricingRepo := repositories.NewPricingRepository(db)
+	pricingHandler := handlers.NewPricingHandler(pricingRepo)
+
+	orderRouter.POST("/quote", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore), middlewares.VerifyToken(cfg.JWT), middlewares.Access("user"), pricingHandler.GetQuote)
 
 	// seat avail
 	seatsRepository := repositories.NewSeatsRepository(db)
 	seatsHandler := handlers.NewSeatsHandler(seatsRepository)
 
-	orderRouter.GET("/seats/:now_showing_id", middlewares.JWTMiddlewareWithBlacklist(authRepo), middlewares.VerifyToken, middlewares.Access("user"), seatsHandler.GetAvailableSeats)
+	orderRouter.GET("/seats/:now_showing_id", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore), middlewares.VerifyToken(cfg.JWT), middlewares.Access("user"), seatsHandler.GetAvailableSeats)
+
+	// seat hold
+	seatHoldRepository := repositories.NewSeatHoldRepository(db, rdb, cfg.SeatHoldDuration)
+	seatHoldHandler := handlers.NewSeatHoldHandler(seatHoldRepository)
+
+	orderRouter.POST("/holds", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore), middlewares.VerifyToken(cfg.JWT), middlewares.Access("user"), seatHoldHandler.HoldSeats)
+	orderRouter.DELETE("/holds/:now_showing_id", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore), middlewares.VerifyToken(cfg.JWT), middlewares.Access("user"), seatHoldHandler.ReleaseHolds)
+
+	// payment
+	paymentRepo := repositories.NewPaymentRepository(db, rdb, cfg.Loyalty)
+	paymentHandler := handlers.NewPaymentHandler(paymentRepo, gateway, cfg.Payment.WebhookSecret)
+
+	orderRouter.POST("/:id/pay", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore), middlewares.VerifyToken(cfg.JWT), middlewares.Access("user"), paymentHandler.PayOrder)
+
+	// ticket QR
+	ticketRepo := repositories.NewTicketRepository(db, signer)
+	ticketHandler := handlers.NewTicketHandler(ticketRepo, signer)
+
+	orderRouter.GET("/:id/ticket.png", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore), middlewares.VerifyToken(cfg.JWT), middlewares.Access("user"), ticketHandler.GetTicketImage)
+
+	// cancel & refund
+	refundRepo := repositories.NewRefundRepository(db, rdb, cfg.OrderCancelCutoff)
+	refundHandler := handlers.NewRefundHandler(refundRepo, gateway)
+
+	orderRouter.POST("/:id/cancel", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore), middlewares.VerifyToken(cfg.JWT), middlewares.Access("user"), refundHandler.CancelOrder)
+
+	adminOrderRouter := router.Group("/admin/orders")
+	adminOrderRouter.POST("/:id/cancel", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore), middlewares.VerifyToken(cfg.JWT), middlewares.Access("admin"), refundHandler.AdminCancelOrder)
 
 	orderHistoryRepository := repositories.NewOrderHistory(db)
 	orderHistoryHandler := handlers.NewOrderHistoryHandler(orderHistoryRepository)
 
-	orderRouter.GET("/history", middlewares.JWTMiddlewareWithBlacklist(authRepo), middlewares.VerifyToken, middlewares.Access("user"), orderHistoryHandler.GetOrderHistory)
+	orderRouter.GET("/history", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore), middlewares.VerifyToken(cfg.JWT), middlewares.Access("user"), orderHistoryHandler.GetOrderHistory)
 
 }
@@ -0,0 +1,21 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/configs"
+	"github.com/raihaninkam/tickitz/internals/handlers"
+	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/pkg"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitPaymentRouter(router *gin.Engine, cfg *configs.Config, db *pgxpool.Pool, rdb *redis.Client, gateway pkg.PaymentGateway) {
+	paymentRouter := router.Group("/payments")
+
+	paymentRepo := repositories.NewPaymentRepository(db, rdb, cfg.Loyalty)
+	paymentHandler := handlers.NewPaymentHandler(paymentRepo, gateway, cfg.Payment.WebhookSecret)
+
+	// dipanggil oleh payment gateway, diverifikasi lewat signature bukan JWT
+	paymentRouter.POST("/webhook", paymentHandler.PaymentWebhook)
+}
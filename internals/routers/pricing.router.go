@@ -0,0 +1,47 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/configs"
+	"github.com/raihaninkam/tickitz/internals/handlers"
+	"github.com/raihaninkam/tickitz/internals/middlewares"
+	"github.com/raihaninkam/tickitz/internals/repositories"
+)
+
+func InitAdminPricingRouter(router *gin.Engine, cfg *configs.Config, db *pgxpool.Pool, revocationStore repositories.TokenRevocationStore) {
+	pricingRouter := router.Group("/admin/pricing")
+
+	pricingRepo := repositories.NewPricingRepository(db)
+	pricingHandler := handlers.NewPricingHandler(pricingRepo)
+
+	pricingRouter.GET("", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore),
+		middlewares.VerifyToken(cfg.JWT),
+		middlewares.Access("admin"),
+		pricingHandler.GetPricing,
+	)
+
+	pricingRouter.PUT("/base", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore),
+		middlewares.VerifyToken(cfg.JWT),
+		middlewares.Access("admin"),
+		pricingHandler.SetBasePrice,
+	)
+
+	pricingRouter.POST("/rules", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore),
+		middlewares.VerifyToken(cfg.JWT),
+		middlewares.Access("admin"),
+		pricingHandler.CreatePriceRule,
+	)
+
+	pricingRouter.PUT("/rules/:ruleId", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore),
+		middlewares.VerifyToken(cfg.JWT),
+		middlewares.Access("admin"),
+		pricingHandler.UpdatePriceRule,
+	)
+
+	pricingRouter.DELETE("/rules/:ruleId", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore),
+		middlewares.VerifyToken(cfg.JWT),
+		middlewares.Access("admin"),
+		pricingHandler.DeletePriceRule,
+	)
+}
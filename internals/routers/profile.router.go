@@ -3,35 +3,55 @@ package routers
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/configs"
 	"github.com/raihaninkam/tickitz/internals/handlers"
 	"github.com/raihaninkam/tickitz/internals/middlewares"
 	"github.com/raihaninkam/tickitz/internals/repositories"
-	"github.com/raihaninkam/tickitz/pkg"
 )
 
-func InitProfileRouter(router *gin.Engine, db *pgxpool.Pool, hc *pkg.HashConfig) {
+func InitProfileRouter(router *gin.Engine, cfg *configs.Config, db *pgxpool.Pool, revocationStore repositories.TokenRevocationStore) {
 	profileRouter := router.Group("/profile")
 
-	authRepo := repositories.NewAuthRepository(db)
-	// profileRouter.Use(middlewares.JWTMiddlewareWithBlacklist(authRepo))
+	// profileRouter.Use(middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore))
 
-	profileRepository := repositories.NewProfileRepository(db, hc)
-	profileHandler := handlers.NewProfileHandler(profileRepository)
+	profileRepository := repositories.NewProfileRepository(db, &cfg.Hash)
+	profileHandler := handlers.NewProfileHandler(profileRepository, cfg.Upload)
 
 	// GET my profile
-	profileRouter.GET("", middlewares.JWTMiddlewareWithBlacklist(authRepo),
-		middlewares.VerifyToken,
+	profileRouter.GET("", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore),
+		middlewares.VerifyToken(cfg.JWT),
 		middlewares.Access("user"),
 		profileHandler.GetMyProfile,
 	)
 
 	// PATCH update profile (ambil userId dari JWT, bukan param)
-	profileRouter.PATCH("", middlewares.JWTMiddlewareWithBlacklist(authRepo),
-		middlewares.VerifyToken,
+	profileRouter.PATCH("", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore),
+		middlewares.VerifyToken(cfg.JWT),
 		middlewares.Access("user"),
 		profileHandler.UpdateProfileWithImage,
 	)
 
+	// GET riwayat poin loyalty
+	pointsRepository := repositories.NewPointsRepository(db, cfg.Loyalty)
+	pointsHandler := handlers.NewPointsHandler(pointsRepository)
+
+	profileRouter.GET("/points", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore),
+		middlewares.VerifyToken(cfg.JWT),
+		middlewares.Access("user"),
+		pointsHandler.GetMyPoints,
+	)
+
+	// POST adjust poin user (admin)
+	router.POST("/admin/users/:id/points", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore),
+		middlewares.VerifyToken(cfg.JWT),
+		middlewares.Access("admin"),
+		pointsHandler.AdjustPoints,
+	)
+
 	// PATCH change password (ambil userId dari JWT, bukan param)
-	profileRouter.PATCH("/change-password", profileHandler.ChangePassword)
+	profileRouter.PATCH("/change-password", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore),
+		middlewares.VerifyToken(cfg.JWT),
+		middlewares.Access("user", "admin"),
+		profileHandler.ChangePassword,
+	)
 }
@@ -0,0 +1,30 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/configs"
+	"github.com/raihaninkam/tickitz/internals/handlers"
+	"github.com/raihaninkam/tickitz/internals/middlewares"
+	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/redis/go-redis/v9"
+)
+
+// InitReviewRouter review movie: listing publik, tulis/hapus oleh user dan moderasi admin
+func InitReviewRouter(router *gin.Engine, cfg *configs.Config, db *pgxpool.Pool, rdb *redis.Client, revocationStore repositories.TokenRevocationStore) {
+	reviewRepo := repositories.NewReviewRepository(db, rdb)
+	reviewHandler := handlers.NewReviewHandler(reviewRepo)
+
+	movieRouter := router.Group("/movies/:movie_id/reviews")
+	movieRouter.GET("", reviewHandler.GetMovieReviews)
+	movieRouter.POST("", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore), middlewares.VerifyToken(cfg.JWT), middlewares.Access("user"), reviewHandler.UpsertReview)
+	movieRouter.DELETE("", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore), middlewares.VerifyToken(cfg.JWT), middlewares.Access("user"), reviewHandler.DeleteOwnReview)
+
+	adminRouter := router.Group("/admin/reviews", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore),
+		middlewares.VerifyToken(cfg.JWT),
+		middlewares.Access("admin"),
+	)
+	adminRouter.GET("", reviewHandler.GetReviews)
+	adminRouter.PATCH("/:id", reviewHandler.ModerateReview)
+	adminRouter.DELETE("/:id", reviewHandler.DeleteReview)
+}
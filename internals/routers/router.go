@@ -1,37 +1,73 @@
 package routers
 
 import (
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	docs "github.com/raihaninkam/tickitz/docs"
+	"github.com/raihaninkam/tickitz/internals/configs"
 	"github.com/raihaninkam/tickitz/internals/middlewares"
+	"github.com/raihaninkam/tickitz/internals/repositories"
 	"github.com/raihaninkam/tickitz/pkg"
 	"github.com/redis/go-redis/v9"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func InitRouter(db *pgxpool.Pool, rdb *redis.Client, hc *pkg.HashConfig) *gin.Engine {
-	router := gin.Default()
+func InitRouter(cfg *configs.Config, db *pgxpool.Pool, rdb *redis.Client, gateway pkg.PaymentGateway, signer *pkg.TicketSigner, mailer pkg.Mailer, revocationStore repositories.TokenRevocationStore, logger *slog.Logger) *gin.Engine {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	// gin.Default tidak dipakai karena logger bawaannya mencatat query string yang bisa berisi token
+	router := gin.New()
+	router.Use(gin.Recovery(), middlewares.RequestLogger(logger))
 
 	router.Static("/public", "./public")
 
 	router.Static("/images", "./public/images")
 	router.Static("/uploads", "./uploads")
 
-	router.Use(middlewares.CORSMiddleware)
+	router.Use(middlewares.CORSMiddleware(cfg.CORS.AllowedOrigins))
+	router.Use(middlewares.MetricsMiddleware)
+
+	InitHealthRouter(router, db, rdb)
+
+	// rate limit per grup route (sliding window di Redis), lihat configs.RateLimitConfig
+	limiter := middlewares.NewRateLimiter(rdb)
+	authLimits := []gin.HandlerFunc{
+		limiter.Limit("auth_ip", cfg.RateLimits.AuthIP, middlewares.KeyByIP),
+		limiter.Limit("auth_email", cfg.RateLimits.AuthEmail, middlewares.KeyByEmail),
+	}
+	orderLimits := []gin.HandlerFunc{
+		limiter.Limit("orders_user", cfg.RateLimits.OrdersUser, middlewares.KeyByUser(cfg.JWT)),
+	}
 
-	InitAuthRouter(router, db)
+	InitAuthRouter(router, cfg, db, rdb, mailer, revocationStore, authLimits...)
 
 	InitMovieRouter(router, db, rdb)
 
-	InitOrderRouter(router, db)
+	InitCatalogRouter(router, cfg, db, rdb, revocationStore)
+
+	InitReviewRouter(router, cfg, db, rdb, revocationStore)
+
+	InitOrderRouter(router, cfg, db, rdb, gateway, signer, revocationStore, orderLimits...)
+
+	InitCheckinRouter(router, cfg, db, signer, revocationStore)
+
+	InitPaymentRouter(router, cfg, db, rdb, gateway)
+
+	InitProfileRouter(router, cfg, db, revocationStore)
+
+	InitAdminMovieRouter(router, cfg, db, rdb, revocationStore)
+
+	InitAdminPricingRouter(router, cfg, db, revocationStore)
 
-	InitProfileRouter(router, db, hc)
+	InitAdminCinemaRouter(router, cfg, db, revocationStore)
 
-	InitAdminMovieRouter(router, db, rdb)
+	InitAdminShowtimeRouter(router, cfg, db, rdb, gateway, mailer, revocationStore)
 
 	docs.SwaggerInfo.BasePath = "/"
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
@@ -0,0 +1,533 @@
+package routers_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/configs"
+	"github.com/raihaninkam/tickitz/internals/models"
+	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/internals/routers"
+	"github.com/raihaninkam/tickitz/internals/testutil"
+	"github.com/raihaninkam/tickitz/pkg"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	testutil.Main(m)
+}
+
+// jadwal, cinema dan metode pembayaran dari seed demo (db/seeds/demo)
+const (
+	demoShowingID = 1
+	demoCinemaID  = 1
+	demoPaymentID = 1
+
+	testWebhookSecret = "test-webhook-secret"
+)
+
+// outbox menampung email yang dikirim handler supaya test bisa mengambil token verifikasi
+type outbox chan pkg.Mail
+
+func (o outbox) Send(ctx context.Context, mail pkg.Mail) error {
+	o <- mail
+	return nil
+}
+
+var tokenRe = regexp.MustCompile(`token=(\S+)`)
+
+// nextToken menunggu email berikutnya (dikirim di background) dan mengambil token dari link di body
+func (o outbox) nextToken(t *testing.T, to string) string {
+	t.Helper()
+	select {
+	case mail := <-o:
+		if mail.To != to {
+			t.Fatalf("mail sent to %s, want %s", mail.To, to)
+		}
+		match := tokenRe.FindStringSubmatch(mail.Body)
+		if match == nil {
+			t.Fatalf("no token in mail body: %s", mail.Body)
+		}
+		token, err := url.QueryUnescape(match[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no mail sent to %s", to)
+		return ""
+	}
+}
+
+type server struct {
+	t      *testing.T
+	router *gin.Engine
+	db     *pgxpool.Pool
+	rdb    *redis.Client
+	mails  outbox
+}
+
+// newServer router lengkap di atas database baru berisi seed demo
+func newServer(t *testing.T) *server {
+	env := testutil.Setup(t)
+	env.Seed(t, "demo")
+
+	// database & redis diberikan langsung ke router, nilai DB* di sini hanya supaya config valid
+	key := make([]byte, 32)
+	rand.Read(key)
+	for k, v := range map[string]string{
+		"DBUSER":                 "test",
+		"DBHOST":                 "localhost",
+		"DBNAME":                 env.DBName,
+		"JWT_SECRET":             "test-secret",
+		"TICKET_SIGNING_KEY":     base64.StdEncoding.EncodeToString(key),
+		"FRONTEND_URL":           "http://localhost:5173",
+		"PAYMENT_WEBHOOK_SECRET": testWebhookSecret,
+		"MAILER":                 "local",
+		// hash password lebih ringan supaya test cepat
+		"ARGON2_MEMORY_KB": "1024",
+		"ARGON2_TIME":      "1",
+	} {
+		t.Setenv(k, v)
+	}
+	cfg, err := configs.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gateway, err := configs.InitPaymentGateway(cfg.Payment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := configs.InitTicketSigner(cfg.Ticket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mails := make(outbox, 10)
+	revocationStore := repositories.NewTokenRevocationStore(env.DB, env.RDB, cfg.TokenRevocationStore, cfg.JWT.AccessTTL)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	router := routers.InitRouter(cfg, env.DB, env.RDB, gateway, signer, mails, revocationStore, logger)
+	return &server{t: t, router: router, db: env.DB, rdb: env.RDB, mails: mails}
+}
+
+// do mengirim request JSON dan men-decode response ke out (jika tidak nil), mengembalikan status code
+func (s *server) do(method, path, token string, body, out any) int {
+	s.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			s.t.Fatal(err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			s.t.Fatalf("%s %s: decode response %q: %v", method, path, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+// registerAndLogin mendaftarkan user, memverifikasi email lewat token di email, lalu login
+func (s *server) registerAndLogin(email, password string) string {
+	s.t.Helper()
+	credentials := map[string]string{"email": email, "password": password}
+
+	if code := s.do(http.MethodPost, "/auth/register", "", credentials, nil); code != http.StatusCreated {
+		s.t.Fatalf("register %s: status %d, want %d", email, code, http.StatusCreated)
+	}
+	token := s.mails.nextToken(s.t, email)
+	if code := s.do(http.MethodPost, "/auth/verify", "", map[string]string{"token": token}, nil); code != http.StatusOK {
+		s.t.Fatalf("verify %s: status %d, want %d", email, code, http.StatusOK)
+	}
+
+	var login struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	if code := s.do(http.MethodPost, "/auth/login", "", credentials, &login); code != http.StatusOK {
+		s.t.Fatalf("login %s: status %d, want %d", email, code, http.StatusOK)
+	}
+	if login.Token == "" || login.RefreshToken == "" {
+		s.t.Fatalf("login %s: missing token in response", email)
+	}
+	return login.Token
+}
+
+func TestRegisterLogin(t *testing.T) {
+	s := newServer(t)
+	credentials := map[string]string{"email": "budi@example.com", "password": "Rahasia#2025"}
+
+	if code := s.do(http.MethodPost, "/auth/register", "", credentials, nil); code != http.StatusCreated {
+		t.Fatalf("register: status %d, want %d", code, http.StatusCreated)
+	}
+	token := s.mails.nextToken(t, credentials["email"])
+
+	if code := s.do(http.MethodPost, "/auth/register", "", credentials, nil); code != http.StatusConflict {
+		t.Errorf("register twice: status %d, want %d", code, http.StatusConflict)
+	}
+	if code := s.do(http.MethodPost, "/auth/login", "", credentials, nil); code != http.StatusForbidden {
+		t.Errorf("login before verification: status %d, want %d", code, http.StatusForbidden)
+	}
+
+	if code := s.do(http.MethodPost, "/auth/verify", "", map[string]string{"token": token}, nil); code != http.StatusOK {
+		t.Fatalf("verify: status %d, want %d", code, http.StatusOK)
+	}
+
+	wrong := map[string]string{"email": credentials["email"], "password": "Salah#2025"}
+	if code := s.do(http.MethodPost, "/auth/login", "", wrong, nil); code != http.StatusBadRequest {
+		t.Errorf("login with wrong password: status %d, want %d", code, http.StatusBadRequest)
+	}
+	if code := s.do(http.MethodPost, "/auth/login", "", credentials, nil); code != http.StatusOK {
+		t.Errorf("login: status %d, want %d", code, http.StatusOK)
+	}
+}
+
+type seatsResponse struct {
+	Data []struct {
+		SeatID   string `json:"seat_id"`
+		IsSold   bool   `json:"is_sold"`
+		IsHeld   bool   `json:"is_held"`
+		HeldByMe bool   `json:"held_by_me"`
+	} `json:"data"`
+}
+
+func (s *server) seat(token, seatID string) (sold, held, heldByMe bool) {
+	s.t.Helper()
+	var seats seatsResponse
+	if code := s.do(http.MethodGet, fmt.Sprintf("/orders/seats/%d", demoShowingID), token, nil, &seats); code != http.StatusOK {
+		s.t.Fatalf("get seats: status %d, want %d", code, http.StatusOK)
+	}
+	for _, seat := range seats.Data {
+		if seat.SeatID == seatID {
+			return seat.IsSold, seat.IsHeld, seat.HeldByMe
+		}
+	}
+	s.t.Fatalf("seat %s not found in layout", seatID)
+	return
+}
+
+func TestSeatHoldAndOrder(t *testing.T) {
+	s := newServer(t)
+	alice := s.registerAndLogin("alice@example.com", "Rahasia#2025")
+	bob := s.registerAndLogin("bob@example.com", "Rahasia#2025")
+
+	if code := s.do(http.MethodGet, fmt.Sprintf("/orders/seats/%d", demoShowingID), "", nil, nil); code != http.StatusUnauthorized {
+		t.Errorf("get seats without token: status %d, want %d", code, http.StatusUnauthorized)
+	}
+	if sold, held, _ := s.seat(alice, "C5"); sold || held {
+		t.Fatalf("C5 before hold: sold=%v held=%v, want free", sold, held)
+	}
+
+	hold := map[string]any{"now_showing_id": demoShowingID, "seats_map": []string{"C5", "C6"}}
+	if code := s.do(http.MethodPost, "/orders/holds", alice, hold, nil); code != http.StatusCreated {
+		t.Fatalf("alice hold: status %d, want %d", code, http.StatusCreated)
+	}
+	if _, held, heldByMe := s.seat(alice, "C5"); !held || !heldByMe {
+		t.Errorf("C5 for alice: held=%v held_by_me=%v, want held by her", held, heldByMe)
+	}
+	if _, held, heldByMe := s.seat(bob, "C5"); !held || heldByMe {
+		t.Errorf("C5 for bob: held=%v held_by_me=%v, want held by someone else", held, heldByMe)
+	}
+	if code := s.do(http.MethodPost, "/orders/holds", bob, hold, nil); code != http.StatusConflict {
+		t.Errorf("bob hold on held seats: status %d, want %d", code, http.StatusConflict)
+	}
+
+	order := map[string]any{
+		"payment_id":     demoPaymentID,
+		"now_showing_id": demoShowingID,
+		"cinema_id":      demoCinemaID,
+		"seats_map":      []string{"C5"},
+	}
+	if code := s.do(http.MethodPost, "/orders", bob, order, nil); code != http.StatusConflict {
+		t.Errorf("bob order on seat held by alice: status %d, want %d", code, http.StatusConflict)
+	}
+
+	var created struct {
+		Data struct {
+			ID       int      `json:"id"`
+			Price    float64  `json:"price"`
+			SeatsMap []string `json:"seats_map"`
+		} `json:"data"`
+	}
+	if code := s.do(http.MethodPost, "/orders", alice, order, &created); code != http.StatusCreated {
+		t.Fatalf("alice order: status %d, want %d", code, http.StatusCreated)
+	}
+	if created.Data.ID == 0 || created.Data.Price <= 0 || len(created.Data.SeatsMap) != 1 {
+		t.Errorf("alice order: unexpected response %+v", created.Data)
+	}
+	if sold, _, _ := s.seat(bob, "C5"); !sold {
+		t.Error("C5 after order: want sold")
+	}
+	// kunci redis kursi yang terjual dihapus, tinggal C6 yang masih ditahan
+	if keys, err := s.rdb.Keys(t.Context(), fmt.Sprintf("seat_hold:%d:*", demoShowingID)).Result(); err != nil || len(keys) != 1 {
+		t.Errorf("seat hold keys after order: %v (err %v), want only C6", keys, err)
+	}
+
+	// kursi yang sudah terjual tidak bisa dipesan lagi, termasuk oleh pemesan yang sama
+	if code := s.do(http.MethodPost, "/orders", alice, order, nil); code != http.StatusConflict {
+		t.Errorf("order sold seat again: status %d, want %d", code, http.StatusConflict)
+	}
+
+	// C6 masih ditahan alice sampai dilepas
+	if code := s.do(http.MethodDelete, fmt.Sprintf("/orders/holds/%d", demoShowingID), alice, nil, nil); code != http.StatusOK {
+		t.Fatalf("alice release holds: status %d, want %d", code, http.StatusOK)
+	}
+	order["seats_map"] = []string{"C6"}
+	if code := s.do(http.MethodPost, "/orders", bob, order, nil); code != http.StatusCreated {
+		t.Errorf("bob order on released seat: status %d, want %d", code, http.StatusCreated)
+	}
+
+	// kursi dari order yang dibatalkan langsung bisa ditahan user lain
+	if code := s.do(http.MethodPost, fmt.Sprintf("/orders/%d/cancel", created.Data.ID), alice, map[string]any{}, nil); code != http.StatusOK {
+		t.Fatalf("alice cancel order: status %d, want %d", code, http.StatusOK)
+	}
+	hold["seats_map"] = []string{"C5"}
+	if code := s.do(http.MethodPost, "/orders/holds", bob, hold, nil); code != http.StatusCreated {
+		t.Errorf("bob hold on seat of cancelled order: status %d, want %d", code, http.StatusCreated)
+	}
+
+	// riwayat hanya berisi kursi milik order itu sendiri, bukan kursi yang sedang ditahan di jadwal yang sama
+	if history := s.orderHistory(bob); len(history) != 1 || history[0].Status != "pending" || !slices.Equal(history[0].seats(), []string{"C6"}) {
+		t.Errorf("bob history %+v, want one pending order for C6", history)
+	}
+	// order yang dibatalkan tetap tampil walaupun kursinya sudah dilepas
+	if history := s.orderHistory(alice); len(history) != 1 || history[0].ID != created.Data.ID || history[0].Status != "cancelled" || len(history[0].Seats) != 0 {
+		t.Errorf("alice history %+v, want the cancelled order without seats", history)
+	}
+}
+
+type historyOrder struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+	Seats  []struct {
+		Row        string `json:"row"`
+		SeatNumber int    `json:"seat_number"`
+	} `json:"seats"`
+}
+
+func (o historyOrder) seats() []string {
+	var seats []string
+	for _, seat := range o.Seats {
+		seats = append(seats, fmt.Sprintf("%s%d", seat.Row, seat.SeatNumber))
+	}
+	return seats
+}
+
+func (s *server) orderHistory(token string) []historyOrder {
+	s.t.Helper()
+	var history struct {
+		Data []historyOrder `json:"data"`
+	}
+	if code := s.do(http.MethodGet, "/orders/history", token, nil, &history); code != http.StatusOK {
+		s.t.Fatalf("order history: status %d, want %d", code, http.StatusOK)
+	}
+	return history.Data
+}
+
+// webhook mengirim hasil pembayaran yang ditandatangani seperti payment gateway
+func (s *server) webhook(reference, status string, amount int) int {
+	s.t.Helper()
+	body, err := json.Marshal(map[string]any{"reference": reference, "status": status, "amount": amount})
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/payments/webhook", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature", pkg.SignWebhookPayload(testWebhookSecret, body))
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestPaymentWebhookAndExpiry(t *testing.T) {
+	s := newServer(t)
+	alice := s.registerAndLogin("alice@example.com", "Rahasia#2025")
+
+	order := func(seat string) int {
+		var created struct {
+			Data struct {
+				ID int `json:"id"`
+			} `json:"data"`
+		}
+		body := map[string]any{"payment_id": demoPaymentID, "now_showing_id": demoShowingID, "cinema_id": demoCinemaID, "seats_map": []string{seat}}
+		if code := s.do(http.MethodPost, "/orders", alice, body, &created); code != http.StatusCreated {
+			t.Fatalf("order %s: status %d, want %d", seat, code, http.StatusCreated)
+		}
+		return created.Data.ID
+	}
+
+	paidOrder := order("D1")
+	var charge struct {
+		Data struct {
+			Reference string `json:"reference"`
+			Amount    int    `json:"amount"`
+		} `json:"data"`
+	}
+	if code := s.do(http.MethodPost, fmt.Sprintf("/orders/%d/pay", paidOrder), alice, nil, &charge); code != http.StatusCreated {
+		t.Fatalf("pay order: status %d, want %d", code, http.StatusCreated)
+	}
+	if code := s.webhook(charge.Data.Reference, "paid", charge.Data.Amount-1000); code != http.StatusConflict {
+		t.Errorf("webhook with underpaid amount: status %d, want %d", code, http.StatusConflict)
+	}
+	if code := s.webhook(charge.Data.Reference, "paid", charge.Data.Amount); code != http.StatusOK {
+		t.Fatalf("webhook paid: status %d, want %d", code, http.StatusOK)
+	}
+
+	unpaidOrder := order("D2")
+	payments := repositories.NewPaymentRepository(s.db, s.rdb, models.LoyaltyRules{})
+	expired, err := payments.ExpireUnpaidOrders(t.Context(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expired != 1 {
+		t.Errorf("expired %d orders, want only the unpaid order", expired)
+	}
+
+	var status string
+	if err := s.db.QueryRow(t.Context(), "SELECT status FROM orders WHERE id = $1", unpaidOrder).Scan(&status); err != nil || status != "cancelled" {
+		t.Errorf("unpaid order status %q (err %v), want cancelled", status, err)
+	}
+	if err := s.db.QueryRow(t.Context(), "SELECT status FROM orders WHERE id = $1", paidOrder).Scan(&status); err != nil || status != "paid" {
+		t.Errorf("paid order status %q (err %v), want paid", status, err)
+	}
+	if sold, held, _ := s.seat(alice, "D2"); sold || held {
+		t.Errorf("D2 after expiry: sold=%v held=%v, want free", sold, held)
+	}
+	if sold, _, _ := s.seat(alice, "D1"); !sold {
+		t.Error("D1 of paid order released by expiry")
+	}
+}
+
+// failingGateway gateway lokal yang refund-nya selalu gagal
+type failingGateway struct{ *pkg.LocalGateway }
+
+func (failingGateway) Refund(ctx context.Context, reference string, amount int) (pkg.Refund, error) {
+	return pkg.Refund{}, errors.New("gateway unavailable")
+}
+
+func TestCancelRefund(t *testing.T) {
+	s := newServer(t)
+	alice := s.registerAndLogin("alice@example.com", "Rahasia#2025")
+
+	order := func(seat string) int {
+		var created struct {
+			Data struct {
+				ID int `json:"id"`
+			} `json:"data"`
+		}
+		body := map[string]any{"payment_id": demoPaymentID, "now_showing_id": demoShowingID, "cinema_id": demoCinemaID, "seats_map": []string{seat}}
+		if code := s.do(http.MethodPost, "/orders", alice, body, &created); code != http.StatusCreated {
+			t.Fatalf("order %s: status %d, want %d", seat, code, http.StatusCreated)
+		}
+		return created.Data.ID
+	}
+	type cancelResponse struct {
+		Data struct {
+			Refund *struct {
+				ID     int    `json:"id"`
+				Status string `json:"status"`
+			} `json:"refund"`
+		} `json:"data"`
+	}
+
+	t.Run("paid with points only", func(t *testing.T) {
+		orderID := order("E1")
+		if _, err := s.db.Exec(t.Context(), `UPDATE orders SET price = 0, "isPaid" = true, status = 'paid' WHERE id = $1`, orderID); err != nil {
+			t.Fatal(err)
+		}
+		var cancelled cancelResponse
+		if code := s.do(http.MethodPost, fmt.Sprintf("/orders/%d/cancel", orderID), alice, nil, &cancelled); code != http.StatusOK {
+			t.Fatalf("cancel: status %d, want %d", code, http.StatusOK)
+		}
+		var refunds int
+		if err := s.db.QueryRow(t.Context(), "SELECT COUNT(*) FROM refunds WHERE orders_id = $1", orderID).Scan(&refunds); err != nil {
+			t.Fatal(err)
+		}
+		if cancelled.Data.Refund != nil || refunds != 0 {
+			t.Errorf("refund %+v and %d refund rows for order without money paid, want none", cancelled.Data.Refund, refunds)
+		}
+	})
+
+	t.Run("failed refund retried", func(t *testing.T) {
+		orderID := order("E2")
+		var charge struct {
+			Data struct {
+				Reference string `json:"reference"`
+				Amount    int    `json:"amount"`
+			} `json:"data"`
+		}
+		if code := s.do(http.MethodPost, fmt.Sprintf("/orders/%d/pay", orderID), alice, nil, &charge); code != http.StatusCreated {
+			t.Fatalf("pay: status %d, want %d", code, http.StatusCreated)
+		}
+		if code := s.webhook(charge.Data.Reference, "paid", charge.Data.Amount); code != http.StatusOK {
+			t.Fatalf("webhook paid: status %d, want %d", code, http.StatusOK)
+		}
+		var cancelled cancelResponse
+		if code := s.do(http.MethodPost, fmt.Sprintf("/orders/%d/cancel", orderID), alice, nil, &cancelled); code != http.StatusOK {
+			t.Fatalf("cancel: status %d, want %d", code, http.StatusOK)
+		}
+		if cancelled.Data.Refund == nil {
+			t.Fatal("paid order cancelled without refund")
+		}
+		refundID := cancelled.Data.Refund.ID
+
+		// anggap refund pertama gagal di gateway beberapa menit lalu
+		markFailed := func() {
+			t.Helper()
+			if _, err := s.db.Exec(t.Context(), "UPDATE refunds SET status = 'failed', updated_at = NOW() - interval '1 hour' WHERE id = $1", refundID); err != nil {
+				t.Fatal(err)
+			}
+		}
+		markFailed()
+		refunds := repositories.NewRefundRepository(s.db, s.rdb, time.Hour)
+
+		retried, refunded, err := refunds.RetryFailedRefunds(t.Context(), failingGateway{pkg.NewLocalGateway(time.Minute)}, time.Minute)
+		if err != nil || retried != 1 || refunded != 0 {
+			t.Fatalf("retry with failing gateway: retried %d refunded %d err %v, want 1 retried", retried, refunded, err)
+		}
+		// baru saja dicoba, belum waktunya dicoba lagi
+		if retried, _, err := refunds.RetryFailedRefunds(t.Context(), pkg.NewLocalGateway(time.Minute), time.Minute); err != nil || retried != 0 {
+			t.Errorf("retry inside backoff: retried %d err %v, want 0", retried, err)
+		}
+
+		markFailed()
+		if retried, refunded, err := refunds.RetryFailedRefunds(t.Context(), pkg.NewLocalGateway(time.Minute), time.Minute); err != nil || retried != 1 || refunded != 1 {
+			t.Fatalf("retry with working gateway: retried %d refunded %d err %v, want 1 refunded", retried, refunded, err)
+		}
+		var status, trxStatus string
+		var attempts int
+		err = s.db.QueryRow(t.Context(), `SELECT r.status, r.attempts, pt.status FROM refunds r
+			JOIN payment_transactions pt ON pt.id = r.payment_transactions_id WHERE r.id = $1`, refundID).Scan(&status, &attempts, &trxStatus)
+		if err != nil || status != "refunded" || attempts != 2 || trxStatus != "refunded" {
+			t.Errorf("refund %s after %d attempts, transaction %s (err %v), want refunded after 2", status, attempts, trxStatus, err)
+		}
+	})
+}
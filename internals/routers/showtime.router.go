@@ -0,0 +1,29 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/configs"
+	"github.com/raihaninkam/tickitz/internals/handlers"
+	"github.com/raihaninkam/tickitz/internals/middlewares"
+	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/pkg"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitAdminShowtimeRouter(router *gin.Engine, cfg *configs.Config, db *pgxpool.Pool, rdb *redis.Client, gateway pkg.PaymentGateway, mailer pkg.Mailer, revocationStore repositories.TokenRevocationStore) {
+	showtimeRouter := router.Group("/admin/showtimes", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore),
+		middlewares.VerifyToken(cfg.JWT),
+		middlewares.Access("admin"),
+	)
+
+	showtimeRepo := repositories.NewShowtimeRepository(db, rdb)
+	refundRepo := repositories.NewRefundRepository(db, rdb, cfg.OrderCancelCutoff)
+	showtimeHandler := handlers.NewShowtimeHandler(showtimeRepo, refundRepo, gateway, mailer)
+
+	showtimeRouter.GET("", showtimeHandler.GetShowtimes)
+	showtimeRouter.POST("", showtimeHandler.CreateShowtime)
+	showtimeRouter.POST("/bulk", showtimeHandler.GenerateShowtimes)
+	showtimeRouter.PATCH("/:id", showtimeHandler.RescheduleShowtime)
+	showtimeRouter.POST("/:id/cancel", showtimeHandler.CancelShowtime)
+}
@@ -0,0 +1,27 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/raihaninkam/tickitz/internals/configs"
+	"github.com/raihaninkam/tickitz/internals/handlers"
+	"github.com/raihaninkam/tickitz/internals/middlewares"
+	"github.com/raihaninkam/tickitz/internals/repositories"
+	"github.com/raihaninkam/tickitz/pkg"
+)
+
+func InitCheckinRouter(router *gin.Engine, cfg *configs.Config, db *pgxpool.Pool, signer *pkg.TicketSigner, revocationStore repositories.TokenRevocationStore) {
+	checkinRouter := router.Group("/checkin")
+
+	ticketRepo := repositories.NewTicketRepository(db, signer)
+	ticketHandler := handlers.NewTicketHandler(ticketRepo, signer)
+
+	checkinRouter.POST("", middlewares.JWTMiddlewareWithBlacklist(cfg.JWT, revocationStore),
+		middlewares.VerifyToken(cfg.JWT),
+		middlewares.Access("usher", "admin"),
+		ticketHandler.CheckIn,
+	)
+
+	// dipakai aplikasi scanner untuk verifikasi offline
+	checkinRouter.GET("/public-key", ticketHandler.GetPublicKey)
+}
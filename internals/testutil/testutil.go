@@ -0,0 +1,321 @@
+// Package testutil menyiapkan Postgres dan Redis sementara untuk integration test.
+//
+// Server yang sudah ada dipakai jika TEST_DATABASE_URL (format URL postgres://, user dengan hak CREATEDB)
+// dan TEST_REDIS_ADDR di-set, misalnya di CI. Jika tidak, binary initdb / postgres dan redis-server lokal
+// dijalankan sekali per package di port acak dengan data di folder sementara. Test di-skip jika keduanya
+// tidak tersedia.
+// Setiap test mendapat database baru yang sudah dimigrasi penuh dan Redis yang sudah dikosongkan.
+package testutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	dbfs "github.com/raihaninkam/tickitz/db"
+	"github.com/raihaninkam/tickitz/pkg"
+	"github.com/redis/go-redis/v9"
+)
+
+// startTimeout batas waktu menunggu server lokal siap menerima koneksi
+const startTimeout = 30 * time.Second
+
+// Env koneksi ke database dan Redis milik satu test
+type Env struct {
+	DB  *pgxpool.Pool
+	RDB *redis.Client
+	// DBName nama database sementara, dihapus setelah test selesai
+	DBName string
+}
+
+// servers Postgres dan Redis yang dipakai bersama oleh semua test di satu package
+type servers struct {
+	once      sync.Once
+	err       error
+	skip      string
+	adminURL  string
+	redisAddr string
+	procs     []*exec.Cmd
+	tempDirs  []string
+}
+
+var (
+	shared servers
+	dbSeq  atomic.Int64
+)
+
+// Main dipanggil dari TestMain, menjalankan test lalu mematikan server lokal yang dijalankan Setup
+//
+//	func TestMain(m *testing.M) { testutil.Main(m) }
+func Main(m *testing.M) {
+	code := m.Run()
+	shared.stop()
+	os.Exit(code)
+}
+
+// Setup membuat database baru yang sudah dimigrasi dan mengosongkan Redis. Test di-skip jika -short
+// atau Postgres / Redis tidak tersedia, dan gagal jika server tersedia tetapi tidak bisa dipakai
+func Setup(t testing.TB) *Env {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("integration test skipped in -short mode")
+	}
+
+	shared.once.Do(shared.start)
+	if shared.skip != "" {
+		t.Skip(shared.skip)
+	}
+	if shared.err != nil {
+		t.Fatalf("start test servers: %v", shared.err)
+	}
+
+	ctx := context.Background()
+	name := fmt.Sprintf("tickitz_test_%d_%d", os.Getpid(), dbSeq.Add(1))
+	if err := adminExec(ctx, shared.adminURL, "CREATE DATABASE "+name); err != nil {
+		t.Fatalf("create database %s: %v", name, err)
+	}
+
+	db, err := pgxpool.New(ctx, withDatabase(shared.adminURL, name))
+	if err != nil {
+		t.Fatalf("connect %s: %v", name, err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		// WITH (FORCE) memutus koneksi yang masih terbuka (Postgres 13+)
+		if err := adminExec(context.Background(), shared.adminURL, "DROP DATABASE IF EXISTS "+name+" WITH (FORCE)"); err != nil {
+			t.Logf("drop database %s: %v", name, err)
+		}
+	})
+
+	migrations, err := fs.Sub(dbfs.Migrations, "migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrator, err := pkg.NewMigrator(db, migrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx, 0); err != nil {
+		t.Fatalf("migrate %s: %v", name, err)
+	}
+
+	rdb := redis.NewClient(&redis.Options{Addr: shared.redisAddr})
+	t.Cleanup(func() { rdb.Close() })
+	if err := rdb.FlushDB(ctx).Err(); err != nil {
+		t.Fatalf("flush redis: %v", err)
+	}
+
+	return &Env{DB: db, RDB: rdb, DBName: name}
+}
+
+// Seed menjalankan profile seed (misalnya "demo") ke database test
+func (e *Env) Seed(t testing.TB, profile string) {
+	t.Helper()
+	// file seed di-embed dengan prefix folder "seeds/"
+	seeds, err := fs.Sub(dbfs.Seeds, "seeds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pkg.Seed(context.Background(), e.DB, seeds, profile); err != nil {
+		t.Fatalf("seed %s: %v", profile, err)
+	}
+}
+
+func (s *servers) start() {
+	s.adminURL = os.Getenv("TEST_DATABASE_URL")
+	s.redisAddr = os.Getenv("TEST_REDIS_ADDR")
+
+	if s.adminURL == "" {
+		s.adminURL, s.skip, s.err = s.startPostgres()
+		if s.skip != "" || s.err != nil {
+			return
+		}
+	}
+	if s.redisAddr == "" {
+		s.redisAddr, s.skip, s.err = s.startRedis()
+		if s.skip != "" || s.err != nil {
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+	if err := waitPostgres(ctx, s.adminURL); err != nil {
+		s.err = fmt.Errorf("postgres not ready: %w", err)
+		return
+	}
+	if err := waitRedis(ctx, s.redisAddr); err != nil {
+		s.err = fmt.Errorf("redis not ready: %w", err)
+	}
+}
+
+// startPostgres initdb ke folder sementara lalu menjalankan postgres tanpa fsync (data tidak perlu awet)
+func (s *servers) startPostgres() (adminURL, skip string, err error) {
+	initdb := findBinary("initdb")
+	postgres := findBinary("postgres")
+	if initdb == "" || postgres == "" {
+		return "", "postgres binaries not found, set TEST_DATABASE_URL to run integration tests", nil
+	}
+	if os.Geteuid() == 0 {
+		return "", "postgres refuses to run as root, set TEST_DATABASE_URL to run integration tests", nil
+	}
+
+	dir, err := os.MkdirTemp("", "tickitz-pg-")
+	if err != nil {
+		return "", "", err
+	}
+	s.tempDirs = append(s.tempDirs, dir)
+	dataDir := filepath.Join(dir, "data")
+
+	out, err := exec.Command(initdb, "-D", dataDir, "-U", "postgres", "-A", "trust", "-E", "UTF8", "--no-sync").CombinedOutput()
+	if err != nil {
+		return "", "", fmt.Errorf("initdb: %w\n%s", err, out)
+	}
+
+	port, err := freePort()
+	if err != nil {
+		return "", "", err
+	}
+	cmd := exec.Command(postgres, "-D", dataDir, "-p", port, "-k", dir,
+		"-c", "listen_addresses=127.0.0.1", "-c", "fsync=off", "-c", "synchronous_commit=off", "-c", "full_page_writes=off")
+	if err := s.run(cmd); err != nil {
+		return "", "", fmt.Errorf("start postgres: %w", err)
+	}
+	return fmt.Sprintf("postgres://postgres@127.0.0.1:%s/postgres?sslmode=disable", port), "", nil
+}
+
+func (s *servers) startRedis() (addr, skip string, err error) {
+	server := findBinary("redis-server")
+	if server == "" {
+		return "", "redis-server not found, set TEST_REDIS_ADDR to run integration tests", nil
+	}
+	port, err := freePort()
+	if err != nil {
+		return "", "", err
+	}
+	cmd := exec.Command(server, "--port", port, "--bind", "127.0.0.1", "--save", "", "--appendonly", "no")
+	if err := s.run(cmd); err != nil {
+		return "", "", fmt.Errorf("start redis-server: %w", err)
+	}
+	return net.JoinHostPort("127.0.0.1", port), "", nil
+}
+
+func (s *servers) run(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	s.procs = append(s.procs, cmd)
+	return nil
+}
+
+// stop mematikan server lokal (SIGINT = fast shutdown untuk postgres) dan menghapus folder datanya
+func (s *servers) stop() {
+	for _, cmd := range s.procs {
+		cmd.Process.Signal(syscall.SIGINT)
+		done := make(chan struct{})
+		go func() {
+			cmd.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			cmd.Process.Kill()
+		}
+	}
+	for _, dir := range s.tempDirs {
+		os.RemoveAll(dir)
+	}
+}
+
+// findBinary mencari di PATH, lalu di lokasi paket postgresql Debian / Ubuntu yang tidak ada di PATH
+func findBinary(name string) string {
+	if path, err := exec.LookPath(name); err == nil {
+		return path
+	}
+	matches, _ := filepath.Glob(filepath.Join("/usr/lib/postgresql/*/bin", name))
+	if len(matches) == 0 {
+		return ""
+	}
+	// versi terbaru (urutan glob leksikografis, cukup untuk versi dua digit)
+	return matches[len(matches)-1]
+}
+
+func freePort() (string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer l.Close()
+	_, port, err := net.SplitHostPort(l.Addr().String())
+	return port, err
+}
+
+func waitPostgres(ctx context.Context, connString string) error {
+	for {
+		conn, err := pgx.Connect(ctx, connString)
+		if err == nil {
+			return conn.Close(ctx)
+		}
+		if !sleep(ctx) {
+			return errors.Join(ctx.Err(), err)
+		}
+	}
+}
+
+func waitRedis(ctx context.Context, addr string) error {
+	rdb := redis.NewClient(&redis.Options{Addr: addr})
+	defer rdb.Close()
+	for {
+		err := rdb.Ping(ctx).Err()
+		if err == nil {
+			return nil
+		}
+		if !sleep(ctx) {
+			return errors.Join(ctx.Err(), err)
+		}
+	}
+}
+
+func sleep(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(100 * time.Millisecond):
+		return true
+	}
+}
+
+// adminExec menjalankan CREATE / DROP DATABASE lewat koneksi admin (tidak bisa di dalam transaksi)
+func adminExec(ctx context.Context, adminURL, sql string) error {
+	conn, err := pgx.Connect(ctx, adminURL)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+	_, err = conn.Exec(ctx, sql)
+	return err
+}
+
+// withDatabase connection string admin dengan nama database diganti
+func withDatabase(connString, name string) string {
+	u, err := url.Parse(connString)
+	if err != nil {
+		return connString
+	}
+	u.Path = "/" + name
+	return u.String()
+}
@@ -0,0 +1,218 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"    // normal, semua perintah dikirim ke Redis
+	BreakerOpen     BreakerState = "open"      // Redis dianggap mati, perintah langsung dilewati
+	BreakerHalfOpen BreakerState = "half-open" // cooldown habis, satu perintah dicoba untuk cek Redis sudah hidup
+)
+
+// ErrRedisUnavailable dikembalikan tanpa menghubungi Redis saat breaker open (atau client nil)
+var ErrRedisUnavailable = errors.New("redis unavailable")
+
+// circuitBreaker membuka setelah threshold error berturut-turut dan mencoba lagi setelah cooldown
+type circuitBreaker struct {
+	mu        sync.Mutex
+	state     BreakerState
+	failures  int
+	threshold int
+	cooldown  time.Duration
+	openedAt  time.Time
+	lastError string
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{state: BreakerClosed, threshold: threshold, cooldown: cooldown}
+}
+
+// allow true jika perintah boleh dikirim. Saat half-open hanya satu perintah (probe) yang diizinkan
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		return true
+	case BreakerHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+// success mencatat perintah berhasil, true jika Redis baru saja pulih
+func (b *circuitBreaker) success() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	recovered := b.state != BreakerClosed
+	b.state = BreakerClosed
+	b.failures = 0
+	b.lastError = ""
+	return recovered
+}
+
+func (b *circuitBreaker) failure(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.lastError = err.Error()
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		if b.state != BreakerOpen {
+			slog.Warn("redis circuit breaker open, skipping redis until cooldown", "cooldown", b.cooldown, "error", err)
+		}
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// abort dipanggil jika perintah batal karena context, bukan karena Redis. Probe half-open
+// dikembalikan ke open tanpa menunggu cooldown lagi
+func (b *circuitBreaker) abort() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerHalfOpen {
+		b.state = BreakerOpen
+		b.openedAt = time.Now().Add(-b.cooldown)
+	}
+}
+
+func (b *circuitBreaker) snapshot() (BreakerState, string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state, b.lastError
+}
+
+// redisBreakers breaker per client Redis. Aplikasi hanya punya satu client sehingga praktis ini satu breaker
+// bersama: 3 error berturut-turut membuka breaker selama 10 detik
+var redisBreakers sync.Map
+
+func breakerFor(rdb *redis.Client) *circuitBreaker {
+	if b, ok := redisBreakers.Load(rdb); ok {
+		return b.(*circuitBreaker)
+	}
+	b, _ := redisBreakers.LoadOrStore(rdb, newCircuitBreaker(3, 10*time.Second))
+	return b.(*circuitBreaker)
+}
+
+// WithRedis menjalankan fn lewat circuit breaker. Semua akses ke Redis harus lewat sini supaya request
+// tidak menunggu timeout dial saat Redis mati: begitu breaker open fn tidak dijalankan dan
+// ErrRedisUnavailable langsung dikembalikan. redis.Nil dan context yang dibatalkan tidak dihitung sebagai error Redis
+func WithRedis(ctx context.Context, rdb *redis.Client, fn func() error) error {
+	if rdb == nil {
+		return ErrRedisUnavailable
+	}
+	breaker := breakerFor(rdb)
+	if !breaker.allow() {
+		return ErrRedisUnavailable
+	}
+
+	err := fn()
+	if err != nil && err != redis.Nil {
+		if ctx.Err() != nil {
+			breaker.abort()
+			return err
+		}
+		breaker.failure(err)
+		return err
+	}
+
+	if breaker.success() {
+		slog.Info("redis recovered, circuit breaker closed")
+	}
+	// invalidasi yang tertunda juga bisa berasal dari satu error yang belum membuka breaker
+	if pendingInvalidations.queued.CompareAndSwap(true, false) {
+		go flushPendingInvalidations(rdb)
+	}
+	return err
+}
+
+// invalidasi yang gagal dikirim selama Redis mati, dikirim ulang begitu Redis pulih
+// supaya Redis tidak menyajikan data yang sudah diubah admin
+var pendingInvalidations = struct {
+	mu     sync.Mutex
+	queued atomic.Bool
+	tags   map[string]struct{}
+	keys   map[string]struct{}
+}{tags: map[string]struct{}{}, keys: map[string]struct{}{}}
+
+func queueInvalidation(tags, keys []string) {
+	pendingInvalidations.mu.Lock()
+	defer pendingInvalidations.mu.Unlock()
+	for _, tag := range tags {
+		pendingInvalidations.tags[tag] = struct{}{}
+	}
+	for _, key := range keys {
+		pendingInvalidations.keys[key] = struct{}{}
+	}
+	pendingInvalidations.queued.Store(true)
+}
+
+func flushPendingInvalidations(rdb *redis.Client) {
+	pendingInvalidations.mu.Lock()
+	tags := make([]string, 0, len(pendingInvalidations.tags))
+	for tag := range pendingInvalidations.tags {
+		tags = append(tags, tag)
+	}
+	keys := make([]string, 0, len(pendingInvalidations.keys))
+	for key := range pendingInvalidations.keys {
+		keys = append(keys, key)
+	}
+	pendingInvalidations.tags = map[string]struct{}{}
+	pendingInvalidations.keys = map[string]struct{}{}
+	pendingInvalidations.mu.Unlock()
+
+	ctx := context.Background()
+	if len(tags) > 0 {
+		if err := InvalidateTags(ctx, rdb, tags...); err != nil {
+			slog.Warn("replaying queued cache invalidation failed", "tags", len(tags), "error", err)
+		}
+	}
+	if len(keys) > 0 {
+		if err := InvalidateCache(ctx, rdb, keys...); err != nil {
+			slog.Warn("replaying queued cache invalidation failed", "keys", len(keys), "error", err)
+		}
+	}
+}
+
+// RedisHealth status Redis untuk health check
+type RedisHealth struct {
+	Status       string       `json:"status" example:"up"` // up / down
+	Breaker      BreakerState `json:"breaker" example:"closed"`
+	LastError    string       `json:"last_error,omitempty"`
+	LocalEntries int          `json:"local_cache_entries"`
+}
+
+// CheckRedis ping Redis lewat breaker. Saat breaker open ping tidak dikirim sampai cooldown habis,
+// setelah itu ping ini sekaligus menjadi probe untuk menutup kembali breaker
+func CheckRedis(ctx context.Context, rdb *redis.Client) RedisHealth {
+	health := RedisHealth{Status: "up"}
+	if err := WithRedis(ctx, rdb, func() error { return rdb.Ping(ctx).Err() }); err != nil {
+		health.Status = "down"
+	}
+	if rdb == nil {
+		health.Breaker, health.LastError = BreakerOpen, "redis not configured"
+	} else {
+		health.Breaker, health.LastError = breakerFor(rdb).snapshot()
+	}
+	health.LocalEntries = localCache.len()
+	return health
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestWithRedisBreaker(t *testing.T) {
+	ctx := t.Context()
+	mr, rdb := newTestRedis(t)
+	_, other := newTestRedis(t)
+
+	calls := 0
+	get := func() error {
+		calls++
+		return rdb.Get(ctx, "missing").Err()
+	}
+	// redis.Nil bukan error Redis
+	if err := WithRedis(ctx, rdb, get); err != redis.Nil {
+		t.Fatalf("missing key: err %v, want redis.Nil", err)
+	}
+
+	mr.Close()
+	for range 3 {
+		if err := WithRedis(ctx, rdb, get); err == nil || errors.Is(err, ErrRedisUnavailable) {
+			t.Fatalf("redis down: err %v, want connection error", err)
+		}
+	}
+	// breaker open: Redis tidak dihubungi sama sekali
+	calls = 0
+	for range 5 {
+		if err := WithRedis(ctx, rdb, get); !errors.Is(err, ErrRedisUnavailable) {
+			t.Fatalf("breaker open: err %v, want %v", err, ErrRedisUnavailable)
+		}
+	}
+	if calls != 0 {
+		t.Errorf("redis called %d times while breaker open", calls)
+	}
+	// breaker milik client lain tidak ikut terbuka
+	if err := WithRedis(ctx, other, func() error { return other.Ping(ctx).Err() }); err != nil {
+		t.Errorf("other client: %v", err)
+	}
+
+	// context yang dibatalkan saat probe tidak dihitung sebagai kegagalan Redis
+	if err := mr.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	breaker := breakerFor(rdb)
+	breaker.mu.Lock()
+	breaker.openedAt = time.Now().Add(-breaker.cooldown)
+	breaker.mu.Unlock()
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := WithRedis(canceled, rdb, func() error { return rdb.Ping(canceled).Err() }); !errors.Is(err, context.Canceled) {
+		t.Fatalf("canceled probe: err %v", err)
+	}
+	if err := WithRedis(ctx, rdb, get); err != redis.Nil {
+		t.Fatalf("probe after cancel: err %v, want redis.Nil", err)
+	}
+	if state, _ := breaker.snapshot(); state != BreakerClosed {
+		t.Errorf("breaker %s after successful probe, want closed", state)
+	}
+}
+
+func TestWithRedisNilClient(t *testing.T) {
+	if err := WithRedis(t.Context(), nil, func() error { return nil }); !errors.Is(err, ErrRedisUnavailable) {
+		t.Errorf("nil client: err %v, want %v", err, ErrRedisUnavailable)
+	}
+	if health := CheckRedis(t.Context(), nil); health.Status != "down" || health.LastError != "redis not configured" {
+		t.Errorf("nil client health %+v", health)
+	}
+}
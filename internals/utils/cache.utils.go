@@ -0,0 +1,210 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+// CacheOptions aturan cache untuk satu key
+type CacheOptions struct {
+	TTL         time.Duration // umur data dianggap fresh
+	StaleTTL    time.Duration // setelah TTL habis data lama masih disajikan selama ini sambil di-refresh di background
+	NegativeTTL time.Duration // umur cache untuk hasil Negative (contoh: "movie not found"), 0 = tidak di-cache
+	Tags        []string      // tag untuk InvalidateTags
+}
+
+// cacheEntry isi value di Redis. Error diisi jika hasilnya negative
+type cacheEntry[T any] struct {
+	Value      T      `json:"v"`
+	Error      string `json:"err,omitempty"`
+	FreshUntil int64  `json:"fresh_until"`
+}
+
+// negativeError error dari loader yang boleh di-cache
+type negativeError struct {
+	err error
+}
+
+func (n negativeError) Error() string { return n.err.Error() }
+func (n negativeError) Unwrap() error { return n.err }
+
+// Negative menandai error dari loader sebagai hasil "tidak ada data" yang boleh di-cache selama NegativeTTL.
+// Error() tetap sama sehingga handler masih bisa mencocokkan pesan error-nya
+func Negative(err error) error {
+	return negativeError{err: err}
+}
+
+// CacheStats counter cache sejak aplikasi jalan
+type CacheStats struct {
+	Hits         int64   `json:"hits"`
+	StaleHits    int64   `json:"stale_hits"`
+	NegativeHits int64   `json:"negative_hits"`
+	Misses       int64   `json:"misses"`
+	Loads        int64   `json:"loads"`        // loader benar-benar dijalankan
+	SharedLoads  int64   `json:"shared_loads"` // request yang menunggu hasil loader milik request lain
+	Refreshes    int64   `json:"refreshes"`    // refresh background untuk data stale
+	LoadErrors   int64   `json:"load_errors"`  // loader gagal (selain Negative)
+	RedisErrors  int64   `json:"redis_errors"` // get/set ke Redis gagal
+	LocalHits    int64   `json:"local_hits"`   // dibaca dari cache lokal karena Redis tidak tersedia
+	HitRate      float64 `json:"hit_rate"`
+}
+
+var cacheCounters struct {
+	hits, staleHits, negativeHits, misses, loads, sharedLoads, refreshes, loadErrors, redisErrors, localHits atomic.Int64
+}
+
+// GetCacheStats snapshot counter cache untuk monitoring
+func GetCacheStats() CacheStats {
+	stats := CacheStats{
+		Hits:         cacheCounters.hits.Load(),
+		StaleHits:    cacheCounters.staleHits.Load(),
+		NegativeHits: cacheCounters.negativeHits.Load(),
+		Misses:       cacheCounters.misses.Load(),
+		Loads:        cacheCounters.loads.Load(),
+		SharedLoads:  cacheCounters.sharedLoads.Load(),
+		Refreshes:    cacheCounters.refreshes.Load(),
+		LoadErrors:   cacheCounters.loadErrors.Load(),
+		RedisErrors:  cacheCounters.redisErrors.Load(),
+		LocalHits:    cacheCounters.localHits.Load(),
+	}
+	served := stats.Hits + stats.StaleHits + stats.NegativeHits
+	if total := served + stats.Misses; total > 0 {
+		stats.HitRate = float64(served) / float64(total)
+	}
+	return stats
+}
+
+var (
+	cacheGroup singleflight.Group
+	refreshing sync.Map // key yang sedang di-refresh di background
+)
+
+// Cached mengambil key dari Redis, jika tidak ada memanggil load sekali saja walaupun banyak request
+// bersamaan (single-flight) lalu menyimpan hasilnya. Data yang sudah lewat TTL tapi masih dalam StaleTTL
+// langsung dikembalikan dan di-refresh di background
+func Cached[T any](ctx context.Context, rdb *redis.Client, key string, opts CacheOptions, load func(context.Context) (T, error)) (T, error) {
+	if entry, ok := readEntry[T](ctx, rdb, key); ok {
+		if entry.Error != "" {
+			cacheCounters.negativeHits.Add(1)
+			return entry.Value, errors.New(entry.Error)
+		}
+		if time.Now().UnixMilli() < entry.FreshUntil {
+			cacheCounters.hits.Add(1)
+			return entry.Value, nil
+		}
+		cacheCounters.staleHits.Add(1)
+		refreshInBackground(ctx, rdb, key, opts, load)
+		return entry.Value, nil
+	}
+
+	cacheCounters.misses.Add(1)
+	value, err, shared := cacheGroup.Do(key, func() (any, error) {
+		return loadAndStore(ctx, rdb, key, opts, load)
+	})
+	if shared {
+		cacheCounters.sharedLoads.Add(1)
+	}
+	result, _ := value.(T)
+	return result, err
+}
+
+func refreshInBackground[T any](ctx context.Context, rdb *redis.Client, key string, opts CacheOptions, load func(context.Context) (T, error)) {
+	if _, running := refreshing.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+	cacheCounters.refreshes.Add(1)
+
+	go func() {
+		defer refreshing.Delete(key)
+		_, _, _ = cacheGroup.Do(key, func() (any, error) {
+			return loadAndStore(ctx, rdb, key, opts, load)
+		})
+	}()
+}
+
+func loadAndStore[T any](ctx context.Context, rdb *redis.Client, key string, opts CacheOptions, load func(context.Context) (T, error)) (T, error) {
+	cacheCounters.loads.Add(1)
+
+	// hasil dipakai bersama request lain (dan refresh background), jadi jangan ikut batal
+	// jika request yang memicu load sudah selesai atau dibatalkan
+	ctx = context.WithoutCancel(ctx)
+	value, err := load(ctx)
+	if err != nil {
+		var negative negativeError
+		if !errors.As(err, &negative) {
+			cacheCounters.loadErrors.Add(1)
+			return value, err
+		}
+		if opts.NegativeTTL > 0 {
+			entry := cacheEntry[T]{Value: value, Error: negative.Error(), FreshUntil: time.Now().Add(opts.NegativeTTL).UnixMilli()}
+			writeEntry(ctx, rdb, key, entry, opts.NegativeTTL, opts.Tags)
+		}
+		return value, negative.err
+	}
+
+	entry := cacheEntry[T]{Value: value, FreshUntil: time.Now().Add(opts.TTL).UnixMilli()}
+	writeEntry(ctx, rdb, key, entry, opts.TTL+opts.StaleTTL, opts.Tags)
+	return value, nil
+}
+
+func readEntry[T any](ctx context.Context, rdb *redis.Client, key string) (cacheEntry[T], bool) {
+	var entry cacheEntry[T]
+	var b []byte
+	err := WithRedis(ctx, rdb, func() (err error) {
+		b, err = rdb.Get(ctx, key).Bytes()
+		return err
+	})
+	if err != nil {
+		if err == redis.Nil {
+			return entry, false
+		}
+		if err != ErrRedisUnavailable {
+			cacheCounters.redisErrors.Add(1)
+			Logger(ctx).Warn("cache read failed, using local cache", "key", key, "error", err)
+		}
+		// Redis tidak tersedia: pakai cache lokal
+		var ok bool
+		if b, ok = localCache.get(key); !ok {
+			return entry, false
+		}
+		cacheCounters.localHits.Add(1)
+	}
+	// format lama (tanpa fresh_until) dianggap miss
+	if err := json.Unmarshal(b, &entry); err != nil || entry.FreshUntil == 0 {
+		return entry, false
+	}
+	return entry, true
+}
+
+func writeEntry[T any](ctx context.Context, rdb *redis.Client, key string, entry cacheEntry[T], ttl time.Duration, tags []string) {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		Logger(ctx).Error("cache encode failed", "key", key, "error", err)
+		return
+	}
+
+	err = WithRedis(ctx, rdb, func() error {
+		pipe := rdb.TxPipeline()
+		pipe.Set(ctx, key, b, ttl)
+		for _, tag := range tags {
+			pipe.SAdd(ctx, tagKey(tag), key)
+			pipe.Expire(ctx, tagKey(tag), tagTTL)
+		}
+		_, err := pipe.Exec(ctx)
+		return err
+	})
+	if err != nil {
+		if err != ErrRedisUnavailable {
+			cacheCounters.redisErrors.Add(1)
+			Logger(ctx).Warn("cache write failed, using local cache", "key", key, "error", err)
+		}
+		localCache.set(key, b, ttl, tags)
+	}
+}
@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCachedSingleFlight(t *testing.T) {
+	_, rdb := newTestRedis(t)
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) ([]int, error) {
+		loads.Add(1)
+		<-release
+		return []int{1, 2, 3}, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([][]int, 20)
+	for i := range results {
+		wg.Go(func() {
+			results[i], _ = Cached(t.Context(), rdb, "movies:popular", CacheOptions{TTL: time.Minute}, load)
+		})
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := loads.Load(); n != 1 {
+		t.Errorf("loader ran %d times, want 1", n)
+	}
+	for i, result := range results {
+		if len(result) != 3 {
+			t.Errorf("caller %d got %v", i, result)
+		}
+	}
+}
+
+func TestCachedEmptyResultIsHit(t *testing.T) {
+	_, rdb := newTestRedis(t)
+	var loads atomic.Int32
+	load := func(context.Context) ([]int, error) {
+		loads.Add(1)
+		return []int{}, nil
+	}
+
+	for range 3 {
+		if result, err := Cached(t.Context(), rdb, "movies:upcoming", CacheOptions{TTL: time.Minute}, load); err != nil || result == nil {
+			t.Fatalf("result %v, err %v", result, err)
+		}
+	}
+	if n := loads.Load(); n != 1 {
+		t.Errorf("empty result loaded %d times, want 1", n)
+	}
+}
+
+func TestCachedStaleWhileRevalidate(t *testing.T) {
+	const key = "movie:1:detail"
+	_, rdb := newTestRedis(t)
+	var loads atomic.Int32
+	load := func(context.Context) (int32, error) {
+		return loads.Add(1), nil
+	}
+	opts := CacheOptions{TTL: 20 * time.Millisecond, StaleTTL: time.Minute}
+
+	if v, _ := Cached(t.Context(), rdb, key, opts, load); v != 1 {
+		t.Fatalf("first load %d, want 1", v)
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	// data stale langsung dikembalikan, refresh berjalan di background
+	if v, _ := Cached(t.Context(), rdb, key, opts, load); v != 1 {
+		t.Errorf("stale read %d, want previous value 1", v)
+	}
+	waitFor(t, "background refresh", func() bool {
+		_, running := refreshing.Load(key)
+		return loads.Load() == 2 && !running
+	})
+	if v, _ := Cached(t.Context(), rdb, key, opts, load); v != 2 {
+		t.Errorf("read after refresh %d, want 2", v)
+	}
+}
+
+func TestCachedNegative(t *testing.T) {
+	_, rdb := newTestRedis(t)
+	var loads atomic.Int32
+	notFound := func(context.Context) (string, error) {
+		loads.Add(1)
+		return "", Negative(errors.New("movie not found"))
+	}
+	opts := CacheOptions{TTL: time.Minute, NegativeTTL: time.Minute}
+
+	for range 2 {
+		_, err := Cached(t.Context(), rdb, "movie:404:detail", opts, notFound)
+		if err == nil || err.Error() != "movie not found" {
+			t.Fatalf("err %v, want movie not found", err)
+		}
+		var negative negativeError
+		if errors.As(err, &negative) {
+			t.Error("negative wrapper leaked to caller")
+		}
+	}
+	if n := loads.Load(); n != 1 {
+		t.Errorf("negative result loaded %d times, want 1", n)
+	}
+
+	// error biasa (contoh: database mati) tidak di-cache
+	loads.Store(0)
+	failing := func(context.Context) (string, error) {
+		loads.Add(1)
+		return "", errors.New("connection refused")
+	}
+	for range 2 {
+		if _, err := Cached(t.Context(), rdb, "movie:5:detail", opts, failing); err == nil {
+			t.Fatal("loader error swallowed")
+		}
+	}
+	if n := loads.Load(); n != 2 {
+		t.Errorf("failing loader ran %d times, want 2", n)
+	}
+}
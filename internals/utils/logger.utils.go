@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"context"
+	"log/slog"
+)
+
+type loggerKey struct{}
+type requestIDKey struct{}
+
+// WithLogger menyimpan logger (biasanya sudah berisi request_id) dan request ID ke context
+func WithLogger(ctx context.Context, logger *slog.Logger, requestID string) context.Context {
+	ctx = context.WithValue(ctx, loggerKey{}, logger)
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// Logger logger milik request, atau slog.Default() jika context bukan dari request HTTP
+func Logger(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return slog.Default()
+}
+
+// RequestID request ID dari context, kosong jika tidak ada
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"container/list"
+	"slices"
+	"sync"
+	"time"
+)
+
+// localMaxTTL batas umur data di cache lokal, karena invalidasi dari instance lain tidak sampai ke sini
+const localMaxTTL = time.Minute
+
+// localCache cache in-process yang dipakai saat Redis tidak tersedia
+var localCache = newLRUCache(512)
+
+type lruItem struct {
+	key      string
+	value    []byte
+	expireAt time.Time
+	tags     []string
+}
+
+// lruCache cache LRU sederhana dengan TTL dan tag, aman dipakai banyak goroutine
+type lruCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+func newLRUCache(capacity int) *lruCache {
+	return &lruCache{capacity: capacity, ll: list.New(), items: map[string]*list.Element{}}
+}
+
+func (c *lruCache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	item := elem.Value.(*lruItem)
+	if time.Now().After(item.expireAt) {
+		c.removeElement(elem)
+		return nil, false
+	}
+	c.ll.MoveToFront(elem)
+	return item.value, true
+}
+
+func (c *lruCache) set(key string, value []byte, ttl time.Duration, tags []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item := &lruItem{key: key, value: value, expireAt: time.Now().Add(min(ttl, localMaxTTL)), tags: tags}
+	if elem, ok := c.items[key]; ok {
+		elem.Value = item
+		c.ll.MoveToFront(elem)
+		return
+	}
+	c.items[key] = c.ll.PushFront(item)
+	for c.ll.Len() > c.capacity {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+func (c *lruCache) delete(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if elem, ok := c.items[key]; ok {
+			c.removeElement(elem)
+		}
+	}
+}
+
+// deleteTags menghapus semua item yang punya salah satu tag
+func (c *lruCache) deleteTags(tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for elem := c.ll.Front(); elem != nil; {
+		next := elem.Next()
+		item := elem.Value.(*lruItem)
+		for _, tag := range tags {
+			if slices.Contains(item.tags, tag) {
+				c.removeElement(elem)
+				break
+			}
+		}
+		elem = next
+	}
+}
+
+func (c *lruCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+func (c *lruCache) removeElement(elem *list.Element) {
+	c.ll.Remove(elem)
+	delete(c.items, elem.Value.(*lruItem).key)
+}
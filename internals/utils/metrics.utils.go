@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Metrics registry metric aplikasi yang ditampilkan di /metrics
+var Metrics = prometheus.NewRegistry()
+
+var metrics = promauto.With(Metrics)
+
+var (
+	HTTPRequests = metrics.NewCounterVec(prometheus.CounterOpts{
+		Name: "tickitz_http_requests_total",
+		Help: "Jumlah request HTTP per route gin",
+	}, []string{"method", "route", "status"})
+	HTTPDuration = metrics.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "tickitz_http_request_duration_seconds",
+		Help:    "Durasi request HTTP per route gin",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	OrdersCreated = metrics.NewCounter(prometheus.CounterOpts{
+		Name: "tickitz_orders_created_total",
+		Help: "Order yang berhasil dibuat",
+	})
+	SeatsSold = metrics.NewCounter(prometheus.CounterOpts{
+		Name: "tickitz_seats_sold_total",
+		Help: "Kursi yang terjual (order lunas)",
+	})
+	PaymentFailures = metrics.NewCounterVec(prometheus.CounterOpts{
+		Name: "tickitz_payment_failures_total",
+		Help: "Pembayaran gagal: gateway_error (gagal membuat charge) atau failed (gagal/expired dari gateway)",
+	}, []string{"reason"})
+)
+
+// counter cache dibaca saat scrape dari cacheCounters
+func init() {
+	cacheCounter := func(name, help string, counter interface{ Load() int64 }) {
+		metrics.NewCounterFunc(prometheus.CounterOpts{Name: name, Help: help}, func() float64 {
+			return float64(counter.Load())
+		})
+	}
+	cacheCounter("tickitz_cache_hits_total", "Cache hit (data fresh)", &cacheCounters.hits)
+	cacheCounter("tickitz_cache_stale_hits_total", "Cache hit data stale (di-refresh di background)", &cacheCounters.staleHits)
+	cacheCounter("tickitz_cache_negative_hits_total", "Cache hit untuk hasil kosong / not found", &cacheCounters.negativeHits)
+	cacheCounter("tickitz_cache_misses_total", "Cache miss", &cacheCounters.misses)
+	cacheCounter("tickitz_cache_loads_total", "Loader cache yang benar-benar query ke database", &cacheCounters.loads)
+	cacheCounter("tickitz_cache_local_hits_total", "Cache dibaca dari fallback lokal karena Redis tidak tersedia", &cacheCounters.localHits)
+	cacheCounter("tickitz_cache_redis_errors_total", "Error get/set ke Redis", &cacheCounters.redisErrors)
+	metrics.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "tickitz_cache_hit_ratio",
+		Help: "Rasio hit cache sejak server jalan",
+	}, func() float64 { return GetCacheStats().HitRate })
+}
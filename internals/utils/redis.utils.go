@@ -2,47 +2,48 @@ package utils
 
 import (
 	"context"
-	"encoding/json"
-	"log"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
-// GetFromCache - ambil data slice dari Redis
-func GetFromCache[T any](ctx context.Context, rdb *redis.Client, key string) ([]T, bool) {
-	cmd := rdb.Get(ctx, key)
-	if cmd.Err() == nil {
-		var result []T
-		if b, err := cmd.Bytes(); err == nil {
-			if err := json.Unmarshal(b, &result); err == nil {
-				if len(result) > 0 {
-					return result, true
-				}
-			}
-		}
-	} else if cmd.Err() != redis.Nil {
-		log.Println("Redis Error.\nCause:", cmd.Err().Error())
+// InvalidateCache - hapus key dari Redis dan cache lokal. Jika Redis tidak tersedia
+// penghapusan diulang otomatis saat Redis pulih
+func InvalidateCache(ctx context.Context, rdb *redis.Client, keys ...string) error {
+	localCache.delete(keys...)
+	err := WithRedis(ctx, rdb, func() error { return rdb.Del(ctx, keys...).Err() })
+	if err != nil && rdb != nil {
+		queueInvalidation(nil, keys)
 	}
-	return nil, false
+	return err
 }
 
-// SetToCache - simpan data slice ke Redis dengan TTL
-func SetToCache[T any](ctx context.Context, rdb *redis.Client, key string, value []T, ttl time.Duration) {
-	if b, err := json.Marshal(value); err == nil {
-		if err := rdb.Set(ctx, key, b, ttl).Err(); err != nil {
-			log.Println("Redis Error saat set.\nCause:", err.Error())
-		}
-	} else {
-		log.Println("Marshal Error.\nCause:", err.Error())
-	}
+// tagTTL umur set tag, dibuat lebih panjang dari TTL cache manapun supaya key tidak "lepas" dari tag-nya
+const tagTTL = 48 * time.Hour
+
+// tagKey key Redis (SET) yang berisi daftar cache key milik sebuah tag, contoh "cache:tag:movie:42"
+func tagKey(tag string) string {
+	return "cache:tag:" + tag
 }
 
-func InvalidateCache(ctx context.Context, rdb *redis.Client, keys ...string) error {
-	for _, key := range keys {
-		if err := rdb.Del(ctx, key).Err(); err != nil {
-			return err
+// InvalidateTags - hapus semua cache key yang tercatat di tag, lalu tag itu sendiri. Jika Redis
+// tidak tersedia penghapusan diulang otomatis saat Redis pulih
+func InvalidateTags(ctx context.Context, rdb *redis.Client, tags ...string) error {
+	localCache.deleteTags(tags...)
+	err := WithRedis(ctx, rdb, func() error {
+		for _, tag := range tags {
+			keys, err := rdb.SMembers(ctx, tagKey(tag)).Result()
+			if err != nil {
+				return err
+			}
+			if err := rdb.Del(ctx, append(keys, tagKey(tag))...).Err(); err != nil {
+				return err
+			}
 		}
+		return nil
+	})
+	if err != nil && rdb != nil {
+		queueInvalidation(tags, nil)
 	}
-	return nil
+	return err
 }
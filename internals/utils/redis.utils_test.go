@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestRedis redis in-memory untuk unit test. Setiap client punya breaker sendiri, sedangkan cache lokal
+// dan antrean invalidasi bersifat global sehingga dikembalikan ke kondisi awal sebelum dan sesudah test
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	resetCacheState()
+	t.Cleanup(resetCacheState)
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { rdb.Close() })
+	return mr, rdb
+}
+
+// resetCacheState mengosongkan state di tempat (bukan mengganti variabel) karena goroutine
+// background dari test sebelumnya bisa masih memakainya
+func resetCacheState() {
+	localCache.mu.Lock()
+	localCache.ll.Init()
+	clear(localCache.items)
+	localCache.mu.Unlock()
+	pendingInvalidations.mu.Lock()
+	pendingInvalidations.tags = map[string]struct{}{}
+	pendingInvalidations.keys = map[string]struct{}{}
+	pendingInvalidations.queued.Store(false)
+	pendingInvalidations.mu.Unlock()
+}
+
+// waitFor menunggu kondisi yang dipenuhi goroutine background
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func cacheMovie(t *testing.T, rdb *redis.Client, key string, tags ...string) {
+	t.Helper()
+	_, err := Cached(t.Context(), rdb, key, CacheOptions{TTL: time.Minute, Tags: tags}, func(context.Context) (string, error) {
+		return "movie", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInvalidateTags(t *testing.T) {
+	ctx := t.Context()
+	mr, rdb := newTestRedis(t)
+
+	cacheMovie(t, rdb, "movie:1:detail", "movie:1", "movies")
+	cacheMovie(t, rdb, "movie:2:detail", "movie:2", "movies")
+	if members, err := mr.Members(tagKey("movies")); err != nil || len(members) != 2 {
+		t.Fatalf("tag movies members %v (err %v), want both keys", members, err)
+	}
+	if ttl := mr.TTL(tagKey("movie:1")); ttl != tagTTL {
+		t.Errorf("tag ttl %v, want %v", ttl, tagTTL)
+	}
+
+	if err := InvalidateTags(ctx, rdb, "movie:1"); err != nil {
+		t.Fatal(err)
+	}
+	if mr.Exists("movie:1:detail") || mr.Exists(tagKey("movie:1")) {
+		t.Errorf("movie:1 still cached, keys %v", mr.Keys())
+	}
+	if !mr.Exists("movie:2:detail") {
+		t.Error("movie:2 removed by unrelated tag")
+	}
+
+	if err := InvalidateTags(ctx, rdb, "movies"); err != nil {
+		t.Fatal(err)
+	}
+	if mr.Exists("movie:2:detail") || mr.Exists(tagKey("movies")) {
+		t.Errorf("movies still cached, keys %v", mr.Keys())
+	}
+}
+
+func TestInvalidateTagsWhileRedisDown(t *testing.T) {
+	ctx := t.Context()
+	mr, rdb := newTestRedis(t)
+	cacheMovie(t, rdb, "movie:1:detail", "movie:1")
+
+	mr.Close()
+	if err := InvalidateTags(ctx, rdb, "movie:1"); err == nil {
+		t.Fatal("invalidate succeeded while redis is down")
+	}
+
+	// satu error belum membuka breaker, invalidasi tetap dikirim ulang pada perintah berikutnya yang berhasil
+	if state, _ := breakerFor(rdb).snapshot(); state != BreakerClosed {
+		t.Fatalf("breaker %s after one failure, want closed", state)
+	}
+	if err := mr.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	if health := CheckRedis(ctx, rdb); health.Status != "up" {
+		t.Fatalf("redis %s after restart", health.Status)
+	}
+	waitFor(t, "queued invalidation", func() bool { return !mr.Exists("movie:1:detail") })
+	if mr.Exists(tagKey("movie:1")) {
+		t.Error("tag set left after queued invalidation")
+	}
+}
+
+func TestPendingInvalidationsFlushedOnRecovery(t *testing.T) {
+	ctx := t.Context()
+	mr, rdb := newTestRedis(t)
+	cacheMovie(t, rdb, "movie:1:detail", "movie:1")
+	mr.Set("showtimes:1", "[]")
+
+	mr.Close()
+	for range 3 {
+		CheckRedis(ctx, rdb)
+	}
+	if state, _ := breakerFor(rdb).snapshot(); state != BreakerOpen {
+		t.Fatalf("breaker %s after 3 failures, want open", state)
+	}
+
+	// Redis mati: data disimpan di cache lokal lengkap dengan tag-nya
+	cacheMovie(t, rdb, "movie:2:detail", "movie:2")
+	if _, ok := localCache.get("movie:2:detail"); !ok {
+		t.Fatal("value not kept in local cache while redis is down")
+	}
+	if err := InvalidateTags(ctx, rdb, "movie:2"); !errors.Is(err, ErrRedisUnavailable) {
+		t.Fatalf("invalidate with open breaker: err %v, want %v", err, ErrRedisUnavailable)
+	}
+	if _, ok := localCache.get("movie:2:detail"); ok {
+		t.Error("local cache not invalidated by tag")
+	}
+	// breaker open: invalidasi tidak menghubungi Redis sama sekali, hanya diantrekan
+	if err := InvalidateTags(ctx, rdb, "movie:1"); !errors.Is(err, ErrRedisUnavailable) {
+		t.Fatalf("invalidate with open breaker: err %v, want %v", err, ErrRedisUnavailable)
+	}
+	if err := InvalidateCache(ctx, rdb, "showtimes:1"); !errors.Is(err, ErrRedisUnavailable) {
+		t.Fatalf("invalidate with open breaker: err %v, want %v", err, ErrRedisUnavailable)
+	}
+
+	if err := mr.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	// cooldown dipersingkat supaya probe berikutnya langsung dikirim
+	breaker := breakerFor(rdb)
+	breaker.mu.Lock()
+	breaker.openedAt = time.Now().Add(-breaker.cooldown)
+	breaker.mu.Unlock()
+	if health := CheckRedis(ctx, rdb); health.Status != "up" || health.Breaker != BreakerClosed {
+		t.Fatalf("health after recovery %+v", health)
+	}
+	waitFor(t, "queued invalidation", func() bool {
+		return !mr.Exists("movie:1:detail") && !mr.Exists("showtimes:1")
+	})
+}
@@ -1,16 +1,26 @@
 package pkg
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// JWTConfig secret, issuer dan masa berlaku access token, dibaca sekali saat start (lihat configs.Load)
+type JWTConfig struct {
+	Secret    string
+	Issuer    string
+	AccessTTL time.Duration
+}
+
 type Claims struct {
-	UserId int    `json:"id"`
-	Role   string `json:"role"`
+	UserId    int    `json:"id"`
+	Role      string `json:"role"`
+	SessionId string `json:"sid,omitempty"` // session/device asal token, kosong untuk token lama
 	jwt.RegisteredClaims
 }
 
@@ -19,27 +29,43 @@ func NewJWTClaims(userid int, role string) *Claims {
 		UserId: userid,
 		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 30)),
-			Issuer:    os.Getenv("JWT_ISSUER"),
+			ID: uuid.NewString(),
 		},
 	}
 }
 
-func (c *Claims) GenToken() (string, error) {
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
+// NewSessionJWTClaims access token yang terikat ke sebuah session (refresh token family)
+func NewSessionJWTClaims(userid int, role, sessionId string) *Claims {
+	claims := NewJWTClaims(userid, role)
+	claims.SessionId = sessionId
+	return claims
+}
+
+// RevocationKey kunci blacklist token: jti, atau sha256 token untuk token lama yang belum punya jti
+func (c *Claims) RevocationKey(token string) string {
+	if c.ID != "" {
+		return c.ID
+	}
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenToken menandatangani claims, issuer dan masa berlaku diambil dari cfg
+func (c *Claims) GenToken(cfg JWTConfig) (string, error) {
+	if cfg.Secret == "" {
 		return "", errors.New("no secret found")
 	}
+	c.Issuer = cfg.Issuer
+	c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(cfg.AccessTTL))
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, c)
-	return token.SignedString([]byte(jwtSecret))
+	return token.SignedString([]byte(cfg.Secret))
 }
 
-func (c *Claims) VerifyToken(token string) error {
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
+func (c *Claims) VerifyToken(token string, cfg JWTConfig) error {
+	if cfg.Secret == "" {
 		return errors.New("no secret found")
 	}
-	parsedToken, err := jwt.ParseWithClaims(token, c, func(t *jwt.Token) (any, error) { return []byte(jwtSecret), nil })
+	parsedToken, err := jwt.ParseWithClaims(token, c, func(t *jwt.Token) (any, error) { return []byte(cfg.Secret), nil })
 	if err != nil {
 		return err
 	}
@@ -50,7 +76,7 @@ func (c *Claims) VerifyToken(token string) error {
 	if err != nil {
 		return err
 	}
-	if iss != os.Getenv("JWT_ISSUER") {
+	if iss != cfg.Issuer {
 		return jwt.ErrTokenInvalidIssuer
 	}
 	return nil
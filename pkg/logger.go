@@ -0,0 +1,108 @@
+package pkg
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+// redactedValue pengganti nilai field sensitif di log
+const redactedValue = "[REDACTED]"
+
+// sensitiveKeys potongan nama field yang nilainya tidak boleh masuk log (dicocokkan tanpa huruf besar/kecil,
+// "_" dan "-"), contoh: password, new_password, access_token, qr_code, Authorization
+var sensitiveKeys = []string{"password", "token", "secret", "authorization", "qrcode", "otp", "signature", "cookie"}
+
+// IsSensitiveKey true jika nama field termasuk data sensitif
+func IsSensitiveKey(key string) bool {
+	normalized := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(normalized, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseLogLevel debug / info / warn / error, selain itu info
+func ParseLogLevel(level string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// NewLogger membuat logger slog (format "json" atau "text") yang menyamarkan field sensitif,
+// termasuk field di dalam struct / map yang di-log dengan slog.Any
+func NewLogger(w io.Writer, level slog.Level, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}
+	if strings.ToLower(format) == "text" {
+		return slog.New(slog.NewTextHandler(w, opts))
+	}
+	return slog.New(slog.NewJSONHandler(w, opts))
+}
+
+func redactAttr(_ []string, attr slog.Attr) slog.Attr {
+	if IsSensitiveKey(attr.Key) {
+		return slog.String(attr.Key, redactedValue)
+	}
+	if attr.Value.Kind() == slog.KindAny {
+		if redacted, ok := redactValue(attr.Value.Any()); ok {
+			return slog.Any(attr.Key, redacted)
+		}
+	}
+	return attr
+}
+
+// redactValue struct / map / slice diubah lewat JSON menjadi map lalu field sensitifnya disamarkan
+func redactValue(value any) (any, bool) {
+	if value == nil {
+		return nil, false
+	}
+	if _, isError := value.(error); isError {
+		return nil, false
+	}
+	kind := reflect.Indirect(reflect.ValueOf(value)).Kind()
+	if kind != reflect.Struct && kind != reflect.Map && kind != reflect.Slice && kind != reflect.Array {
+		return nil, false
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, false
+	}
+	var generic any
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return nil, false
+	}
+	return redactGeneric(generic), true
+}
+
+func redactGeneric(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, inner := range v {
+			if IsSensitiveKey(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactGeneric(inner)
+			}
+		}
+		return v
+	case []any:
+		for i, inner := range v {
+			v[i] = redactGeneric(inner)
+		}
+		return v
+	default:
+		return v
+	}
+}
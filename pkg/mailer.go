@@ -0,0 +1,99 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer mengirim email transaksional (verifikasi akun, reset password)
+type Mailer interface {
+	Send(ctx context.Context, mail Mail) error
+}
+
+// SMTPMailer mengirim email lewat server SMTP (PLAIN auth jika username diisi)
+type SMTPMailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	return &SMTPMailer{host: host, port: port, username: username, password: password, from: from}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, mail Mail) error {
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(net.JoinHostPort(m.host, m.port), auth, m.from, []string{mail.To}, buildMessage(m.from, mail))
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-done:
+		return err
+	}
+}
+
+// LocalMailer untuk development dan test: email ditulis ke file .eml di dir,
+// atau hanya di-log jika dir kosong
+type LocalMailer struct {
+	dir  string
+	from string
+}
+
+func NewLocalMailer(dir, from string) *LocalMailer {
+	return &LocalMailer{dir: dir, from: from}
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+func (m *LocalMailer) Send(ctx context.Context, mail Mail) error {
+	if m.dir == "" {
+		slog.InfoContext(ctx, "mail not delivered, outbox dir not set", "to", mail.To, "subject", mail.Subject, "body", mail.Body)
+		return nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d_%s.eml", time.Now().UnixNano(), unsafeFileChars.ReplaceAllString(mail.To, "_"))
+	return os.WriteFile(filepath.Join(m.dir, name), buildMessage(m.from, mail), 0o644)
+}
+
+// headerValue membuang CR/LF supaya nilai header tidak bisa menyisipkan header lain
+var headerValue = strings.NewReplacer("\r", "", "\n", "")
+
+func buildMessage(from string, mail Mail) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", headerValue.Replace(from))
+	fmt.Fprintf(&b, "To: %s\r\n", headerValue.Replace(mail.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", headerValue.Replace(mail.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(mail.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,53 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildMessageStripsHeaderInjection(t *testing.T) {
+	msg := string(buildMessage("Tickitz <no-reply@tickitz.id>", Mail{
+		To:      "budi@example.com\r\nBcc: korban@example.com",
+		Subject: "Reset password\nX-Injected: 1",
+		Body:    "baris 1\nbaris 2",
+	}))
+
+	headers, body, ok := strings.Cut(msg, "\r\n\r\n")
+	if !ok {
+		t.Fatalf("no header/body separator in %q", msg)
+	}
+	for line := range strings.SplitSeq(headers, "\r\n") {
+		if strings.HasPrefix(line, "Bcc:") || strings.HasPrefix(line, "X-Injected:") {
+			t.Errorf("injected header %q", line)
+		}
+	}
+	if !strings.Contains(headers, "To: budi@example.comBcc: korban@example.com") {
+		t.Errorf("To header not sanitized: %q", headers)
+	}
+	if body != "baris 1\r\nbaris 2" {
+		t.Errorf("body %q, want CRLF line endings", body)
+	}
+}
+
+func TestLocalMailerWritesEml(t *testing.T) {
+	dir := t.TempDir()
+	mailer := NewLocalMailer(dir, "no-reply@tickitz.id")
+	if err := mailer.Send(t.Context(), Mail{To: "budi/../@example.com", Subject: "Halo", Body: "isi"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// path separator di alamat email tidak boleh membuat file di luar dir
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("eml files %v (err %v), want 1", files, err)
+	}
+	content, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "Subject: Halo\r\n") {
+		t.Errorf("mail file %q missing subject", content)
+	}
+}
@@ -0,0 +1,352 @@
+package pkg
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrationLockID kunci pg_advisory_lock supaya dua proses tidak menjalankan migrasi bersamaan
+const migrationLockID = 7293021
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+var (
+	ErrDirtyDatabase = errors.New("database is dirty")
+	ErrSchemaBehind  = errors.New("database schema is behind")
+)
+
+// Migration satu versi migrasi beserta SQL up dan down-nya
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus status satu migrasi terhadap database
+type MigrationStatus struct {
+	Migration
+	Applied bool
+}
+
+// Migrator menjalankan migrasi SQL yang di-embed. Versi disimpan di tabel schema_migrations
+// (version, dirty) yang sama dengan golang-migrate, jadi database lama yang dimigrasi lewat
+// CLI migrate tetap dikenali. Setiap migrasi dijalankan dalam satu transaksi
+type Migrator struct {
+	db         *pgxpool.Pool
+	migrations []Migration
+}
+
+// NewMigrator membaca file <versi>_<nama>.up.sql / .down.sql di root fsys
+func NewMigrator(db *pgxpool.Pool, fsys fs.FS) (*Migrator, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[uint64]*Migration{}
+	for _, entry := range entries {
+		match := migrationFileRe.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %s: %w", entry.Name(), err)
+		}
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrator := &Migrator{db: db}
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		migrator.migrations = append(migrator.migrations, *m)
+	}
+	slices.SortFunc(migrator.migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return migrator, nil
+}
+
+// Latest versi migrasi terbaru yang dibawa binary, 0 jika tidak ada migrasi
+func (m *Migrator) Latest() uint64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version versi schema di database, 0 jika belum pernah dimigrasi
+func (m *Migrator) Version(ctx context.Context) (uint64, bool, error) {
+	if err := m.ensureTable(ctx, m.db); err != nil {
+		return 0, false, err
+	}
+	return m.version(ctx, m.db)
+}
+
+// Status daftar semua migrasi beserta apakah sudah diterapkan
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, uint64, bool, error) {
+	version, dirty, err := m.Version(ctx)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		statuses = append(statuses, MigrationStatus{Migration: migration, Applied: migration.Version <= version})
+	}
+	return statuses, version, dirty, nil
+}
+
+// CheckCurrent error jika database dirty atau versinya di bawah migrasi terbaru binary
+func (m *Migrator) CheckCurrent(ctx context.Context) error {
+	version, dirty, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("%w at version %d", ErrDirtyDatabase, version)
+	}
+	if latest := m.Latest(); version < latest {
+		return fmt.Errorf("%w: database at version %d, binary expects %d", ErrSchemaBehind, version, latest)
+	}
+	return nil
+}
+
+// Up menerapkan maksimal n migrasi berikutnya, n <= 0 berarti semua. Mengembalikan migrasi yang diterapkan
+func (m *Migrator) Up(ctx context.Context, n int) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		version, err := m.cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if migration.Version <= version {
+				continue
+			}
+			if n > 0 && len(applied) == n {
+				break
+			}
+			if err := m.apply(ctx, conn, migration.Up, migration.Version); err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down membatalkan n migrasi terakhir (n <= 0 dianggap 1). Mengembalikan migrasi yang dibatalkan
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	n = max(n, 1)
+	var reverted []Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		version, err := m.cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < n; i-- {
+			migration := m.migrations[i]
+			if migration.Version > version {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+			}
+			var previous uint64
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+			if err := m.apply(ctx, conn, migration.Down, previous); err != nil {
+				return fmt.Errorf("migration %d_%s down: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Force menandai database di versi tertentu dan bersih (tanpa menjalankan SQL),
+// dipakai setelah memperbaiki migrasi yang gagal secara manual. Versi 0 mengosongkan schema_migrations
+func (m *Migrator) Force(ctx context.Context, version uint64) error {
+	if version != 0 && !slices.ContainsFunc(m.migrations, func(migration Migration) bool { return migration.Version == version }) {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		tx, err := conn.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+		if err := setVersion(ctx, tx, version); err != nil {
+			return err
+		}
+		return tx.Commit(ctx)
+	})
+}
+
+// apply menjalankan SQL dan mencatat versi baru dalam satu transaksi, jika gagal database tetap di versi lama
+func (m *Migrator) apply(ctx context.Context, conn *pgxpool.Conn, sql string, version uint64) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Exec tanpa argumen memakai simple protocol, jadi satu file boleh berisi banyak statement
+	if _, err := tx.Exec(ctx, sql); err != nil {
+		return err
+	}
+	if err := setVersion(ctx, tx, version); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// setVersion schema_migrations hanya berisi satu baris, versi 0 berarti tabel kosong (belum ada migrasi)
+func setVersion(ctx context.Context, tx pgx.Tx, version uint64) error {
+	if _, err := tx.Exec(ctx, "TRUNCATE public.schema_migrations"); err != nil {
+		return err
+	}
+	if version == 0 {
+		return nil
+	}
+	_, err := tx.Exec(ctx, "INSERT INTO public.schema_migrations (version, dirty) VALUES ($1, false)", int64(version))
+	return err
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return err
+	}
+	defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// cleanVersion versi saat ini, error jika dirty (migrasi lewat CLI migrate pernah gagal di tengah jalan)
+func (m *Migrator) cleanVersion(ctx context.Context, q querier) (uint64, error) {
+	version, dirty, err := m.version(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("%w at version %d, fix it manually then run `migrate force %d`", ErrDirtyDatabase, version, version)
+	}
+	return version, nil
+}
+
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func (m *Migrator) ensureTable(ctx context.Context, q querier) error {
+	_, err := q.Exec(ctx, "CREATE TABLE IF NOT EXISTS public.schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)")
+	return err
+}
+
+func (m *Migrator) version(ctx context.Context, q querier) (uint64, bool, error) {
+	var version int64
+	var dirty bool
+	err := q.QueryRow(ctx, "SELECT version, dirty FROM public.schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return uint64(max(version, 0)), dirty, nil
+}
+
+// SeedProfiles nama profile seed yang tersedia (sub folder di root fsys)
+func SeedProfiles(fsys fs.FS) ([]string, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	var profiles []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			profiles = append(profiles, entry.Name())
+		}
+	}
+	return profiles, nil
+}
+
+// Seed menjalankan semua file <profile>/*.sql urut nama file dalam satu transaksi,
+// jika satu file gagal tidak ada data yang masuk. Mengembalikan nama file yang dijalankan
+func Seed(ctx context.Context, db *pgxpool.Pool, fsys fs.FS, profile string) ([]string, error) {
+	if profile == "" || strings.ContainsAny(profile, `/\.`) {
+		return nil, fmt.Errorf("invalid seed profile %q", profile)
+	}
+	files, err := fs.Glob(fsys, path.Join(profile, "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("seed profile %q not found", profile)
+	}
+	slices.Sort(files)
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, file := range files {
+		body, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(ctx, string(body)); err != nil {
+			return nil, fmt.Errorf("seed %s: %w", file, err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
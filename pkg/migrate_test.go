@@ -0,0 +1,110 @@
+package pkg
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestNewMigrator(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000010_add_reviews.up.sql":   {Data: []byte("CREATE TABLE reviews ()")},
+		"000010_add_reviews.down.sql": {Data: []byte("DROP TABLE reviews")},
+		"000002_add_movies.up.sql":    {Data: []byte("CREATE TABLE movies ()")},
+		"000001_init.up.sql":          {Data: []byte("CREATE TABLE users ()")},
+		"000001_init.down.sql":        {Data: []byte("DROP TABLE users")},
+		"README.md":                   {Data: []byte("bukan migrasi")},
+		"000003_notes.sql":            {Data: []byte("tanpa arah up/down")},
+		"archive/000004_old.up.sql":   {Data: []byte("di sub folder")},
+	}
+	migrator, err := NewMigrator(nil, fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var versions []uint64
+	for _, m := range migrator.migrations {
+		versions = append(versions, m.Version)
+	}
+	if want := []uint64{1, 2, 10}; !slices.Equal(versions, want) {
+		t.Fatalf("versions %v, want %v (numeric order, other files ignored)", versions, want)
+	}
+	if migrator.Latest() != 10 {
+		t.Errorf("latest %d, want 10", migrator.Latest())
+	}
+	first := migrator.migrations[0]
+	if first.Name != "init" || first.Up != "CREATE TABLE users ()" || first.Down != "DROP TABLE users" {
+		t.Errorf("migration 1 %+v", first)
+	}
+	if migrator.migrations[1].Down != "" {
+		t.Error("migration without down file got a down script")
+	}
+}
+
+func TestNewMigratorErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		wantErr string
+	}{
+		{
+			name:    "down without up",
+			fsys:    fstest.MapFS{"000001_init.down.sql": {Data: []byte("DROP TABLE users")}},
+			wantErr: "migration 1_init has no up file",
+		},
+		{
+			name: "duplicate version",
+			fsys: fstest.MapFS{
+				"000001_init.up.sql":  {Data: []byte("CREATE TABLE users ()")},
+				"000001_other.up.sql": {Data: []byte("CREATE TABLE other ()")},
+			},
+			wantErr: "duplicate migration version 1",
+		},
+		{
+			name:    "version overflow",
+			fsys:    fstest.MapFS{"99999999999999999999_big.up.sql": {Data: []byte("SELECT 1")}},
+			wantErr: "invalid migration version",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMigrator(nil, tt.fsys)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewMigratorEmpty(t *testing.T) {
+	migrator, err := NewMigrator(nil, fstest.MapFS{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if migrator.Latest() != 0 {
+		t.Errorf("latest %d, want 0", migrator.Latest())
+	}
+}
+
+func TestSeedProfiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"demo/001_users.sql":    {Data: []byte("INSERT INTO users DEFAULT VALUES")},
+		"minimal/001_admin.sql": {Data: []byte("INSERT INTO users DEFAULT VALUES")},
+		"README.md":             {Data: []byte("profile = sub folder")},
+	}
+	profiles, err := SeedProfiles(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"demo", "minimal"}; !slices.Equal(profiles, want) {
+		t.Errorf("profiles %v, want %v", profiles, want)
+	}
+
+	// nama profile divalidasi sebelum menyentuh database
+	for _, profile := range []string{"", "../migrations", "demo/sub", `demo\sub`, "missing"} {
+		if _, err := Seed(t.Context(), nil, fsys, profile); err == nil {
+			t.Errorf("seed profile %q accepted", profile)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package pkg
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+type ChargeStatus string
+
+const (
+	ChargePending  ChargeStatus = "pending"
+	ChargePaid     ChargeStatus = "paid"
+	ChargeFailed   ChargeStatus = "failed"
+	ChargeRefunded ChargeStatus = "refunded"
+)
+
+var ErrChargeNotFound = errors.New("charge not found")
+
+type ChargeRequest struct {
+	OrderId int
+	Amount  int
+	Method  string
+}
+
+type Charge struct {
+	Reference   string       `json:"reference"`
+	Status      ChargeStatus `json:"status"`
+	Amount      int          `json:"amount"`
+	RedirectURL string       `json:"redirect_url,omitempty"`
+	ExpiresAt   time.Time    `json:"expires_at"`
+}
+
+type Refund struct {
+	Reference string       `json:"reference"`
+	Amount    int          `json:"amount"`
+	Status    ChargeStatus `json:"status"`
+}
+
+// PaymentGateway adalah kontrak untuk penyedia pembayaran (local, Midtrans, Xendit, ...)
+type PaymentGateway interface {
+	Name() string
+	CreateCharge(ctx context.Context, req ChargeRequest) (Charge, error)
+	GetStatus(ctx context.Context, reference string) (ChargeStatus, error)
+	Refund(ctx context.Context, reference string, amount int) (Refund, error)
+}
+
+// LocalGateway adalah gateway in-memory untuk development dan testing.
+// Charge tetap pending sampai ada webhook yang ditandatangani dengan secret yang sama
+type LocalGateway struct {
+	mu      sync.Mutex
+	charges map[string]*Charge
+	ttl     time.Duration
+}
+
+// NewLocalGateway charge kadaluarsa setelah ttl
+func NewLocalGateway(ttl time.Duration) *LocalGateway {
+	return &LocalGateway{
+		charges: make(map[string]*Charge),
+		ttl:     ttl,
+	}
+}
+
+func (l *LocalGateway) Name() string {
+	return "local"
+}
+
+func (l *LocalGateway) CreateCharge(ctx context.Context, req ChargeRequest) (Charge, error) {
+	if req.Amount <= 0 {
+		return Charge{}, errors.New("invalid charge amount")
+	}
+
+	ref := make([]byte, 8)
+	if _, err := rand.Read(ref); err != nil {
+		return Charge{}, err
+	}
+
+	charge := Charge{
+		Reference: fmt.Sprintf("LOCAL-%d-%s", req.OrderId, strings.ToUpper(hex.EncodeToString(ref))),
+		Status:    ChargePending,
+		Amount:    req.Amount,
+		ExpiresAt: time.Now().Add(l.ttl),
+	}
+
+	l.mu.Lock()
+	l.charges[charge.Reference] = &charge
+	l.mu.Unlock()
+
+	return charge, nil
+}
+
+func (l *LocalGateway) GetStatus(ctx context.Context, reference string) (ChargeStatus, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	charge, ok := l.charges[reference]
+	if !ok {
+		return "", ErrChargeNotFound
+	}
+	return charge.Status, nil
+}
+
+// SetStatus mensimulasikan perubahan status dari sisi penyedia pembayaran
+func (l *LocalGateway) SetStatus(reference string, status ChargeStatus) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	charge, ok := l.charges[reference]
+	if !ok {
+		return ErrChargeNotFound
+	}
+	charge.Status = status
+	return nil
+}
+
+func (l *LocalGateway) Refund(ctx context.Context, reference string, amount int) (Refund, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// charge yang dibuat sebelum restart tidak ada di memory, anggap refund berhasil
+	if charge, ok := l.charges[reference]; ok {
+		charge.Status = ChargeRefunded
+	}
+	return Refund{Reference: reference, Amount: amount, Status: ChargeRefunded}, nil
+}
+
+// SignWebhookPayload menghasilkan signature HMAC-SHA256 (hex) dari body webhook
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature membandingkan signature dengan waktu konstan
+func VerifyWebhookSignature(secret string, body []byte, signature string) bool {
+	if secret == "" || signature == "" {
+		return false
+	}
+	expected := SignWebhookPayload(secret, body)
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(signature)))
+}
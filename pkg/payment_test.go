@@ -0,0 +1,64 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	body := []byte(`{"reference":"LOCAL-1-AB","status":"paid","amount":50000}`)
+	signature := SignWebhookPayload("secret", body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		body      []byte
+		signature string
+		want      bool
+	}{
+		{name: "valid", secret: "secret", body: body, signature: signature, want: true},
+		{name: "uppercase hex", secret: "secret", body: body, signature: strings.ToUpper(signature), want: true},
+		{name: "tampered body", secret: "secret", body: []byte(`{"reference":"LOCAL-1-AB","status":"paid","amount":1}`), signature: signature, want: false},
+		{name: "wrong secret", secret: "other", body: body, signature: signature, want: false},
+		{name: "truncated signature", secret: "secret", body: body, signature: signature[:32], want: false},
+		{name: "empty signature", secret: "secret", body: body, signature: "", want: false},
+		// tanpa secret semua webhook ditolak, termasuk yang ditandatangani dengan secret kosong
+		{name: "empty secret", secret: "", body: body, signature: SignWebhookPayload("", body), want: false},
+	}
+	for _, tt := range tests {
+		if got := VerifyWebhookSignature(tt.secret, tt.body, tt.signature); got != tt.want {
+			t.Errorf("%s: VerifyWebhookSignature = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLocalGatewayCharge(t *testing.T) {
+	gateway := NewLocalGateway(15 * time.Minute)
+
+	if _, err := gateway.CreateCharge(t.Context(), ChargeRequest{OrderId: 1, Amount: 0}); err == nil {
+		t.Error("charge with zero amount accepted")
+	}
+
+	before := time.Now()
+	charge, err := gateway.CreateCharge(t.Context(), ChargeRequest{OrderId: 1, Amount: 50000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if charge.Status != ChargePending || charge.Amount != 50000 || !strings.HasPrefix(charge.Reference, "LOCAL-1-") {
+		t.Errorf("unexpected charge %+v", charge)
+	}
+	if charge.ExpiresAt.Before(before.Add(15*time.Minute)) || charge.ExpiresAt.After(time.Now().Add(15*time.Minute)) {
+		t.Errorf("charge expires at %s, want 15 minutes from now", charge.ExpiresAt)
+	}
+
+	if err := gateway.SetStatus(charge.Reference, ChargePaid); err != nil {
+		t.Fatal(err)
+	}
+	if status, err := gateway.GetStatus(t.Context(), charge.Reference); err != nil || status != ChargePaid {
+		t.Errorf("status %s (err %v), want paid", status, err)
+	}
+	if _, err := gateway.GetStatus(t.Context(), "LOCAL-404"); err != ErrChargeNotFound {
+		t.Errorf("unknown reference: err %v, want ErrChargeNotFound", err)
+	}
+}
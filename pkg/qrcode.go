@@ -0,0 +1,14 @@
+package pkg
+
+import qrcode "github.com/skip2/go-qrcode"
+
+// TicketQRPNG merender kode tiket menjadi gambar PNG QR Code level M,
+// 8 px per modul dengan quiet zone 4 modul supaya mudah dibaca scanner
+func TicketQRPNG(code string) ([]byte, error) {
+	qr, err := qrcode.New(code, qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+	// ukuran negatif = lebar satu modul dalam pixel
+	return qr.PNG(-8)
+}
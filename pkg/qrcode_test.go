@@ -0,0 +1,51 @@
+package pkg
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+func TestTicketQRPNG(t *testing.T) {
+	signer := newTestSigner(t)
+	code, err := signer.Sign(TicketPayload{TicketId: 1, OrderId: 2, NowShowingId: 3, Seats: []string{"A1", "A2", "A3"}, ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := TicketQRPNG(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("ticket QR is not a PNG: %v", err)
+	}
+
+	// setiap modul QR (termasuk quiet zone) digambar 8x8 pixel sesuai bitmap dari encoder
+	qr, err := qrcode.New(code, qrcode.Medium)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bitmap := qr.Bitmap()
+	const scale = 8
+	if bounds := img.Bounds(); bounds.Dx() != len(bitmap)*scale || bounds.Dy() != len(bitmap)*scale {
+		t.Fatalf("image %v, want %d modules of %d px", bounds, len(bitmap), scale)
+	}
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if got := isDark(img, x*scale+scale/2, y*scale+scale/2); got != dark {
+				t.Fatalf("module (%d, %d) dark=%v, want %v", x, y, got, dark)
+			}
+		}
+	}
+}
+
+func isDark(img image.Image, x, y int) bool {
+	r, g, b, _ := img.At(x, y).RGBA()
+	return r+g+b < 3*0x8000
+}
@@ -0,0 +1,109 @@
+package pkg
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// prefix kode tiket bertanda tangan, kode lama (hex acak) tidak memakai prefix ini
+const ticketCodePrefix = "TKZ1."
+
+var (
+	ErrTicketInvalid = errors.New("invalid ticket code")
+	ErrTicketExpired = errors.New("ticket expired")
+)
+
+// TicketPayload isi QR tiket, cukup untuk diverifikasi offline di pintu studio
+type TicketPayload struct {
+	TicketId     int      `json:"tid"`
+	OrderId      int      `json:"oid"`
+	NowShowingId int      `json:"sid"`
+	Seats        []string `json:"seats"`
+	ExpiresAt    int64    `json:"exp"`
+}
+
+// TicketSigner menandatangani kode tiket dengan ed25519.
+// Scanner cukup menyimpan public key untuk memverifikasi tiket tanpa koneksi ke server
+type TicketSigner struct {
+	priv           ed25519.PrivateKey
+	pub            ed25519.PublicKey
+	validAfterShow time.Duration
+}
+
+// NewTicketSigner membuat signer dari seed ed25519 (32 byte).
+// validAfterShow = lama tiket masih berlaku setelah jam tayang dimulai
+func NewTicketSigner(seed []byte, validAfterShow time.Duration) (*TicketSigner, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, errors.New("ticket signing key must be 32 bytes")
+	}
+	priv := ed25519.NewKeyFromSeed(seed)
+	return &TicketSigner{
+		priv:           priv,
+		pub:            priv.Public().(ed25519.PublicKey),
+		validAfterShow: validAfterShow,
+	}, nil
+}
+
+// PublicKey public key dalam base64 (std) untuk dibagikan ke aplikasi scanner
+func (s *TicketSigner) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(s.pub)
+}
+
+// ExpiryFor batas berlaku tiket untuk jadwal tayang yang dimulai pada showtime
+func (s *TicketSigner) ExpiryFor(showtime time.Time) time.Time {
+	return showtime.Add(s.validAfterShow)
+}
+
+// Sign menghasilkan kode tiket: TKZ1.<payload base64url>.<signature base64url>
+func (s *TicketSigner) Sign(payload TicketPayload) (string, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(body)
+	sig := ed25519.Sign(s.priv, []byte(ticketCodePrefix+encoded))
+	return ticketCodePrefix + encoded + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// Verify memeriksa signature dan masa berlaku kode tiket
+func (s *TicketSigner) Verify(code string, now time.Time) (TicketPayload, error) {
+	return VerifyTicketCode(s.pub, code, now)
+}
+
+// VerifyTicketCode memverifikasi kode tiket hanya dengan public key
+func VerifyTicketCode(pub ed25519.PublicKey, code string, now time.Time) (TicketPayload, error) {
+	if !IsSignedTicketCode(code) {
+		return TicketPayload{}, ErrTicketInvalid
+	}
+
+	encoded, sigPart, ok := strings.Cut(strings.TrimPrefix(code, ticketCodePrefix), ".")
+	if !ok {
+		return TicketPayload{}, ErrTicketInvalid
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(sigPart)
+	if err != nil || !ed25519.Verify(pub, []byte(ticketCodePrefix+encoded), sig) {
+		return TicketPayload{}, ErrTicketInvalid
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return TicketPayload{}, ErrTicketInvalid
+	}
+	var payload TicketPayload
+	if err := json.Unmarshal(body, &payload); err != nil || payload.TicketId <= 0 {
+		return TicketPayload{}, ErrTicketInvalid
+	}
+
+	if now.Unix() > payload.ExpiresAt {
+		return payload, ErrTicketExpired
+	}
+	return payload, nil
+}
+
+func IsSignedTicketCode(code string) bool {
+	return strings.HasPrefix(code, ticketCodePrefix)
+}
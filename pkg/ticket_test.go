@@ -0,0 +1,100 @@
+package pkg
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestSigner(t *testing.T) *TicketSigner {
+	t.Helper()
+	signer, err := NewTicketSigner(make([]byte, ed25519.SeedSize), 3*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+func TestNewTicketSignerKeySize(t *testing.T) {
+	for _, size := range []int{0, 16, 64} {
+		if _, err := NewTicketSigner(make([]byte, size), time.Hour); err == nil {
+			t.Errorf("%d byte seed accepted, want only 32 bytes", size)
+		}
+	}
+}
+
+func TestTicketSignVerify(t *testing.T) {
+	signer := newTestSigner(t)
+	now := time.Date(2025, 9, 20, 19, 0, 0, 0, time.UTC)
+	payload := TicketPayload{TicketId: 10, OrderId: 7, NowShowingId: 3, Seats: []string{"C5", "C6"}, ExpiresAt: signer.ExpiryFor(now).Unix()}
+
+	code, err := signer.Sign(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSignedTicketCode(code) {
+		t.Fatalf("code %q without prefix %q", code, ticketCodePrefix)
+	}
+
+	got, err := signer.Verify(code, now)
+	if err != nil {
+		t.Fatalf("verify own ticket: %v", err)
+	}
+	if got.TicketId != payload.TicketId || got.OrderId != payload.OrderId || got.NowShowingId != payload.NowShowingId || strings.Join(got.Seats, ",") != "C5,C6" {
+		t.Errorf("payload %+v, want %+v", got, payload)
+	}
+
+	// scanner offline hanya memegang public key
+	pub, err := base64.StdEncoding.DecodeString(signer.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyTicketCode(ed25519.PublicKey(pub), code, now); err != nil {
+		t.Errorf("verify with published public key: %v", err)
+	}
+
+	if _, err := signer.Verify(code, now.Add(3*time.Hour+time.Second)); !errors.Is(err, ErrTicketExpired) {
+		t.Errorf("verify after validity: err %v, want ErrTicketExpired", err)
+	}
+}
+
+func TestTicketVerifyRejectsForgery(t *testing.T) {
+	signer := newTestSigner(t)
+	now := time.Now()
+	code, err := signer.Sign(TicketPayload{TicketId: 10, OrderId: 7, NowShowingId: 3, Seats: []string{"C5"}, ExpiresAt: now.Add(time.Hour).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, sig, _ := strings.Cut(strings.TrimPrefix(code, ticketCodePrefix), ".")
+
+	// payload dengan kursi lain tetapi signature lama
+	forgedBody := base64.RawURLEncoding.EncodeToString([]byte(`{"tid":10,"oid":7,"sid":3,"seats":["A1"],"exp":` + "9999999999" + `}`))
+
+	otherSeed := make([]byte, ed25519.SeedSize)
+	otherSeed[0] = 1
+	other, err := NewTicketSigner(otherSeed, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCode, err := other.Sign(TicketPayload{TicketId: 10, OrderId: 7, NowShowingId: 3, Seats: []string{"C5"}, ExpiresAt: now.Add(time.Hour).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, forged := range map[string]string{
+		"modified payload":   ticketCodePrefix + forgedBody + "." + sig,
+		"signed by other":    otherCode,
+		"missing signature":  ticketCodePrefix + encoded,
+		"garbage signature":  ticketCodePrefix + encoded + ".!!!",
+		"truncated":          code[:len(code)-4],
+		"legacy random code": "9f86d081884c7d659a2feaa0c55ad015",
+		"empty":              "",
+	} {
+		if _, err := signer.Verify(forged, now); !errors.Is(err, ErrTicketInvalid) {
+			t.Errorf("%s: err %v, want ErrTicketInvalid", name, err)
+		}
+	}
+}